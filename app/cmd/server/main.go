@@ -13,11 +13,17 @@ import (
 	"time"
 	"vcv/internal/metrics"
 
+	"vcv/internal/ack"
+	"vcv/internal/certs"
 	"vcv/internal/config"
+	"vcv/internal/events"
 	"vcv/internal/handlers"
+	"vcv/internal/history"
 	"vcv/internal/logger"
 	"vcv/internal/middleware"
 	"vcv/internal/notify"
+	"vcv/internal/openapi"
+	"vcv/internal/probe"
 	"vcv/internal/vault"
 	"vcv/internal/version"
 	"vcv/web"
@@ -33,6 +39,9 @@ const serverMaxHeaderBytes int = 1 << 20
 const routerMaxBodyBytes int64 = 1 << 20
 const routerRateLimitMaxRequests int = 300
 const notifyCheckInterval time.Duration = 15 * time.Minute
+const serverWriteTimeout time.Duration = 15 * time.Second
+const eventsRefreshInterval time.Duration = 30 * time.Second
+const eventsHistorySize int = 1000
 const routerRateLimitWindow time.Duration = 1 * time.Minute
 
 // publicVaultStatusError maps internal vault connection errors to stable,
@@ -47,22 +56,24 @@ func publicVaultStatusError(err error) string {
 	return "vault unavailable"
 }
 
+type vaultStatusEntry struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Connected   bool   `json:"connected"`
+	Error       string `json:"error,omitempty"`
+}
+
+type statusResponse struct {
+	Version         string             `json:"version"`
+	VaultConnected  bool               `json:"vault_connected"`
+	VaultError      string             `json:"vault_error,omitempty"`
+	AdminAPIEnabled bool               `json:"admin_api_enabled"`
+	Vaults          []vaultStatusEntry `json:"vaults"`
+}
+
 func newStatusHandler(cfg config.Config, primaryVaultClient vault.Client, statusClients map[string]vault.Client, adminAPIEnabled bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
-		type vaultStatusEntry struct {
-			ID          string `json:"id"`
-			DisplayName string `json:"display_name"`
-			Connected   bool   `json:"connected"`
-			Error       string `json:"error,omitempty"`
-		}
-		type statusResponse struct {
-			Version         string             `json:"version"`
-			VaultConnected  bool               `json:"vault_connected"`
-			VaultError      string             `json:"vault_error,omitempty"`
-			AdminAPIEnabled bool               `json:"admin_api_enabled"`
-			Vaults          []vaultStatusEntry `json:"vaults"`
-		}
 		response := statusResponse{Version: version.Version, AdminAPIEnabled: adminAPIEnabled, Vaults: make([]vaultStatusEntry, 0, len(cfg.Vaults))}
 		// Primary connection (historical field) checked in parallel with per-vault checks via helper.
 		primaryClients := map[string]vault.Client{"primary": primaryVaultClient}
@@ -103,9 +114,32 @@ func newStatusHandler(cfg config.Config, primaryVaultClient vault.Client, status
 	}
 }
 
-func buildRouter(cfg config.Config, primaryVaultClient vault.Client, statusClients map[string]vault.Client, multiVaultClient vault.Client, registry *prometheus.Registry, webFS fs.FS, settingsPath string, vaultRegistry *vault.Registry) (*chi.Mux, error) {
+// routerDeps is what buildRouter wires into the handlers. The optional
+// features (acknowledgements, events, probes, discovery, history and the
+// event journal) are nil when disabled.
+type routerDeps struct {
+	cfg              config.Config
+	primaryClient    vault.Client
+	statusClients    map[string]vault.Client
+	vaultClient      vault.Client
+	registry         *prometheus.Registry
+	webFS            fs.FS
+	settingsPath     string
+	vaultRegistry    *vault.Registry
+	acknowledgements *ack.Store
+	classifier       *certs.Classifier
+	expiryThresholds *certs.ExpiryThresholds
+	eventBroker      *events.Broker
+	prober           *probe.Prober
+	scanner          *probe.Scanner
+	recorder         *history.Recorder
+	journal          *events.Journal
+}
+
+func buildRouter(deps routerDeps) (*chi.Mux, error) {
+	cfg := deps.cfg
 	r := chi.NewRouter()
-	distFS, distError := fs.Sub(webFS, "dist")
+	distFS, distError := fs.Sub(deps.webFS, "dist")
 	if distError != nil {
 		return nil, distError
 	}
@@ -128,6 +162,10 @@ func buildRouter(cfg config.Config, primaryVaultClient vault.Client, statusClien
 	r.Use(middleware.RateLimit(rateLimitConfig))
 	r.Use(middleware.BodyLimit(routerMaxBodyBytes))
 	r.Use(middleware.CSRFProtectionWithTrust(cfg.TrustProxy))
+	// promhttp negotiates its own compression for /metrics.
+	compressConfig := middleware.DefaultCompressConfig()
+	compressConfig.ExemptPaths = []string{"/metrics"}
+	r.Use(middleware.Compress(compressConfig))
 
 	handlers.RegisterStaticRoutes(r, distFS)
 
@@ -136,20 +174,63 @@ func buildRouter(cfg config.Config, primaryVaultClient vault.Client, statusClien
 	r.Get("/api/health", handlers.HealthCheck)
 	r.Get("/api/ready", handlers.ReadinessCheck)
 	// Admin is optional; process stays up. Surface enablement on /api/status (not fail-ready).
-	adminAPIEnabled := handlers.RegisterAdminRoutes(r, settingsPath, cfg.Env, vaultRegistry, statusClients, multiVaultClient, cfg.TrustProxy)
-	r.Get("/api/status", newStatusHandler(cfg, primaryVaultClient, statusClients, adminAPIEnabled))
-	r.Get("/api/version", func(w http.ResponseWriter, req *http.Request) {
+	adminAPIEnabled := handlers.RegisterAdminRoutes(r, deps.settingsPath, cfg.Env, deps.vaultRegistry, deps.statusClients, deps.vaultClient, deps.acknowledgements, cfg.TrustProxy)
+	handlers.HandleAPI(r, http.MethodGet, "/status", newStatusHandler(cfg, deps.primaryClient, deps.statusClients, adminAPIEnabled))
+	handlers.HandleAPI(r, http.MethodGet, "/version", func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(version.Info())
 	})
-	r.Get("/api/config", handlers.GetConfig(cfg, vaultRegistry))
-	r.Get("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
+	handlers.HandleAPI(r, http.MethodGet, "/config", handlers.GetConfig(cfg, deps.vaultRegistry, deps.classifier, deps.expiryThresholds))
+	r.Get("/metrics", promhttp.HandlerFor(deps.registry, promhttp.HandlerOpts{}).ServeHTTP)
 	handlers.RegisterI18nRoutes(r)
-	handlers.RegisterCertRoutes(r, multiVaultClient)
+	handlers.RegisterCertRoutes(r, deps.vaultClient, deps.expiryThresholds)
+	handlers.RegisterFindingsRoutes(r, deps.vaultClient)
+	handlers.RegisterLookupRoutes(r, deps.vaultClient, deps.expiryThresholds)
+	handlers.RegisterInspectRoutes(r, deps.vaultClient)
+	handlers.RegisterEventRoutes(r, deps.eventBroker, serverWriteTimeout)
+	handlers.RegisterEventHistoryRoutes(r, deps.journal)
+	// A nil *Prober or *Scanner in the interface would read as enabled.
+	var probeResults handlers.ProbeResults
+	if deps.prober != nil {
+		probeResults = deps.prober
+	}
+	handlers.RegisterProbeRoutes(r, probeResults)
+	var discoveryResults handlers.DiscoveryResults
+	if deps.scanner != nil {
+		discoveryResults = deps.scanner
+	}
+	handlers.RegisterDiscoveryRoutes(r, discoveryResults)
+	handlers.RegisterHistoryRoutes(r, deps.recorder)
+	r.Get("/api/openapi.json", openapi.Handler(buildOpenAPIDocument()))
 
 	return r, nil
 }
 
+// buildOpenAPIDocument adds the routes defined in this file to the handlers'
+// document.
+func buildOpenAPIDocument() *openapi.Document {
+	doc := handlers.OpenAPIDocument(version.Version)
+	handlers.AddAPIOperation(doc, http.MethodGet, "/status", openapi.Operation{
+		Summary:   "Vault connectivity and admin API state",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "Status", Content: doc.JSON(statusResponse{})}},
+	})
+	handlers.AddAPIOperation(doc, http.MethodGet, "/version", openapi.Operation{
+		Summary:   "Server version",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "Version", Content: doc.JSON(version.Info())}},
+	})
+	doc.Add(http.MethodGet, "/metrics", openapi.Operation{
+		Summary: "Prometheus metrics",
+		Tags:    []string{"system"},
+		Responses: map[string]openapi.Response{"200": {
+			Description: "Prometheus text exposition format",
+			Content:     map[string]openapi.MediaType{"text/plain": {Schema: openapi.String("")}},
+		}},
+	})
+	return doc
+}
+
 func main() {
 	cfg, cfgErr := config.Load()
 	if cfgErr != nil {
@@ -196,8 +277,45 @@ func main() {
 		primaryVaultClient = vault.NewDisabledClient()
 	}
 
+	classifier, classifierErr := certs.NewClassifier(cfg.ClassificationRules)
+	if classifierErr != nil {
+		log.Fatal().Err(classifierErr).
+			Msg("Invalid certificate classification rules")
+	}
+	expiryThresholds, thresholdsErr := certs.NewExpiryThresholds(cfg.ExpirationThresholds, cfg.AllVaults)
+	if thresholdsErr != nil {
+		log.Fatal().Err(thresholdsErr).
+			Msg("Invalid certificate expiration thresholds")
+	}
+	mountPolicies, policiesErr := certs.NewMountPolicies(cfg.MountPolicies)
+	if policiesErr != nil {
+		log.Fatal().Err(policiesErr).
+			Msg("Invalid certificate mount policies")
+	}
+
+	acknowledgements, ackErr := ack.NewStore(ack.PathForSettings(cfg.SettingsPath))
+	if ackErr != nil {
+		log.Fatal().Err(ackErr).
+			Msg("Invalid certificate acknowledgements")
+	}
+
 	vaultRegistry := vault.NewRegistry(cfg.AllVaults)
-	multiVaultClient := vault.NewMultiClient(cfg.AllVaults, allClients, vaultRegistry)
+	multiVaultClient := vault.NewMultiClient(cfg.AllVaults, allClients, vaultRegistry, vault.WithClassifier(classifier), vault.WithMountPolicies(mountPolicies), vault.WithAcknowledgements(acknowledgements))
+	prober, proberErr := probe.New(cfg.Probes, multiVaultClient)
+	if proberErr != nil {
+		log.Fatal().Err(proberErr).
+			Msg("Invalid TLS probe settings")
+	}
+	scanner, scannerErr := probe.NewScanner(cfg.Discovery, multiVaultClient)
+	if scannerErr != nil {
+		log.Fatal().Err(scannerErr).
+			Msg("Invalid discovery settings")
+	}
+	recorder, recorderErr := history.New(cfg.History, history.DirForSettings(cfg.History, cfg.SettingsPath), multiVaultClient, expiryThresholds)
+	if recorderErr != nil {
+		log.Fatal().Err(recorderErr).
+			Msg("Invalid history settings")
+	}
 
 	log.Info().
 		Str("vault_addr", cfg.Vault.Addr).
@@ -209,6 +327,12 @@ func main() {
 	promRegistry := prometheus.NewRegistry()
 	promRegistry.MustRegister(collectors.NewGoCollector())
 	promRegistry.MustRegister(metrics.NewCertificateCollectorWithVaults(multiVaultClient, allClients, cfg.ExpirationThresholds, cfg.Metrics, cfg.AllVaults))
+	if prober != nil {
+		promRegistry.MustRegister(metrics.NewProbeCollector(prober))
+	}
+	if scanner != nil {
+		promRegistry.MustRegister(metrics.NewDiscoveryCollector(scanner))
+	}
 
 	webFS, fsError := fs.Sub(web.EmbeddedFS, ".")
 	if fsError != nil {
@@ -222,7 +346,30 @@ func main() {
 		Str("settings_path", settingsPath).
 		Msg("Using admin settings file")
 
-	router, buildErr := buildRouter(cfg, primaryVaultClient, allClients, multiVaultClient, promRegistry, webFS, settingsPath, vaultRegistry)
+	journal, journalErr := events.NewJournal(cfg.Journal, events.JournalDirForSettings(cfg.Journal, cfg.SettingsPath))
+	if journalErr != nil {
+		log.Fatal().Err(journalErr).
+			Msg("Invalid event journal settings")
+	}
+	eventBroker := events.NewBroker(eventsHistorySize, events.WithJournal(journal))
+	router, buildErr := buildRouter(routerDeps{
+		cfg:              cfg,
+		primaryClient:    primaryVaultClient,
+		statusClients:    allClients,
+		vaultClient:      multiVaultClient,
+		registry:         promRegistry,
+		webFS:            webFS,
+		settingsPath:     settingsPath,
+		vaultRegistry:    vaultRegistry,
+		acknowledgements: acknowledgements,
+		classifier:       classifier,
+		expiryThresholds: expiryThresholds,
+		eventBroker:      eventBroker,
+		prober:           prober,
+		scanner:          scanner,
+		recorder:         recorder,
+		journal:          journal,
+	})
 	if buildErr != nil {
 		log.Fatal().Err(buildErr).
 			Msg("Failed to initialize router")
@@ -233,11 +380,14 @@ func main() {
 		Handler:           router,
 		ReadTimeout:       15 * time.Second,
 		ReadHeaderTimeout: serverReadHeaderTimeout,
-		WriteTimeout:      15 * time.Second,
+		WriteTimeout:      serverWriteTimeout,
 		IdleTimeout:       60 * time.Second,
 		MaxHeaderBytes:    serverMaxHeaderBytes,
 	}
 
+	// Open event streams would otherwise hold up Shutdown until its timeout.
+	srv.RegisterOnShutdown(eventBroker.Close)
+
 	go func() {
 		log.Info().Str("port", cfg.Port).Msg("Server starting")
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -246,29 +396,37 @@ func main() {
 	}()
 
 	notifier := notify.New(multiVaultClient, config.Load)
-	notifyCtx, notifyCancel := context.WithCancel(context.Background())
-	go func() {
-		// Check once shortly after startup so an already-crossed threshold
-		// notifies promptly, then on a fixed interval.
-		notifier.Check(notifyCtx)
-		ticker := time.NewTicker(notifyCheckInterval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				notifier.Check(notifyCtx)
-			case <-notifyCtx.Done():
-				return
-			}
-		}
-	}()
+	backgroundCtx, backgroundCancel := context.WithCancel(context.Background())
+	// Check once shortly after startup so an already-crossed threshold
+	// notifies promptly, then on a fixed interval.
+	go runEvery(backgroundCtx, notifyCheckInterval, notifier.Check)
+
+	watcher := events.NewWatcher(multiVaultClient, func(ctx context.Context) []vault.InstanceStatus {
+		return vault.CheckInstances(ctx, vaultRegistry.EnabledIDs(), allClients, 5*time.Second)
+	}, expiryThresholds, eventBroker)
+	// With the journal, the first refresh diffs against the baseline saved
+	// before the restart; without it, it only records the baseline.
+	go runEvery(backgroundCtx, eventsRefreshInterval, watcher.Refresh)
+
+	if prober != nil {
+		go runEvery(backgroundCtx, prober.Interval(), func(ctx context.Context) {
+			prober.Run(ctx)
+			notifier.CheckProbes(ctx, prober.Results())
+		})
+	}
+	if scanner != nil {
+		go runEvery(backgroundCtx, scanner.Interval(), scanner.Run)
+	}
+	if recorder != nil {
+		go runEvery(backgroundCtx, recorder.Interval(), recorder.Record)
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info().Msg("Shutting down server...")
-	notifyCancel()
+	backgroundCancel()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -289,3 +447,18 @@ func main() {
 
 	log.Info().Msg("Server stopped")
 }
+
+// runEvery calls fn once, then every interval until ctx is done.
+func runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	fn(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fn(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/fs"
@@ -8,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -126,7 +128,7 @@ func TestBuildRouter_Dev(t *testing.T) {
 	registry := prometheus.NewRegistry()
 	vaultRegistry := vault.NewRegistry(cfg.Vaults)
 
-	router, err := buildRouter(cfg, primary, map[string]vault.Client{"v1": primary}, multi, registry, webFS, "/tmp/settings.json", vaultRegistry)
+	router, err := buildRouter(routerDeps{cfg: cfg, primaryClient: primary, statusClients: map[string]vault.Client{"v1": primary}, vaultClient: multi, registry: registry, webFS: webFS, settingsPath: "/tmp/settings.json", vaultRegistry: vaultRegistry})
 	require.NoError(t, err)
 	assert.NotNil(t, router)
 
@@ -152,7 +154,7 @@ func TestBuildRouter_Prod(t *testing.T) {
 	registry := prometheus.NewRegistry()
 	vaultRegistry := vault.NewRegistry(cfg.Vaults)
 
-	router, err := buildRouter(cfg, primary, map[string]vault.Client{}, multi, registry, webFS, "/tmp/settings.json", vaultRegistry)
+	router, err := buildRouter(routerDeps{cfg: cfg, primaryClient: primary, statusClients: map[string]vault.Client{}, vaultClient: multi, registry: registry, webFS: webFS, settingsPath: "/tmp/settings.json", vaultRegistry: vaultRegistry})
 	require.NoError(t, err)
 	assert.NotNil(t, router)
 }
@@ -169,7 +171,7 @@ func TestBuildRouter_MissingDist(t *testing.T) {
 	registry := prometheus.NewRegistry()
 	vaultRegistry := vault.NewRegistry(nil)
 
-	_, err := buildRouter(cfg, primary, nil, multi, registry, errFS{}, "/tmp/settings.json", vaultRegistry)
+	_, err := buildRouter(routerDeps{cfg: cfg, primaryClient: primary, vaultClient: multi, registry: registry, webFS: errFS{}, settingsPath: "/tmp/settings.json", vaultRegistry: vaultRegistry})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no dist dir")
 }
@@ -189,7 +191,7 @@ func TestBuildRouter_Routes(t *testing.T) {
 	registry := prometheus.NewRegistry()
 	vaultRegistry := vault.NewRegistry(cfg.Vaults)
 
-	router, err := buildRouter(cfg, primary, map[string]vault.Client{}, multi, registry, webFS, "/tmp/settings.json", vaultRegistry)
+	router, err := buildRouter(routerDeps{cfg: cfg, primaryClient: primary, statusClients: map[string]vault.Client{}, vaultClient: multi, registry: registry, webFS: webFS, settingsPath: "/tmp/settings.json", vaultRegistry: vaultRegistry})
 	require.NoError(t, err)
 
 	// Test /api/version
@@ -225,3 +227,24 @@ func TestBuildRouter_Routes(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestRunEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		runEvery(ctx, time.Millisecond, func(context.Context) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runEvery did not return after the context was cancelled")
+	}
+	assert.GreaterOrEqual(t, calls, 3)
+}
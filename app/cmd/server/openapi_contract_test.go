@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"vcv/internal/ack"
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/events"
+	"vcv/internal/openapi"
+	"vcv/internal/vault"
+)
+
+// contractRouter builds the full router with the admin API enabled, so every
+// route the server can expose is registered.
+func contractRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("contract-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	settingsPath := filepath.Join(dir, "settings.json")
+	data, err := json.Marshal(config.SettingsFile{
+		App:    config.AppSettings{Env: "dev"},
+		Admin:  config.AdminSettings{Password: string(hash)},
+		Vaults: []config.VaultInstance{{ID: "v1", Address: "http://vault:8200", Token: "token", PKIMounts: []string{"pki"}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(settingsPath, data, 0o600))
+	acknowledgements, err := ack.NewStore(ack.PathForSettings(settingsPath))
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	certificate := certs.Certificate{
+		ID: "v1|pki:aa", CommonName: "api.example.com", Sans: []string{"api.example.com", "10.0.0.1"},
+		SerialNumber: "aa", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(90 * 24 * time.Hour),
+		IssuerCN: "Issuing CA", KeyAlgorithm: "ECDSA", KeySize: 256, PublicKeyFingerprint: "fp", CertType: "machine",
+	}
+	twin := certificate
+	twin.ID, twin.SerialNumber = "v1|pki:bb", "bb"
+	client := &vault.MockClient{}
+	client.On("CheckConnection", mock.Anything).Return(nil)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{certificate, twin}, nil)
+	client.On("GetCertificateDetails", mock.Anything, "v1|pki:aa").Return(certs.DetailedCertificate{Certificate: certificate, Usage: []string{"serverAuth"}}, nil)
+	client.On("GetCertificatePEM", mock.Anything, "v1|pki:aa").Return(certs.PEMResponse{SerialNumber: "aa", PEM: "pem"}, nil)
+	client.On("GetCertificateDetails", mock.Anything, "v1|pki:missing").Return(certs.DetailedCertificate{}, &vault.Error{VaultID: "v1", Err: vault.ErrCertificateNotFound})
+	client.On("GetCertificatePEM", mock.Anything, "v1|pki:down").Return(certs.PEMResponse{}, &vault.Error{VaultID: "v1", Err: vault.ErrVaultUnavailable})
+	client.On("GetIntermediateCA", mock.Anything, "v1|pki").Return(certs.DetailedCertificate{Certificate: certificate, CAType: "intermediate"}, nil)
+
+	cfg := config.Config{Env: config.EnvDev, Vaults: []config.VaultInstance{{ID: "v1", DisplayName: "Vault 1", PKIMounts: []string{"pki"}}}}
+	webFS := fstest.MapFS{"dist/index.html": &fstest.MapFile{Data: []byte("<html></html>")}}
+	expiryThresholds, err := certs.NewExpiryThresholds(cfg.ExpirationThresholds, cfg.AllVaults)
+	require.NoError(t, err)
+	router, err := buildRouter(routerDeps{
+		cfg:              cfg,
+		primaryClient:    client,
+		statusClients:    map[string]vault.Client{"v1": client},
+		vaultClient:      client,
+		registry:         prometheus.NewRegistry(),
+		webFS:            webFS,
+		settingsPath:     settingsPath,
+		vaultRegistry:    vault.NewRegistry(cfg.Vaults),
+		acknowledgements: acknowledgements,
+		expiryThresholds: expiryThresholds,
+		eventBroker:      events.NewBroker(10),
+	})
+	require.NoError(t, err)
+	return router
+}
+
+func TestOpenAPI_ServedDocument(t *testing.T) {
+	router := contractRouter(t)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var served map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, openapi.Version, served["openapi"])
+	assert.Len(t, served["paths"], len(buildOpenAPIDocument().Paths))
+}
+
+// TestOpenAPI_RoutesMatchDocument fails when a route is added without being
+// documented, or documented without being registered.
+func TestOpenAPI_RoutesMatchDocument(t *testing.T) {
+	router := contractRouter(t)
+	registered := make([]string, 0)
+	require.NoError(t, chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/") || route == "/metrics" {
+			registered = append(registered, method+" "+route)
+		}
+		return nil
+	}))
+	sort.Strings(registered)
+	assert.Equal(t, buildOpenAPIDocument().Operations(), registered)
+}
+
+// TestOpenAPI_ResponsesMatchSchemas fails when a handler encodes a field the
+// document does not declare, omits a required one, or changes its type.
+func TestOpenAPI_ResponsesMatchSchemas(t *testing.T) {
+	router := contractRouter(t)
+	doc := buildOpenAPIDocument()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/admin/login", strings.NewReader(`{"username":"admin","password":"contract-password"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.NotEmpty(t, cookies)
+
+	tests := []struct {
+		method string
+		target string
+		route  string
+		body   string
+		status int
+	}{
+		{method: http.MethodGet, target: "/api/v1/certs?page_size=1", route: "/api/v1/certs", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/certs/v1%7Cpki:aa/details", route: "/api/v1/certs/{id}/details", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/certs/v1%7Cpki:aa/pem", route: "/api/v1/certs/{id}/pem", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/certs/v1%7Cpki:aa/ca", route: "/api/v1/certs/{id}/ca", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/certs/v1%7Cpki:missing/details", route: "/api/v1/certs/{id}/details", status: http.StatusNotFound},
+		{method: http.MethodGet, target: "/api/v1/certs/v1%7Cpki:down/pem", route: "/api/v1/certs/{id}/pem", status: http.StatusServiceUnavailable},
+		{method: http.MethodGet, target: "/api/v1/certs?page=0", route: "/api/v1/certs", status: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/certs/v1%7Cpki:missing/details", route: "/api/certs/{id}/details", status: http.StatusNotFound},
+		{method: http.MethodGet, target: "/api/v1/findings/key-reuse", route: "/api/v1/findings/key-reuse", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/lookup?host=api.example.com", route: "/api/v1/lookup", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/config", route: "/api/v1/config", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/i18n?lang=fr", route: "/api/v1/i18n", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/status", route: "/api/v1/status", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/version", route: "/api/v1/version", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/session", route: "/api/admin/session", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/docs", route: "/api/admin/docs", status: http.StatusOK},
+		{method: http.MethodGet, target: "/api/admin/settings", route: "/api/admin/settings", status: http.StatusOK},
+		{method: http.MethodPost, target: "/api/admin/vault", route: "/api/admin/vault", status: http.StatusOK},
+		{method: http.MethodPost, target: "/api/admin/acknowledgements", route: "/api/admin/acknowledgements", body: `{"pattern":"*.example.com","reason":"contract"}`, status: http.StatusCreated},
+		{method: http.MethodPost, target: "/api/admin/acknowledgements", route: "/api/admin/acknowledgements", body: `{}`, status: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/admin/acknowledgements", route: "/api/admin/acknowledgements", status: http.StatusOK},
+		{method: http.MethodDelete, target: "/api/admin/vault/missing", route: "/api/admin/vault/{id}", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Origin", "http://example.com")
+			for _, cookie := range cookies {
+				req.AddCookie(cookie)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+
+			operation := doc.Paths[tt.route][strings.ToLower(tt.method)]
+			require.NotNil(t, operation, "undocumented operation")
+			response, documented := operation.Responses[strconv.Itoa(tt.status)]
+			require.True(t, documented, "undocumented status %d", tt.status)
+			media, ok := response.Content["application/json"]
+			require.True(t, ok, "response is not documented as JSON")
+			var body any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.NoError(t, doc.Validate(media.Schema, body))
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Package ack keeps the acknowledgement list: certificates that are expected
+// to expire (decommissioned hosts, retired services) and must not drive
+// expiry alerts. Entries live in acknowledgements.json next to settings.json.
+package ack
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"vcv/internal/certs"
+	vcverrors "vcv/internal/errors"
+)
+
+// FileName is the name of the acknowledgement file in the settings directory.
+const FileName = "acknowledgements.json"
+
+// Entry acknowledges one certificate by ID, or every certificate whose common
+// name or a SAN matches Pattern (a case-insensitive glob such as
+// "*.old.example.com"). A nil ExpiresAt never lapses.
+type Entry struct {
+	ID            string     `json:"id"`
+	CertificateID string     `json:"certificate_id,omitempty"`
+	Pattern       string     `json:"pattern,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Active reports whether the entry has not lapsed at now.
+func (entry Entry) Active(now time.Time) bool {
+	return entry.ExpiresAt == nil || entry.ExpiresAt.After(now)
+}
+
+// Matches reports whether the entry covers the certificate, ignoring expiry.
+func (entry Entry) Matches(certificate certs.Certificate) bool {
+	if entry.CertificateID != "" {
+		return entry.CertificateID == certificate.ID
+	}
+	pattern := strings.ToLower(entry.Pattern)
+	if matchesName(pattern, certificate.CommonName) {
+		return true
+	}
+	for _, san := range certificate.Sans {
+		if matchesName(pattern, san) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesName(pattern, name string) bool {
+	if name == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, strings.ToLower(name))
+	return err == nil && matched
+}
+
+type fileContent struct {
+	Acknowledgements []Entry `json:"acknowledgements"`
+}
+
+// Store is the persisted acknowledgement list. A nil *Store acknowledges
+// nothing.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	entries []Entry
+	now     func() time.Time
+}
+
+// PathForSettings returns the acknowledgement file path for a settings file.
+func PathForSettings(settingsPath string) string {
+	return filepath.Join(filepath.Dir(settingsPath), FileName)
+}
+
+// NewStore loads the store from path. A missing file is an empty list.
+func NewStore(path string) (*Store, error) {
+	store := &Store{path: path, now: time.Now}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	var content fileContent
+	if jsonErr := json.Unmarshal(data, &content); jsonErr != nil {
+		return nil, fmt.Errorf("%s: %w", path, jsonErr)
+	}
+	for _, entry := range content.Acknowledgements {
+		if validateErr := validate(entry); validateErr != nil {
+			return nil, fmt.Errorf("%s: acknowledgement %q: %w", path, entry.ID, validateErr)
+		}
+	}
+	store.entries = content.Acknowledgements
+	return store, nil
+}
+
+// List returns every entry, lapsed ones included, oldest first.
+func (s *Store) List() []Entry {
+	if s == nil {
+		return []Entry{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]Entry, len(s.entries))
+	copy(entries, s.entries)
+	return entries
+}
+
+// Add validates the entry, assigns its ID and creation time and persists it.
+// Validation failures wrap vcverrors.ErrInvalidAcknowledgement.
+func (s *Store) Add(entry Entry) (Entry, error) {
+	entry.CertificateID = strings.TrimSpace(entry.CertificateID)
+	entry.Pattern = strings.TrimSpace(entry.Pattern)
+	entry.Reason = strings.TrimSpace(entry.Reason)
+	if err := validate(entry); err != nil {
+		return Entry{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Entry{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if entry.ExpiresAt != nil && !entry.ExpiresAt.After(now) {
+		return Entry{}, fmt.Errorf("%w: expires_at must be in the future", vcverrors.ErrInvalidAcknowledgement)
+	}
+	entry.ID = id
+	entry.CreatedAt = now.UTC()
+	entries := append(append([]Entry{}, s.entries...), entry)
+	if saveErr := s.save(entries); saveErr != nil {
+		return Entry{}, saveErr
+	}
+	s.entries = entries
+	return entry, nil
+}
+
+// Remove deletes the entry with the given ID. It reports false when no such
+// entry exists.
+func (s *Store) Remove(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.ID != id {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == len(s.entries) {
+		return false, nil
+	}
+	if err := s.save(entries); err != nil {
+		return false, err
+	}
+	s.entries = entries
+	return true, nil
+}
+
+// Apply sets Acknowledgement on every certificate covered by an active entry;
+// an ID entry wins over a pattern entry. The input is not modified.
+func (s *Store) Apply(certificates []certs.Certificate) []certs.Certificate {
+	if s == nil {
+		return certificates
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.entries) == 0 {
+		return certificates
+	}
+	now := s.now()
+	result := make([]certs.Certificate, len(certificates))
+	for i, certificate := range certificates {
+		certificate.Acknowledgement = s.match(certificate, now)
+		result[i] = certificate
+	}
+	return result
+}
+
+// Acknowledge returns the certificate with Acknowledgement set when an active
+// entry covers it.
+func (s *Store) Acknowledge(certificate certs.Certificate) certs.Certificate {
+	if s == nil {
+		return certificate
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	certificate.Acknowledgement = s.match(certificate, s.now())
+	return certificate
+}
+
+func (s *Store) match(certificate certs.Certificate, now time.Time) *certs.Acknowledgement {
+	var matched *Entry
+	for i := range s.entries {
+		entry := &s.entries[i]
+		if !entry.Active(now) || !entry.Matches(certificate) {
+			continue
+		}
+		if matched == nil || (entry.CertificateID != "" && matched.CertificateID == "") {
+			matched = entry
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+	return &certs.Acknowledgement{ID: matched.ID, Reason: matched.Reason, ExpiresAt: matched.ExpiresAt}
+}
+
+func (s *Store) save(entries []Entry) error {
+	payload, err := json.MarshalIndent(fileContent{Acknowledgements: entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(s.path)
+	if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
+		return mkErr
+	}
+	tmp, err := os.CreateTemp(dir, "acknowledgements-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, writeErr := tmp.Write(payload); writeErr != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return writeErr
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		_ = os.Remove(tmpPath)
+		return closeErr
+	}
+	if renameErr := os.Rename(tmpPath, s.path); renameErr != nil {
+		_ = os.Remove(tmpPath)
+		return renameErr
+	}
+	return nil
+}
+
+func validate(entry Entry) error {
+	hasID := strings.TrimSpace(entry.CertificateID) != ""
+	hasPattern := strings.TrimSpace(entry.Pattern) != ""
+	if hasID == hasPattern {
+		return fmt.Errorf("%w: set exactly one of certificate_id and pattern", vcverrors.ErrInvalidAcknowledgement)
+	}
+	if hasPattern {
+		if _, err := path.Match(strings.ToLower(entry.Pattern), ""); err != nil {
+			return fmt.Errorf("%w: pattern %q: %v", vcverrors.ErrInvalidAcknowledgement, entry.Pattern, err)
+		}
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,118 @@
+package ack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	vcverrors "vcv/internal/errors"
+)
+
+func newTestStore(t *testing.T, now time.Time) *Store {
+	t.Helper()
+	store, err := NewStore(filepath.Join(t.TempDir(), FileName))
+	require.NoError(t, err)
+	store.now = func() time.Time { return now }
+	return store
+}
+
+func TestNewStore_MissingFileIsEmpty(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), FileName))
+	require.NoError(t, err)
+	assert.Empty(t, store.List())
+}
+
+func TestNewStore_RejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"acknowledgements":[{"id":"a"}]}`), 0o600))
+	_, err := NewStore(path)
+	assert.ErrorIs(t, err, vcverrors.ErrInvalidAcknowledgement)
+}
+
+func TestStore_AddPersistsAndRemove(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newTestStore(t, now)
+
+	added, err := store.Add(Entry{CertificateID: " v1|pki:aa ", Reason: "decommissioned"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, added.ID)
+	assert.Equal(t, "v1|pki:aa", added.CertificateID)
+	assert.Equal(t, now, added.CreatedAt)
+
+	reloaded, err := NewStore(store.path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{added}, reloaded.List())
+
+	removed, err := store.Remove(added.ID)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = store.Remove(added.ID)
+	require.NoError(t, err)
+	assert.False(t, removed)
+	assert.Empty(t, store.List())
+}
+
+func TestStore_AddValidation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	store := newTestStore(t, now)
+
+	tests := []struct {
+		name  string
+		entry Entry
+	}{
+		{name: "neither", entry: Entry{Reason: "x"}},
+		{name: "both", entry: Entry{CertificateID: "v1|pki:aa", Pattern: "*.example.com"}},
+		{name: "bad pattern", entry: Entry{Pattern: "[web"}},
+		{name: "expired", entry: Entry{Pattern: "*.example.com", ExpiresAt: &past}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Add(tt.entry)
+			assert.ErrorIs(t, err, vcverrors.ErrInvalidAcknowledgement)
+		})
+	}
+	assert.Empty(t, store.List())
+}
+
+func TestStore_Apply(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := now.Add(24 * time.Hour)
+	store := newTestStore(t, now)
+	pattern, err := store.Add(Entry{Pattern: "*.OLD.example.com", Reason: "retired"})
+	require.NoError(t, err)
+	byID, err := store.Add(Entry{CertificateID: "v1|pki:bb", Reason: "pinned", ExpiresAt: &later})
+	require.NoError(t, err)
+
+	certificates := []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "api", Sans: []string{"api.old.example.com"}},
+		{ID: "v1|pki:bb", CommonName: "web.old.example.com"},
+		{ID: "v1|pki:cc", CommonName: "web.example.com"},
+	}
+	applied := store.Apply(certificates)
+	require.Len(t, applied, 3)
+	require.NotNil(t, applied[0].Acknowledgement)
+	assert.Equal(t, pattern.ID, applied[0].Acknowledgement.ID)
+	require.NotNil(t, applied[1].Acknowledgement)
+	assert.Equal(t, byID.ID, applied[1].Acknowledgement.ID, "certificate ID entry wins over pattern")
+	assert.Nil(t, applied[2].Acknowledgement)
+	assert.Nil(t, certificates[0].Acknowledgement, "input is not modified")
+
+	store.now = func() time.Time { return later }
+	applied = store.Apply(certificates)
+	require.NotNil(t, applied[1].Acknowledgement)
+	assert.Equal(t, pattern.ID, applied[1].Acknowledgement.ID, "lapsed entries are ignored")
+}
+
+func TestNilStore(t *testing.T) {
+	var store *Store
+	certificates := []certs.Certificate{{ID: "v1|pki:aa"}}
+	assert.Equal(t, certificates, store.Apply(certificates))
+	assert.Nil(t, store.Acknowledge(certificates[0]).Acknowledgement)
+	assert.Empty(t, store.List())
+}
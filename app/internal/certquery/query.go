@@ -0,0 +1,482 @@
+// Package certquery implements the certificate search language shared by
+// /api/certs?query= and notification rules.
+//
+// A query is a list of terms combined with AND (also implied by juxtaposition),
+// OR and NOT, grouped with parentheses. AND binds tighter than OR. Terms are:
+//
+//	cn:web.example.com      common name; substring, or glob when it contains *
+//	san:*.prod.example.com  any SAN; substring, or glob when it contains *
+//	issuer:"Intermediate-2" issuer common name; substring or glob
+//	serial:1a:2b            serial number; substring or glob
+//	status:critical         valid, warning, critical, expired or revoked
+//	type:machine            certificate type
+//	key:RSA-2048            key algorithm, optionally with its size
+//	vault:prod mount:pki    vault ID and mount, exact
+//	expires<30d             remaining lifetime (<, <=, >, >=) as a duration,
+//	                        excluding expired certificates for < and <=,
+//	expires>2025-06-01      or the expiry compared with a date
+//	created>2025-01-01      issue date compared with a date, or
+//	created<7d              age compared with a duration
+//	example                 free text: common name, serial or SAN substring
+//
+// Values may be double-quoted to include spaces; keywords and field names are
+// case-insensitive, and so is every comparison.
+package certquery
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vcv/internal/certs"
+)
+
+// Env carries what a query needs besides the certificate: the clock and the
+// thresholds deciding warning and critical statuses.
+type Env struct {
+	Now        time.Time
+	Thresholds *certs.ExpiryThresholds
+}
+
+// Query is a parsed query. The zero value and a nil *Query match everything.
+type Query struct {
+	source string
+	root   node
+}
+
+// ParseError reports where and why a query failed to parse. Pos is the
+// zero-based byte offset in the input.
+type ParseError struct {
+	Pos     int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: position %d: %s", e.Pos+1, e.Message)
+}
+
+// Parse compiles input. A blank input yields a query matching everything.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, input: input}
+	if p.done() {
+		return &Query{source: input}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		tok := p.peek()
+		if tok.kind == tokenClose {
+			return nil, &ParseError{Pos: tok.pos, Message: "unexpected )"}
+		}
+		return nil, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return &Query{source: input, root: root}, nil
+}
+
+// String returns the source the query was parsed from.
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+	return q.source
+}
+
+// Match reports whether the certificate satisfies the query.
+func (q *Query) Match(certificate certs.Certificate, env Env) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	return q.root.match(certificate, env)
+}
+
+// Filter returns the certificates matching the query, in input order.
+func (q *Query) Filter(certificates []certs.Certificate, env Env) []certs.Certificate {
+	if q == nil || q.root == nil {
+		return certificates
+	}
+	filtered := make([]certs.Certificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		if q.root.match(certificate, env) {
+			filtered = append(filtered, certificate)
+		}
+	}
+	return filtered
+}
+
+type node interface {
+	match(certificate certs.Certificate, env Env) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(c certs.Certificate, env Env) bool {
+	return n.left.match(c, env) && n.right.match(c, env)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(c certs.Certificate, env Env) bool {
+	return n.left.match(c, env) || n.right.match(c, env)
+}
+
+type notNode struct{ inner node }
+
+func (n notNode) match(c certs.Certificate, env Env) bool {
+	return !n.inner.match(c, env)
+}
+
+type matchFunc func(certificate certs.Certificate, env Env) bool
+
+func (f matchFunc) match(c certs.Certificate, env Env) bool {
+	return f(c, env)
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// quoted is set when the whole token was a quoted string, which is
+	// always free text and never a keyword.
+	quoted bool
+	pos    int
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		ch := input[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i})
+			i++
+		default:
+			start := i
+			var text strings.Builder
+			quotedOnly := ch == '"'
+			for i < len(input) {
+				ch = input[i]
+				if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '(' || ch == ')' {
+					break
+				}
+				if ch != '"' {
+					text.WriteByte(ch)
+					i++
+					continue
+				}
+				end := strings.IndexByte(input[i+1:], '"')
+				if end < 0 {
+					return nil, &ParseError{Pos: i, Message: "unterminated quoted string"}
+				}
+				text.WriteString(input[i+1 : i+1+end])
+				i += end + 2
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: text.String(), quoted: quotedOnly && input[i-1] == '"', pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+// Parser.
+
+type parser struct {
+	tokens []token
+	pos    int
+	input  string
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) keyword(name string) bool {
+	if p.done() {
+		return false
+	}
+	tok := p.peek()
+	return tok.kind == tokenWord && !tok.quoted && strings.EqualFold(tok.text, name)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.pos++
+		right, rightErr := p.parseAnd()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().kind != tokenClose && !p.keyword("OR") {
+		if p.keyword("AND") {
+			p.pos++
+		}
+		right, rightErr := p.parseUnary()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.done() {
+		return nil, &ParseError{Pos: len(p.input), Message: "unexpected end of query"}
+	}
+	if p.keyword("NOT") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	tok := p.peek()
+	switch tok.kind {
+	case tokenOpen:
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenClose {
+			return nil, &ParseError{Pos: tok.pos, Message: "missing )"}
+		}
+		p.pos++
+		return inner, nil
+	case tokenClose:
+		return nil, &ParseError{Pos: tok.pos, Message: "unexpected )"}
+	}
+	if !tok.quoted && (strings.EqualFold(tok.text, "AND") || strings.EqualFold(tok.text, "OR")) {
+		return nil, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %s", strings.ToUpper(tok.text))}
+	}
+	p.pos++
+	return parseTerm(tok)
+}
+
+// Terms.
+
+func parseTerm(tok token) (node, error) {
+	if tok.quoted {
+		return freeText(tok.text), nil
+	}
+	opIndex := strings.IndexAny(tok.text, ":<>=")
+	if opIndex <= 0 {
+		return freeText(tok.text), nil
+	}
+	field := strings.ToLower(tok.text[:opIndex])
+	rest := tok.text[opIndex:]
+	op := rest[:1]
+	if len(rest) > 1 && rest[1] == '=' && (op == "<" || op == ">") {
+		op = rest[:2]
+	}
+	value := rest[len(op):]
+	valuePos := tok.pos + opIndex + len(op)
+	if value == "" {
+		return nil, &ParseError{Pos: valuePos, Message: fmt.Sprintf("missing value for %s", field)}
+	}
+	switch field {
+	case "expires", "created":
+		if op == ":" {
+			op = "="
+		}
+		return parseTimeTerm(field, op, value, valuePos)
+	}
+	if op != ":" {
+		if _, known := textFields[field]; known || isExactField(field) {
+			return nil, &ParseError{Pos: tok.pos + opIndex, Message: fmt.Sprintf("%s only supports ':'", field)}
+		}
+		return nil, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unknown field %q", field)}
+	}
+	if extract, ok := textFields[field]; ok {
+		matcher, err := newTextMatcher(value, valuePos)
+		if err != nil {
+			return nil, err
+		}
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			return slices.ContainsFunc(extract(c), matcher)
+		}), nil
+	}
+	lowered := strings.ToLower(value)
+	switch field {
+	case "status":
+		if !slices.Contains(statuses, lowered) {
+			return nil, &ParseError{Pos: valuePos, Message: fmt.Sprintf("unknown status %q (want %s)", value, strings.Join(statuses, ", "))}
+		}
+		return matchFunc(func(c certs.Certificate, env Env) bool {
+			return env.Thresholds.Status(c, env.Now) == lowered
+		}), nil
+	case "type":
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			return strings.EqualFold(c.CertType, value)
+		}), nil
+	case "key":
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			return strings.EqualFold(c.KeyAlgorithm, value) ||
+				strings.EqualFold(c.KeyAlgorithm+"-"+strconv.Itoa(c.KeySize), value)
+		}), nil
+	case "vault":
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			vaultID, _ := certs.VaultAndMount(c.ID)
+			return strings.EqualFold(vaultID, value)
+		}), nil
+	case "mount":
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			_, mount := certs.VaultAndMount(c.ID)
+			return strings.EqualFold(mount, value)
+		}), nil
+	}
+	return nil, &ParseError{Pos: tok.pos, Message: fmt.Sprintf("unknown field %q", field)}
+}
+
+var statuses = []string{certs.StatusValid, certs.StatusWarning, certs.StatusCritical, certs.StatusExpired, certs.StatusRevoked}
+
+var textFields = map[string]func(certs.Certificate) []string{
+	"cn":     func(c certs.Certificate) []string { return []string{c.CommonName} },
+	"san":    func(c certs.Certificate) []string { return c.Sans },
+	"issuer": func(c certs.Certificate) []string { return []string{c.IssuerCN} },
+	"serial": func(c certs.Certificate) []string { return []string{c.SerialNumber} },
+}
+
+func isExactField(field string) bool {
+	switch field {
+	case "status", "type", "key", "vault", "mount":
+		return true
+	}
+	return false
+}
+
+func freeText(value string) node {
+	needle := strings.ToLower(value)
+	return matchFunc(func(c certs.Certificate, _ Env) bool {
+		if strings.Contains(strings.ToLower(c.CommonName), needle) || strings.Contains(strings.ToLower(c.SerialNumber), needle) {
+			return true
+		}
+		return slices.ContainsFunc(c.Sans, func(san string) bool {
+			return strings.Contains(strings.ToLower(san), needle)
+		})
+	})
+}
+
+// newTextMatcher matches a case-insensitive substring, or the whole value as
+// a glob when the pattern contains *.
+func newTextMatcher(value string, pos int) (func(string) bool, error) {
+	if !strings.Contains(value, "*") {
+		needle := strings.ToLower(value)
+		return func(candidate string) bool {
+			return strings.Contains(strings.ToLower(candidate), needle)
+		}, nil
+	}
+	parts := strings.Split(value, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return nil, &ParseError{Pos: pos, Message: fmt.Sprintf("invalid pattern %q", value)}
+	}
+	return re.MatchString, nil
+}
+
+func parseTimeTerm(field, op, value string, pos int) (node, error) {
+	if op == "=" {
+		day, err := parseDate(value)
+		if err != nil {
+			return nil, &ParseError{Pos: pos, Message: fmt.Sprintf("%s: want a date such as 2025-01-01", field)}
+		}
+		next := day.AddDate(0, 0, 1)
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			at := timeField(field, c)
+			return !at.IsZero() && !at.Before(day) && at.Before(next)
+		}), nil
+	}
+	if day, err := parseDate(value); err == nil {
+		return matchFunc(func(c certs.Certificate, _ Env) bool {
+			at := timeField(field, c)
+			return !at.IsZero() && compare(at.Sub(day), op)
+		}), nil
+	}
+	duration, err := certs.ParseThresholdDuration(value)
+	if err != nil {
+		return nil, &ParseError{Pos: pos, Message: fmt.Sprintf("%s: want a duration such as 30d or a date such as 2025-01-01", field)}
+	}
+	return matchFunc(func(c certs.Certificate, env Env) bool {
+		at := timeField(field, c)
+		if at.IsZero() {
+			return false
+		}
+		// Remaining lifetime for expires, age for created.
+		span := at.Sub(env.Now)
+		if field == "created" {
+			span = env.Now.Sub(at)
+		} else if span < 0 && (op == "<" || op == "<=") {
+			// Like expiring_within: an expired certificate is not expiring.
+			return false
+		}
+		return compare(span-duration, op)
+	}), nil
+}
+
+func timeField(field string, c certs.Certificate) time.Time {
+	if field == "created" {
+		return c.CreatedAt
+	}
+	return c.ExpiresAt
+}
+
+func parseDate(value string) (time.Time, error) {
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// compare applies op to the sign of diff (left minus right).
+func compare(diff time.Duration, op string) bool {
+	switch op {
+	case "<":
+		return diff < 0
+	case "<=":
+		return diff <= 0
+	case ">":
+		return diff > 0
+	case ">=":
+		return diff >= 0
+	}
+	return false
+}
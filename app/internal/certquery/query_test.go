@@ -0,0 +1,110 @@
+package certquery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+)
+
+func testEnv(t *testing.T) Env {
+	t.Helper()
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	return Env{Now: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Thresholds: thresholds}
+}
+
+func testCertificates(now time.Time) []certs.Certificate {
+	return []certs.Certificate{
+		{ID: "prod|pki:01", SerialNumber: "01", CommonName: "*.prod.example.com", Sans: []string{"*.prod.example.com"}, IssuerCN: "Intermediate-2", KeyAlgorithm: "RSA", KeySize: 2048, CertType: "machine", CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: now.Add(15 * 24 * time.Hour)},
+		{ID: "prod|pki:02", SerialNumber: "02", CommonName: "api.prod.example.com", Sans: []string{"api.prod.example.com"}, IssuerCN: "Intermediate-1", KeyAlgorithm: "ECDSA", KeySize: 256, CertType: "machine", CreatedAt: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: now.Add(200 * 24 * time.Hour)},
+		{ID: "dev|pki_int:03", SerialNumber: "03", CommonName: "alice", Sans: []string{"alice@example.com"}, IssuerCN: "Intermediate-2", KeyAlgorithm: "RSA", KeySize: 4096, CertType: "user", CreatedAt: now.Add(-2 * 24 * time.Hour), ExpiresAt: now.Add(3 * 24 * time.Hour)},
+		{ID: "dev|pki:04", SerialNumber: "04", CommonName: "old.dev.example.com", IssuerCN: "Intermediate-1", KeyAlgorithm: "RSA", KeySize: 2048, CertType: "machine", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: now.Add(-24 * time.Hour)},
+		{ID: "dev|pki:05", SerialNumber: "05", CommonName: "revoked.dev.example.com", IssuerCN: "Intermediate-2", KeyAlgorithm: "RSA", KeySize: 2048, CertType: "machine", CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: now.Add(10 * 24 * time.Hour), Revoked: true},
+	}
+}
+
+func TestQuery_Filter(t *testing.T) {
+	env := testEnv(t)
+	certificates := testCertificates(env.Now)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"01", "02", "03", "04", "05"}},
+		{query: "cn:prod", want: []string{"01", "02"}},
+		{query: "san:*.prod.example.com", want: []string{"01", "02"}},
+		{query: "issuer:intermediate-2", want: []string{"01", "03", "05"}},
+		{query: "status:valid", want: []string{"02"}},
+		{query: "status:critical OR status:expired", want: []string{"03", "04"}},
+		{query: "expires<30d", want: []string{"01", "03", "05"}},
+		{query: "expires<=30d", want: []string{"01", "03", "05"}},
+		{query: "expires<30d OR status:expired", want: []string{"01", "03", "04", "05"}},
+		{query: "expires>30d", want: []string{"02"}},
+		{query: "expires>=2025-06-10", want: []string{"01", "02", "05"}},
+		{query: "created>2025-01-01", want: []string{"01", "03"}},
+		{query: "created<7d", want: []string{"03"}},
+		{query: "created:2025-03-01", want: []string{"01"}},
+		{query: "key:RSA-2048", want: []string{"01", "04", "05"}},
+		{query: "key:ecdsa", want: []string{"02"}},
+		{query: "vault:dev mount:pki", want: []string{"04", "05"}},
+		{query: "type:user", want: []string{"03"}},
+		{query: "serial:0", want: []string{"01", "02", "03", "04", "05"}},
+		{query: "NOT mount:pki", want: []string{"03"}},
+		{query: "alice", want: []string{"03"}},
+		{query: `"old.dev"`, want: []string{"04"}},
+		{query: "status:warning san:*.prod.example.com issuer:Intermediate-2 expires<20d", want: []string{"01"}},
+		{query: "(vault:prod OR type:user) and not status:critical", want: []string{"01", "02"}},
+		{query: "vault:dev OR vault:prod AND key:ecdsa", want: []string{"02", "03", "04", "05"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			parsed, err := Parse(tt.query)
+			require.NoError(t, err)
+			got := make([]string, 0)
+			for _, certificate := range parsed.Filter(certificates, env) {
+				got = append(got, certificate.SerialNumber)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{query: "color:red", pos: 0, message: `unknown field "color"`},
+		{query: "status:soon", pos: 7, message: `unknown status "soon"`},
+		{query: "cn:", pos: 3, message: "missing value for cn"},
+		{query: "cn<3", pos: 2, message: "cn only supports ':'"},
+		{query: "expires<soon", pos: 8, message: "expires: want a duration"},
+		{query: "(cn:a", pos: 0, message: "missing )"},
+		{query: "cn:a)", pos: 4, message: "unexpected )"},
+		{query: "cn:a OR", pos: 7, message: "unexpected end of query"},
+		{query: "AND cn:a", pos: 0, message: "unexpected AND"},
+		{query: `cn:"open`, pos: 3, message: "unterminated quoted string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "got %v", err)
+			assert.Equal(t, tt.pos, parseErr.Pos)
+			assert.Contains(t, parseErr.Message, tt.message)
+		})
+	}
+}
+
+func TestQuery_NilMatchesEverything(t *testing.T) {
+	var parsed *Query
+	assert.True(t, parsed.Match(certs.Certificate{}, Env{}))
+	assert.Equal(t, "", parsed.String())
+}
@@ -0,0 +1,120 @@
+package certs
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+// ErrNoCertificate is returned when a PEM input holds no CERTIFICATE block.
+var ErrNoCertificate = errors.New("no PEM certificate found")
+
+// DecodePEMCertificates returns the DER bytes of every CERTIFICATE block in
+// data, in order. Other block types are skipped.
+func DecodePEMCertificates(data string) ([][]byte, error) {
+	var ders [][]byte
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		return nil, ErrNoCertificate
+	}
+	return ders, nil
+}
+
+// EncodePEMCertificates concatenates the DER certificates as PEM blocks.
+func EncodePEMCertificates(ders [][]byte) string {
+	var b strings.Builder
+	for _, der := range ders {
+		b.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	return b.String()
+}
+
+var (
+	oidPKCS7Data       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7SignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      asn1.RawValue
+}
+
+// EncodePKCS7 builds a DER "certs-only" PKCS#7 SignedData structure (the
+// .p7b format) holding the given DER certificates and no signatures.
+func EncodePKCS7(ders [][]byte) ([]byte, error) {
+	if len(ders) == 0 {
+		return nil, ErrNoCertificate
+	}
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: []byte{}}
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidPKCS7Data},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: joinDER(ders)},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidPKCS7SignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// DecodePKCS7 returns the DER certificates of a certs-only PKCS#7 structure.
+func DecodePKCS7(data []byte) ([][]byte, error) {
+	var content pkcs7ContentInfo
+	if _, err := asn1.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	if !content.ContentType.Equal(oidPKCS7SignedData) {
+		return nil, errors.New("pkcs7: not a SignedData structure")
+	}
+	var signedData pkcs7SignedData
+	if _, err := asn1.Unmarshal(content.Content.Bytes, &signedData); err != nil {
+		return nil, err
+	}
+	var ders [][]byte
+	rest := signedData.Certificates.Bytes
+	for len(rest) > 0 {
+		var raw asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &raw)
+		if err != nil {
+			return nil, err
+		}
+		ders = append(ders, raw.FullBytes)
+	}
+	if len(ders) == 0 {
+		return nil, ErrNoCertificate
+	}
+	return ders, nil
+}
+
+func joinDER(ders [][]byte) []byte {
+	var joined []byte
+	for _, der := range ders {
+		joined = append(joined, der...)
+	}
+	return joined
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"strconv"
 	"strings"
 	"time"
@@ -19,12 +21,45 @@ type Certificate struct {
 	CreatedAt    time.Time `json:"createdAt"`
 	ExpiresAt    time.Time `json:"expiresAt"`
 	Revoked      bool      `json:"revoked"`
+	// RevokedAt is the revocation time reported by Vault, nil when the
+	// certificate is not revoked or Vault did not report one.
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
 	// IssuerCN is the issuer Common Name (list-time parse for metrics/UI).
 	IssuerCN string `json:"issuerCN,omitempty"`
 	// KeyAlgorithm is the public key algorithm (RSA, ECDSA, Ed25519, ...).
 	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
 	// KeySize is the public key size in bits (0 when not applicable).
 	KeySize int `json:"keySize,omitempty"`
+	// PublicKeyFingerprint is the hex SHA-256 of the SubjectPublicKeyInfo.
+	// Certificates sharing this value were issued for the same key pair.
+	PublicKeyFingerprint string `json:"publicKeyFingerprint,omitempty"`
+	// SharedKeyWith lists the IDs of other certificates using the same public
+	// key. Filled by AnnotateSharedKeys; empty when the key is unique.
+	SharedKeyWith []string `json:"sharedKeyWith,omitempty"`
+	// ExtKeyUsages holds the dotted OIDs of the extended key usages, kept at
+	// list time so classification rules can run without re-parsing.
+	ExtKeyUsages []string `json:"extKeyUsages,omitempty"`
+	// IsCA reports the BasicConstraints CA flag.
+	IsCA bool `json:"isCA,omitempty"`
+	// CertLabel is the display label of the classification rule that set
+	// CertType, empty when the built-in inference was used.
+	CertLabel string `json:"certLabel,omitempty"`
+	// RollupCount is set on the row a mount policy kept for a group of
+	// short-lived certificates sharing a common name: the size of the group.
+	RollupCount int `json:"rollupCount,omitempty"`
+	// ExcludeFromPerCertificateMetrics is set by a mount policy to keep the
+	// certificate out of the per-certificate Prometheus series.
+	ExcludeFromPerCertificateMetrics bool `json:"-"`
+	// Acknowledgement is set when an active acknowledgement covers the
+	// certificate; acknowledged certificates are left out of expiry counts.
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// Acknowledgement records why a certificate is excluded from alerting.
+type Acknowledgement struct {
+	ID        string     `json:"id"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type DetailedCertificate struct {
@@ -84,6 +119,8 @@ func (c *Certificate) GetStatus() string {
 	return "valid"
 }
 
+// InferCertType derives machine/user/both/unknown from the server and client
+// auth EKUs. Configured classification rules (see Classifier) take precedence.
 func InferCertType(cert *x509.Certificate) string {
 	if cert == nil {
 		return "unknown"
@@ -115,7 +152,13 @@ func KeyAlgoAndSize(certificate *x509.Certificate) (string, int) {
 	if certificate == nil {
 		return "", 0
 	}
-	switch pub := certificate.PublicKey.(type) {
+	return publicKeyAlgoAndSize(certificate.PublicKey, certificate.PublicKeyAlgorithm)
+}
+
+// publicKeyAlgoAndSize names a parsed public key, falling back to the
+// declared algorithm for key types without a size.
+func publicKeyAlgoAndSize(publicKey any, algorithm x509.PublicKeyAlgorithm) (string, int) {
+	switch pub := publicKey.(type) {
 	case *rsa.PublicKey:
 		return "RSA", pub.N.BitLen()
 	case *ecdsa.PublicKey:
@@ -123,8 +166,18 @@ func KeyAlgoAndSize(certificate *x509.Certificate) (string, int) {
 	case ed25519.PublicKey:
 		return "Ed25519", len(pub) * 8
 	default:
-		return certificate.PublicKeyAlgorithm.String(), 0
+		return algorithm.String(), 0
+	}
+}
+
+// PublicKeyFingerprint returns the hex SHA-256 of the certificate's
+// SubjectPublicKeyInfo, or "" for a nil certificate.
+func PublicKeyFingerprint(certificate *x509.Certificate) string {
+	if certificate == nil || len(certificate.RawSubjectPublicKeyInfo) == 0 {
+		return ""
 	}
+	sum := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
 }
 
 // KeySizeLabel formats a key size for metric labels.
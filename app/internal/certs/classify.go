@@ -0,0 +1,241 @@
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"vcv/internal/config"
+)
+
+// extKeyUsageOIDs maps the x509 enum values to their dotted OIDs.
+var extKeyUsageOIDs = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "2.5.29.37.0",
+	x509.ExtKeyUsageServerAuth:      "1.3.6.1.5.5.7.3.1",
+	x509.ExtKeyUsageClientAuth:      "1.3.6.1.5.5.7.3.2",
+	x509.ExtKeyUsageCodeSigning:     "1.3.6.1.5.5.7.3.3",
+	x509.ExtKeyUsageEmailProtection: "1.3.6.1.5.5.7.3.4",
+	x509.ExtKeyUsageTimeStamping:    "1.3.6.1.5.5.7.3.8",
+	x509.ExtKeyUsageOCSPSigning:     "1.3.6.1.5.5.7.3.9",
+}
+
+// extKeyUsageNames maps the short names accepted in rules to dotted OIDs.
+var extKeyUsageNames = map[string]string{
+	"any":             "2.5.29.37.0",
+	"serverauth":      "1.3.6.1.5.5.7.3.1",
+	"clientauth":      "1.3.6.1.5.5.7.3.2",
+	"codesigning":     "1.3.6.1.5.5.7.3.3",
+	"emailprotection": "1.3.6.1.5.5.7.3.4",
+	"timestamping":    "1.3.6.1.5.5.7.3.8",
+	"ocspsigning":     "1.3.6.1.5.5.7.3.9",
+}
+
+var dottedOIDPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+// ExtKeyUsageOIDs returns the dotted OIDs of every extended key usage on the
+// certificate, including ones the x509 package does not know by name.
+func ExtKeyUsageOIDs(certificate *x509.Certificate) []string {
+	if certificate == nil {
+		return nil
+	}
+	oids := make([]string, 0, len(certificate.ExtKeyUsage)+len(certificate.UnknownExtKeyUsage))
+	for _, usage := range certificate.ExtKeyUsage {
+		if oid, ok := extKeyUsageOIDs[usage]; ok {
+			oids = append(oids, oid)
+		}
+	}
+	for _, oid := range certificate.UnknownExtKeyUsage {
+		oids = append(oids, oid.String())
+	}
+	return oids
+}
+
+// CertTypeInfo describes one certificate type the classifier can produce.
+type CertTypeInfo struct {
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+}
+
+// builtinCertTypes are the values InferCertType can return.
+var builtinCertTypes = []string{"machine", "user", "both", "unknown"}
+
+type classificationRule struct {
+	certType   string
+	label      string
+	ekus       []string
+	isCA       *bool
+	commonName *regexp.Regexp
+	san        *regexp.Regexp
+	mounts     map[string]struct{}
+}
+
+// Classifier assigns CertType from configured rules, falling back to the
+// type inferred at list time. A nil *Classifier leaves certificates as-is.
+type Classifier struct {
+	rules []classificationRule
+}
+
+// NewClassifier compiles rules in order. It fails on an empty type, an
+// unknown EKU name or an invalid regular expression, naming the rule index.
+func NewClassifier(rules []config.ClassificationRule) (*Classifier, error) {
+	compiled := make([]classificationRule, 0, len(rules))
+	for index, rule := range rules {
+		certType := strings.TrimSpace(rule.Type)
+		if certType == "" {
+			return nil, fmt.Errorf("classification rule %d: type is empty", index)
+		}
+		entry := classificationRule{certType: certType, label: strings.TrimSpace(rule.Label), isCA: rule.IsCA}
+		for _, eku := range rule.EKUs {
+			oid, err := resolveExtKeyUsage(eku)
+			if err != nil {
+				return nil, fmt.Errorf("classification rule %d: %w", index, err)
+			}
+			entry.ekus = append(entry.ekus, oid)
+		}
+		if pattern := strings.TrimSpace(rule.CommonName); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("classification rule %d: invalid common_name pattern: %w", index, err)
+			}
+			entry.commonName = re
+		}
+		if pattern := strings.TrimSpace(rule.SAN); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("classification rule %d: invalid san pattern: %w", index, err)
+			}
+			entry.san = re
+		}
+		if len(rule.Mounts) > 0 {
+			entry.mounts = make(map[string]struct{}, len(rule.Mounts))
+			for _, mount := range rule.Mounts {
+				if trimmed := strings.TrimSpace(mount); trimmed != "" {
+					entry.mounts[trimmed] = struct{}{}
+				}
+			}
+		}
+		compiled = append(compiled, entry)
+	}
+	return &Classifier{rules: compiled}, nil
+}
+
+func resolveExtKeyUsage(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if oid, ok := extKeyUsageNames[strings.ToLower(trimmed)]; ok {
+		return oid, nil
+	}
+	if dottedOIDPattern.MatchString(trimmed) {
+		return trimmed, nil
+	}
+	return "", fmt.Errorf("unknown extended key usage %q", value)
+}
+
+// Classify returns the type and label for certificate. Without a matching
+// rule it returns the certificate's current CertType and an empty label.
+func (c *Classifier) Classify(certificate Certificate) (string, string) {
+	if c != nil {
+		for _, rule := range c.rules {
+			if rule.matches(certificate) {
+				return rule.certType, rule.label
+			}
+		}
+	}
+	return certificate.CertType, ""
+}
+
+// Apply returns a copy of certificates with CertType and CertLabel set by
+// Classify. The input slice is not modified.
+func (c *Classifier) Apply(certificates []Certificate) []Certificate {
+	if c == nil || len(c.rules) == 0 {
+		return certificates
+	}
+	classified := make([]Certificate, len(certificates))
+	for index, certificate := range certificates {
+		certificate.CertType, certificate.CertLabel = c.Classify(certificate)
+		classified[index] = certificate
+	}
+	return classified
+}
+
+// Types lists the built-in types followed by each distinct rule type, in
+// rule order. Used by /api/config so the UI type filter knows every value.
+func (c *Classifier) Types() []CertTypeInfo {
+	types := make([]CertTypeInfo, 0, len(builtinCertTypes))
+	seen := make(map[string]struct{})
+	for _, certType := range builtinCertTypes {
+		seen[certType] = struct{}{}
+		types = append(types, CertTypeInfo{Type: certType})
+	}
+	if c == nil {
+		return types
+	}
+	for _, rule := range c.rules {
+		if _, ok := seen[rule.certType]; ok {
+			continue
+		}
+		seen[rule.certType] = struct{}{}
+		types = append(types, CertTypeInfo{Type: rule.certType, Label: rule.label})
+	}
+	return types
+}
+
+func (r classificationRule) matches(certificate Certificate) bool {
+	if r.isCA != nil && *r.isCA != certificate.IsCA {
+		return false
+	}
+	for _, required := range r.ekus {
+		found := false
+		for _, present := range certificate.ExtKeyUsages {
+			if present == required {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.commonName != nil && !r.commonName.MatchString(certificate.CommonName) {
+		return false
+	}
+	if r.san != nil {
+		found := false
+		for _, san := range certificate.Sans {
+			if r.san.MatchString(san) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.mounts != nil {
+		vaultID, mount := VaultAndMount(certificate.ID)
+		_, byMount := r.mounts[mount]
+		_, byKey := r.mounts[vaultID+"|"+mount]
+		if mount == "" || (!byMount && !byKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// VaultAndMount splits a certificate ID of the form "vault|mount:serial" (or
+// the single-vault "mount:serial") into its vault ID and mount name. Missing
+// parts are returned empty.
+func VaultAndMount(id string) (string, string) {
+	trimmed := strings.TrimSpace(id)
+	vaultID := ""
+	mountSerial := trimmed
+	if parts := strings.SplitN(trimmed, "|", 2); len(parts) == 2 {
+		vaultID = strings.TrimSpace(parts[0])
+		mountSerial = strings.TrimSpace(parts[1])
+	}
+	parts := strings.SplitN(mountSerial, ":", 2)
+	if len(parts) < 2 {
+		return vaultID, ""
+	}
+	return vaultID, strings.TrimSpace(parts[0])
+}
@@ -0,0 +1,116 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+)
+
+func boolRef(value bool) *bool {
+	return &value
+}
+
+func TestExtKeyUsageOIDs(t *testing.T) {
+	certificate := &x509.Certificate{
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageCodeSigning},
+		UnknownExtKeyUsage: []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 311, 20, 2, 2}},
+	}
+	assert.Equal(t, []string{"1.3.6.1.5.5.7.3.1", "1.3.6.1.5.5.7.3.3", "1.3.6.1.4.1.311.20.2.2"}, ExtKeyUsageOIDs(certificate))
+	assert.Nil(t, ExtKeyUsageOIDs(nil))
+}
+
+func TestNewClassifier_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.ClassificationRule
+	}{
+		{name: "empty type", rule: config.ClassificationRule{}},
+		{name: "unknown eku", rule: config.ClassificationRule{Type: "x", EKUs: []string{"notAnEKU"}}},
+		{name: "bad cn regex", rule: config.ClassificationRule{Type: "x", CommonName: "("}},
+		{name: "bad san regex", rule: config.ClassificationRule{Type: "x", SAN: "["}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClassifier([]config.ClassificationRule{tt.rule})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	classifier, err := NewClassifier([]config.ClassificationRule{
+		{Type: "ca", Label: "Certificate authority", IsCA: boolRef(true)},
+		{Type: "code-signing", EKUs: []string{"codeSigning"}},
+		{Type: "smime", EKUs: []string{"1.3.6.1.5.5.7.3.4"}, SAN: `@example\.com$`},
+		{Type: "mesh", CommonName: `\.mesh\.internal$`, Mounts: []string{"vault-a|pki_mesh"}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		certificate   Certificate
+		expectedType  string
+		expectedLabel string
+	}{
+		{name: "ca flag", certificate: Certificate{CertType: "unknown", IsCA: true}, expectedType: "ca", expectedLabel: "Certificate authority"},
+		{name: "code signing eku", certificate: Certificate{CertType: "unknown", ExtKeyUsages: []string{"1.3.6.1.5.5.7.3.3"}}, expectedType: "code-signing"},
+		{name: "smime needs san match", certificate: Certificate{CertType: "unknown", ExtKeyUsages: []string{"1.3.6.1.5.5.7.3.4"}, Sans: []string{"bob@example.org"}}, expectedType: "unknown"},
+		{name: "smime", certificate: Certificate{CertType: "unknown", ExtKeyUsages: []string{"1.3.6.1.5.5.7.3.4"}, Sans: []string{"bob@example.com"}}, expectedType: "smime"},
+		{name: "mount key match", certificate: Certificate{ID: "vault-a|pki_mesh:01", CommonName: "svc.mesh.internal", CertType: "both"}, expectedType: "mesh"},
+		{name: "other vault same mount", certificate: Certificate{ID: "vault-b|pki_mesh:01", CommonName: "svc.mesh.internal", CertType: "both"}, expectedType: "both"},
+		{name: "fallback", certificate: Certificate{CertType: "machine"}, expectedType: "machine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certType, label := classifier.Classify(tt.certificate)
+			assert.Equal(t, tt.expectedType, certType)
+			assert.Equal(t, tt.expectedLabel, label)
+		})
+	}
+}
+
+func TestClassifier_NilIsPassthrough(t *testing.T) {
+	var classifier *Classifier
+	certificates := []Certificate{{ID: "a", CertType: "machine"}}
+	assert.Equal(t, certificates, classifier.Apply(certificates))
+	certType, label := classifier.Classify(certificates[0])
+	assert.Equal(t, "machine", certType)
+	assert.Empty(t, label)
+	assert.Len(t, classifier.Types(), 4)
+}
+
+func TestClassifier_ApplyAndTypes(t *testing.T) {
+	classifier, err := NewClassifier([]config.ClassificationRule{
+		{Type: "ca", Label: "CA", IsCA: boolRef(true)},
+		{Type: "machine", CommonName: "^web"},
+		{Type: "ca", CommonName: "root"},
+	})
+	require.NoError(t, err)
+	input := []Certificate{{ID: "a", IsCA: true, CertType: "unknown"}, {ID: "b", CertType: "user"}}
+
+	applied := classifier.Apply(input)
+	assert.Equal(t, "ca", applied[0].CertType)
+	assert.Equal(t, "CA", applied[0].CertLabel)
+	assert.Equal(t, "user", applied[1].CertType)
+	assert.Equal(t, "unknown", input[0].CertType)
+
+	types := classifier.Types()
+	require.Len(t, types, 5)
+	assert.Equal(t, CertTypeInfo{Type: "ca", Label: "CA"}, types[4])
+}
+
+func TestVaultAndMount(t *testing.T) {
+	vaultID, mount := VaultAndMount("vault-a|pki_int:aa:bb")
+	assert.Equal(t, "vault-a", vaultID)
+	assert.Equal(t, "pki_int", mount)
+	vaultID, mount = VaultAndMount("pki:aa")
+	assert.Empty(t, vaultID)
+	assert.Equal(t, "pki", mount)
+	_, mount = VaultAndMount("serial-only")
+	assert.Empty(t, mount)
+}
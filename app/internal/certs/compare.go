@@ -0,0 +1,241 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Comparison is the field-by-field difference between two certificates. A
+// is the reference, usually the certificate being replaced, and B the one
+// compared with it: "added" means present in B only.
+type Comparison struct {
+	// Identical is true when both are the same certificate (same DER).
+	Identical bool `json:"identical"`
+	// SameKey is true when both certify the same public key.
+	SameKey            bool        `json:"sameKey"`
+	Subject            ValueChange `json:"subject"`
+	Issuer             ValueChange `json:"issuer"`
+	KeyAlgorithm       ValueChange `json:"keyAlgorithm"`
+	KeySize            ValueChange `json:"keySize"`
+	SignatureAlgorithm ValueChange `json:"signatureAlgorithm"`
+	SANs               SetChange   `json:"sans"`
+	KeyUsages          SetChange   `json:"keyUsages"`
+	ExtKeyUsages       SetChange   `json:"extKeyUsages"`
+	Validity           Validity    `json:"validity"`
+	Extensions         SetChange   `json:"extensions"`
+	// Changed names the fields above that differ, in declaration order.
+	Changed []string `json:"changed"`
+}
+
+// ValueChange holds a single-valued field of both certificates.
+type ValueChange struct {
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Changed bool   `json:"changed"`
+}
+
+// SetChange holds a multi-valued field of both certificates. Modified is
+// only used for extensions: present in both with a different value or
+// criticality.
+type SetChange struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Modified  []string `json:"modified,omitempty"`
+	Unchanged []string `json:"unchanged"`
+	Changed   bool     `json:"changed"`
+}
+
+// Validity compares the validity periods; LifetimeDays is NotAfter minus
+// NotBefore in whole days.
+type Validity struct {
+	NotBefore    ValueChange `json:"notBefore"`
+	NotAfter     ValueChange `json:"notAfter"`
+	LifetimeDays ValueChange `json:"lifetimeDays"`
+}
+
+// extKeyUsageDisplayNames are the RFC 5280 names of the extended key usages
+// extKeyUsageOIDs knows.
+var extKeyUsageDisplayNames = map[string]string{
+	"2.5.29.37.0":       "anyExtendedKeyUsage",
+	"1.3.6.1.5.5.7.3.1": "serverAuth",
+	"1.3.6.1.5.5.7.3.2": "clientAuth",
+	"1.3.6.1.5.5.7.3.3": "codeSigning",
+	"1.3.6.1.5.5.7.3.4": "emailProtection",
+	"1.3.6.1.5.5.7.3.8": "timeStamping",
+	"1.3.6.1.5.5.7.3.9": "OCSPSigning",
+}
+
+// keyUsageNames are the RFC 5280 names of the key usage bits, in bit order.
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "contentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+// extensionNames names the extensions commonly found on PKI certificates.
+// Others are reported by dotted OID.
+var extensionNames = map[string]string{
+	"2.5.29.14":               "subjectKeyIdentifier",
+	"2.5.29.15":               "keyUsage",
+	"2.5.29.17":               "subjectAltName",
+	"2.5.29.19":               "basicConstraints",
+	"2.5.29.30":               "nameConstraints",
+	"2.5.29.31":               "cRLDistributionPoints",
+	"2.5.29.32":               "certificatePolicies",
+	"2.5.29.35":               "authorityKeyIdentifier",
+	"2.5.29.37":               "extKeyUsage",
+	"1.3.6.1.5.5.7.1.1":       "authorityInfoAccess",
+	"1.3.6.1.4.1.11129.2.4.2": "signedCertificateTimestampList",
+}
+
+// comparedAltNames adds the URI names, such as SPIFFE IDs, that
+// SubjectAltNames leaves out: a renewal that changes one must show up.
+func comparedAltNames(certificate *x509.Certificate) []string {
+	names := SubjectAltNames(certificate)
+	for _, uri := range certificate.URIs {
+		names = append(names, uri.String())
+	}
+	return names
+}
+
+// CompareCertificates diffs b against a. Set members keep the order of the
+// certificate they come from.
+func CompareCertificates(a, b *x509.Certificate) Comparison {
+	algorithmA, sizeA := KeyAlgoAndSize(a)
+	algorithmB, sizeB := KeyAlgoAndSize(b)
+	comparison := Comparison{
+		Identical:          bytes.Equal(a.Raw, b.Raw),
+		SameKey:            bytes.Equal(a.RawSubjectPublicKeyInfo, b.RawSubjectPublicKeyInfo),
+		Subject:            compareValues(a.Subject.String(), b.Subject.String()),
+		Issuer:             compareValues(a.Issuer.String(), b.Issuer.String()),
+		KeyAlgorithm:       compareValues(algorithmA, algorithmB),
+		KeySize:            compareValues(strconv.Itoa(sizeA), strconv.Itoa(sizeB)),
+		SignatureAlgorithm: compareValues(a.SignatureAlgorithm.String(), b.SignatureAlgorithm.String()),
+		SANs:               compareSets(comparedAltNames(a), comparedAltNames(b)),
+		KeyUsages:          compareSets(keyUsages(a), keyUsages(b)),
+		ExtKeyUsages:       compareSets(extKeyUsages(a), extKeyUsages(b)),
+		Validity: Validity{
+			NotBefore:    compareValues(a.NotBefore.UTC().Format(time.RFC3339), b.NotBefore.UTC().Format(time.RFC3339)),
+			NotAfter:     compareValues(a.NotAfter.UTC().Format(time.RFC3339), b.NotAfter.UTC().Format(time.RFC3339)),
+			LifetimeDays: compareValues(lifetimeDays(a), lifetimeDays(b)),
+		},
+		Extensions: compareExtensions(a, b),
+	}
+	for _, field := range []struct {
+		name    string
+		changed bool
+	}{
+		{"subject", comparison.Subject.Changed},
+		{"issuer", comparison.Issuer.Changed},
+		{"keyAlgorithm", comparison.KeyAlgorithm.Changed},
+		{"keySize", comparison.KeySize.Changed},
+		{"signatureAlgorithm", comparison.SignatureAlgorithm.Changed},
+		{"sans", comparison.SANs.Changed},
+		{"keyUsages", comparison.KeyUsages.Changed},
+		{"extKeyUsages", comparison.ExtKeyUsages.Changed},
+		{"validity", comparison.Validity.NotBefore.Changed || comparison.Validity.NotAfter.Changed},
+		{"extensions", comparison.Extensions.Changed},
+	} {
+		if field.changed {
+			comparison.Changed = append(comparison.Changed, field.name)
+		}
+	}
+	if comparison.Changed == nil {
+		comparison.Changed = []string{}
+	}
+	return comparison
+}
+
+func compareValues(a, b string) ValueChange {
+	return ValueChange{A: a, B: b, Changed: a != b}
+}
+
+func compareSets(a, b []string) SetChange {
+	change := SetChange{Added: []string{}, Removed: []string{}, Unchanged: []string{}}
+	for _, value := range a {
+		if slices.Contains(b, value) {
+			change.Unchanged = append(change.Unchanged, value)
+		} else {
+			change.Removed = append(change.Removed, value)
+		}
+	}
+	for _, value := range b {
+		if !slices.Contains(a, value) {
+			change.Added = append(change.Added, value)
+		}
+	}
+	change.Changed = len(change.Added) > 0 || len(change.Removed) > 0
+	return change
+}
+
+// compareExtensions matches extensions by OID. The subject and authority
+// key identifiers are left out of Modified: they follow the key and the
+// issuer, which are compared on their own.
+func compareExtensions(a, b *x509.Certificate) SetChange {
+	names := func(certificate *x509.Certificate) []string {
+		result := make([]string, 0, len(certificate.Extensions))
+		for _, extension := range certificate.Extensions {
+			result = append(result, extensionName(extension.Id.String()))
+		}
+		return result
+	}
+	change := compareSets(names(a), names(b))
+	for _, extension := range a.Extensions {
+		oid := extension.Id.String()
+		if oid == "2.5.29.14" || oid == "2.5.29.35" {
+			continue
+		}
+		for _, other := range b.Extensions {
+			if other.Id.Equal(extension.Id) && (other.Critical != extension.Critical || !bytes.Equal(other.Value, extension.Value)) {
+				change.Modified = append(change.Modified, extensionName(oid))
+				change.Unchanged = slices.DeleteFunc(change.Unchanged, func(name string) bool { return name == extensionName(oid) })
+				break
+			}
+		}
+	}
+	change.Changed = change.Changed || len(change.Modified) > 0
+	return change
+}
+
+func extensionName(oid string) string {
+	if name, ok := extensionNames[oid]; ok {
+		return name
+	}
+	return oid
+}
+
+func keyUsages(certificate *x509.Certificate) []string {
+	var names []string
+	for _, usage := range keyUsageNames {
+		if certificate.KeyUsage&usage.usage != 0 {
+			names = append(names, usage.name)
+		}
+	}
+	return names
+}
+
+func extKeyUsages(certificate *x509.Certificate) []string {
+	oids := ExtKeyUsageOIDs(certificate)
+	for i, oid := range oids {
+		if name, ok := extKeyUsageDisplayNames[oid]; ok {
+			oids[i] = name
+		}
+	}
+	return oids
+}
+
+func lifetimeDays(certificate *x509.Certificate) string {
+	return strconv.Itoa(int(certificate.NotAfter.Sub(certificate.NotBefore) / (24 * time.Hour)))
+}
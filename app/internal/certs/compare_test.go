@@ -0,0 +1,126 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issueForComparison(t *testing.T, key crypto.Signer, template *x509.Certificate) *x509.Certificate {
+	t.Helper()
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate
+}
+
+func TestCompareCertificates(t *testing.T) {
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	old := issueForComparison(t, ecKey, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "api.example.com", Organization: []string{"Example"}},
+		DNSNames:     []string{"api.example.com", "legacy.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	renewed := issueForComparison(t, rsaKey, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "api.example.com", Organization: []string{"Example"}},
+		DNSNames:              []string{"api.example.com", "api2.example.com"},
+		NotBefore:             notBefore.Add(80 * 24 * time.Hour),
+		NotAfter:              notBefore.Add(445 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		CRLDistributionPoints: []string{"http://crl.example.com/pki.crl"},
+	})
+
+	comparison := CompareCertificates(old, renewed)
+
+	assert.False(t, comparison.Identical)
+	assert.False(t, comparison.SameKey)
+	assert.Equal(t, ValueChange{A: "CN=api.example.com,O=Example", B: "CN=api.example.com,O=Example"}, comparison.Subject)
+	assert.Equal(t, ValueChange{A: "ECDSA", B: "RSA", Changed: true}, comparison.KeyAlgorithm)
+	assert.Equal(t, ValueChange{A: "256", B: "2048", Changed: true}, comparison.KeySize)
+	assert.Equal(t, []string{"api2.example.com"}, comparison.SANs.Added)
+	assert.Equal(t, []string{"legacy.example.com"}, comparison.SANs.Removed)
+	assert.Equal(t, []string{"api.example.com"}, comparison.SANs.Unchanged)
+	assert.Equal(t, []string{"clientAuth"}, comparison.ExtKeyUsages.Removed)
+	assert.False(t, comparison.KeyUsages.Changed)
+	assert.Equal(t, []string{"digitalSignature", "keyEncipherment"}, comparison.KeyUsages.Unchanged)
+	assert.Equal(t, ValueChange{A: "90", B: "365", Changed: true}, comparison.Validity.LifetimeDays)
+	assert.Equal(t, "2026-01-01T00:00:00Z", comparison.Validity.NotBefore.A)
+	assert.Equal(t, []string{"cRLDistributionPoints"}, comparison.Extensions.Added)
+	assert.Contains(t, comparison.Extensions.Modified, "subjectAltName")
+	assert.Contains(t, comparison.Extensions.Modified, "extKeyUsage")
+	assert.NotContains(t, comparison.Extensions.Unchanged, "subjectAltName")
+	assert.Contains(t, comparison.Extensions.Unchanged, "keyUsage")
+	assert.Equal(t, []string{"keyAlgorithm", "keySize", "signatureAlgorithm", "sans", "extKeyUsages", "validity", "extensions"}, comparison.Changed)
+}
+
+func TestCompareCertificates_Identical(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	certificate := issueForComparison(t, key, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "same.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	})
+
+	comparison := CompareCertificates(certificate, certificate)
+
+	assert.True(t, comparison.Identical)
+	assert.True(t, comparison.SameKey)
+	assert.Empty(t, comparison.Changed)
+	assert.NotNil(t, comparison.Changed, "an empty list, not null, in JSON")
+	assert.Empty(t, comparison.SANs.Added)
+	assert.Empty(t, comparison.Extensions.Modified)
+}
+
+func TestCompareCertificates_URISAN(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	issue := func(serial int64, id string) *x509.Certificate {
+		uri, parseErr := url.Parse(id)
+		require.NoError(t, parseErr)
+		return issueForComparison(t, key, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "workload"},
+			DNSNames:     []string{"workload.example.com"},
+			URIs:         []*url.URL{uri},
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.Add(24 * time.Hour),
+		})
+	}
+
+	comparison := CompareCertificates(
+		issue(1, "spiffe://example.org/ns/prod/sa/api"),
+		issue(2, "spiffe://example.org/ns/prod/sa/worker"),
+	)
+
+	assert.True(t, comparison.SANs.Changed)
+	assert.Equal(t, []string{"spiffe://example.org/ns/prod/sa/worker"}, comparison.SANs.Added)
+	assert.Equal(t, []string{"spiffe://example.org/ns/prod/sa/api"}, comparison.SANs.Removed)
+	assert.Equal(t, []string{"workload.example.com"}, comparison.SANs.Unchanged)
+	assert.Contains(t, comparison.Changed, "sans")
+}
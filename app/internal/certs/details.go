@@ -0,0 +1,135 @@
+package certs
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+)
+
+// NewCertificate fills the list-time fields of an inventory entry from a
+// parsed certificate. ID is left empty and SerialNumber is the
+// colon-separated hex form; the vault client replaces both with the
+// values from its mount, and sets the revocation state.
+func NewCertificate(certificate *x509.Certificate) Certificate {
+	algorithm, keySize := KeyAlgoAndSize(certificate)
+	return Certificate{
+		SerialNumber:         FormatSerial(certificate),
+		CommonName:           certificate.Subject.CommonName,
+		Sans:                 SubjectAltNames(certificate),
+		CertType:             InferCertType(certificate),
+		CreatedAt:            certificate.NotBefore.UTC(),
+		ExpiresAt:            certificate.NotAfter.UTC(),
+		IssuerCN:             certificate.Issuer.CommonName,
+		KeyAlgorithm:         algorithm,
+		KeySize:              keySize,
+		PublicKeyFingerprint: PublicKeyFingerprint(certificate),
+		ExtKeyUsages:         ExtKeyUsageOIDs(certificate),
+		IsCA:                 certificate.IsCA,
+	}
+}
+
+// NewDetailedCertificate builds the detail view of a parsed certificate,
+// the one GET /api/certs/{id}/details returns. certificatePEM is returned
+// as is.
+func NewDetailedCertificate(certificate *x509.Certificate, certificatePEM string) DetailedCertificate {
+	sha1Fingerprint := sha1.Sum(certificate.Raw)
+	sha256Fingerprint := sha256.Sum256(certificate.Raw)
+	summary := NewCertificate(certificate)
+	return DetailedCertificate{
+		Certificate:       summary,
+		Issuer:            certificate.Issuer.String(),
+		Subject:           certificate.Subject.String(),
+		KeyAlgorithm:      summary.KeyAlgorithm,
+		KeySize:           summary.KeySize,
+		FingerprintSHA1:   hex.EncodeToString(sha1Fingerprint[:]),
+		FingerprintSHA256: hex.EncodeToString(sha256Fingerprint[:]),
+		Usage:             usageLabels(certificate),
+		PEM:               certificatePEM,
+	}
+}
+
+// CertificateRequest is the detail view of a PKCS #10 certificate request.
+type CertificateRequest struct {
+	Subject              string   `json:"subject"`
+	CommonName           string   `json:"commonName"`
+	Sans                 []string `json:"sans"`
+	KeyAlgorithm         string   `json:"keyAlgorithm"`
+	KeySize              int      `json:"keySize"`
+	SignatureAlgorithm   string   `json:"signatureAlgorithm"`
+	PublicKeyFingerprint string   `json:"publicKeyFingerprint"`
+	PEM                  string   `json:"pem"`
+}
+
+// NewCertificateRequest builds the detail view of a parsed request.
+// PublicKeyFingerprint matches Certificate.PublicKeyFingerprint for the
+// same key.
+func NewCertificateRequest(request *x509.CertificateRequest, requestPEM string) CertificateRequest {
+	algorithm, keySize := publicKeyAlgoAndSize(request.PublicKey, request.PublicKeyAlgorithm)
+	sum := sha256.Sum256(request.RawSubjectPublicKeyInfo)
+	return CertificateRequest{
+		Subject:              request.Subject.String(),
+		CommonName:           request.Subject.CommonName,
+		Sans:                 subjectAltNames(request.DNSNames, request.IPAddresses, request.EmailAddresses),
+		KeyAlgorithm:         algorithm,
+		KeySize:              keySize,
+		SignatureAlgorithm:   request.SignatureAlgorithm.String(),
+		PublicKeyFingerprint: hex.EncodeToString(sum[:]),
+		PEM:                  requestPEM,
+	}
+}
+
+// SubjectAltNames collects the DNS, IP and email subject alternative names.
+func SubjectAltNames(certificate *x509.Certificate) []string {
+	return subjectAltNames(certificate.DNSNames, certificate.IPAddresses, certificate.EmailAddresses)
+}
+
+func subjectAltNames(dnsNames []string, addresses []net.IP, emails []string) []string {
+	names := make([]string, 0, len(dnsNames)+len(addresses)+len(emails))
+	names = append(names, dnsNames...)
+	for _, address := range addresses {
+		names = append(names, address.String())
+	}
+	names = append(names, emails...)
+	return names
+}
+
+// FormatSerial renders the serial number as colon-separated lowercase hex
+// bytes, the form Vault reports in serial_number.
+func FormatSerial(certificate *x509.Certificate) string {
+	if certificate.SerialNumber == nil {
+		return ""
+	}
+	raw := certificate.SerialNumber.Bytes()
+	if len(raw) == 0 {
+		return "00"
+	}
+	const digits = "0123456789abcdef"
+	formatted := make([]byte, 0, len(raw)*3-1)
+	for i, value := range raw {
+		if i > 0 {
+			formatted = append(formatted, ':')
+		}
+		formatted = append(formatted, digits[value>>4], digits[value&0x0f])
+	}
+	return string(formatted)
+}
+
+// usageLabels names the common extended key usages for the detail view.
+func usageLabels(certificate *x509.Certificate) []string {
+	var usage []string
+	for _, extUsage := range certificate.ExtKeyUsage {
+		switch extUsage {
+		case x509.ExtKeyUsageServerAuth:
+			usage = append(usage, "Server Auth")
+		case x509.ExtKeyUsageClientAuth:
+			usage = append(usage, "Client Auth")
+		case x509.ExtKeyUsageCodeSigning:
+			usage = append(usage, "Code Signing")
+		case x509.ExtKeyUsageEmailProtection:
+			usage = append(usage, "Email Protection")
+		}
+	}
+	return usage
+}
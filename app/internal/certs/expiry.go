@@ -1,35 +1,343 @@
 package certs
 
 import (
-	"math"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"vcv/internal/config"
 )
 
-// CountExpiring counts non-revoked, unexpired certificates whose remaining days
-// until expiry fall within the warning and critical thresholds. A certificate
-// inside the critical window is also counted toward warning when its remaining
-// days are within the warning threshold too - callers get both counts from one
-// pass. warningDays or criticalDays <= 0 disables that threshold.
-func CountExpiring(certificates []Certificate, warningDays, criticalDays int, now time.Time) (warning, critical int) {
-	for _, certificate := range certificates {
-		if certificate.Revoked || certificate.ExpiresAt.IsZero() || certificate.ExpiresAt.Before(now) {
-			continue
+// Expiry tiers returned by ExpiryThresholds.Tier.
+const (
+	ExpiryTierNone     = ""
+	ExpiryTierWarning  = "warning"
+	ExpiryTierCritical = "critical"
+)
+
+// ExpiryWindow is the evaluated form of one config.ExpirationThresholds
+// entry. A certificate is inside a level when its remaining time is within
+// the duration or its remaining share of total lifetime is at or below the
+// percentage; zero values disable that criterion.
+type ExpiryWindow struct {
+	Critical        time.Duration
+	Warning         time.Duration
+	CriticalPercent float64
+	WarningPercent  float64
+}
+
+// Evaluate reports whether the certificate falls inside the warning and
+// critical windows. Revoked, expired, undated and acknowledged certificates
+// are in neither.
+func (window ExpiryWindow) Evaluate(certificate Certificate, now time.Time) (warning, critical bool) {
+	if certificate.Revoked || certificate.Acknowledgement != nil || certificate.ExpiresAt.IsZero() || certificate.ExpiresAt.Before(now) {
+		return false, false
+	}
+	remaining := certificate.ExpiresAt.Sub(now)
+	var lifetime time.Duration
+	if !certificate.CreatedAt.IsZero() {
+		lifetime = certificate.ExpiresAt.Sub(certificate.CreatedAt)
+	}
+	warning = withinWindow(remaining, lifetime, window.Warning, window.WarningPercent)
+	critical = withinWindow(remaining, lifetime, window.Critical, window.CriticalPercent)
+	return warning, critical
+}
+
+// Describe renders one level of the window for humans, e.g. "7 days",
+// "6h0m0s" or "7 days or 20% of lifetime".
+func (window ExpiryWindow) Describe(tier string) string {
+	duration, percent := window.Warning, window.WarningPercent
+	if tier == ExpiryTierCritical {
+		duration, percent = window.Critical, window.CriticalPercent
+	}
+	parts := make([]string, 0, 2)
+	if duration > 0 {
+		if duration%(24*time.Hour) == 0 {
+			parts = append(parts, fmt.Sprintf("%d days", int(duration/(24*time.Hour))))
+		} else {
+			parts = append(parts, duration.String())
 		}
-		daysRemaining := daysUntilExpiry(certificate.ExpiresAt, now)
-		if daysRemaining < 0 {
+	}
+	if percent > 0 {
+		parts = append(parts, strconv.FormatFloat(percent, 'f', -1, 64)+"% of lifetime")
+	}
+	return strings.Join(parts, " or ")
+}
+
+// Lead returns how long before expiry the certificate enters the warning
+// and critical windows, or zero when a level is disabled. When a level has
+// both a duration and a lifetime percentage, the longer lead wins, as in
+// Evaluate.
+func (window ExpiryWindow) Lead(certificate Certificate) (warning, critical time.Duration) {
+	var lifetime time.Duration
+	if !certificate.CreatedAt.IsZero() && certificate.ExpiresAt.After(certificate.CreatedAt) {
+		lifetime = certificate.ExpiresAt.Sub(certificate.CreatedAt)
+	}
+	return leadTime(lifetime, window.Warning, window.WarningPercent), leadTime(lifetime, window.Critical, window.CriticalPercent)
+}
+
+func leadTime(lifetime, duration time.Duration, percent float64) time.Duration {
+	lead := max(duration, 0)
+	if percent > 0 && lifetime > 0 {
+		lead = max(lead, time.Duration(percent*float64(lifetime)/100).Round(time.Second))
+	}
+	return lead
+}
+
+func withinWindow(remaining, lifetime, duration time.Duration, percent float64) bool {
+	if duration > 0 && remaining <= duration {
+		return true
+	}
+	if percent > 0 && lifetime > 0 && float64(remaining)*100 <= percent*float64(lifetime) {
+		return true
+	}
+	return false
+}
+
+// ExpiryThresholds resolves the ExpiryWindow that applies to a certificate
+// from the global thresholds, their mount overrides and per-vault overrides.
+// A nil *ExpiryThresholds disables every window.
+type ExpiryThresholds struct {
+	global ExpiryWindow
+	mounts map[string]ExpiryWindow
+	vaults map[string]vaultExpiryWindows
+}
+
+type vaultExpiryWindows struct {
+	window *ExpiryWindow
+	mounts map[string]ExpiryWindow
+}
+
+// NewExpiryThresholds compiles the global thresholds, their mount overrides
+// (bare mount names or "vault_id|mount") and the expiration_thresholds of
+// each vault. An override replaces the window as a whole rather than field
+// by field; a vault entry that only lists mounts defines no vault-wide window.
+func NewExpiryThresholds(thresholds config.ExpirationThresholds, vaults []config.VaultInstance) (*ExpiryThresholds, error) {
+	global, err := compileExpiryWindow(thresholds)
+	if err != nil {
+		return nil, err
+	}
+	mounts, err := compileMountWindows(thresholds.Mounts, true)
+	if err != nil {
+		return nil, err
+	}
+	compiled := &ExpiryThresholds{global: global, mounts: mounts, vaults: make(map[string]vaultExpiryWindows)}
+	for _, instance := range vaults {
+		if instance.ExpirationThresholds == nil {
 			continue
 		}
-		if warningDays > 0 && daysRemaining <= warningDays {
-			warning++
+		vaultID := strings.TrimSpace(instance.ID)
+		overrides := *instance.ExpirationThresholds
+		entry := vaultExpiryWindows{}
+		if hasExpiryLevels(overrides) {
+			window, windowErr := compileExpiryWindow(overrides)
+			if windowErr != nil {
+				return nil, fmt.Errorf("vault %q: %w", vaultID, windowErr)
+			}
+			entry.window = &window
 		}
-		if criticalDays > 0 && daysRemaining <= criticalDays {
-			critical++
+		entry.mounts, err = compileMountWindows(overrides.Mounts, false)
+		if err != nil {
+			return nil, fmt.Errorf("vault %q: %w", vaultID, err)
 		}
+		compiled.vaults[vaultID] = entry
 	}
-	return warning, critical
+	return compiled, nil
 }
 
-func daysUntilExpiry(expiresAt, now time.Time) int {
-	diff := expiresAt.Sub(now)
-	return int(math.Ceil(diff.Hours() / 24))
+// Global returns the window used when no mount override applies.
+func (thresholds *ExpiryThresholds) Global() ExpiryWindow {
+	if thresholds == nil {
+		return ExpiryWindow{}
+	}
+	return thresholds.global
+}
+
+// HasOverrides reports whether any mount or vault override is configured.
+func (thresholds *ExpiryThresholds) HasOverrides() bool {
+	return thresholds != nil && (len(thresholds.mounts) > 0 || len(thresholds.vaults) > 0)
+}
+
+// MountWindows returns a copy of the global mount override windows keyed as
+// configured.
+func (thresholds *ExpiryThresholds) MountWindows() map[string]ExpiryWindow {
+	if thresholds == nil {
+		return nil
+	}
+	windows := make(map[string]ExpiryWindow, len(thresholds.mounts))
+	for key, window := range thresholds.mounts {
+		windows[key] = window
+	}
+	return windows
+}
+
+// WindowFor is the single threshold lookup for a vault mount. The most
+// specific entry wins: the vault's own mount override, a global
+// "vault_id|mount" override, the vault-wide override, a global bare-mount
+// override and finally the global window.
+func (thresholds *ExpiryThresholds) WindowFor(vaultID, mount string) ExpiryWindow {
+	if thresholds == nil {
+		return ExpiryWindow{}
+	}
+	vaultEntry, hasVault := thresholds.vaults[vaultID]
+	if mount != "" {
+		if window, ok := vaultEntry.mounts[mount]; hasVault && ok {
+			return window
+		}
+		if vaultID != "" {
+			if window, ok := thresholds.mounts[vaultID+"|"+mount]; ok {
+				return window
+			}
+		}
+	}
+	if hasVault && vaultEntry.window != nil {
+		return *vaultEntry.window
+	}
+	if window, ok := thresholds.mounts[mount]; ok && mount != "" {
+		return window
+	}
+	return thresholds.global
+}
+
+// Tier returns ExpiryTierCritical, ExpiryTierWarning or ExpiryTierNone for
+// the certificate under the window of its mount.
+func (thresholds *ExpiryThresholds) Tier(certificate Certificate, now time.Time) string {
+	vaultID, mount := VaultAndMount(certificate.ID)
+	warning, critical := thresholds.WindowFor(vaultID, mount).Evaluate(certificate, now)
+	switch {
+	case critical:
+		return ExpiryTierCritical
+	case warning:
+		return ExpiryTierWarning
+	default:
+		return ExpiryTierNone
+	}
+}
+
+// Certificate statuses returned by ExpiryThresholds.Status; they match the
+// statuses shown by the UI.
+const (
+	StatusValid    = "valid"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+	StatusExpired  = "expired"
+	StatusRevoked  = "revoked"
+)
+
+// Status classifies the certificate as revoked, expired, critical, warning
+// or valid. A certificate without an expiry date counts as expired, and an
+// acknowledged one is valid until it actually expires.
+func (thresholds *ExpiryThresholds) Status(certificate Certificate, now time.Time) string {
+	if certificate.Revoked {
+		return StatusRevoked
+	}
+	if certificate.ExpiresAt.IsZero() || certificate.ExpiresAt.Before(now) {
+		return StatusExpired
+	}
+	switch thresholds.Tier(certificate, now) {
+	case ExpiryTierCritical:
+		return StatusCritical
+	case ExpiryTierWarning:
+		return StatusWarning
+	default:
+		return StatusValid
+	}
+}
+
+// ParseThresholdDuration parses a Go duration ("6h", "90m") or a whole
+// number of days ("3d"). The result must be positive.
+func ParseThresholdDuration(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(trimmed, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(trimmed)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return duration, nil
+}
+
+func compileExpiryWindow(thresholds config.ExpirationThresholds) (ExpiryWindow, error) {
+	critical, err := compileLevel("critical", thresholds.Critical, thresholds.CriticalDuration, thresholds.CriticalPercent)
+	if err != nil {
+		return ExpiryWindow{}, err
+	}
+	warning, err := compileLevel("warning", thresholds.Warning, thresholds.WarningDuration, thresholds.WarningPercent)
+	if err != nil {
+		return ExpiryWindow{}, err
+	}
+	return ExpiryWindow{
+		Critical:        critical,
+		Warning:         warning,
+		CriticalPercent: thresholds.CriticalPercent,
+		WarningPercent:  thresholds.WarningPercent,
+	}, nil
+}
+
+// compileLevel returns the duration for one level; an explicit duration
+// takes precedence over whole days.
+func compileLevel(name string, days int, duration string, percent float64) (time.Duration, error) {
+	if days < 0 {
+		return 0, fmt.Errorf("%s: days must not be negative", name)
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%s_percent: must be between 0 and 100", name)
+	}
+	if strings.TrimSpace(duration) != "" {
+		parsed, err := ParseThresholdDuration(duration)
+		if err != nil {
+			return 0, fmt.Errorf("%s_duration: %w", name, err)
+		}
+		return parsed, nil
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// compileMountWindows compiles mount overrides; allowVaultKeys accepts the
+// "vault_id|mount" form used at the global level.
+func compileMountWindows(mounts map[string]config.ExpirationThresholds, allowVaultKeys bool) (map[string]ExpiryWindow, error) {
+	windows := make(map[string]ExpiryWindow, len(mounts))
+	for _, key := range sortedMountKeys(mounts) {
+		override := mounts[key]
+		mountKey := strings.TrimSpace(key)
+		if mountKey == "" {
+			return nil, fmt.Errorf("mount override: empty mount key")
+		}
+		if !allowVaultKeys && strings.Contains(mountKey, "|") {
+			return nil, fmt.Errorf("mount override %q: use a bare mount name inside a vault", key)
+		}
+		if len(override.Mounts) > 0 {
+			return nil, fmt.Errorf("mount override %q: nested mount overrides are not supported", key)
+		}
+		window, err := compileExpiryWindow(override)
+		if err != nil {
+			return nil, fmt.Errorf("mount override %q: %w", key, err)
+		}
+		windows[mountKey] = window
+	}
+	return windows, nil
+}
+
+// hasExpiryLevels reports whether thresholds set any level of their own, as
+// opposed to only carrying mount overrides.
+func hasExpiryLevels(thresholds config.ExpirationThresholds) bool {
+	return thresholds.Critical != 0 || thresholds.Warning != 0 ||
+		strings.TrimSpace(thresholds.CriticalDuration) != "" || strings.TrimSpace(thresholds.WarningDuration) != "" ||
+		thresholds.CriticalPercent != 0 || thresholds.WarningPercent != 0
+}
+
+func sortedMountKeys(mounts map[string]config.ExpirationThresholds) []string {
+	keys := make([]string, 0, len(mounts))
+	for key := range mounts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
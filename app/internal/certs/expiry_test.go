@@ -5,36 +5,180 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
 )
 
-func TestCountExpiring(t *testing.T) {
+func mustExpiryThresholds(t *testing.T, thresholds config.ExpirationThresholds) *ExpiryThresholds {
+	t.Helper()
+	compiled, err := NewExpiryThresholds(thresholds, nil)
+	require.NoError(t, err)
+	return compiled
+}
+
+func TestExpiryThresholds_Status(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	thresholds := mustExpiryThresholds(t, config.ExpirationThresholds{Warning: 30, Critical: 7})
+	tests := []struct {
+		name        string
+		certificate Certificate
+		want        string
+	}{
+		{name: "revoked", certificate: Certificate{ExpiresAt: now.Add(time.Hour), Revoked: true}, want: StatusRevoked},
+		{name: "expired", certificate: Certificate{ExpiresAt: now.Add(-time.Hour)}, want: StatusExpired},
+		{name: "undated", certificate: Certificate{}, want: StatusExpired},
+		{name: "critical", certificate: Certificate{ExpiresAt: now.Add(48 * time.Hour)}, want: StatusCritical},
+		{name: "warning", certificate: Certificate{ExpiresAt: now.Add(20 * 24 * time.Hour)}, want: StatusWarning},
+		{name: "valid", certificate: Certificate{ExpiresAt: now.Add(90 * 24 * time.Hour)}, want: StatusValid},
+		{name: "acknowledged", certificate: Certificate{ExpiresAt: now.Add(48 * time.Hour), Acknowledgement: &Acknowledgement{ID: "a"}}, want: StatusValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, thresholds.Status(tt.certificate, now))
+		})
+	}
+}
+
+func TestExpiryThresholds_MountOverrides(t *testing.T) {
 	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	thresholds := mustExpiryThresholds(t, config.ExpirationThresholds{
+		Critical: 7,
+		Warning:  30,
+		Mounts: map[string]config.ExpirationThresholds{
+			"pki_mesh":          {CriticalDuration: "6h", WarningDuration: "24h"},
+			"vault-b|pki_mesh":  {CriticalPercent: 10},
+			"vault-a|pki_other": {Critical: 1},
+		},
+	})
+	mesh := Certificate{ID: "vault-a|pki_mesh:01", CreatedAt: now.Add(-60 * time.Hour), ExpiresAt: now.Add(12 * time.Hour)}
+
+	assert.Equal(t, ExpiryTierWarning, thresholds.Tier(mesh, now))
+	mesh.ID = "vault-b|pki_mesh:01"
+	assert.Equal(t, ExpiryTierNone, thresholds.Tier(mesh, now))
+	mesh.ID = "vault-c|pki:01"
+	assert.Equal(t, ExpiryTierCritical, thresholds.Tier(mesh, now))
+	assert.Equal(t, 24*time.Hour, thresholds.WindowFor("vault-a", "pki_other").Critical)
+	assert.Equal(t, 7*24*time.Hour, thresholds.WindowFor("vault-b", "pki_other").Critical)
+	assert.Len(t, thresholds.MountWindows(), 3)
+}
+
+func TestExpiryThresholds_VaultOverrides(t *testing.T) {
+	thresholds, err := NewExpiryThresholds(config.ExpirationThresholds{
+		Critical: 7,
+		Warning:  30,
+		Mounts: map[string]config.ExpirationThresholds{
+			"pki_public":        {Critical: 14, Warning: 45},
+			"vault-a|pki_other": {Critical: 2, Warning: 5},
+		},
+	}, []config.VaultInstance{
+		{
+			ID: "vault-a",
+			ExpirationThresholds: &config.ExpirationThresholds{
+				Critical: 5,
+				Warning:  20,
+				Mounts:   map[string]config.ExpirationThresholds{"pki_mtls": {Critical: 3, Warning: 10}},
+			},
+		},
+		{
+			ID:                   "vault-b",
+			ExpirationThresholds: &config.ExpirationThresholds{Mounts: map[string]config.ExpirationThresholds{"pki_mtls": {Critical: 1, Warning: 4}}},
+		},
+		{ID: "vault-c"},
+	})
+	require.NoError(t, err)
 
-	certs := []Certificate{
-		{ID: "critical", ExpiresAt: now.Add(3 * 24 * time.Hour)},
-		{ID: "warning", ExpiresAt: now.Add(20 * 24 * time.Hour)},
-		{ID: "far-future", ExpiresAt: now.Add(365 * 24 * time.Hour)},
-		{ID: "expired", ExpiresAt: now.Add(-24 * time.Hour)},
-		{ID: "revoked-but-soon", ExpiresAt: now.Add(1 * 24 * time.Hour), Revoked: true},
-		{ID: "no-expiry"},
+	day := 24 * time.Hour
+	tests := []struct {
+		vaultID  string
+		mount    string
+		critical time.Duration
+		warning  time.Duration
+	}{
+		{vaultID: "vault-a", mount: "pki_mtls", critical: 3 * day, warning: 10 * day},
+		{vaultID: "vault-a", mount: "pki_other", critical: 2 * day, warning: 5 * day},
+		{vaultID: "vault-a", mount: "pki_public", critical: 5 * day, warning: 20 * day},
+		{vaultID: "vault-b", mount: "pki_mtls", critical: 1 * day, warning: 4 * day},
+		{vaultID: "vault-b", mount: "pki_public", critical: 14 * day, warning: 45 * day},
+		{vaultID: "vault-b", mount: "pki", critical: 7 * day, warning: 30 * day},
+		{vaultID: "vault-c", mount: "pki", critical: 7 * day, warning: 30 * day},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vaultID+"|"+tt.mount, func(t *testing.T) {
+			window := thresholds.WindowFor(tt.vaultID, tt.mount)
+			assert.Equal(t, tt.critical, window.Critical)
+			assert.Equal(t, tt.warning, window.Warning)
+		})
 	}
+	assert.True(t, thresholds.HasOverrides())
+}
+
+func TestNewExpiryThresholds_VaultErrors(t *testing.T) {
+	_, err := NewExpiryThresholds(config.ExpirationThresholds{Critical: 7}, []config.VaultInstance{
+		{ID: "vault-a", ExpirationThresholds: &config.ExpirationThresholds{CriticalDuration: "never"}},
+	})
+	assert.ErrorContains(t, err, `vault "vault-a"`)
 
-	warning, critical := CountExpiring(certs, 30, 7, now)
-	assert.Equal(t, 2, warning) // critical + warning both fall within the 30-day warning window
-	assert.Equal(t, 1, critical)
+	_, err = NewExpiryThresholds(config.ExpirationThresholds{Critical: 7}, []config.VaultInstance{
+		{ID: "vault-a", ExpirationThresholds: &config.ExpirationThresholds{Mounts: map[string]config.ExpirationThresholds{"vault-b|pki": {Critical: 1}}}},
+	})
+	assert.ErrorContains(t, err, "bare mount name")
 }
 
-func TestCountExpiring_DisabledThresholds(t *testing.T) {
-	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
-	certs := []Certificate{{ID: "soon", ExpiresAt: now.Add(24 * time.Hour)}}
+func TestNewExpiryThresholds_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds config.ExpirationThresholds
+	}{
+		{name: "negative days", thresholds: config.ExpirationThresholds{Critical: -1}},
+		{name: "bad duration", thresholds: config.ExpirationThresholds{WarningDuration: "soon"}},
+		{name: "zero duration", thresholds: config.ExpirationThresholds{CriticalDuration: "0s"}},
+		{name: "percent above 100", thresholds: config.ExpirationThresholds{WarningPercent: 120}},
+		{name: "bad mount override", thresholds: config.ExpirationThresholds{Mounts: map[string]config.ExpirationThresholds{"pki": {CriticalDuration: "-1h"}}}},
+		{name: "empty mount key", thresholds: config.ExpirationThresholds{Mounts: map[string]config.ExpirationThresholds{" ": {}}}},
+		{name: "nested mounts", thresholds: config.ExpirationThresholds{Mounts: map[string]config.ExpirationThresholds{"pki": {Mounts: map[string]config.ExpirationThresholds{"x": {}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExpiryThresholds(tt.thresholds, nil)
+			assert.Error(t, err)
+		})
+	}
+}
 
-	warning, critical := CountExpiring(certs, 0, 0, now)
-	assert.Equal(t, 0, warning)
-	assert.Equal(t, 0, critical)
+func TestParseThresholdDuration(t *testing.T) {
+	duration, err := ParseThresholdDuration("3d")
+	require.NoError(t, err)
+	assert.Equal(t, 72*time.Hour, duration)
+	duration, err = ParseThresholdDuration(" 90m ")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, duration)
+	_, err = ParseThresholdDuration("1.5d")
+	assert.Error(t, err)
 }
 
-func TestCountExpiring_Empty(t *testing.T) {
-	warning, critical := CountExpiring(nil, 30, 7, time.Now())
-	assert.Equal(t, 0, warning)
-	assert.Equal(t, 0, critical)
+func TestExpiryWindow_Describe(t *testing.T) {
+	window := ExpiryWindow{Critical: 6 * time.Hour, Warning: 30 * 24 * time.Hour, WarningPercent: 20}
+	assert.Equal(t, "6h0m0s", window.Describe(ExpiryTierCritical))
+	assert.Equal(t, "30 days or 20% of lifetime", window.Describe(ExpiryTierWarning))
+	assert.Empty(t, ExpiryWindow{}.Describe(ExpiryTierWarning))
+}
+
+func TestExpiryWindow_Lead(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := ExpiryWindow{Critical: 6 * time.Hour, Warning: 30 * 24 * time.Hour, WarningPercent: 50, CriticalPercent: 1}
+	// 100-day certificate: half its lifetime beats the 30-day warning; 1%
+	// (24h) beats the 6h critical duration.
+	long := Certificate{CreatedAt: now, ExpiresAt: now.Add(100 * 24 * time.Hour)}
+	warning, critical := window.Lead(long)
+	assert.Equal(t, 50*24*time.Hour, warning)
+	assert.Equal(t, 24*time.Hour, critical)
+
+	warning, critical = window.Lead(Certificate{ExpiresAt: now})
+	assert.Equal(t, 30*24*time.Hour, warning)
+	assert.Equal(t, 6*time.Hour, critical)
+
+	warning, critical = ExpiryWindow{}.Lead(long)
+	assert.Zero(t, warning)
+	assert.Zero(t, critical)
 }
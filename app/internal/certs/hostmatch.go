@@ -0,0 +1,151 @@
+package certs
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// ErrInvalidHost is returned by NormalizeHost for input that is neither a DNS
+// name nor an IP address.
+var ErrInvalidHost = errors.New("invalid host")
+
+// HostMatch is one certificate covering a looked-up host. MatchedName is the
+// SAN (or common name) that matched, e.g. "*.example.com".
+type HostMatch struct {
+	Certificate Certificate
+	MatchedName string
+	Wildcard    bool
+}
+
+// HostIndex maps the DNS names and IP addresses presented by certificates to
+// the certificates presenting them, following RFC 6125 section 6.4:
+//
+//   - DNS names compare case-insensitively, ignoring a trailing dot.
+//   - A wildcard is only honored as the whole left-most label ("*.example.com")
+//     with at least two labels after it, and covers exactly one label:
+//     "*.example.com" covers "api.example.com" but not "example.com" or
+//     "a.b.example.com". Partial-label wildcards ("api*.example.com") are not
+//     honored, matching browser behavior.
+//   - IP addresses only match IP SANs, never wildcards or DNS names.
+//   - The common name is used only when the certificate has no DNS or IP SAN.
+type HostIndex struct {
+	certificates []Certificate
+	exact        map[string][]indexedName
+	wildcard     map[string][]indexedName
+}
+
+type indexedName struct {
+	certificate int
+	name        string
+}
+
+// NewHostIndex indexes the names of every certificate.
+func NewHostIndex(certificates []Certificate) *HostIndex {
+	index := &HostIndex{certificates: certificates, exact: make(map[string][]indexedName), wildcard: make(map[string][]indexedName)}
+	for i, certificate := range certificates {
+		names := presentedNames(certificate)
+		seen := make(map[string]struct{}, len(names))
+		for _, name := range names {
+			key := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+			if _, dup := seen[key]; dup || key == "" {
+				continue
+			}
+			seen[key] = struct{}{}
+			if addr, err := netip.ParseAddr(key); err == nil {
+				index.exact[addr.Unmap().String()] = append(index.exact[addr.Unmap().String()], indexedName{certificate: i, name: name})
+				continue
+			}
+			if parent, ok := strings.CutPrefix(key, "*."); ok {
+				if strings.Contains(parent, "*") || strings.Count(parent, ".") < 1 {
+					continue
+				}
+				index.wildcard[parent] = append(index.wildcard[parent], indexedName{certificate: i, name: name})
+				continue
+			}
+			if strings.Contains(key, "*") {
+				continue
+			}
+			index.exact[key] = append(index.exact[key], indexedName{certificate: i, name: name})
+		}
+	}
+	return index
+}
+
+// Lookup returns the certificates covering host, which must come from
+// NormalizeHost. Exact matches come before wildcard matches; each
+// certificate appears once.
+func (index *HostIndex) Lookup(host string) []HostMatch {
+	matches := make([]HostMatch, 0)
+	seen := make(map[int]struct{})
+	add := func(entries []indexedName, wildcard bool) {
+		for _, entry := range entries {
+			if _, dup := seen[entry.certificate]; dup {
+				continue
+			}
+			seen[entry.certificate] = struct{}{}
+			matches = append(matches, HostMatch{Certificate: index.certificates[entry.certificate], MatchedName: entry.name, Wildcard: wildcard})
+		}
+	}
+	add(index.exact[host], false)
+	if _, err := netip.ParseAddr(host); err != nil {
+		if _, parent, ok := strings.Cut(host, "."); ok && parent != "" {
+			add(index.wildcard[parent], true)
+		}
+	}
+	return matches
+}
+
+// NormalizeHost accepts a host name, an IP address, "host:port" or a URL and
+// returns the lower-case name (or canonical IP) to look up.
+func NormalizeHost(input string) (string, error) {
+	host := strings.TrimSpace(input)
+	if scheme := strings.Index(host, "://"); scheme >= 0 {
+		host = host[scheme+3:]
+	}
+	if end := strings.IndexAny(host, "/?#"); end >= 0 {
+		host = host[:end]
+	}
+	if _, after, ok := strings.Cut(host, "@"); ok {
+		host = after
+	}
+	if splitHost, _, err := net.SplitHostPort(host); err == nil {
+		host = splitHost
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.Unmap().String(), nil
+	}
+	host = strings.ToLower(host)
+	if host == "" || len(host) > 253 {
+		return "", ErrInvalidHost
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return "", ErrInvalidHost
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return "", ErrInvalidHost
+			}
+		}
+	}
+	return host, nil
+}
+
+// presentedNames returns the DNS and IP SANs, or the common name when there
+// are none. Email SANs are ignored.
+func presentedNames(certificate Certificate) []string {
+	names := make([]string, 0, len(certificate.Sans))
+	for _, san := range certificate.Sans {
+		if strings.Contains(san, "@") {
+			continue
+		}
+		names = append(names, san)
+	}
+	if len(names) == 0 && certificate.CommonName != "" {
+		names = append(names, certificate.CommonName)
+	}
+	return names
+}
@@ -0,0 +1,62 @@
+package certs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "API.Example.com.", want: "api.example.com"},
+		{input: "https://api.example.com:8443/health?x=1", want: "api.example.com"},
+		{input: "api.example.com:443", want: "api.example.com"},
+		{input: "[2001:DB8::1]:443", want: "2001:db8::1"},
+		{input: "::ffff:10.0.0.1", want: "10.0.0.1"},
+		{input: "10.0.0.1", want: "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeHost(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+	for _, input := range []string{"", "  ", "*.example.com", "api..example.com", "api example.com"} {
+		_, err := NormalizeHost(input)
+		assert.ErrorIs(t, err, ErrInvalidHost, input)
+	}
+}
+
+func TestHostIndex_Lookup(t *testing.T) {
+	certificates := []Certificate{
+		{ID: "exact", CommonName: "api.example.com", Sans: []string{"API.example.com."}},
+		{ID: "wildcard", CommonName: "wildcard", Sans: []string{"*.example.com", "ops@example.com"}},
+		{ID: "tld-wildcard", Sans: []string{"*.com"}},
+		{ID: "partial", Sans: []string{"api*.example.com"}},
+		{ID: "ip", CommonName: "api.example.com", Sans: []string{"10.0.0.1", "2001:db8::1"}},
+		{ID: "cn-only", CommonName: "legacy.example.com"},
+		{ID: "cn-ignored", CommonName: "db.example.com", Sans: []string{"db-1.example.com"}},
+	}
+	index := NewHostIndex(certificates)
+	ids := func(host string) []string {
+		result := make([]string, 0)
+		for _, match := range index.Lookup(host) {
+			result = append(result, match.Certificate.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"exact", "wildcard"}, ids("api.example.com"))
+	matches := index.Lookup("api.example.com")
+	assert.Equal(t, "API.example.com.", matches[0].MatchedName)
+	assert.True(t, matches[1].Wildcard)
+	assert.Empty(t, ids("example.com"), "wildcard does not cover the parent domain")
+	assert.Empty(t, ids("a.b.example.com"), "wildcard covers exactly one label")
+	assert.Equal(t, []string{"ip"}, ids("10.0.0.1"))
+	assert.Equal(t, []string{"ip"}, ids("2001:db8::1"))
+	assert.Equal(t, []string{"cn-only", "wildcard"}, ids("legacy.example.com"))
+	assert.Equal(t, []string{"wildcard"}, ids("db.example.com"), "CN is ignored when SANs are present")
+}
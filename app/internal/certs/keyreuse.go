@@ -0,0 +1,77 @@
+package certs
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyReuseGroup is a set of certificates issued for the same public key.
+type KeyReuseGroup struct {
+	Fingerprint  string        `json:"fingerprint"`
+	KeyAlgorithm string        `json:"keyAlgorithm,omitempty"`
+	KeySize      int           `json:"keySize,omitempty"`
+	Certificates []Certificate `json:"certificates"`
+}
+
+// FindKeyReuse groups certificates by PublicKeyFingerprint and returns only
+// the groups holding more than one certificate. Certificates without a
+// fingerprint are ignored. Groups are ordered by size (largest first), then
+// fingerprint; certificates inside a group keep the input order.
+func FindKeyReuse(certificates []Certificate) []KeyReuseGroup {
+	byFingerprint := make(map[string][]Certificate)
+	for _, certificate := range certificates {
+		fingerprint := strings.TrimSpace(certificate.PublicKeyFingerprint)
+		if fingerprint == "" {
+			continue
+		}
+		byFingerprint[fingerprint] = append(byFingerprint[fingerprint], certificate)
+	}
+	groups := make([]KeyReuseGroup, 0)
+	for fingerprint, members := range byFingerprint {
+		if len(members) < 2 {
+			continue
+		}
+		groups = append(groups, KeyReuseGroup{
+			Fingerprint:  fingerprint,
+			KeyAlgorithm: members[0].KeyAlgorithm,
+			KeySize:      members[0].KeySize,
+			Certificates: members,
+		})
+	}
+	sort.Slice(groups, func(left, right int) bool {
+		if len(groups[left].Certificates) != len(groups[right].Certificates) {
+			return len(groups[left].Certificates) > len(groups[right].Certificates)
+		}
+		return groups[left].Fingerprint < groups[right].Fingerprint
+	})
+	return groups
+}
+
+// AnnotateSharedKeys returns a copy of certificates with SharedKeyWith set to
+// the IDs of every other certificate carrying the same public key. The input
+// slice is not modified.
+func AnnotateSharedKeys(certificates []Certificate) []Certificate {
+	idsByFingerprint := make(map[string][]string)
+	for _, certificate := range certificates {
+		if certificate.PublicKeyFingerprint == "" {
+			continue
+		}
+		idsByFingerprint[certificate.PublicKeyFingerprint] = append(idsByFingerprint[certificate.PublicKeyFingerprint], certificate.ID)
+	}
+	annotated := make([]Certificate, len(certificates))
+	for index, certificate := range certificates {
+		certificate.SharedKeyWith = nil
+		ids := idsByFingerprint[certificate.PublicKeyFingerprint]
+		if certificate.PublicKeyFingerprint != "" && len(ids) > 1 {
+			shared := make([]string, 0, len(ids)-1)
+			for _, id := range ids {
+				if id != certificate.ID {
+					shared = append(shared, id)
+				}
+			}
+			certificate.SharedKeyWith = shared
+		}
+		annotated[index] = certificate
+	}
+	return annotated
+}
@@ -0,0 +1,79 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyFingerprint_SameKeyMatches(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issue := func(serial int64) *x509.Certificate {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "reuse.example.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, createErr := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		require.NoError(t, createErr)
+		parsed, parseErr := x509.ParseCertificate(der)
+		require.NoError(t, parseErr)
+		return parsed
+	}
+
+	first := PublicKeyFingerprint(issue(1))
+	second := PublicKeyFingerprint(issue(2))
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, second)
+	assert.Empty(t, PublicKeyFingerprint(nil))
+}
+
+func TestFindKeyReuse(t *testing.T) {
+	certificates := []Certificate{
+		{ID: "vault-a|pki:1", PublicKeyFingerprint: "aa", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "vault-b|pki:2", PublicKeyFingerprint: "aa", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "vault-a|pki:3", PublicKeyFingerprint: "bb"},
+		{ID: "vault-a|pki_int:4", PublicKeyFingerprint: "cc"},
+		{ID: "vault-a|pki_int:5", PublicKeyFingerprint: "cc"},
+		{ID: "vault-a|pki_int:6", PublicKeyFingerprint: "cc"},
+		{ID: "vault-a|pki:7"},
+		{ID: "vault-a|pki:8"},
+	}
+
+	groups := FindKeyReuse(certificates)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "cc", groups[0].Fingerprint)
+	assert.Len(t, groups[0].Certificates, 3)
+	assert.Equal(t, "aa", groups[1].Fingerprint)
+	assert.Equal(t, "RSA", groups[1].KeyAlgorithm)
+	assert.Equal(t, 2048, groups[1].KeySize)
+	assert.Empty(t, FindKeyReuse(nil))
+}
+
+func TestAnnotateSharedKeys(t *testing.T) {
+	certificates := []Certificate{
+		{ID: "a", PublicKeyFingerprint: "aa"},
+		{ID: "b", PublicKeyFingerprint: "aa"},
+		{ID: "c", PublicKeyFingerprint: "aa"},
+		{ID: "d", PublicKeyFingerprint: "dd"},
+		{ID: "e"},
+	}
+
+	annotated := AnnotateSharedKeys(certificates)
+	require.Len(t, annotated, 5)
+	assert.Equal(t, []string{"b", "c"}, annotated[0].SharedKeyWith)
+	assert.Equal(t, []string{"a", "c"}, annotated[1].SharedKeyWith)
+	assert.Empty(t, annotated[3].SharedKeyWith)
+	assert.Empty(t, annotated[4].SharedKeyWith)
+	assert.Nil(t, certificates[0].SharedKeyWith)
+}
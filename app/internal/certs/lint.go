@@ -0,0 +1,114 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Severities of a LintFinding.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+// maxLeafValidity is the CA/Browser Forum limit on the lifetime of a
+// publicly trusted TLS certificate.
+const maxLeafValidity = 398 * 24 * time.Hour
+
+// minSerialBits is the serial number entropy the CA/Browser Forum requires.
+const minSerialBits = 64
+
+// LintFinding is one rule a certificate or request does not follow.
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// weakSignatureAlgorithms rely on MD2, MD5 or SHA-1.
+var weakSignatureAlgorithms = []x509.SignatureAlgorithm{
+	x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1,
+}
+
+// LintCertificate checks a certificate against common PKI hygiene rules:
+// validity at now, key strength, signature algorithm, SAN and key usage
+// consistency, lifetime and serial number length. Findings are ordered by
+// rule, not severity.
+func LintCertificate(certificate *x509.Certificate, now time.Time) []LintFinding {
+	findings := []LintFinding{}
+	switch {
+	case now.After(certificate.NotAfter):
+		findings = append(findings, LintFinding{Rule: "expired", Severity: LintError, Message: "expired on " + certificate.NotAfter.UTC().Format(time.RFC3339)})
+	case now.Before(certificate.NotBefore):
+		findings = append(findings, LintFinding{Rule: "not_yet_valid", Severity: LintWarning, Message: "not valid before " + certificate.NotBefore.UTC().Format(time.RFC3339)})
+	}
+	algorithm, keySize := KeyAlgoAndSize(certificate)
+	findings = append(findings, lintKeyAndSignature(algorithm, keySize, certificate.SignatureAlgorithm)...)
+	if !certificate.IsCA {
+		findings = append(findings, lintNames(certificate.Subject.CommonName, certificate.DNSNames, certificate.IPAddresses, certificate.EmailAddresses, len(certificate.URIs))...)
+		if lifetime := certificate.NotAfter.Sub(certificate.NotBefore); lifetime > maxLeafValidity {
+			findings = append(findings, LintFinding{Rule: "long_validity", Severity: LintWarning, Message: fmt.Sprintf("valid for %d days, more than the 398 days browsers accept", int(lifetime/(24*time.Hour)))})
+		}
+		if certificate.KeyUsage&x509.KeyUsageCertSign != 0 {
+			findings = append(findings, LintFinding{Rule: "leaf_cert_sign", Severity: LintWarning, Message: "keyCertSign usage on a certificate that is not a CA"})
+		}
+		if bytes.Equal(certificate.RawIssuer, certificate.RawSubject) && certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature) == nil {
+			findings = append(findings, LintFinding{Rule: "self_signed", Severity: LintInfo, Message: "self-signed certificate that is not a CA"})
+		}
+	} else if certificate.KeyUsage&x509.KeyUsageCertSign == 0 {
+		findings = append(findings, LintFinding{Rule: "ca_missing_cert_sign", Severity: LintWarning, Message: "CA certificate without the keyCertSign usage"})
+	}
+	if certificate.SerialNumber == nil || certificate.SerialNumber.BitLen() < minSerialBits {
+		findings = append(findings, LintFinding{Rule: "short_serial", Severity: LintWarning, Message: "serial number shorter than 64 bits"})
+	}
+	return findings
+}
+
+// LintCertificateRequest checks a certificate request's signature, key
+// strength, signature algorithm and names.
+func LintCertificateRequest(request *x509.CertificateRequest) []LintFinding {
+	findings := []LintFinding{}
+	if err := request.CheckSignature(); err != nil {
+		findings = append(findings, LintFinding{Rule: "invalid_signature", Severity: LintError, Message: "the request signature does not verify: " + err.Error()})
+	}
+	algorithm, keySize := publicKeyAlgoAndSize(request.PublicKey, request.PublicKeyAlgorithm)
+	findings = append(findings, lintKeyAndSignature(algorithm, keySize, request.SignatureAlgorithm)...)
+	findings = append(findings, lintNames(request.Subject.CommonName, request.DNSNames, request.IPAddresses, request.EmailAddresses, len(request.URIs))...)
+	return findings
+}
+
+func lintKeyAndSignature(algorithm string, keySize int, signature x509.SignatureAlgorithm) []LintFinding {
+	var findings []LintFinding
+	if (algorithm == "RSA" && keySize < 2048) || (algorithm == "ECDSA" && keySize < 256) {
+		findings = append(findings, LintFinding{Rule: "weak_key", Severity: LintError, Message: fmt.Sprintf("%s key of %d bits", algorithm, keySize)})
+	}
+	if slices.Contains(weakSignatureAlgorithms, signature) {
+		findings = append(findings, LintFinding{Rule: "weak_signature", Severity: LintError, Message: "signed with " + signature.String()})
+	}
+	return findings
+}
+
+// lintNames checks that an end-entity names its subject in SANs: clients
+// ignore the common name once SANs are present, and most ignore it
+// altogether.
+func lintNames(commonName string, dnsNames []string, addresses []net.IP, emails []string, uris int) []LintFinding {
+	names := subjectAltNames(dnsNames, addresses, emails)
+	if len(names) == 0 && uris == 0 {
+		return []LintFinding{{Rule: "missing_san", Severity: LintWarning, Message: "no subject alternative name"}}
+	}
+	if commonName == "" {
+		return nil
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, commonName) {
+			return nil
+		}
+	}
+	return []LintFinding{{Rule: "cn_not_in_san", Severity: LintWarning, Message: "common name " + commonName + " is not among the subject alternative names"}}
+}
@@ -0,0 +1,95 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintRules(findings []LintFinding) []string {
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.Rule)
+	}
+	return rules
+}
+
+func TestLintCertificate(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	require.NoError(t, err)
+	serial.SetBit(serial, 120, 1)
+
+	clean := issueForComparison(t, key, &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "api.example.com"},
+		DNSNames:     []string{"api.example.com"},
+		NotBefore:    now.Add(-24 * time.Hour),
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	})
+	// Self-signed leaves are reported as such; nothing else is wrong.
+	assert.Equal(t, []string{"self_signed"}, lintRules(LintCertificate(clean, now)))
+
+	sloppy := issueForComparison(t, weakKey, &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "old.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    now.Add(-800 * 24 * time.Hour),
+		NotAfter:     now.Add(-24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	})
+	assert.Equal(t, []string{"expired", "weak_key", "cn_not_in_san", "long_validity", "leaf_cert_sign", "self_signed", "short_serial"}, lintRules(LintCertificate(sloppy, now)))
+
+	ca := issueForComparison(t, key, &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Example Root"},
+		NotBefore:             now.Add(time.Hour),
+		NotAfter:              now.Add(3650 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+	})
+	assert.Equal(t, []string{"not_yet_valid", "ca_missing_cert_sign"}, lintRules(LintCertificate(ca, now)))
+}
+
+func TestLintCertificateRequest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "app.example.com"},
+	}, key)
+	require.NoError(t, err)
+	request, err := x509.ParseCertificateRequest(der)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"missing_san"}, lintRules(LintCertificateRequest(request)))
+
+	view := NewCertificateRequest(request, "pem")
+	assert.Equal(t, "app.example.com", view.CommonName)
+	assert.Equal(t, "ECDSA", view.KeyAlgorithm)
+	assert.Equal(t, 256, view.KeySize)
+	assert.Empty(t, view.Sans)
+
+	request.Signature[len(request.Signature)-1] ^= 0xff
+	assert.Contains(t, lintRules(LintCertificateRequest(request)), "invalid_signature")
+}
+
+func TestFormatSerial(t *testing.T) {
+	assert.Equal(t, "01:00:ff", FormatSerial(&x509.Certificate{SerialNumber: big.NewInt(0x0100ff)}))
+	assert.Equal(t, "00", FormatSerial(&x509.Certificate{SerialNumber: big.NewInt(0)}))
+	assert.Equal(t, "", FormatSerial(&x509.Certificate{}))
+}
@@ -0,0 +1,113 @@
+package certs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"vcv/internal/config"
+)
+
+type mountPolicy struct {
+	hideExpiredAfter time.Duration
+	rollupLifetime   time.Duration
+	excludeMetrics   bool
+}
+
+// MountPolicies applies config.MountPolicy entries to listed certificates.
+// A nil *MountPolicies leaves certificates as-is.
+type MountPolicies struct {
+	policies map[string]mountPolicy
+}
+
+// NewMountPolicies compiles policies keyed by bare mount name or
+// "vault_id|mount". It fails on an empty key, negative days or an invalid
+// rollup lifetime, naming the key.
+func NewMountPolicies(policies map[string]config.MountPolicy) (*MountPolicies, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	compiled := &MountPolicies{policies: make(map[string]mountPolicy, len(policies))}
+	for key, policy := range policies {
+		mountKey := strings.TrimSpace(key)
+		if mountKey == "" {
+			return nil, fmt.Errorf("mount policy: empty mount key")
+		}
+		if policy.HideExpiredAfterDays < 0 {
+			return nil, fmt.Errorf("mount policy %q: hide_expired_after_days must not be negative", key)
+		}
+		entry := mountPolicy{
+			hideExpiredAfter: time.Duration(policy.HideExpiredAfterDays) * 24 * time.Hour,
+			excludeMetrics:   policy.ExcludeFromPerCertificateMetrics,
+		}
+		if strings.TrimSpace(policy.RollupLifetime) != "" {
+			lifetime, err := ParseThresholdDuration(policy.RollupLifetime)
+			if err != nil {
+				return nil, fmt.Errorf("mount policy %q: rollup_lifetime: %w", key, err)
+			}
+			entry.rollupLifetime = lifetime
+		}
+		compiled.policies[mountKey] = entry
+	}
+	return compiled, nil
+}
+
+func (policies *MountPolicies) lookup(id string) (mountPolicy, bool) {
+	if policies == nil {
+		return mountPolicy{}, false
+	}
+	vaultID, mount := VaultAndMount(id)
+	if mount == "" {
+		return mountPolicy{}, false
+	}
+	if vaultID != "" {
+		if policy, ok := policies.policies[vaultID+"|"+mount]; ok {
+			return policy, true
+		}
+	}
+	policy, ok := policies.policies[mount]
+	return policy, ok
+}
+
+// Apply drops long-expired certificates, collapses short-lived ones into one
+// row per vault, mount and common name - the one expiring last, with
+// RollupCount set to the group size - and flags metric exclusions. The input
+// order of the remaining rows is preserved and the input is not modified.
+func (policies *MountPolicies) Apply(certificates []Certificate, now time.Time) []Certificate {
+	if policies == nil || len(policies.policies) == 0 {
+		return certificates
+	}
+	kept := make([]Certificate, 0, len(certificates))
+	rollupIndex := make(map[string]int)
+	for _, certificate := range certificates {
+		policy, ok := policies.lookup(certificate.ID)
+		if !ok {
+			kept = append(kept, certificate)
+			continue
+		}
+		if policy.hideExpiredAfter > 0 && !certificate.ExpiresAt.IsZero() && certificate.ExpiresAt.Before(now.Add(-policy.hideExpiredAfter)) {
+			continue
+		}
+		certificate.ExcludeFromPerCertificateMetrics = policy.excludeMetrics
+		if policy.rollupLifetime <= 0 || certificate.CreatedAt.IsZero() || certificate.ExpiresAt.IsZero() ||
+			certificate.ExpiresAt.Sub(certificate.CreatedAt) >= policy.rollupLifetime {
+			kept = append(kept, certificate)
+			continue
+		}
+		vaultID, mount := VaultAndMount(certificate.ID)
+		key := vaultID + "|" + mount + "|" + certificate.CommonName
+		index, seen := rollupIndex[key]
+		if !seen {
+			certificate.RollupCount = 1
+			rollupIndex[key] = len(kept)
+			kept = append(kept, certificate)
+			continue
+		}
+		count := kept[index].RollupCount + 1
+		if certificate.ExpiresAt.After(kept[index].ExpiresAt) {
+			kept[index] = certificate
+		}
+		kept[index].RollupCount = count
+	}
+	return kept
+}
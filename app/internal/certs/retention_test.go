@@ -0,0 +1,87 @@
+package certs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+)
+
+func TestNewMountPolicies_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]config.MountPolicy
+	}{
+		{name: "empty key", policies: map[string]config.MountPolicy{" ": {}}},
+		{name: "negative days", policies: map[string]config.MountPolicy{"pki": {HideExpiredAfterDays: -1}}},
+		{name: "bad lifetime", policies: map[string]config.MountPolicy{"pki": {RollupLifetime: "short"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMountPolicies(tt.policies)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMountPolicies_NilIsPassthrough(t *testing.T) {
+	policies, err := NewMountPolicies(nil)
+	require.NoError(t, err)
+	certificates := []Certificate{{ID: "v|pki:1"}}
+	assert.Equal(t, certificates, policies.Apply(certificates, time.Now()))
+}
+
+func TestMountPolicies_HideExpired(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	policies, err := NewMountPolicies(map[string]config.MountPolicy{"pki_mesh": {HideExpiredAfterDays: 2}})
+	require.NoError(t, err)
+	certificates := []Certificate{
+		{ID: "v|pki_mesh:old", ExpiresAt: now.Add(-3 * 24 * time.Hour)},
+		{ID: "v|pki_mesh:recent", ExpiresAt: now.Add(-24 * time.Hour)},
+		{ID: "v|pki:old", ExpiresAt: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	got := policies.Apply(certificates, now)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "v|pki_mesh:recent", got[0].ID)
+	assert.Equal(t, "v|pki:old", got[1].ID)
+}
+
+func TestMountPolicies_RollupByCommonName(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	policies, err := NewMountPolicies(map[string]config.MountPolicy{
+		"vault-a|pki_mesh": {RollupLifetime: "3d", ExcludeFromPerCertificateMetrics: true},
+	})
+	require.NoError(t, err)
+	shortLived := func(id, cn string, issued time.Time) Certificate {
+		return Certificate{ID: id, CommonName: cn, CreatedAt: issued, ExpiresAt: issued.Add(72*time.Hour - time.Minute)}
+	}
+	certificates := []Certificate{
+		shortLived("vault-a|pki_mesh:1", "svc-a", now.Add(-48*time.Hour)),
+		shortLived("vault-a|pki_mesh:2", "svc-a", now.Add(-2*time.Hour)),
+		shortLived("vault-a|pki_mesh:3", "svc-a", now.Add(-24*time.Hour)),
+		shortLived("vault-a|pki_mesh:4", "svc-b", now.Add(-time.Hour)),
+		{ID: "vault-a|pki_mesh:5", CommonName: "svc-a", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(365 * 24 * time.Hour)},
+		shortLived("vault-b|pki_mesh:6", "svc-a", now.Add(-48*time.Hour)),
+		shortLived("vault-b|pki_mesh:7", "svc-a", now.Add(-2*time.Hour)),
+	}
+
+	got := policies.Apply(certificates, now)
+
+	require.Len(t, got, 5)
+	assert.Equal(t, "vault-a|pki_mesh:2", got[0].ID)
+	assert.Equal(t, 3, got[0].RollupCount)
+	assert.True(t, got[0].ExcludeFromPerCertificateMetrics)
+	assert.Equal(t, "vault-a|pki_mesh:4", got[1].ID)
+	assert.Equal(t, 1, got[1].RollupCount)
+	assert.Equal(t, "vault-a|pki_mesh:5", got[2].ID)
+	assert.Zero(t, got[2].RollupCount)
+	assert.True(t, got[2].ExcludeFromPerCertificateMetrics)
+	assert.Equal(t, "vault-b|pki_mesh:6", got[3].ID)
+	assert.False(t, got[3].ExcludeFromPerCertificateMetrics)
+	assert.Zero(t, certificates[0].RollupCount)
+}
@@ -0,0 +1,50 @@
+package certs
+
+import (
+	"sort"
+	"strings"
+)
+
+// Superseded returns the IDs of certificates that a renewal has replaced:
+// another unrevoked certificate of the same vault, mount and common name
+// was issued after it and expires later. Certificates without a common name
+// or an issuance time cannot be paired and are never reported.
+func Superseded(certificates []Certificate) map[string]bool {
+	groups := make(map[string][]Certificate)
+	for _, certificate := range certificates {
+		commonName := strings.ToLower(strings.TrimSpace(certificate.CommonName))
+		if certificate.Revoked || commonName == "" || certificate.CreatedAt.IsZero() {
+			continue
+		}
+		vaultID, mount := VaultAndMount(certificate.ID)
+		key := vaultID + "|" + mount + "|" + commonName
+		groups[key] = append(groups[key], certificate)
+	}
+	superseded := make(map[string]bool)
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.SliceStable(members, func(left, right int) bool {
+			return members[left].CreatedAt.After(members[right].CreatedAt)
+		})
+		// Walk from the newest issuance back, tracking the latest expiry of
+		// the certificates issued strictly after the current one.
+		var latestNewer, latestSameTime Certificate
+		for index, member := range members {
+			if index > 0 && !member.CreatedAt.Equal(members[index-1].CreatedAt) {
+				if latestSameTime.ExpiresAt.After(latestNewer.ExpiresAt) {
+					latestNewer = latestSameTime
+				}
+				latestSameTime = Certificate{}
+			}
+			if latestNewer.ExpiresAt.After(member.ExpiresAt) {
+				superseded[member.ID] = true
+			}
+			if member.ExpiresAt.After(latestSameTime.ExpiresAt) {
+				latestSameTime = member
+			}
+		}
+	}
+	return superseded
+}
@@ -0,0 +1,32 @@
+package certs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuperseded(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	certificates := []Certificate{
+		{ID: "v1|pki:01", CommonName: "app.example.com", CreatedAt: now.AddDate(0, -11, 0), ExpiresAt: now.AddDate(0, 1, 0)},
+		{ID: "v1|pki:02", CommonName: "APP.example.com", CreatedAt: now.AddDate(0, -1, 0), ExpiresAt: now.AddDate(0, 11, 0)},
+		// Issued later but expiring sooner: not a renewal of 02.
+		{ID: "v1|pki:03", CommonName: "app.example.com", CreatedAt: now, ExpiresAt: now.AddDate(0, 0, 7)},
+		// Other mount, vault, revoked successor, unknown issuance, no name.
+		{ID: "v1|pki_int:04", CommonName: "app.example.com", CreatedAt: now.AddDate(0, -11, 0), ExpiresAt: now.AddDate(0, 1, 0)},
+		{ID: "v2|pki:05", CommonName: "app.example.com", CreatedAt: now.AddDate(0, -11, 0), ExpiresAt: now.AddDate(0, 1, 0)},
+		{ID: "v1|pki:06", CommonName: "db.example.com", CreatedAt: now.AddDate(0, -11, 0), ExpiresAt: now.AddDate(0, 1, 0)},
+		{ID: "v1|pki:07", CommonName: "db.example.com", CreatedAt: now, ExpiresAt: now.AddDate(1, 0, 0), Revoked: true},
+		{ID: "v1|pki:08", CommonName: "app.example.com", ExpiresAt: now.AddDate(0, 0, 1)},
+		{ID: "v1|pki:09", CreatedAt: now.AddDate(0, -11, 0), ExpiresAt: now.AddDate(0, 0, 1)},
+		{ID: "v1|pki:10", CreatedAt: now, ExpiresAt: now.AddDate(1, 0, 0)},
+		// Same issuance time: neither replaces the other.
+		{ID: "v1|pki:11", CommonName: "twin.example.com", CreatedAt: now, ExpiresAt: now.AddDate(0, 1, 0)},
+		{ID: "v1|pki:12", CommonName: "twin.example.com", CreatedAt: now, ExpiresAt: now.AddDate(0, 2, 0)},
+	}
+
+	assert.Equal(t, map[string]bool{"v1|pki:01": true}, Superseded(certificates))
+	assert.Empty(t, Superseded(nil))
+}
@@ -36,8 +36,18 @@ type Config struct {
 	Vaults               []VaultInstance
 	AllVaults            []VaultInstance
 	ExpirationThresholds ExpirationThresholds
-	Metrics              MetricsConfig
-	Notifications        NotificationsConfig
+	// ClassificationRules assign custom certificate types ahead of the
+	// built-in EKU inference. Evaluated in order; the first match wins.
+	ClassificationRules []ClassificationRule
+	// MountPolicies trims noisy mounts before certificates reach handlers,
+	// metrics and the notifier. Keyed by bare mount name or "vault_id|mount".
+	MountPolicies map[string]MountPolicy
+	Metrics       MetricsConfig
+	Notifications NotificationsConfig
+	Probes        ProbeSettings
+	Discovery     DiscoverySettings
+	History       HistorySettings
+	Journal       JournalSettings
 }
 
 // CORSConfig holds CORS-specific configuration.
@@ -57,10 +67,33 @@ type VaultConfig struct {
 	TLSInsecure     bool
 }
 
-// ExpirationThresholds holds certificate expiration alert thresholds (in days).
+// ExpirationThresholds holds certificate expiration alert thresholds.
+// Critical and Warning are whole days; CriticalDuration and WarningDuration
+// ("6h", "3d") take precedence over them when set. The percent fields flag a
+// certificate once its remaining lifetime drops to that share of its total
+// lifetime, in addition to the time-based window. Mounts overrides the whole
+// set for a bare mount name or a "vault_id|mount" key.
 type ExpirationThresholds struct {
-	Critical int `json:"critical"`
-	Warning  int `json:"warning"`
+	Critical         int                             `json:"critical"`
+	Warning          int                             `json:"warning"`
+	CriticalDuration string                          `json:"critical_duration,omitempty"`
+	WarningDuration  string                          `json:"warning_duration,omitempty"`
+	CriticalPercent  float64                         `json:"critical_percent,omitempty"`
+	WarningPercent   float64                         `json:"warning_percent,omitempty"`
+	Mounts           map[string]ExpirationThresholds `json:"mounts,omitempty"`
+}
+
+// WithDefaults returns thresholds with the 7-day critical and 30-day
+// warning defaults applied. A default only applies to a level with no days,
+// duration or percentage, so a percent-only level is not silently widened.
+func (thresholds ExpirationThresholds) WithDefaults() ExpirationThresholds {
+	if thresholds.Critical <= 0 && strings.TrimSpace(thresholds.CriticalDuration) == "" && thresholds.CriticalPercent <= 0 {
+		thresholds.Critical = 7
+	}
+	if thresholds.Warning <= 0 && strings.TrimSpace(thresholds.WarningDuration) == "" && thresholds.WarningPercent <= 0 {
+		thresholds.Warning = 30
+	}
+	return thresholds
 }
 
 // MetricsConfig holds metrics collection configuration.
@@ -75,6 +108,8 @@ type NotificationsConfig struct {
 	// WebhookURL receives a JSON POST when a certificate crosses into the
 	// warning or critical expiration window. Empty disables webhook delivery.
 	WebhookURL string
+	// Query limits which certificates count toward notifications.
+	Query string
 }
 
 type SettingsFile struct {
@@ -84,6 +119,10 @@ type SettingsFile struct {
 	Metrics       MetricsSettings      `json:"metrics"`
 	CORS          CORSSettings         `json:"cors"`
 	Notifications NotificationSettings `json:"notifications"`
+	Probes        ProbeSettings        `json:"probes"`
+	Discovery     DiscoverySettings    `json:"discovery"`
+	History       HistorySettings      `json:"history"`
+	Journal       JournalSettings      `json:"journal"`
 	Vaults        []VaultInstance      `json:"vaults"`
 }
 
@@ -107,7 +146,38 @@ type CORSSettings struct {
 }
 
 type CertificateSettings struct {
-	ExpirationThresholds ExpirationThresholds `json:"expiration_thresholds"`
+	ExpirationThresholds ExpirationThresholds   `json:"expiration_thresholds"`
+	ClassificationRules  []ClassificationRule   `json:"classification_rules,omitempty"`
+	MountPolicies        map[string]MountPolicy `json:"mount_policies,omitempty"`
+}
+
+// MountPolicy controls how a high-volume mount is presented.
+// HideExpiredAfterDays drops certificates that expired more than that many
+// days ago. RollupLifetime ("72h", "3d") collapses certificates whose total
+// lifetime is shorter than it into one row per common name, carrying the
+// number of certificates it stands for. ExcludeFromPerCertificateMetrics
+// keeps the mount out of the per-certificate series while aggregates still
+// count it.
+type MountPolicy struct {
+	HideExpiredAfterDays             int    `json:"hide_expired_after_days,omitempty"`
+	RollupLifetime                   string `json:"rollup_lifetime,omitempty"`
+	ExcludeFromPerCertificateMetrics bool   `json:"exclude_from_per_certificate_metrics,omitempty"`
+}
+
+// ClassificationRule assigns Type (and an optional display Label) to every
+// certificate matching all of its non-empty criteria. EKUs accepts dotted
+// OIDs or the short names serverAuth, clientAuth, codeSigning,
+// emailProtection, timeStamping, ocspSigning and any; every listed EKU must
+// be present. CommonName and SAN are regular expressions (SAN matches when
+// any SAN does). Mounts lists bare mount names or "vault_id|mount" keys.
+type ClassificationRule struct {
+	Type       string   `json:"type"`
+	Label      string   `json:"label,omitempty"`
+	EKUs       []string `json:"ekus,omitempty"`
+	IsCA       *bool    `json:"is_ca,omitempty"`
+	CommonName string   `json:"common_name,omitempty"`
+	SAN        string   `json:"san,omitempty"`
+	Mounts     []string `json:"mounts,omitempty"`
 }
 
 type MetricsSettings struct {
@@ -118,6 +188,66 @@ type MetricsSettings struct {
 
 type NotificationSettings struct {
 	WebhookURL string `json:"webhook_url"`
+	// Query restricts notifications to certificates matching a certificate
+	// query (see internal/certquery); empty means every certificate.
+	Query string `json:"query,omitempty"`
+}
+
+// ProbeSettings configures live TLS probing of deployed endpoints (see
+// internal/probe). Targets are probed as listed; SANPattern, a regular
+// expression, also probes every DNS SAN of a currently valid certificate
+// that matches it, on SANPort (443 by default). Interval ("15m") and
+// Timeout ("5s") are Go durations.
+type ProbeSettings struct {
+	Enabled    bool          `json:"enabled"`
+	Interval   string        `json:"interval,omitempty"`
+	Timeout    string        `json:"timeout,omitempty"`
+	Targets    []ProbeTarget `json:"targets,omitempty"`
+	SANPattern string        `json:"san_pattern,omitempty"`
+	SANPort    int           `json:"san_port,omitempty"`
+}
+
+// ProbeTarget is one endpoint to probe. Address is "host:port" (port 443
+// when omitted); ServerName is the SNI to send and defaults to the host.
+type ProbeTarget struct {
+	Address    string `json:"address"`
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// DiscoverySettings configures the network discovery scan (see
+// internal/probe). Every address of CIDRs is tried on every port of Ports
+// (443 by default), Concurrency connections at a time (32 by default); a
+// scan covers at most 65536 addresses. Interval defaults to "24h" and
+// Timeout, per connection, to "2s".
+type DiscoverySettings struct {
+	Enabled     bool     `json:"enabled"`
+	Interval    string   `json:"interval,omitempty"`
+	Timeout     string   `json:"timeout,omitempty"`
+	CIDRs       []string `json:"cidrs,omitempty"`
+	Ports       []int    `json:"ports,omitempty"`
+	Concurrency int      `json:"concurrency,omitempty"`
+}
+
+// HistorySettings configures the inventory snapshots (see
+// internal/history). A snapshot is taken every Interval ("1h" by default)
+// and stored in Dir, relative to the settings file ("history" by default).
+// Snapshots older than HourlyRetentionDays (30) are thinned to one per day
+// and those older than RetentionDays (365) are deleted.
+type HistorySettings struct {
+	Enabled             bool   `json:"enabled"`
+	Dir                 string `json:"dir,omitempty"`
+	Interval            string `json:"interval,omitempty"`
+	HourlyRetentionDays int    `json:"hourly_retention_days,omitempty"`
+	RetentionDays       int    `json:"retention_days,omitempty"`
+}
+
+// JournalSettings configures the persistent journal of inventory events (see
+// internal/events). Events are stored in Dir, relative to the settings file
+// ("journal" by default), and deleted after RetentionDays (365).
+type JournalSettings struct {
+	Enabled       bool   `json:"enabled"`
+	Dir           string `json:"dir,omitempty"`
+	RetentionDays int    `json:"retention_days,omitempty"`
 }
 
 type AdminSettings struct {
@@ -208,13 +338,7 @@ func buildConfigFromSettings(settings SettingsFile) Config {
 		cors.AllowedOrigins = settings.CORS.AllowedOrigins
 		cors.AllowCredentials = settings.CORS.AllowCredentials
 	}
-	expirations := ExpirationThresholds{Critical: 7, Warning: 30}
-	if settings.Certificates.ExpirationThresholds.Critical > 0 {
-		expirations.Critical = settings.Certificates.ExpirationThresholds.Critical
-	}
-	if settings.Certificates.ExpirationThresholds.Warning > 0 {
-		expirations.Warning = settings.Certificates.ExpirationThresholds.Warning
-	}
+	expirations := settings.Certificates.ExpirationThresholds.WithDefaults()
 	metrics := MetricsConfig{PerCertificate: false, EnhancedMetrics: true, PinnedCertificates: []string{}}
 	// Check if metrics section exists in settings (non-nil pointers)
 	if settings.Metrics.PerCertificate != nil {
@@ -227,7 +351,10 @@ func buildConfigFromSettings(settings SettingsFile) Config {
 		metrics.PinnedCertificates = settings.Metrics.PinnedCertificates
 	}
 	// Otherwise, keep defaults (PerCertificate: false, EnhancedMetrics: true)
-	notifications := NotificationsConfig{WebhookURL: strings.TrimSpace(settings.Notifications.WebhookURL)}
+	notifications := NotificationsConfig{
+		WebhookURL: strings.TrimSpace(settings.Notifications.WebhookURL),
+		Query:      strings.TrimSpace(settings.Notifications.Query),
+	}
 	return Config{
 		Env:                  env,
 		Port:                 port,
@@ -240,8 +367,14 @@ func buildConfigFromSettings(settings SettingsFile) Config {
 		Vault:                VaultConfig{},
 		Vaults:               []VaultInstance{},
 		ExpirationThresholds: expirations,
+		ClassificationRules:  settings.Certificates.ClassificationRules,
+		MountPolicies:        settings.Certificates.MountPolicies,
 		Metrics:              metrics,
 		Notifications:        notifications,
+		Probes:               settings.Probes,
+		Discovery:            settings.Discovery,
+		History:              settings.History,
+		Journal:              settings.Journal,
 	}
 }
 
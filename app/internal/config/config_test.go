@@ -320,3 +320,28 @@ func TestLoadCORSConfig(t *testing.T) {
 		t.Fatalf("expected allow credentials true for prod, got %v", prodConfig.AllowCredentials)
 	}
 }
+
+func TestBuildConfigFromSettings_DefaultDaysOnlyForUnsetLevels(t *testing.T) {
+	settings := SettingsFile{
+		Certificates: CertificateSettings{
+			ExpirationThresholds: ExpirationThresholds{
+				CriticalPercent: 10,
+				WarningDuration: "36h",
+			},
+		},
+	}
+
+	cfg := buildConfigFromSettings(settings)
+
+	if cfg.ExpirationThresholds.Critical != 0 {
+		t.Fatalf("expected no default critical days with a percentage set, got %d", cfg.ExpirationThresholds.Critical)
+	}
+	if cfg.ExpirationThresholds.Warning != 0 {
+		t.Fatalf("expected no default warning days with a duration set, got %d", cfg.ExpirationThresholds.Warning)
+	}
+
+	cfg = buildConfigFromSettings(SettingsFile{})
+	if cfg.ExpirationThresholds.Critical != 7 || cfg.ExpirationThresholds.Warning != 30 {
+		t.Fatalf("expected 7/30 defaults, got %+v", cfg.ExpirationThresholds)
+	}
+}
@@ -35,6 +35,9 @@ type VaultInstance struct {
 	TLSCAPath       string   `json:"tls_ca_path,omitempty"`
 	TLSServerName   string   `json:"tls_server_name,omitempty"`
 	Enabled         *bool    `json:"enabled,omitempty"`
+	// ExpirationThresholds overrides the global thresholds for this vault;
+	// its Mounts (bare mount names) override them per mount of this vault.
+	ExpirationThresholds *ExpirationThresholds `json:"expiration_thresholds,omitempty"`
 }
 
 func normalizeVaultInstances(instances []VaultInstance) ([]VaultInstance, error) {
@@ -125,18 +128,19 @@ func normalizeVaultInstance(instance VaultInstance) (VaultInstance, error) {
 		displayName = id
 	}
 	return VaultInstance{
-		ID:              id,
-		Address:         address,
-		Token:           token,
-		PKIMount:        pkiMount,
-		PKIMounts:       pkiMounts,
-		DisplayName:     displayName,
-		TLSInsecure:     instance.TLSInsecure,
-		TLSCACertBase64: tlsCACertBase64,
-		TLSCACert:       tlsCACert,
-		TLSCAPath:       tlsCAPath,
-		TLSServerName:   tlsServerName,
-		Enabled:         instance.Enabled,
+		ID:                   id,
+		Address:              address,
+		Token:                token,
+		PKIMount:             pkiMount,
+		PKIMounts:            pkiMounts,
+		DisplayName:          displayName,
+		TLSInsecure:          instance.TLSInsecure,
+		TLSCACertBase64:      tlsCACertBase64,
+		TLSCACert:            tlsCACert,
+		TLSCAPath:            tlsCAPath,
+		TLSServerName:        tlsServerName,
+		Enabled:              instance.Enabled,
+		ExpirationThresholds: instance.ExpirationThresholds,
 	}, nil
 }
 
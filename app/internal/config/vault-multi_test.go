@@ -388,3 +388,14 @@ func TestVaultPKIMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeVaultInstance_KeepsExpirationThresholds(t *testing.T) {
+	overrides := &ExpirationThresholds{Critical: 3, Warning: 10, Mounts: map[string]ExpirationThresholds{"pki_mtls": {CriticalDuration: "12h"}}}
+	normalized, err := normalizeVaultInstance(VaultInstance{ID: "v1", Address: "https://vault:8200", Token: "token", ExpirationThresholds: overrides})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.ExpirationThresholds != overrides {
+		t.Fatalf("expected expiration thresholds to be preserved, got %+v", normalized.ExpirationThresholds)
+	}
+}
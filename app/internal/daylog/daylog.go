@@ -0,0 +1,188 @@
+// Package daylog stores JSON Lines records in one file per UTC day, named
+// YYYY-MM-DD.ndjson, in a data directory. It backs the inventory history and
+// the event journal: records are appended to the file of their day, read back
+// day by day, and whole days are deleted once they fall out of retention.
+package daylog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	fileSuffix = ".ndjson"
+	dayLayout  = "2006-01-02"
+)
+
+// Store is a data directory of day files. It does not lock: callers
+// serialize writes and prunes.
+type Store struct {
+	dir     string
+	maxLine int
+}
+
+// Dir resolves a data directory for a settings file: a relative dir, or
+// defaultDir when dir is empty, is taken from the settings file's directory.
+func Dir(dir, defaultDir, settingsPath string) string {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = defaultDir
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(settingsPath), dir)
+}
+
+// Open creates dir when missing. maxLine bounds the length of one record
+// when reading.
+func Open(dir string, maxLine int) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, maxLine: maxLine}, nil
+}
+
+// Day returns the UTC day at falls on, the key of its file.
+func Day(at time.Time) time.Time {
+	return at.UTC().Truncate(24 * time.Hour)
+}
+
+// Path returns the file of day.
+func (s *Store) Path(day time.Time) string {
+	return filepath.Join(s.dir, Day(day).Format(dayLayout)+fileSuffix)
+}
+
+// Append appends lines, each ending with a newline, to the file of day. A
+// line cut short by a crash must not swallow them, so a newline is written
+// first when the file does not end with one.
+func (s *Store) Append(day time.Time, lines []byte) error {
+	file, err := os.OpenFile(s.Path(day), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	if info, statErr := file.Stat(); statErr == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, readErr := file.ReadAt(last, info.Size()-1); readErr == nil && last[0] != '\n' {
+			lines = append([]byte{'\n'}, lines...)
+		}
+	}
+	if _, err := file.Write(lines); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Replace atomically replaces the file of day with lines.
+func (s *Store) Replace(day time.Time, lines []byte) error {
+	return s.replace(s.Path(day), lines)
+}
+
+// WriteFile atomically replaces name, a file of the data directory that is
+// not a day file, with data.
+func (s *Store) WriteFile(name string, data []byte) error {
+	return s.replace(filepath.Join(s.dir, name), data)
+}
+
+// ReadFile reads name, a file of the data directory that is not a day file.
+func (s *Store) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, name))
+}
+
+func (s *Store) replace(path string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, "daylog-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, writeErr := tmp.Write(data); writeErr != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return writeErr
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		_ = os.Remove(tmpPath)
+		return closeErr
+	}
+	if renameErr := os.Rename(tmpPath, path); renameErr != nil {
+		_ = os.Remove(tmpPath)
+		return renameErr
+	}
+	return nil
+}
+
+// Days lists the days with a file, oldest first.
+func (s *Store) Days() ([]time.Time, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var days []time.Time
+	for _, entry := range entries {
+		stem, found := strings.CutSuffix(entry.Name(), fileSuffix)
+		if !found || entry.IsDir() {
+			continue
+		}
+		if day, parseErr := time.Parse(dayLayout, stem); parseErr == nil {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+	return days, nil
+}
+
+// Lines calls visit with every non-empty line of the file of day and its
+// 1-based line number, in file order, and stops at the first error visit
+// returns. A missing file, such as one removed by a concurrent prune, has no
+// lines.
+func (s *Store) Lines(day time.Time, visit func(line int, data []byte) error) error {
+	file, err := os.Open(s.Path(day))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), s.maxLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		if err := visit(line, scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Prune deletes the files of the days before expired. Every file is tried;
+// the failures are returned together.
+func (s *Store) Prune(expired time.Time) error {
+	days, err := s.Days()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, day := range days {
+		if !day.Before(Day(expired)) {
+			break
+		}
+		if removeErr := os.Remove(s.Path(day)); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			errs = append(errs, removeErr)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("remove expired days: %w", errors.Join(errs...))
+	}
+	return nil
+}
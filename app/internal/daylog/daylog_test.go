@@ -0,0 +1,97 @@
+package daylog_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/daylog"
+)
+
+func TestDir(t *testing.T) {
+	settingsPath := filepath.Join("/etc", "vcv", "settings.json")
+
+	assert.Equal(t, filepath.Join("/etc", "vcv", "history"), daylog.Dir("", "history", settingsPath))
+	assert.Equal(t, filepath.Join("/etc", "vcv", "data"), daylog.Dir(" data ", "history", settingsPath))
+	assert.Equal(t, "/var/lib/vcv", daylog.Dir("/var/lib/vcv", "history", settingsPath))
+}
+
+func lines(t *testing.T, store *daylog.Store, day time.Time) []string {
+	t.Helper()
+	var found []string
+	require.NoError(t, store.Lines(day, func(_ int, data []byte) error {
+		found = append(found, string(data))
+		return nil
+	}))
+	return found
+}
+
+func TestStore_AppendAndLines(t *testing.T) {
+	dir := t.TempDir()
+	store, err := daylog.Open(filepath.Join(dir, "nested"), 1024)
+	require.NoError(t, err)
+	day := time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	require.NoError(t, store.Append(day, []byte("{\"a\":1}\n\n")))
+	assert.Equal(t, filepath.Join(dir, "nested", "2026-03-01.ndjson"), store.Path(day), "files are keyed by UTC day")
+
+	// A crash left a partial line behind; the next append starts fresh.
+	file, err := os.OpenFile(store.Path(day), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"b":`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.NoError(t, store.Append(day, []byte("{\"c\":3}\n")))
+
+	assert.Equal(t, []string{`{"a":1}`, `{"b":`, `{"c":3}`}, lines(t, store, day), "empty lines are skipped")
+	assert.Empty(t, lines(t, store, day.AddDate(0, 0, 1)), "a missing day has no lines")
+
+	stop := errors.New("stop")
+	visited := 0
+	assert.ErrorIs(t, store.Lines(day, func(int, []byte) error {
+		visited++
+		return stop
+	}), stop)
+	assert.Equal(t, 1, visited)
+}
+
+func TestStore_DaysPruneReplace(t *testing.T) {
+	dir := t.TempDir()
+	store, err := daylog.Open(dir, 1024)
+	require.NoError(t, err)
+	today := daylog.Day(time.Now())
+	for _, age := range []int{0, 5, 2} {
+		require.NoError(t, store.Append(today.AddDate(0, 0, -age), []byte("{}\n")))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o600))
+
+	days, err := store.Days()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{today.AddDate(0, 0, -5), today.AddDate(0, 0, -2), today}, days, "oldest first, other files ignored")
+
+	require.NoError(t, store.Prune(today.AddDate(0, 0, -2)))
+	days, err = store.Days()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{today.AddDate(0, 0, -2), today}, days)
+
+	require.NoError(t, store.Replace(today, []byte("{\"only\":true}\n")))
+	assert.Equal(t, []string{`{"only":true}`}, lines(t, store, today))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3, "no temporary file is left behind")
+
+	_, err = store.ReadFile("state.json")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	require.NoError(t, store.WriteFile("state.json", []byte(`{"a":1}`)))
+	data, err := store.ReadFile("state.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1}`, string(data))
+	days, err = store.Days()
+	require.NoError(t, err)
+	assert.Len(t, days, 2, "other files are not days")
+}
@@ -9,4 +9,9 @@ var (
 	ErrInvalidToken      = errors.New("invalid vault token")
 	ErrInvalidThreshold  = errors.New("invalid expiration threshold")
 	ErrInvalidWebhookURL = errors.New("invalid webhook url")
+
+	ErrInvalidClassificationRule = errors.New("invalid classification rule")
+	ErrInvalidMountPolicy        = errors.New("invalid mount policy")
+	ErrInvalidAcknowledgement    = errors.New("invalid acknowledgement")
+	ErrInvalidQuery              = errors.New("invalid certificate query")
 )
@@ -20,6 +20,10 @@ func TestErrorVariables(t *testing.T) {
 		{"ErrInvalidToken", ErrInvalidToken, "invalid vault token"},
 		{"ErrInvalidThreshold", ErrInvalidThreshold, "invalid expiration threshold"},
 		{"ErrInvalidWebhookURL", ErrInvalidWebhookURL, "invalid webhook url"},
+		{"ErrInvalidClassificationRule", ErrInvalidClassificationRule, "invalid classification rule"},
+		{"ErrInvalidMountPolicy", ErrInvalidMountPolicy, "invalid mount policy"},
+		{"ErrInvalidAcknowledgement", ErrInvalidAcknowledgement, "invalid acknowledgement"},
+		{"ErrInvalidQuery", ErrInvalidQuery, "invalid certificate query"},
 	}
 
 	for _, tt := range tests {
@@ -38,6 +42,10 @@ func TestErrorUniqueness(t *testing.T) {
 		ErrInvalidToken,
 		ErrInvalidThreshold,
 		ErrInvalidWebhookURL,
+		ErrInvalidClassificationRule,
+		ErrInvalidMountPolicy,
+		ErrInvalidAcknowledgement,
+		ErrInvalidQuery,
 	}
 
 	seen := make(map[string]bool)
@@ -0,0 +1,200 @@
+// Package events turns successive inventory refreshes into change events
+// (certificate added, revoked, expired, entering the warning or critical
+// window, removed, vault connected or disconnected), fans them out to
+// subscribers such as the /api/events stream and can record them in a
+// persistent journal.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"vcv/internal/logger"
+)
+
+// Event types.
+const (
+	TypeCertificateAdded    = "certificate_added"
+	TypeCertificateRevoked  = "certificate_revoked"
+	TypeCertificateExpired  = "certificate_expired"
+	TypeCertificateWarning  = "certificate_warning"
+	TypeCertificateCritical = "certificate_critical"
+	// TypeCertificateTidied is a revoked or expired certificate that is no
+	// longer listed, as after a Vault tidy.
+	TypeCertificateTidied = "certificate_tidied"
+	// TypeCertificateRemoved is any other certificate that is no longer
+	// listed.
+	TypeCertificateRemoved = "certificate_removed"
+	TypeVaultConnected     = "vault_connected"
+	TypeVaultDisconnected  = "vault_disconnected"
+)
+
+// Types lists every event type.
+var Types = []string{
+	TypeCertificateAdded, TypeCertificateRevoked, TypeCertificateExpired, TypeCertificateWarning, TypeCertificateCritical,
+	TypeCertificateTidied, TypeCertificateRemoved, TypeVaultConnected, TypeVaultDisconnected,
+}
+
+// Event is one inventory change. Certificate events carry the certificate
+// fields; vault events only VaultID.
+type Event struct {
+	ID            uint64     `json:"id"`
+	Type          string     `json:"type"`
+	Time          time.Time  `json:"time"`
+	VaultID       string     `json:"vaultId,omitempty"`
+	Mount         string     `json:"mount,omitempty"`
+	CertificateID string     `json:"certificateId,omitempty"`
+	CommonName    string     `json:"commonName,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+}
+
+// subscriberBuffer is how many events a subscriber may lag behind before it
+// is dropped; it resumes from the history when it reconnects.
+const subscriberBuffer = 64
+
+// Broker numbers published events, keeps the most recent ones for resuming
+// streams and delivers new ones to subscribers. A nil *Broker publishes
+// nothing and its subscriptions never receive an event.
+type Broker struct {
+	mu          sync.Mutex
+	capacity    int
+	history     []Event
+	lastID      uint64
+	subscribers map[chan Event]struct{}
+	closed      bool
+	journal     *Journal
+	// journalMu orders journal writes by ID without holding mu during disk
+	// I/O: Publish takes it before releasing mu.
+	journalMu sync.Mutex
+}
+
+// BrokerOption configures a Broker.
+type BrokerOption func(*Broker)
+
+// WithJournal also appends every published event to journal.
+func WithJournal(journal *Journal) BrokerOption {
+	return func(b *Broker) {
+		b.journal = journal
+	}
+}
+
+// NewBroker keeps the last capacity events. IDs start at the current Unix
+// time in milliseconds, so IDs from before a restart are older than any new
+// one and resuming from them asks for a resync instead of skipping events.
+func NewBroker(capacity int, options ...BrokerOption) *Broker {
+	broker := &Broker{
+		capacity:    max(capacity, 1),
+		lastID:      uint64(time.Now().UnixMilli()),
+		subscribers: make(map[chan Event]struct{}),
+	}
+	for _, option := range options {
+		option(broker)
+	}
+	return broker
+}
+
+// Publish assigns IDs to events, records them and delivers them. A
+// subscriber whose buffer is full is dropped rather than blocking the
+// publisher. The journal is written after mu is released, so subscribers do
+// not wait on the disk; a journal write failure is logged and does not stop
+// delivery.
+func (b *Broker) Publish(events ...Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	numbered := make([]Event, 0, len(events))
+	for _, event := range events {
+		b.lastID++
+		event.ID = b.lastID
+		numbered = append(numbered, event)
+		b.history = append(b.history, event)
+		if overflow := len(b.history) - b.capacity; overflow > 0 {
+			b.history = append(b.history[:0], b.history[overflow:]...)
+		}
+		for subscriber := range b.subscribers {
+			select {
+			case subscriber <- event:
+			default:
+				delete(b.subscribers, subscriber)
+				close(subscriber)
+			}
+		}
+	}
+	b.journalMu.Lock()
+	b.mu.Unlock()
+	defer b.journalMu.Unlock()
+	if err := b.journal.Append(numbered); err != nil {
+		logger.Get().Warn().Err(err).Int("events", len(numbered)).Msg("events: failed to journal events")
+	}
+}
+
+// Subscribe returns a channel of new events and a function releasing it.
+// With resume set, backlog holds the recorded events after lastID; complete
+// is false when events after lastID are no longer recorded, or lastID was
+// never issued, and the caller should reload the inventory instead. The
+// channel is closed when the subscriber lags too far behind or the broker
+// closes.
+func (b *Broker) Subscribe(lastID uint64, resume bool) (backlog []Event, complete bool, updates <-chan Event, cancel func()) {
+	if b == nil {
+		// A nil channel blocks forever: the stream stays open on heartbeats.
+		return nil, !resume, nil, func() {}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	channel := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(channel)
+		return nil, !resume, channel, func() {}
+	}
+	b.subscribers[channel] = struct{}{}
+	complete = true
+	if resume {
+		backlog, complete = b.since(lastID)
+	}
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[channel]; ok {
+			delete(b.subscribers, channel)
+			close(channel)
+		}
+	}
+	return backlog, complete, channel, cancel
+}
+
+// since returns the recorded events after lastID. Callers hold b.mu.
+func (b *Broker) since(lastID uint64) ([]Event, bool) {
+	if lastID > b.lastID {
+		return nil, false
+	}
+	oldest := b.lastID - uint64(len(b.history)) + 1
+	if lastID+1 < oldest {
+		return nil, false
+	}
+	start := len(b.history) - int(b.lastID-lastID)
+	return append([]Event(nil), b.history[start:]...), true
+}
+
+// Close ends every subscription; later Publish calls are ignored. Used on
+// server shutdown so open streams do not hold it up.
+func (b *Broker) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for subscriber := range b.subscribers {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
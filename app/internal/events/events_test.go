@@ -0,0 +1,108 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishN(broker *Broker, n int) {
+	for range n {
+		broker.Publish(Event{Type: TypeCertificateAdded})
+	}
+}
+
+func TestBroker_DeliversAndNumbers(t *testing.T) {
+	broker := NewBroker(10)
+	backlog, complete, updates, cancel := broker.Subscribe(0, false)
+	defer cancel()
+	assert.Empty(t, backlog)
+	assert.True(t, complete)
+
+	broker.Publish(Event{Type: TypeCertificateAdded}, Event{Type: TypeCertificateRevoked})
+	first, second := <-updates, <-updates
+	assert.Equal(t, TypeCertificateAdded, first.Type)
+	assert.Equal(t, first.ID+1, second.ID)
+}
+
+func TestBroker_Resume(t *testing.T) {
+	broker := NewBroker(3)
+	publishN(broker, 2)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	publishN(broker, 1)
+	latest := <-updates
+	cancel()
+
+	backlog, complete, _, cancelResume := broker.Subscribe(latest.ID-2, true)
+	defer cancelResume()
+	require.True(t, complete)
+	require.Len(t, backlog, 2)
+	assert.Equal(t, latest.ID-1, backlog[0].ID)
+	assert.Equal(t, latest.ID, backlog[1].ID)
+
+	backlog, complete, _, cancelCurrent := broker.Subscribe(latest.ID, true)
+	defer cancelCurrent()
+	assert.True(t, complete)
+	assert.Empty(t, backlog)
+}
+
+func TestBroker_ResumeGap(t *testing.T) {
+	broker := NewBroker(2)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	publishN(broker, 4)
+	first := <-updates
+	cancel()
+
+	_, complete, _, cancelOld := broker.Subscribe(first.ID, true)
+	defer cancelOld()
+	assert.False(t, complete, "events after the first one were evicted")
+
+	_, complete, _, cancelFuture := broker.Subscribe(first.ID+100, true)
+	defer cancelFuture()
+	assert.False(t, complete, "an id that was never issued needs a resync")
+
+	_, complete, _, cancelRestart := broker.Subscribe(1, true)
+	defer cancelRestart()
+	assert.False(t, complete, "ids from a previous run are older than the history")
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker(subscriberBuffer * 2)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	defer cancel()
+	publishN(broker, subscriberBuffer+1)
+	received := 0
+	for range updates {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(10)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	defer cancel()
+	broker.Close()
+	_, open := <-updates
+	assert.False(t, open)
+
+	broker.Publish(Event{Type: TypeCertificateAdded})
+	_, _, late, cancelLate := broker.Subscribe(0, false)
+	defer cancelLate()
+	_, open = <-late
+	assert.False(t, open)
+}
+
+func TestBroker_Nil(t *testing.T) {
+	var broker *Broker
+	broker.Publish(Event{Type: TypeCertificateAdded})
+	backlog, complete, updates, cancel := broker.Subscribe(5, true)
+	defer cancel()
+	assert.Empty(t, backlog)
+	assert.False(t, complete)
+	assert.Nil(t, updates)
+	_, complete, _, _ = broker.Subscribe(0, false)
+	assert.True(t, complete)
+	broker.Close()
+}
@@ -0,0 +1,238 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"vcv/internal/config"
+	"vcv/internal/daylog"
+	"vcv/internal/logger"
+)
+
+// JournalDirName is the default journal directory, next to settings.json.
+const JournalDirName = "journal"
+
+const (
+	defaultJournalRetentionDays = 365
+	// maxJournalLine bounds one stored event; events are a few hundred bytes.
+	maxJournalLine = 64 << 10
+	// baselineFile holds the inventory state of the last watcher refresh.
+	baselineFile = "baseline.json"
+)
+
+// Journal keeps every published event in a daylog store, filed under the
+// day of the event time, so the audit trail survives restarts. Days older
+// than the retention are deleted. A nil *Journal stores nothing.
+type Journal struct {
+	store     *daylog.Store
+	retention int
+	now       func() time.Time
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// JournalQuery selects journal events. Zero From or To leave that side of
+// the range open; Include, when set, is applied last. Descending scans the
+// newest events first.
+type JournalQuery struct {
+	From          time.Time
+	To            time.Time
+	Types         []string
+	CertificateID string
+	Include       func(Event) bool
+	Descending    bool
+}
+
+// JournalDirForSettings resolves the journal directory for a settings file:
+// a relative dir, or the default JournalDirName, is taken from the settings
+// file's directory.
+func JournalDirForSettings(settings config.JournalSettings, settingsPath string) string {
+	return daylog.Dir(settings.Dir, JournalDirName, settingsPath)
+}
+
+// NewJournal validates settings and creates dir when missing. It returns
+// nil when the journal is disabled.
+func NewJournal(settings config.JournalSettings, dir string) (*Journal, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	if settings.RetentionDays < 0 {
+		return nil, fmt.Errorf("journal: retention_days must not be negative")
+	}
+	store, err := daylog.Open(dir, maxJournalLine)
+	if err != nil {
+		return nil, fmt.Errorf("journal: %w", err)
+	}
+	journal := &Journal{store: store, retention: defaultJournalRetentionDays, now: time.Now}
+	if settings.RetentionDays > 0 {
+		journal.retention = settings.RetentionDays
+	}
+	journal.prune()
+	return journal, nil
+}
+
+// Append stores events, which the broker has numbered, in publication
+// order.
+func (j *Journal) Append(events []Event) error {
+	if j == nil || len(events) == 0 {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for start := 0; start < len(events); {
+		day := daylog.Day(events[start].Time)
+		var lines []byte
+		end := start
+		for ; end < len(events) && daylog.Day(events[end].Time).Equal(day); end++ {
+			line, err := json.Marshal(events[end])
+			if err != nil {
+				return err
+			}
+			lines = append(append(lines, line...), '\n')
+		}
+		if err := j.store.Append(day, lines); err != nil {
+			return err
+		}
+		start = end
+	}
+	if today := daylog.Day(j.now()); !today.Equal(j.lastPruned) {
+		j.prune()
+	}
+	return nil
+}
+
+// Scan calls visit with every event matching query, oldest first unless
+// query.Descending, and stops at the first error visit returns. Lines that
+// do not parse, such as one cut short by a crash, are logged and skipped.
+func (j *Journal) Scan(query JournalQuery, visit func(Event) error) error {
+	if j == nil {
+		return nil
+	}
+	days, err := j.store.Days()
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+	if query.Descending {
+		slices.Reverse(days)
+	}
+	for _, day := range days {
+		if (!query.From.IsZero() && day.Before(daylog.Day(query.From))) || (!query.To.IsZero() && day.After(query.To)) {
+			continue
+		}
+		if err := j.scanFile(day, query, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanFile visits the matching events of one day. A descending scan holds
+// the day's matching events to visit them backwards.
+func (j *Journal) scanFile(day time.Time, query JournalQuery, visit func(Event) error) error {
+	var held []Event
+	err := j.store.Lines(day, func(line int, data []byte) error {
+		var event Event
+		if jsonErr := json.Unmarshal(data, &event); jsonErr != nil {
+			logger.Get().Warn().Err(jsonErr).Str("file", j.store.Path(day)).Int("line", line).Msg("journal: skipping unreadable event")
+			return nil
+		}
+		if !query.matches(event) {
+			return nil
+		}
+		if query.Descending {
+			held = append(held, event)
+			return nil
+		}
+		return visit(event)
+	})
+	if err != nil {
+		return err
+	}
+	for index := len(held) - 1; index >= 0; index-- {
+		if err := visit(held[index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (query JournalQuery) matches(event Event) bool {
+	if !query.From.IsZero() && event.Time.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && event.Time.After(query.To) {
+		return false
+	}
+	if len(query.Types) > 0 && !slices.Contains(query.Types, event.Type) {
+		return false
+	}
+	if query.CertificateID != "" && event.CertificateID != query.CertificateID {
+		return false
+	}
+	return query.Include == nil || query.Include(event)
+}
+
+// baseline is what a Watcher last saw, kept with the journal so the first
+// refresh after a restart publishes what changed while the server was down.
+type baseline struct {
+	Connected    map[string]bool       `json:"connected"`
+	Certificates []baselineCertificate `json:"certificates"`
+}
+
+// baselineCertificate keeps the certificate fields events carry.
+type baselineCertificate struct {
+	ID         string    `json:"id"`
+	CommonName string    `json:"commonName,omitempty"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Status     string    `json:"status"`
+}
+
+// loadBaseline returns the saved baseline, nil when there is none.
+func (j *Journal) loadBaseline() (*baseline, error) {
+	if j == nil {
+		return nil, nil
+	}
+	data, err := j.store.ReadFile(baselineFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("journal: %w", err)
+	}
+	var saved baseline
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("journal: read %s: %w", baselineFile, err)
+	}
+	return &saved, nil
+}
+
+// saveBaseline atomically replaces the saved baseline.
+func (j *Journal) saveBaseline(saved baseline) error {
+	if j == nil {
+		return nil
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.store.WriteFile(baselineFile, data)
+}
+
+// prune deletes the files of days older than the retention. Failures are
+// logged; the next day retries them. Callers hold j.mu or have not
+// published j yet.
+func (j *Journal) prune() {
+	today := daylog.Day(j.now())
+	j.lastPruned = today
+	if err := j.store.Prune(today.AddDate(0, 0, -j.retention)); err != nil {
+		logger.Get().Warn().Err(err).Msg("journal: failed to remove expired events")
+	}
+}
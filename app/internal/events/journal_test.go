@@ -0,0 +1,129 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+)
+
+func scanAll(t *testing.T, journal *Journal, query JournalQuery) []Event {
+	t.Helper()
+	var found []Event
+	require.NoError(t, journal.Scan(query, func(event Event) error {
+		found = append(found, event)
+		return nil
+	}))
+	return found
+}
+
+func TestNewJournal(t *testing.T) {
+	journal, err := NewJournal(config.JournalSettings{}, t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, journal)
+	assert.NoError(t, journal.Append([]Event{{Type: TypeCertificateAdded}}))
+	assert.Empty(t, scanAll(t, journal, JournalQuery{}))
+
+	_, err = NewJournal(config.JournalSettings{Enabled: true, RetentionDays: -1}, t.TempDir())
+	assert.Error(t, err)
+
+	assert.Equal(t, filepath.Join("/etc/vcv", JournalDirName), JournalDirForSettings(config.JournalSettings{}, "/etc/vcv/settings.json"))
+	assert.Equal(t, "/var/lib/vcv/journal", JournalDirForSettings(config.JournalSettings{Dir: "/var/lib/vcv/journal"}, "/etc/vcv/settings.json"))
+}
+
+func TestBroker_WithJournal(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewJournal(config.JournalSettings{Enabled: true}, dir)
+	require.NoError(t, err)
+	now := time.Now().UTC()
+	yesterday := now.AddDate(0, 0, -1)
+	broker := NewBroker(10, WithJournal(journal))
+
+	broker.Publish(
+		Event{Type: TypeCertificateAdded, Time: yesterday, VaultID: "v1", CertificateID: "v1|pki:aa"},
+		Event{Type: TypeVaultDisconnected, Time: now, VaultID: "v2"},
+	)
+	broker.Publish(Event{Type: TypeCertificateRevoked, Time: now, VaultID: "v1", CertificateID: "v1|pki:aa"})
+
+	reopened, err := NewJournal(config.JournalSettings{Enabled: true}, dir)
+	require.NoError(t, err)
+	all := scanAll(t, reopened, JournalQuery{})
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{TypeCertificateAdded, TypeVaultDisconnected, TypeCertificateRevoked}, []string{all[0].Type, all[1].Type, all[2].Type})
+	assert.Equal(t, all[0].ID+1, all[1].ID)
+	assert.Equal(t, all[1].ID+1, all[2].ID)
+	descending := scanAll(t, reopened, JournalQuery{Descending: true})
+	assert.Equal(t, []uint64{all[2].ID, all[1].ID, all[0].ID}, []uint64{descending[0].ID, descending[1].ID, descending[2].ID}, "newest day and newest line first")
+
+	assert.Len(t, scanAll(t, reopened, JournalQuery{CertificateID: "v1|pki:aa"}), 2)
+	assert.Len(t, scanAll(t, reopened, JournalQuery{Types: []string{TypeVaultDisconnected, TypeCertificateRevoked}}), 2)
+	assert.Len(t, scanAll(t, reopened, JournalQuery{From: now.Add(-time.Minute)}), 2)
+	assert.Len(t, scanAll(t, reopened, JournalQuery{To: yesterday}), 1)
+	assert.Len(t, scanAll(t, reopened, JournalQuery{Include: func(event Event) bool { return event.VaultID == "v2" }}), 1)
+
+	stop := errors.New("stop")
+	visited := 0
+	assert.ErrorIs(t, reopened.Scan(JournalQuery{}, func(Event) error {
+		visited++
+		return stop
+	}), stop)
+	assert.Equal(t, 1, visited)
+	visited = 0
+	assert.ErrorIs(t, reopened.Scan(JournalQuery{Descending: true}, func(Event) error {
+		visited++
+		return stop
+	}), stop)
+	assert.Equal(t, 1, visited)
+}
+
+func TestBroker_JournalWriteDoesNotBlockSubscribers(t *testing.T) {
+	journal, err := NewJournal(config.JournalSettings{Enabled: true}, t.TempDir())
+	require.NoError(t, err)
+	broker := NewBroker(10, WithJournal(journal))
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	defer cancel()
+
+	// A held journal lock stands in for a slow disk.
+	journal.mu.Lock()
+	published := make(chan struct{})
+	go func() {
+		broker.Publish(Event{Type: TypeVaultDisconnected, Time: time.Now(), VaultID: "v1"})
+		close(published)
+	}()
+	event := <-updates
+	backlog, complete, _, cancelResume := broker.Subscribe(event.ID-1, true)
+	cancelResume()
+	assert.True(t, complete)
+	assert.Len(t, backlog, 1, "resuming does not wait for the journal")
+	journal.mu.Unlock()
+	<-published
+
+	assert.Len(t, scanAll(t, journal, JournalQuery{}), 1)
+}
+
+func TestJournal_Retention(t *testing.T) {
+	dir := t.TempDir()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	dayFile := func(day time.Time) string { return filepath.Join(dir, day.Format("2006-01-02")+".ndjson") }
+	for _, days := range []int{3, 2, 0} {
+		line := `{"id":1,"type":"certificate_added","time":"` + today.AddDate(0, 0, -days).Format(time.RFC3339) + `"}` + "\n"
+		require.NoError(t, os.WriteFile(dayFile(today.AddDate(0, 0, -days)), []byte(line+`{"id":2,"ty`), 0o600))
+	}
+
+	journal, err := NewJournal(config.JournalSettings{Enabled: true, RetentionDays: 2}, dir)
+	require.NoError(t, err)
+
+	found := scanAll(t, journal, JournalQuery{})
+	require.Len(t, found, 2, "the expired day is deleted and truncated lines are skipped")
+	assert.Equal(t, today.AddDate(0, 0, -2), found[0].Time)
+	assert.NoFileExists(t, dayFile(today.AddDate(0, 0, -3)))
+
+	require.NoError(t, journal.Append([]Event{{ID: 3, Type: TypeCertificateRevoked, Time: today.Add(time.Hour)}}))
+	assert.Len(t, scanAll(t, journal, JournalQuery{}), 3, "the next event starts on a new line")
+}
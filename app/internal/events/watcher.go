@@ -0,0 +1,278 @@
+package events
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/vault"
+)
+
+// VaultChecker reports the connection state of the enabled vaults.
+type VaultChecker func(ctx context.Context) []vault.InstanceStatus
+
+// certificateState is what a refresh remembers of a certificate.
+type certificateState struct {
+	certificate certs.Certificate
+	status      string
+}
+
+// Watcher compares each inventory refresh with the previous one and
+// publishes the differences. When the broker has a journal, the last
+// refresh is saved with it and restored at startup, so the first refresh
+// after a restart publishes what changed meanwhile; otherwise the first
+// successful refresh only records the baseline.
+type Watcher struct {
+	certs      vault.Client
+	vaults     VaultChecker
+	thresholds *certs.ExpiryThresholds
+	broker     *Broker
+	journal    *Journal
+	now        func() time.Time
+
+	mu sync.Mutex
+	// certificates and connected are nil until their first refresh.
+	certificates map[string]certificateState
+	connected    map[string]bool
+}
+
+// NewWatcher builds a Watcher. client is normally the multi-vault client,
+// whose listings are served from the vault cache between syncs; statuses
+// are still re-evaluated on every refresh since they move with time.
+// A baseline that cannot be read is logged and the watcher starts without
+// one.
+func NewWatcher(client vault.Client, checker VaultChecker, thresholds *certs.ExpiryThresholds, broker *Broker) *Watcher {
+	watcher := &Watcher{
+		certs:      client,
+		vaults:     checker,
+		thresholds: thresholds,
+		broker:     broker,
+		now:        time.Now,
+	}
+	if broker != nil {
+		watcher.journal = broker.journal
+	}
+	saved, err := watcher.journal.loadBaseline()
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("events: failed to restore the inventory baseline")
+	}
+	if saved != nil {
+		watcher.restore(*saved)
+	}
+	return watcher
+}
+
+// Refresh lists certificates, checks the vaults and publishes what changed
+// since the previous refresh.
+func (w *Watcher) Refresh(ctx context.Context) {
+	statuses := w.vaults(ctx)
+	certificates, vaultErrors, listErr := w.list(ctx)
+	now := w.now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changes []Event
+	connected := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		connected[status.ID] = status.Connected
+	}
+	if w.connected != nil {
+		changes = append(changes, diffVaults(w.connected, statuses)...)
+	}
+	changed := w.connected == nil || !maps.Equal(w.connected, connected)
+	w.connected = connected
+
+	if listErr != nil {
+		logger.Get().Warn().Err(listErr).Msg("events: failed to list certificates")
+	} else {
+		next := make(map[string]certificateState, len(certificates))
+		for _, certificate := range certificates {
+			next[certificate.ID] = certificateState{certificate: certificate, status: w.thresholds.Status(certificate, now)}
+		}
+		// A vault that failed to list keeps its previous certificates, so it
+		// does not come back as a burst of additions.
+		for _, vaultErr := range vaultErrors {
+			for id, state := range w.certificates {
+				if vaultID, _ := certs.VaultAndMount(id); vaultID == vaultErr.VaultID {
+					next[id] = state
+				}
+			}
+		}
+		if w.certificates != nil {
+			changes = append(changes, diffCertificates(w.certificates, next, certificates)...)
+			changes = append(changes, removedCertificates(w.certificates, next, connected)...)
+		}
+		changed = changed || w.certificates == nil || statesChanged(w.certificates, next)
+		w.certificates = next
+	}
+
+	if len(changes) > 0 {
+		for index := range changes {
+			changes[index].Time = now
+		}
+		w.broker.Publish(changes...)
+		logger.Get().Debug().
+			Int("events", len(changes)).
+			Msg("published inventory events")
+	}
+	// Saved after publishing: a crash in between publishes the changes again
+	// rather than losing them.
+	if changed && w.journal != nil {
+		if err := w.journal.saveBaseline(w.baseline()); err != nil {
+			logger.Get().Warn().Err(err).Msg("events: failed to save the inventory baseline")
+		}
+	}
+}
+
+// restore seeds the state a refresh diffs against from a saved baseline.
+func (w *Watcher) restore(saved baseline) {
+	w.connected = saved.Connected
+	if w.connected == nil {
+		w.connected = make(map[string]bool)
+	}
+	w.certificates = make(map[string]certificateState, len(saved.Certificates))
+	for _, entry := range saved.Certificates {
+		w.certificates[entry.ID] = certificateState{
+			certificate: certs.Certificate{ID: entry.ID, CommonName: entry.CommonName, ExpiresAt: entry.ExpiresAt},
+			status:      entry.Status,
+		}
+	}
+}
+
+// baseline is the state to save, certificates ordered by ID. Callers hold
+// w.mu.
+func (w *Watcher) baseline() baseline {
+	saved := baseline{Connected: w.connected, Certificates: make([]baselineCertificate, 0, len(w.certificates))}
+	for id, state := range w.certificates {
+		saved.Certificates = append(saved.Certificates, baselineCertificate{
+			ID:         id,
+			CommonName: state.certificate.CommonName,
+			ExpiresAt:  state.certificate.ExpiresAt,
+			Status:     state.status,
+		})
+	}
+	sort.Slice(saved.Certificates, func(i, j int) bool { return saved.Certificates[i].ID < saved.Certificates[j].ID })
+	return saved
+}
+
+// statesChanged reports whether next holds other certificates or statuses
+// than previous.
+func statesChanged(previous, next map[string]certificateState) bool {
+	if len(previous) != len(next) {
+		return true
+	}
+	for id, state := range next {
+		if before, ok := previous[id]; !ok || before.status != state.status {
+			return true
+		}
+	}
+	return false
+}
+
+// list prefers the inventory before mount policies, so a certificate a
+// policy hides or rolls up is not reported as removed, then the per-vault
+// envelope so one unreachable vault does not hide the others.
+func (w *Watcher) list(ctx context.Context) ([]certs.Certificate, []vault.VaultError, error) {
+	if inventory, ok := w.certs.(vault.InventoryLister); ok {
+		certificates, vaultErrors := inventory.ListInventory(ctx)
+		return certificates, vaultErrors, nil
+	}
+	if envelope, ok := w.certs.(vault.CertificatesEnvelopeLister); ok {
+		certificates, vaultErrors := envelope.ListCertificatesEnvelope(ctx)
+		return certificates, vaultErrors, nil
+	}
+	certificates, err := w.certs.ListCertificates(ctx)
+	return certificates, nil, err
+}
+
+func diffVaults(previous map[string]bool, statuses []vault.InstanceStatus) []Event {
+	var changes []Event
+	for _, status := range statuses {
+		// A vault missing from previous was just enabled and counts as
+		// disconnected, so enabling an unreachable vault is not an event.
+		if previous[status.ID] == status.Connected {
+			continue
+		}
+		eventType := TypeVaultDisconnected
+		if status.Connected {
+			eventType = TypeVaultConnected
+		}
+		changes = append(changes, Event{Type: eventType, VaultID: status.ID})
+	}
+	return changes
+}
+
+// diffCertificates walks certificates in listing order so events come out
+// in a stable order.
+func diffCertificates(previous, next map[string]certificateState, ordered []certs.Certificate) []Event {
+	var changes []Event
+	for _, certificate := range ordered {
+		state := next[certificate.ID]
+		before, known := previous[certificate.ID]
+		eventType := ""
+		switch {
+		case !known:
+			eventType = TypeCertificateAdded
+		case before.status == state.status:
+			continue
+		case state.status == certs.StatusRevoked:
+			eventType = TypeCertificateRevoked
+		case state.status == certs.StatusExpired:
+			eventType = TypeCertificateExpired
+		case state.status == certs.StatusCritical && before.status != certs.StatusExpired:
+			eventType = TypeCertificateCritical
+		case state.status == certs.StatusWarning && before.status == certs.StatusValid:
+			eventType = TypeCertificateWarning
+		default:
+			continue
+		}
+		changes = append(changes, certificateEvent(eventType, state))
+	}
+	return changes
+}
+
+// removedCertificates reports the certificates of previous missing from
+// next, ordered by ID. Certificates of a vault that is no longer enabled
+// are dropped silently: disabling a vault removes nothing from it.
+func removedCertificates(previous, next map[string]certificateState, enabled map[string]bool) []Event {
+	var changes []Event
+	for id, state := range previous {
+		if _, listed := next[id]; listed {
+			continue
+		}
+		if vaultID, _ := certs.VaultAndMount(id); vaultID != "" {
+			if _, ok := enabled[vaultID]; !ok {
+				continue
+			}
+		}
+		eventType := TypeCertificateRemoved
+		if state.status == certs.StatusRevoked || state.status == certs.StatusExpired {
+			eventType = TypeCertificateTidied
+		}
+		changes = append(changes, certificateEvent(eventType, state))
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].CertificateID < changes[j].CertificateID })
+	return changes
+}
+
+func certificateEvent(eventType string, state certificateState) Event {
+	vaultID, mount := certs.VaultAndMount(state.certificate.ID)
+	event := Event{
+		Type:          eventType,
+		VaultID:       vaultID,
+		Mount:         mount,
+		CertificateID: state.certificate.ID,
+		CommonName:    state.certificate.CommonName,
+		Status:        state.status,
+	}
+	if !state.certificate.ExpiresAt.IsZero() {
+		expiresAt := state.certificate.ExpiresAt
+		event.ExpiresAt = &expiresAt
+	}
+	return event
+}
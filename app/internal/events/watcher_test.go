@@ -0,0 +1,250 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/vault"
+)
+
+// fakeEnvelopeClient lists certificates through the per-vault envelope.
+type fakeEnvelopeClient struct {
+	vault.MockClient
+	certificates []certs.Certificate
+	vaultErrors  []vault.VaultError
+}
+
+func (c *fakeEnvelopeClient) ListCertificatesEnvelope(context.Context) ([]certs.Certificate, []vault.VaultError) {
+	return c.certificates, c.vaultErrors
+}
+
+type watcherFixture struct {
+	client   *fakeEnvelopeClient
+	statuses []vault.InstanceStatus
+	now      time.Time
+	watcher  *Watcher
+	updates  <-chan Event
+}
+
+func newWatcherFixture(t *testing.T, options ...BrokerOption) *watcherFixture {
+	t.Helper()
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	broker := NewBroker(100, options...)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	t.Cleanup(cancel)
+	fixture := &watcherFixture{
+		client:  &fakeEnvelopeClient{},
+		now:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		updates: updates,
+	}
+	fixture.watcher = NewWatcher(fixture.client, func(context.Context) []vault.InstanceStatus {
+		return fixture.statuses
+	}, thresholds, broker)
+	fixture.watcher.now = func() time.Time { return fixture.now }
+	return fixture
+}
+
+// refresh runs a refresh and returns the published events.
+func (f *watcherFixture) refresh() []Event {
+	f.watcher.Refresh(context.Background())
+	var published []Event
+	for {
+		select {
+		case event := <-f.updates:
+			published = append(published, event)
+		default:
+			return published
+		}
+	}
+}
+
+func eventTypes(published []Event) []string {
+	types := make([]string, 0, len(published))
+	for _, event := range published {
+		subject := event.CertificateID
+		if subject == "" {
+			subject = event.VaultID
+		}
+		types = append(types, event.Type+" "+subject)
+	}
+	return types
+}
+
+func TestWatcher_CertificateTransitions(t *testing.T) {
+	f := newWatcherFixture(t)
+	day := 24 * time.Hour
+	f.client.certificates = []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "a", ExpiresAt: f.now.Add(31 * day)},
+		{ID: "v1|pki:bb", CommonName: "b", ExpiresAt: f.now.Add(90 * day)},
+	}
+	assert.Empty(t, f.refresh(), "the first refresh is the baseline")
+
+	f.now = f.now.Add(2 * day)
+	f.client.certificates = append(f.client.certificates, certs.Certificate{ID: "v1|pki_int:cc", CommonName: "c", ExpiresAt: f.now.Add(90 * day)})
+	published := f.refresh()
+	assert.Equal(t, []string{"certificate_warning v1|pki:aa", "certificate_added v1|pki_int:cc"}, eventTypes(published))
+	assert.Equal(t, "v1", published[1].VaultID)
+	assert.Equal(t, "pki_int", published[1].Mount)
+	assert.Equal(t, certs.StatusValid, published[1].Status)
+	assert.Equal(t, f.now, published[1].Time)
+
+	f.now = f.now.Add(23 * day)
+	f.client.certificates[1].Revoked = true
+	assert.Equal(t, []string{"certificate_critical v1|pki:aa", "certificate_revoked v1|pki:bb"}, eventTypes(f.refresh()))
+
+	f.now = f.now.Add(7 * day)
+	assert.Equal(t, []string{"certificate_expired v1|pki:aa"}, eventTypes(f.refresh()))
+	assert.Empty(t, f.refresh(), "unchanged statuses publish nothing")
+}
+
+func TestWatcher_RestoresBaselineFromJournal(t *testing.T) {
+	dir := t.TempDir()
+	openJournal := func() *Journal {
+		journal, err := NewJournal(config.JournalSettings{Enabled: true}, dir)
+		require.NoError(t, err)
+		return journal
+	}
+	day := 24 * time.Hour
+	f := newWatcherFixture(t, WithJournal(openJournal()))
+	f.statuses = []vault.InstanceStatus{{ID: "v1", Connected: true}}
+	f.client.certificates = []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "a", ExpiresAt: f.now.Add(31 * day)},
+		{ID: "v1|pki:bb", CommonName: "b", ExpiresAt: f.now.Add(90 * day)},
+	}
+	assert.Empty(t, f.refresh(), "without a saved baseline the first refresh is the baseline")
+
+	restarted := newWatcherFixture(t, WithJournal(openJournal()))
+	restarted.now = f.now.Add(2 * day)
+	restarted.statuses = []vault.InstanceStatus{{ID: "v1", Connected: false}}
+	restarted.client.certificates = []certs.Certificate{
+		f.client.certificates[0],
+		{ID: "v1|pki:cc", CommonName: "c", ExpiresAt: f.now.Add(90 * day)},
+	}
+	published := restarted.refresh()
+	assert.Equal(t, []string{
+		"vault_disconnected v1", "certificate_warning v1|pki:aa", "certificate_added v1|pki:cc", "certificate_removed v1|pki:bb",
+	}, eventTypes(published), "the first refresh after a restart diffs against the saved baseline")
+	assert.Equal(t, "b", published[3].CommonName)
+	require.NotNil(t, published[3].ExpiresAt)
+	assert.True(t, f.now.Add(90*day).Equal(*published[3].ExpiresAt))
+
+	again := newWatcherFixture(t, WithJournal(openJournal()))
+	again.now = restarted.now
+	again.statuses = restarted.statuses
+	again.client.certificates = restarted.client.certificates
+	assert.Empty(t, again.refresh(), "the baseline follows every refresh")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, baselineFile), []byte("{"), 0o600))
+	corrupt := newWatcherFixture(t, WithJournal(openJournal()))
+	corrupt.client.certificates = restarted.client.certificates
+	assert.Empty(t, corrupt.refresh(), "an unreadable baseline is ignored")
+}
+
+func TestWatcher_RemovedCertificates(t *testing.T) {
+	f := newWatcherFixture(t)
+	f.statuses = []vault.InstanceStatus{{ID: "v1", Connected: true}, {ID: "v2", Connected: true}}
+	day := 24 * time.Hour
+	f.client.certificates = []certs.Certificate{
+		{ID: "v1|pki:aa", ExpiresAt: f.now.Add(90 * day)},
+		{ID: "v1|pki:bb", ExpiresAt: f.now.Add(-day)},
+		{ID: "v1|pki:cc", ExpiresAt: f.now.Add(90 * day), Revoked: true},
+		{ID: "v1|pki:dd", ExpiresAt: f.now.Add(90 * day)},
+		{ID: "v2|pki:ee", ExpiresAt: f.now.Add(90 * day)},
+	}
+	f.refresh()
+
+	f.statuses = f.statuses[:1]
+	f.client.certificates = f.client.certificates[3:4]
+	published := f.refresh()
+	assert.Equal(t, []string{"certificate_removed v1|pki:aa", "certificate_tidied v1|pki:bb", "certificate_tidied v1|pki:cc"}, eventTypes(published),
+		"certificates of a disabled vault are not reported")
+	assert.Equal(t, certs.StatusExpired, published[1].Status)
+}
+
+func TestWatcher_IgnoresMountPolicies(t *testing.T) {
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	policies, err := certs.NewMountPolicies(map[string]config.MountPolicy{"pki": {HideExpiredAfterDays: 1, RollupLifetime: "24h"}})
+	require.NoError(t, err)
+	now := time.Now()
+	expired := certs.Certificate{ID: "pki:aa", CommonName: "old", CreatedAt: now.AddDate(0, 0, -90), ExpiresAt: now.Add(-time.Hour)}
+	shortLived := certs.Certificate{ID: "pki:bb", CommonName: "svc", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	inner := new(vault.MockClient)
+	inner.On("ListCertificates", mock.Anything).Return([]certs.Certificate{expired, shortLived}, nil).Once()
+	// By the second listing aa expired past the policy window and is hidden,
+	// and a renewal of bb rolls it up.
+	hidden := expired
+	hidden.ExpiresAt = now.Add(-48 * time.Hour)
+	renewal := certs.Certificate{ID: "pki:cc", CommonName: "svc", CreatedAt: now, ExpiresAt: now.Add(2 * time.Hour)}
+	inner.On("ListCertificates", mock.Anything).Return([]certs.Certificate{hidden, shortLived, renewal}, nil).Once()
+	client := vault.NewMultiClient([]config.VaultInstance{{ID: "v1"}}, map[string]vault.Client{"v1": inner}, nil, vault.WithMountPolicies(policies))
+	broker := NewBroker(100)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	t.Cleanup(cancel)
+	watcher := NewWatcher(client, func(context.Context) []vault.InstanceStatus {
+		return []vault.InstanceStatus{{ID: "v1", Connected: true}}
+	}, thresholds, broker)
+
+	watcher.Refresh(context.Background())
+	watcher.Refresh(context.Background())
+
+	var published []Event
+	for len(updates) > 0 {
+		published = append(published, <-updates)
+	}
+	assert.Equal(t, []string{"certificate_added v1|pki:cc"}, eventTypes(published),
+		"hidden and rolled-up certificates are still in Vault and are not reported as tidied or removed")
+}
+
+func TestWatcher_VaultTransitions(t *testing.T) {
+	f := newWatcherFixture(t)
+	f.statuses = []vault.InstanceStatus{{ID: "v1", Connected: true}, {ID: "v2", Connected: false}}
+	assert.Empty(t, f.refresh())
+
+	f.statuses = []vault.InstanceStatus{{ID: "v1", Connected: false}, {ID: "v2", Connected: true}, {ID: "v3", Connected: false}}
+	assert.Equal(t, []string{"vault_disconnected v1", "vault_connected v2"}, eventTypes(f.refresh()))
+}
+
+func TestWatcher_FailedVaultKeepsCertificates(t *testing.T) {
+	f := newWatcherFixture(t)
+	expiresAt := f.now.Add(90 * 24 * time.Hour)
+	f.client.certificates = []certs.Certificate{
+		{ID: "v1|pki:aa", ExpiresAt: expiresAt},
+		{ID: "v2|pki:bb", ExpiresAt: expiresAt},
+	}
+	f.refresh()
+
+	f.client.certificates = []certs.Certificate{{ID: "v1|pki:aa", ExpiresAt: expiresAt}}
+	f.client.vaultErrors = []vault.VaultError{{VaultID: "v2", Message: "unavailable"}}
+	assert.Empty(t, f.refresh())
+
+	f.client.certificates = []certs.Certificate{{ID: "v1|pki:aa", ExpiresAt: expiresAt}, {ID: "v2|pki:bb", ExpiresAt: expiresAt}}
+	f.client.vaultErrors = nil
+	assert.Empty(t, f.refresh(), "certificates of a recovered vault are not new")
+}
+
+func TestWatcher_ListErrorKeepsBaseline(t *testing.T) {
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	client := &vault.MockClient{}
+	client.On("ListCertificates", context.Background()).Return(nil, vault.ErrVaultUnavailable).Once()
+	client.On("ListCertificates", context.Background()).Return([]certs.Certificate{{ID: "v1|pki:aa"}}, nil)
+	broker := NewBroker(10)
+	_, _, updates, cancel := broker.Subscribe(0, false)
+	defer cancel()
+	watcher := NewWatcher(client, func(context.Context) []vault.InstanceStatus { return nil }, thresholds, broker)
+
+	watcher.Refresh(context.Background())
+	watcher.Refresh(context.Background())
+	assert.Empty(t, updates, "a failed first listing is not an empty baseline")
+}
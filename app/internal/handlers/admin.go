@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -17,6 +18,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"golang.org/x/crypto/bcrypt"
 
+	"vcv/internal/ack"
+	"vcv/internal/certquery"
+	"vcv/internal/certs"
 	"vcv/internal/config"
 	vcverrors "vcv/internal/errors"
 	"vcv/internal/httputil"
@@ -303,6 +307,20 @@ func shouldFallbackToDirectWrite(err error) bool {
 }
 
 func validateSettings(settings config.SettingsFile) error {
+	if _, err := certs.NewClassifier(settings.Certificates.ClassificationRules); err != nil {
+		return fmt.Errorf("%w: %v", vcverrors.ErrInvalidClassificationRule, err)
+	}
+	if _, err := certs.NewExpiryThresholds(settings.Certificates.ExpirationThresholds, settings.Vaults); err != nil {
+		return fmt.Errorf("%w: %v", vcverrors.ErrInvalidThreshold, err)
+	}
+	if _, err := certs.NewMountPolicies(settings.Certificates.MountPolicies); err != nil {
+		return fmt.Errorf("%w: %v", vcverrors.ErrInvalidMountPolicy, err)
+	}
+
+	if _, err := certquery.Parse(settings.Notifications.Query); err != nil {
+		return fmt.Errorf("%w: %v", vcverrors.ErrInvalidQuery, err)
+	}
+
 	if webhookURL := strings.TrimSpace(settings.Notifications.WebhookURL); webhookURL != "" {
 		parsed, err := url.Parse(webhookURL)
 		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
@@ -360,8 +378,9 @@ func isBcryptHash(s string) bool {
 // in favor of the Svelte admin panel that talks to /api/admin/*.
 // Admin remains optional: missing/invalid password skips registration with a clear log.
 // trustProxy should match app.trust_proxy (same as global RateLimit / CSRF).
+// acknowledgements, when non-nil, is exposed under /api/admin/acknowledgements.
 // Returns true when admin routes were registered (bcrypt password present and valid).
-func RegisterAdminRoutes(router chi.Router, settingsPath string, env config.Environment, vaultRegistry *vault.Registry, vaultStatusClients map[string]vault.Client, cacheClient vault.Client, trustProxy bool) bool {
+func RegisterAdminRoutes(router chi.Router, settingsPath string, env config.Environment, vaultRegistry *vault.Registry, vaultStatusClients map[string]vault.Client, cacheClient vault.Client, acknowledgements *ack.Store, trustProxy bool) bool {
 	settingsStore := newAdminSettingsStore(settingsPath, env)
 	settings, err := settingsStore.load()
 	if err != nil {
@@ -392,6 +411,9 @@ func RegisterAdminRoutes(router chi.Router, settingsPath string, env config.Envi
 	}
 
 	registerAdminAPIRoutes(router, sessions, store, vaultStatusClients, refreshRegistry)
+	if acknowledgements != nil {
+		registerAcknowledgementRoutes(router, sessions, acknowledgements)
+	}
 
 	router.Group(func(r chi.Router) {
 		r.Use(sessions.requireAuth)
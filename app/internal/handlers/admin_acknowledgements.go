@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/ack"
+	vcverrors "vcv/internal/errors"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+)
+
+type adminAcknowledgementsResponse struct {
+	Acknowledgements []ack.Entry `json:"acknowledgements"`
+}
+
+type adminAcknowledgementRequest struct {
+	CertificateID string     `json:"certificate_id"`
+	Pattern       string     `json:"pattern"`
+	Reason        string     `json:"reason"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// registerAcknowledgementRoutes mounts the acknowledgement list endpoints
+// behind the admin session.
+func registerAcknowledgementRoutes(router chi.Router, sessions *adminSessionStore, acknowledgements *ack.Store) {
+	router.Group(func(r chi.Router) {
+		r.Use(sessions.requireAuth)
+
+		r.Get("/api/admin/acknowledgements", func(w http.ResponseWriter, req *http.Request) {
+			writeJSON(w, http.StatusOK, adminAcknowledgementsResponse{Acknowledgements: acknowledgements.List()})
+		})
+
+		r.Post("/api/admin/acknowledgements", func(w http.ResponseWriter, req *http.Request) {
+			var body adminAcknowledgementRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid acknowledgement payload")
+				return
+			}
+			entry, err := acknowledgements.Add(ack.Entry{
+				CertificateID: body.CertificateID,
+				Pattern:       body.Pattern,
+				Reason:        body.Reason,
+				ExpiresAt:     body.ExpiresAt,
+			})
+			if err != nil {
+				if errors.Is(err, vcverrors.ErrInvalidAcknowledgement) {
+					writeJSONError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				requestID := middleware.GetRequestID(req.Context())
+				logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+					Str("request_id", requestID).
+					Msg("failed to save acknowledgement")
+				writeJSONError(w, http.StatusInternalServerError, "failed to save acknowledgement")
+				return
+			}
+			writeJSON(w, http.StatusCreated, entry)
+		})
+
+		r.Delete("/api/admin/acknowledgements/{id}", func(w http.ResponseWriter, req *http.Request) {
+			id := strings.TrimSpace(chi.URLParam(req, "id"))
+			if id == "" {
+				writeJSONError(w, http.StatusBadRequest, "acknowledgement id required")
+				return
+			}
+			removed, err := acknowledgements.Remove(id)
+			if err != nil {
+				requestID := middleware.GetRequestID(req.Context())
+				logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+					Str("request_id", requestID).
+					Msg("failed to remove acknowledgement")
+				writeJSONError(w, http.StatusInternalServerError, "failed to save acknowledgements")
+				return
+			}
+			if !removed {
+				writeJSONError(w, http.StatusNotFound, "acknowledgement not found")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
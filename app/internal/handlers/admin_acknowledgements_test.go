@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/ack"
+)
+
+func TestAcknowledgementRoutes(t *testing.T) {
+	r, sessions, _, settingsPath := setupAdminAPIRouter(t)
+	acknowledgements, err := ack.NewStore(ack.PathForSettings(settingsPath))
+	require.NoError(t, err)
+	registerAcknowledgementRoutes(r, sessions, acknowledgements)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/acknowledgements", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	cookie := loginAdmin(t, r)
+
+	body := []byte(`{"pattern":"*.old.example.com","reason":"decommissioned","expires_at":"2099-01-01T00:00:00Z"}`)
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/acknowledgements", bytes.NewReader(body))
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created ack.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "decommissioned", created.Reason)
+	assert.FileExists(t, filepath.Join(filepath.Dir(settingsPath), ack.FileName))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/acknowledgements", bytes.NewReader([]byte(`{"reason":"missing target"}`)))
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/acknowledgements", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed adminAcknowledgementsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Equal(t, []ack.Entry{created}, listed.Acknowledgements)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/admin/acknowledgements/"+created.ID, nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/admin/acknowledgements/"+created.ID, nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -57,7 +57,7 @@ func writeJSON(w http.ResponseWriter, status int, body any) {
 }
 
 func writeJSONError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
+	writeJSON(w, status, jsonError{Error: message})
 }
 
 func (s *adminSessionStore) loginFromJSON(w http.ResponseWriter, r *http.Request, body adminLoginRequest) (bool, string) {
@@ -159,6 +159,9 @@ func registerAdminAPIRoutes(
 					!errors.Is(saveErr, vcverrors.ErrInvalidToken) &&
 					!errors.Is(saveErr, vcverrors.ErrInvalidThreshold) &&
 					!errors.Is(saveErr, vcverrors.ErrInvalidWebhookURL) &&
+					!errors.Is(saveErr, vcverrors.ErrInvalidClassificationRule) &&
+					!errors.Is(saveErr, vcverrors.ErrInvalidMountPolicy) &&
+					!errors.Is(saveErr, vcverrors.ErrInvalidQuery) &&
 					!errors.Is(saveErr, vcverrors.ErrVaultIDEmpty) &&
 					!errors.Is(saveErr, vcverrors.ErrDuplicateVaultID) {
 					status = http.StatusInternalServerError
@@ -237,11 +240,14 @@ func registerAdminAPIRoutes(
 func mergeAdminSettings(current, incoming config.SettingsFile) config.SettingsFile {
 	merged := current
 	merged.Certificates.ExpirationThresholds = incoming.Certificates.ExpirationThresholds
+	merged.Certificates.ClassificationRules = incoming.Certificates.ClassificationRules
+	merged.Certificates.MountPolicies = incoming.Certificates.MountPolicies
 	merged.Metrics.PerCertificate = incoming.Metrics.PerCertificate
 	merged.Metrics.EnhancedMetrics = incoming.Metrics.EnhancedMetrics
 	merged.Metrics.PinnedCertificates = incoming.Metrics.PinnedCertificates
 	merged.CORS.AllowedOrigins = incoming.CORS.AllowedOrigins
 	merged.Notifications.WebhookURL = mergeSecret(incoming.Notifications.WebhookURL, current.Notifications.WebhookURL)
+	merged.Notifications.Query = incoming.Notifications.Query
 	merged.Vaults = mergeVaultTokens(incoming.Vaults, current.Vaults)
 	return merged
 }
@@ -915,6 +915,86 @@ func TestRegisterAdminAPIRoutes_SettingsPut_ValidationError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestRegisterAdminAPIRoutes_SettingsPut_InvalidClassificationRule(t *testing.T) {
+	r, _, _, _ := setupAdminAPIRouter(t)
+	cookie := loginAdmin(t, r)
+
+	updated := config.SettingsFile{
+		Vaults: []config.VaultInstance{{ID: "v1", Address: "http://127.0.0.1:8200", Token: "t"}},
+		Certificates: config.CertificateSettings{
+			ClassificationRules: []config.ClassificationRule{{Type: "signing", EKUs: []string{"notAnEKU"}}},
+		},
+	}
+	body, _ := json.Marshal(updated)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/settings", bytes.NewReader(body))
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "classification rule")
+}
+
+func TestRegisterAdminAPIRoutes_SettingsPut_InvalidVaultThresholds(t *testing.T) {
+	r, _, _, _ := setupAdminAPIRouter(t)
+	cookie := loginAdmin(t, r)
+
+	updated := config.SettingsFile{
+		Vaults: []config.VaultInstance{{
+			ID:                   "v1",
+			Address:              "http://127.0.0.1:8200",
+			Token:                "t",
+			ExpirationThresholds: &config.ExpirationThresholds{WarningDuration: "tomorrow"},
+		}},
+	}
+	body, _ := json.Marshal(updated)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/settings", bytes.NewReader(body))
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid expiration threshold")
+}
+
+func TestRegisterAdminAPIRoutes_SettingsPut_InvalidMountPolicy(t *testing.T) {
+	r, _, _, _ := setupAdminAPIRouter(t)
+	cookie := loginAdmin(t, r)
+
+	updated := config.SettingsFile{
+		Vaults: []config.VaultInstance{{ID: "v1", Address: "http://127.0.0.1:8200", Token: "t"}},
+		Certificates: config.CertificateSettings{
+			MountPolicies: map[string]config.MountPolicy{"pki_mesh": {RollupLifetime: "brief"}},
+		},
+	}
+	body, _ := json.Marshal(updated)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/settings", bytes.NewReader(body))
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid mount policy")
+}
+
+func TestRegisterAdminAPIRoutes_SettingsPut_InvalidNotificationQuery(t *testing.T) {
+	r, _, _, _ := setupAdminAPIRouter(t)
+	cookie := loginAdmin(t, r)
+
+	updated := config.SettingsFile{
+		Vaults:        []config.VaultInstance{{ID: "v1", Address: "http://127.0.0.1:8200", Token: "t"}},
+		Notifications: config.NotificationSettings{Query: "status:soon"},
+	}
+	body, _ := json.Marshal(updated)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/settings", bytes.NewReader(body))
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid certificate query")
+}
+
 func TestRegisterAdminAPIRoutes_VaultPost(t *testing.T) {
 	r, _, _, _ := setupAdminAPIRouter(t)
 
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// APIPrefix is the root of the versioned public API. The same routes stay
+// reachable under the unversioned /api prefix as deprecated aliases.
+const APIPrefix = "/api/v1"
+
+const legacyAPIPrefix = "/api"
+
+// Error codes of the /api/v1 error envelope.
+const (
+	errCodeInvalidRequest      = "invalid_request"
+	errCodeNotFound            = "not_found"
+	errCodeCertificateNotFound = "certificate_not_found"
+	errCodeMountNotConfigured  = "mount_not_configured"
+	errCodeVaultNotFound       = "vault_not_found"
+	errCodeVaultError          = "vault_error"
+	errCodeVaultUnavailable    = "vault_unavailable"
+	errCodeInternal            = "internal_error"
+)
+
+// apiError is the body of every public API error response. Message is safe
+// to show to end users; causes are logged, never returned.
+type apiError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+	VaultID   string `json:"vault_id,omitempty"`
+	status    int
+}
+
+// HandleAPI registers handler on APIPrefix+path and on the deprecated
+// /api+path alias. path starts with "/", e.g. "/certs/{id}/pem".
+func HandleAPI(r chi.Router, method, path string, handler http.HandlerFunc) {
+	r.Method(method, APIPrefix+path, handler)
+	r.Method(method, legacyAPIPrefix+path, deprecatedAlias(handler))
+}
+
+// deprecatedAlias marks responses of unversioned paths as deprecated
+// (RFC 9745) and links to their /api/v1 successor.
+func deprecatedAlias(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		successor := APIPrefix + strings.TrimPrefix(req.URL.EscapedPath(), legacyAPIPrefix)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+		handler(w, req)
+	}
+}
+
+// invalidRequest builds a 400 error; message describes what to fix.
+func invalidRequest(message string) apiError {
+	return apiError{status: http.StatusBadRequest, Code: errCodeInvalidRequest, Message: message}
+}
+
+// notFound builds a 404 error for a request that matched nothing.
+func notFound(message string) apiError {
+	return apiError{status: http.StatusNotFound, Code: errCodeNotFound, Message: message}
+}
+
+// classifyError maps the vault layer's error causes to a status and code.
+// Unrecognized errors are internal errors.
+func classifyError(err error) apiError {
+	result := apiError{VaultID: vault.VaultIDOf(err)}
+	switch {
+	case errors.Is(err, vault.ErrInvalidCertificateID):
+		result.status, result.Code, result.Message = http.StatusBadRequest, errCodeInvalidRequest, "invalid certificate id"
+	case errors.Is(err, vault.ErrCertificateNotFound):
+		result.status, result.Code, result.Message = http.StatusNotFound, errCodeCertificateNotFound, "certificate not found"
+	case errors.Is(err, vault.ErrMountNotConfigured):
+		result.status, result.Code, result.Message = http.StatusNotFound, errCodeMountNotConfigured, "PKI mount is not configured"
+	case errors.Is(err, vault.ErrUnknownVault):
+		result.status, result.Code, result.Message = http.StatusNotFound, errCodeVaultNotFound, "vault is not configured"
+	case errors.Is(err, vault.ErrVaultUnavailable), errors.Is(err, vault.ErrVaultNotConfigured):
+		result.status, result.Code, result.Message = http.StatusServiceUnavailable, errCodeVaultUnavailable, "vault is unavailable"
+	case errors.Is(err, vault.ErrVaultResponse):
+		result.status, result.Code, result.Message = http.StatusBadGateway, errCodeVaultError, "vault returned an error"
+	default:
+		result.status, result.Code, result.Message = http.StatusInternalServerError, errCodeInternal, http.StatusText(http.StatusInternalServerError)
+	}
+	return result
+}
+
+// writeAPIError writes apiErr with the request ID of req.
+func writeAPIError(w http.ResponseWriter, req *http.Request, apiErr apiError) {
+	apiErr.RequestID = middleware.GetRequestID(req.Context())
+	writeJSON(w, apiErr.status, apiErr)
+}
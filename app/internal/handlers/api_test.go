@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/vault"
+)
+
+type apiErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+	VaultID   string `json:"vault_id"`
+}
+
+func TestAPI_VersionedAndDeprecatedPaths(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificatePEM", mock.Anything, "v1|pki:aa").Return(certs.PEMResponse{SerialNumber: "aa", PEM: "pem"}, nil)
+	router := setupRouter(mockVault)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs/v1%7Cpki:aa/pem", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/v1%7Cpki:aa/pem", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/certs/v1%7Cpki:aa/pem>; rel="successor-version"`, rec.Header().Get("Link"))
+}
+
+func TestAPI_ErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		vaultID string
+	}{
+		{name: "invalid id", err: vault.ErrInvalidCertificateID, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "not found", err: &vault.Error{VaultID: "v1", Err: fmt.Errorf("read: %w", vault.ErrCertificateNotFound)}, status: http.StatusNotFound, code: "certificate_not_found", vaultID: "v1"},
+		{name: "mount", err: fmt.Errorf("%w: pki_old", vault.ErrMountNotConfigured), status: http.StatusNotFound, code: "mount_not_configured"},
+		{name: "unknown vault", err: &vault.Error{VaultID: "v9", Err: vault.ErrUnknownVault}, status: http.StatusNotFound, code: "vault_not_found", vaultID: "v9"},
+		{name: "vault error", err: &vault.Error{VaultID: "v1", Err: vault.ErrVaultResponse}, status: http.StatusBadGateway, code: "vault_error", vaultID: "v1"},
+		{name: "vault down", err: &vault.Error{VaultID: "v1", Err: vault.ErrVaultUnavailable}, status: http.StatusServiceUnavailable, code: "vault_unavailable", vaultID: "v1"},
+		{name: "not configured", err: vault.ErrVaultNotConfigured, status: http.StatusServiceUnavailable, code: "vault_unavailable"},
+		{name: "unclassified", err: errors.New("dial tcp 10.0.0.1:8200: secret detail"), status: http.StatusInternalServerError, code: "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVault := new(vault.MockClient)
+			mockVault.On("GetCertificateDetails", mock.Anything, "v1|pki:aa").Return(certs.DetailedCertificate{}, tt.err)
+			rec := httptest.NewRecorder()
+			setupRouter(mockVault).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs/v1%7Cpki:aa/details", nil))
+
+			require.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var got apiErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.code, got.Code)
+			assert.Equal(t, tt.vaultID, got.VaultID)
+			assert.NotEmpty(t, got.RequestID)
+			assert.NotEmpty(t, got.Message)
+			assert.NotContains(t, got.Message, "secret detail")
+		})
+	}
+}
+
+func TestAPI_InvalidRequestEnvelope(t *testing.T) {
+	router := setupRouter(new(vault.MockClient))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs?sort=owner", nil))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var got apiErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "invalid_request", got.Code)
+	assert.Contains(t, got.Message, "sort")
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// maxBundleCertificates caps one ZIP bundle; every entry costs a Vault read.
+const maxBundleCertificates = 1000
+
+// maxBundleBodyBytes bounds the POST /api/certs/bundle ID list.
+const maxBundleBodyBytes = 1 << 20
+
+type downloadFormat struct {
+	contentType string
+	suffix      string
+	withChain   bool
+	encode      func(ders [][]byte) ([]byte, error)
+}
+
+var downloadFormats = map[string]downloadFormat{
+	"pem": {contentType: "application/x-pem-file", suffix: ".pem", encode: func(ders [][]byte) ([]byte, error) {
+		return []byte(certs.EncodePEMCertificates(ders[:1])), nil
+	}},
+	"der": {contentType: "application/pkix-cert", suffix: ".cer", encode: func(ders [][]byte) ([]byte, error) {
+		return ders[0], nil
+	}},
+	"fullchain": {contentType: "application/x-pem-file", suffix: "-fullchain.pem", withChain: true, encode: func(ders [][]byte) ([]byte, error) {
+		return []byte(certs.EncodePEMCertificates(ders)), nil
+	}},
+	"p7b": {contentType: "application/x-pkcs7-certificates", suffix: ".p7b", withChain: true, encode: certs.EncodePKCS7},
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// bundleRequest is the JSON body of POST /api/certs/bundle.
+type bundleRequest struct {
+	IDs   []string `json:"ids"`
+	Chain bool     `json:"chain"`
+}
+
+func registerCertBundleRoutes(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/certs/{id}/download", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		certificateID, statusCode, decodeErr := decodeCertificateIDParam(req)
+		if statusCode != http.StatusOK {
+			logger.HTTPError(req.Method, req.URL.Path, statusCode, decodeErr).
+				Str("request_id", requestID).
+				Msg("missing certificate id in download path")
+			writeAPIError(w, req, invalidRequest("certificate id is missing or malformed"))
+			return
+		}
+		formatName := strings.ToLower(strings.TrimSpace(req.URL.Query().Get("format")))
+		if formatName == "" {
+			formatName = "pem"
+		}
+		format, ok := downloadFormats[formatName]
+		if !ok {
+			writeAPIError(w, req, invalidRequest("format: must be one of pem, der, fullchain, p7b"))
+			return
+		}
+		serialNumber, ders, err := certificateBundle(req.Context(), vaultClient, certificateID, format.withChain)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Str("serial_number", certificateID).
+				Msg("failed to build certificate download")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		payload, err := format.encode(ders)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Str("serial_number", certificateID).
+				Msg("failed to encode certificate download")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s%s"`, bundleFileName(serialNumber), format.suffix))
+		if _, writeErr := w.Write(payload); writeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, writeErr).
+				Str("request_id", requestID).
+				Msg("failed to write certificate download")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("serial_number", certificateID).
+			Str("format", formatName).
+			Msg("served certificate download")
+	})
+
+	serveBundle := func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		ids, chain, status, err := resolveBundleIDs(req, vaultClient, thresholds)
+		if err != nil {
+			var apiErr apiError
+			switch status {
+			case http.StatusBadRequest:
+				apiErr = invalidRequest(err.Error())
+			case http.StatusNotFound:
+				apiErr = notFound(err.Error())
+			default:
+				apiErr = classifyError(err)
+			}
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("invalid certificate bundle request")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="vcv-certificates-%s.zip"`, time.Now().UTC().Format("2006-01-02")))
+		archive := zip.NewWriter(w)
+		var failures []string
+		for _, id := range ids {
+			serialNumber, ders, bundleErr := certificateBundle(req.Context(), vaultClient, id, chain)
+			if bundleErr != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", id, bundleErr))
+				continue
+			}
+			entry, createErr := archive.Create(bundleEntryName(id, serialNumber))
+			if createErr == nil {
+				_, createErr = io.WriteString(entry, certs.EncodePEMCertificates(ders))
+			}
+			if createErr != nil {
+				logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, createErr).
+					Str("request_id", requestID).
+					Msg("failed to write certificate bundle")
+				return
+			}
+		}
+		if len(failures) > 0 {
+			entry, createErr := archive.Create("errors.txt")
+			if createErr == nil {
+				_, _ = io.WriteString(entry, strings.Join(failures, "\n")+"\n")
+			}
+		}
+		if closeErr := archive.Close(); closeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, closeErr).
+				Str("request_id", requestID).
+				Msg("failed to finish certificate bundle")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("count", len(ids)-len(failures)).
+			Int("failures", len(failures)).
+			Bool("chain", chain).
+			Msg("served certificate bundle")
+	}
+	HandleAPI(r, http.MethodGet, "/certs/bundle", serveBundle)
+	HandleAPI(r, http.MethodPost, "/certs/bundle", serveBundle)
+}
+
+// resolveBundleIDs returns the certificate IDs of a bundle request: the ids
+// of a POST body or of ?ids=, otherwise every certificate matching the
+// /api/certs filters. The status code goes with a non-nil error.
+func resolveBundleIDs(req *http.Request, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) ([]string, bool, int, error) {
+	query := req.URL.Query()
+	chain := query.Get("chain") == "true"
+	var ids []string
+	if req.Method == http.MethodPost {
+		var body bundleRequest
+		if err := json.NewDecoder(http.MaxBytesReader(nil, req.Body, maxBundleBodyBytes)).Decode(&body); err != nil {
+			return nil, false, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err)
+		}
+		ids, chain = body.IDs, chain || body.Chain
+	} else if _, present := query["ids"]; present {
+		ids = parseListQueryParam(query, "ids")
+	} else {
+		listQuery, err := parseCertListQuery(query)
+		if err != nil {
+			return nil, false, http.StatusBadRequest, err
+		}
+		certificates, _, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			return nil, false, http.StatusInternalServerError, err
+		}
+		result := listQuery.apply(filterCertificatesByMounts(certificates, parseMountsQueryParam(query)), thresholds, time.Now())
+		for _, certificate := range result.Certificates {
+			ids = append(ids, certificate.ID)
+		}
+	}
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, dup := seen[id]; dup || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	if len(unique) == 0 {
+		return nil, false, http.StatusNotFound, fmt.Errorf("no certificates matched")
+	}
+	if len(unique) > maxBundleCertificates {
+		return nil, false, http.StatusBadRequest, fmt.Errorf("too many certificates (%d > %d); narrow the filter", len(unique), maxBundleCertificates)
+	}
+	return unique, chain, http.StatusOK, nil
+}
+
+// certificateBundle returns the serial number and DER certificates of a
+// certificate: the leaf, followed by its mount's CA chain when withChain is
+// set.
+func certificateBundle(ctx context.Context, vaultClient vault.Client, certificateID string, withChain bool) (string, [][]byte, error) {
+	pemResponse, err := vaultClient.GetCertificatePEM(ctx, certificateID)
+	if err != nil {
+		return "", nil, err
+	}
+	leaf, err := certs.DecodePEMCertificates(pemResponse.PEM)
+	if err != nil {
+		return "", nil, err
+	}
+	serialNumber := pemResponse.SerialNumber
+	if serialNumber == "" {
+		serialNumber = certificateID
+	}
+	ders := leaf[:1]
+	if !withChain {
+		return serialNumber, ders, nil
+	}
+	chain, err := caChainPEMs(ctx, vaultClient, certificateID)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, caPEM := range chain {
+		caDERs, decodeErr := certs.DecodePEMCertificates(caPEM)
+		if decodeErr != nil {
+			return "", nil, decodeErr
+		}
+		for _, der := range caDERs {
+			if !bytes.Equal(der, ders[0]) {
+				ders = append(ders, der)
+			}
+		}
+	}
+	return serialNumber, ders, nil
+}
+
+func caChainPEMs(ctx context.Context, vaultClient vault.Client, certificateID string) ([]string, error) {
+	vaultMountKey, _ := extractVaultMountFromCertificateID(certificateID)
+	if getter, ok := vaultClient.(vault.CAChainGetter); ok {
+		return getter.GetCAChain(ctx, vaultMountKey)
+	}
+	details, err := vaultClient.GetIntermediateCA(ctx, vaultMountKey)
+	if err != nil {
+		return nil, err
+	}
+	return []string{details.PEM}, nil
+}
+
+func bundleFileName(value string) string {
+	name := strings.Trim(unsafeFileNameChars.ReplaceAllString(value, "_"), "._")
+	if name == "" {
+		return "certificate"
+	}
+	return name
+}
+
+// bundleEntryName places each certificate under vault/mount/serial.pem.
+func bundleEntryName(certificateID, serialNumber string) string {
+	vaultID, mount := certs.VaultAndMount(certificateID)
+	parts := make([]string, 0, 3)
+	for _, part := range []string{vaultID, mount} {
+		if part != "" {
+			parts = append(parts, bundleFileName(part))
+		}
+	}
+	return strings.Join(append(parts, bundleFileName(serialNumber)+".pem"), "/")
+}
@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/vault"
+)
+
+func newBundleTestPEM(t *testing.T, commonName string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func unzipEntries(t *testing.T, body []byte) map[string]string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	entries := make(map[string]string, len(archive.File))
+	for _, file := range archive.File {
+		reader, openErr := file.Open()
+		require.NoError(t, openErr)
+		content, readErr := io.ReadAll(reader)
+		require.NoError(t, readErr)
+		entries[file.Name] = string(content)
+	}
+	return entries
+}
+
+func TestDownloadCertificate_Formats(t *testing.T) {
+	leafPEM := newBundleTestPEM(t, "leaf.example.com")
+	caPEM := newBundleTestPEM(t, "Issuing CA")
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificatePEM", mock.Anything, "pki:aa-bb").Return(certs.PEMResponse{SerialNumber: "aa:bb", PEM: leafPEM}, nil)
+	mockVault.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{PEM: caPEM}, nil)
+	router := setupRouter(mockVault)
+	leafDERs, err := certs.DecodePEMCertificates(leafPEM)
+	require.NoError(t, err)
+	caDERs, err := certs.DecodePEMCertificates(caPEM)
+	require.NoError(t, err)
+
+	tests := []struct {
+		format      string
+		contentType string
+		fileName    string
+		check       func(t *testing.T, body []byte)
+	}{
+		{format: "", contentType: "application/x-pem-file", fileName: "aa_bb.pem", check: func(t *testing.T, body []byte) {
+			assert.Equal(t, leafPEM, string(body))
+		}},
+		{format: "der", contentType: "application/pkix-cert", fileName: "aa_bb.cer", check: func(t *testing.T, body []byte) {
+			assert.Equal(t, leafDERs[0], body)
+		}},
+		{format: "fullchain", contentType: "application/x-pem-file", fileName: "aa_bb-fullchain.pem", check: func(t *testing.T, body []byte) {
+			assert.Equal(t, leafPEM+caPEM, string(body))
+		}},
+		{format: "p7b", contentType: "application/x-pkcs7-certificates", fileName: "aa_bb.p7b", check: func(t *testing.T, body []byte) {
+			ders, decodeErr := certs.DecodePKCS7(body)
+			require.NoError(t, decodeErr)
+			assert.Equal(t, [][]byte{leafDERs[0], caDERs[0]}, ders)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/certs/pki:aa-bb/download?format="+tt.format, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="`+tt.fileName+`"`, rec.Header().Get("Content-Disposition"))
+			tt.check(t, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestDownloadCertificate_Errors(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificatePEM", mock.Anything, "pki:missing").Return(certs.PEMResponse{}, errors.New("not found"))
+	router := setupRouter(mockVault)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/pki:aa/download?format=pfx", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/pki:missing/download", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestCertificateBundle_ByFilter(t *testing.T) {
+	leafPEM := newBundleTestPEM(t, "api.example.com")
+	caPEM := newBundleTestPEM(t, "Issuing CA")
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "pki:aa", CommonName: "api.example.com", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "pki:bb", CommonName: "web.example.com", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "pki:cc", CommonName: "api.internal", ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+	mockVault.On("GetCertificatePEM", mock.Anything, "pki:aa").Return(certs.PEMResponse{SerialNumber: "aa", PEM: leafPEM}, nil)
+	mockVault.On("GetCertificatePEM", mock.Anything, "pki:cc").Return(certs.PEMResponse{}, errors.New("vault unavailable"))
+	mockVault.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{PEM: caPEM}, nil)
+	router := setupRouter(mockVault)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/bundle?q=api&chain=true", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	entries := unzipEntries(t, rec.Body.Bytes())
+	assert.Equal(t, leafPEM+caPEM, entries["pki/aa.pem"])
+	assert.Contains(t, entries["errors.txt"], "pki:cc: vault unavailable")
+	assert.Len(t, entries, 2)
+}
+
+func TestCertificateBundle_ByIDs(t *testing.T) {
+	leafPEM := newBundleTestPEM(t, "api.example.com")
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificatePEM", mock.Anything, "v1|pki:aa").Return(certs.PEMResponse{SerialNumber: "aa", PEM: leafPEM}, nil)
+	router := setupRouter(mockVault)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/certs/bundle", strings.NewReader(`{"ids":["v1|pki:aa","v1|pki:aa"]}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]string{"v1/pki/aa.pem": leafPEM}, unzipEntries(t, rec.Body.Bytes()))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/bundle?ids=v1|pki:aa", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, unzipEntries(t, rec.Body.Bytes()), "v1/pki/aa.pem")
+	mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+}
+
+func TestCertificateBundle_InvalidRequests(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, nil)
+	router := setupRouter(mockVault)
+
+	for _, tt := range []struct {
+		req    *http.Request
+		status int
+	}{
+		{req: httptest.NewRequest(http.MethodGet, "/api/certs/bundle", nil), status: http.StatusNotFound},
+		{req: httptest.NewRequest(http.MethodGet, "/api/certs/bundle?status=soon", nil), status: http.StatusBadRequest},
+		{req: httptest.NewRequest(http.MethodPost, "/api/certs/bundle", strings.NewReader(`{"ids":`)), status: http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, tt.req)
+		assert.Equal(t, tt.status, rec.Code, tt.req.URL.String())
+	}
+}
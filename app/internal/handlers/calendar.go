@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// calendarRefresh is the polling interval suggested to calendar clients.
+const calendarRefresh = time.Hour
+
+// icalTimeFormat is the RFC 5545 UTC DATE-TIME form.
+const icalTimeFormat = "20060102T150405Z"
+
+// registerCalendarRoute mounts /api/certs/calendar.ics, an RFC 5545 feed
+// with one event per certificate expiry. It accepts the /api/certs
+// filters; alarms=true adds a reminder when the certificate enters its
+// mount's warning and critical windows.
+func registerCalendarRoute(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/certs/calendar.ics", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		alarms := query.Get("alarms") == "true"
+		listQuery, queryErr := parseCertListQuery(query)
+		if queryErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, queryErr).
+				Str("request_id", requestID).
+				Msg("invalid certificate calendar query")
+			writeAPIError(w, req, invalidRequest(queryErr.Error()))
+			return
+		}
+		listQuery.page, listQuery.pageSize = 0, 0
+
+		certificates, _, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for calendar")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		now := time.Now()
+		result := listQuery.apply(filterCertificatesByMounts(certificates, parseMountsQueryParam(query)), thresholds, now)
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="vcv-certificates.ics"`)
+		feed := &icalWriter{w: bufio.NewWriter(w)}
+		feed.line("BEGIN", "VCALENDAR")
+		feed.line("VERSION", "2.0")
+		feed.line("PRODID", "-//VaultCertsViewer//Certificate expirations//EN")
+		feed.line("CALSCALE", "GREGORIAN")
+		feed.line("METHOD", "PUBLISH")
+		feed.line("NAME", icalText("Certificate expirations"))
+		feed.line("X-WR-CALNAME", icalText("Certificate expirations"))
+		feed.line("REFRESH-INTERVAL;VALUE=DURATION", icalDuration(calendarRefresh))
+		feed.line("X-PUBLISHED-TTL", icalDuration(calendarRefresh))
+		events := 0
+		for _, certificate := range result.Certificates {
+			if certificate.ExpiresAt.IsZero() {
+				continue
+			}
+			writeCalendarEvent(feed, certificate, thresholds, now, alarms)
+			events++
+		}
+		feed.line("END", "VCALENDAR")
+		if flushErr := feed.flush(); flushErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, flushErr).
+				Str("request_id", requestID).
+				Msg("failed to write certificate calendar")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("events", events).
+			Bool("alarms", alarms).
+			Msg("certificate calendar served")
+	})
+}
+
+// writeCalendarEvent writes the VEVENT of one certificate. Alarms are only
+// added to certificates that can still alert (not revoked, acknowledged or
+// expired) and only for reminders still ahead.
+func writeCalendarEvent(feed *icalWriter, certificate certs.Certificate, thresholds *certs.ExpiryThresholds, now time.Time, alarms bool) {
+	vaultID, mount := certs.VaultAndMount(certificate.ID)
+	status := thresholds.Status(certificate, now)
+	feed.line("BEGIN", "VEVENT")
+	feed.line("UID", calendarUID(certificate.ID))
+	feed.line("DTSTAMP", now.UTC().Format(icalTimeFormat))
+	feed.line("DTSTART", certificate.ExpiresAt.UTC().Format(icalTimeFormat))
+	feed.line("SUMMARY", icalText("Certificate expires: "+certificate.CommonName))
+	description := []string{
+		"Common name: " + certificate.CommonName,
+		"Vault: " + vaultID,
+		"Mount: " + mount,
+		"Serial: " + certificate.SerialNumber,
+		"Status: " + status,
+		"ID: " + certificate.ID,
+	}
+	if len(certificate.Sans) > 0 {
+		description = append(description, "SANs: "+strings.Join(certificate.Sans, ", "))
+	}
+	if certificate.IssuerCN != "" {
+		description = append(description, "Issuer: "+certificate.IssuerCN)
+	}
+	feed.line("DESCRIPTION", icalText(strings.Join(description, "\n")))
+	feed.line("CATEGORIES", icalText(status))
+	feed.line("TRANSP", "TRANSPARENT")
+	if alarms && status != certs.StatusRevoked && status != certs.StatusExpired && certificate.Acknowledgement == nil {
+		warning, critical := thresholds.WindowFor(vaultID, mount).Lead(certificate)
+		if warning > 0 && warning != critical && certificate.ExpiresAt.Add(-warning).After(now) {
+			writeCalendarAlarm(feed, warning, "Certificate enters the warning window: "+certificate.CommonName)
+		}
+		if critical > 0 && certificate.ExpiresAt.Add(-critical).After(now) {
+			writeCalendarAlarm(feed, critical, "Certificate enters the critical window: "+certificate.CommonName)
+		}
+	}
+	feed.line("END", "VEVENT")
+}
+
+func writeCalendarAlarm(feed *icalWriter, lead time.Duration, description string) {
+	feed.line("BEGIN", "VALARM")
+	feed.line("ACTION", "DISPLAY")
+	feed.line("DESCRIPTION", icalText(description))
+	feed.line("TRIGGER", "-"+icalDuration(lead))
+	feed.line("END", "VALARM")
+}
+
+// calendarUID derives a stable UID from the certificate ID, so clients
+// update a known event on refresh instead of adding a copy.
+func calendarUID(certificateID string) string {
+	sum := sha256.Sum256([]byte(certificateID))
+	return hex.EncodeToString(sum[:16]) + "@vcv"
+}
+
+// icalText escapes a TEXT value (RFC 5545 section 3.3.11).
+func icalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// icalDuration formats a positive duration as P<n>D when it is a whole
+// number of days, else PT<n>H<n>M<n>S.
+func icalDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	if duration%(24*time.Hour) == 0 {
+		return "P" + strconv.FormatInt(int64(duration/(24*time.Hour)), 10) + "D"
+	}
+	var builder strings.Builder
+	builder.WriteString("PT")
+	if hours := duration / time.Hour; hours > 0 {
+		fmt.Fprintf(&builder, "%dH", hours)
+	}
+	if minutes := duration % time.Hour / time.Minute; minutes > 0 {
+		fmt.Fprintf(&builder, "%dM", minutes)
+	}
+	if seconds := duration % time.Minute / time.Second; seconds > 0 || duration < time.Minute {
+		fmt.Fprintf(&builder, "%dS", seconds)
+	}
+	return builder.String()
+}
+
+// icalWriter writes content lines with CRLF endings, folded at 75 octets
+// without splitting UTF-8 sequences. The first write error is kept.
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (feed *icalWriter) line(name, value string) {
+	if feed.err != nil {
+		return
+	}
+	content := name + ":" + value
+	var folded strings.Builder
+	// Continuation lines start with the folding space, which counts.
+	for limit := 75; len(content) > limit; limit = 74 {
+		cut := limit
+		for !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		folded.WriteString(content[:cut])
+		folded.WriteString("\r\n ")
+		content = content[cut:]
+	}
+	folded.WriteString(content)
+	folded.WriteString("\r\n")
+	_, feed.err = feed.w.WriteString(folded.String())
+}
+
+func (feed *icalWriter) flush() error {
+	if feed.err != nil {
+		return feed.err
+	}
+	return feed.w.Flush()
+}
@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+func calendarRouter(t *testing.T, certificates []certs.Certificate) *chi.Mux {
+	t.Helper()
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certificates, nil)
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterCertRoutes(r, mockVault, thresholds)
+	return r
+}
+
+func getCalendar(t *testing.T, router http.Handler, target string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	return rec.Body.String()
+}
+
+// unfold joins folded content lines back together.
+func unfold(feed string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(feed, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+// calendarEvents returns the unfolded lines of each VEVENT.
+func calendarEvents(feed string) [][]string {
+	var events [][]string
+	var current []string
+	for _, line := range unfold(feed) {
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = []string{}
+		case line == "END:VEVENT":
+			events = append(events, current)
+			current = nil
+		case current != nil:
+			current = append(current, line)
+		}
+	}
+	return events
+}
+
+func linesWithPrefix(lines []string, prefix string) []string {
+	var matched []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+func TestCalendar_Feed(t *testing.T) {
+	expiresAt := time.Now().Add(60 * 24 * time.Hour).UTC().Truncate(time.Second)
+	router := calendarRouter(t, []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "api.example.com", SerialNumber: "aa", ExpiresAt: expiresAt, Sans: []string{"api.example.com", "api2.example.com"}},
+		{ID: "v1|pki:bb", CommonName: "undated.example.com"},
+		{ID: "v2|pki:cc", CommonName: "long-" + strings.Repeat("é", 60) + ".example.com", ExpiresAt: expiresAt},
+	})
+
+	feed := getCalendar(t, router, "/api/v1/certs/calendar.ics")
+
+	assert.True(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:"))
+	assert.True(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+	for _, line := range strings.Split(feed, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	events := calendarEvents(feed)
+	require.Len(t, events, 2, "undated certificates have no event")
+
+	first := events[0]
+	assert.Contains(t, first, "DTSTART:"+expiresAt.Format("20060102T150405Z"))
+	assert.Contains(t, first, "SUMMARY:Certificate expires: api.example.com")
+	assert.Contains(t, linesWithPrefix(first, "DESCRIPTION:")[0], `SANs: api.example.com\, api2.example.com`)
+	assert.Contains(t, linesWithPrefix(first, "DESCRIPTION:")[0], `\nVault: v1\n`)
+	assert.Empty(t, linesWithPrefix(first, "BEGIN:VALARM"), "alarms are opt-in")
+	assert.Contains(t, events[1], "SUMMARY:Certificate expires: long-"+strings.Repeat("é", 60)+".example.com")
+
+	uid := linesWithPrefix(first, "UID:")
+	require.Len(t, uid, 1)
+	again := calendarEvents(getCalendar(t, router, "/api/v1/certs/calendar.ics"))
+	assert.Equal(t, uid, linesWithPrefix(again[0], "UID:"), "UIDs are stable across fetches")
+	assert.NotEqual(t, uid, linesWithPrefix(events[1], "UID:"))
+}
+
+func TestCalendar_Filters(t *testing.T) {
+	now := time.Now()
+	router := calendarRouter(t, []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "a.example.com", ExpiresAt: now.Add(60 * 24 * time.Hour)},
+		{ID: "v1|pki_int:bb", CommonName: "b.example.com", ExpiresAt: now.Add(3 * 24 * time.Hour)},
+		{ID: "v2|pki:cc", CommonName: "c.example.com", ExpiresAt: now.Add(-24 * time.Hour)},
+	})
+
+	summaries := func(target string) []string {
+		var result []string
+		for _, event := range calendarEvents(getCalendar(t, router, target)) {
+			result = append(result, linesWithPrefix(event, "SUMMARY:")...)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"SUMMARY:Certificate expires: b.example.com"}, summaries("/api/v1/certs/calendar.ics?status=critical"))
+	assert.Equal(t, []string{"SUMMARY:Certificate expires: a.example.com"}, summaries("/api/v1/certs/calendar.ics?mounts=v1|pki"))
+	assert.Equal(t, []string{"SUMMARY:Certificate expires: c.example.com"}, summaries("/api/v1/certs/calendar.ics?query=vault:v2"))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs/calendar.ics?status=unknown", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCalendar_Alarms(t *testing.T) {
+	now := time.Now()
+	router := calendarRouter(t, []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "later.example.com", ExpiresAt: now.Add(60 * 24 * time.Hour)},
+		{ID: "v1|pki:bb", CommonName: "warning.example.com", ExpiresAt: now.Add(20 * 24 * time.Hour)},
+		{ID: "v1|pki:cc", CommonName: "acknowledged.example.com", ExpiresAt: now.Add(60 * 24 * time.Hour), Acknowledgement: &certs.Acknowledgement{ID: "ack-1"}},
+		{ID: "v1|pki:dd", CommonName: "revoked.example.com", ExpiresAt: now.Add(60 * 24 * time.Hour), Revoked: true},
+	})
+
+	events := calendarEvents(getCalendar(t, router, "/api/v1/certs/calendar.ics?alarms=true"))
+	require.Len(t, events, 4)
+	assert.Equal(t, []string{"TRIGGER:-P30D", "TRIGGER:-P7D"}, linesWithPrefix(events[0], "TRIGGER:"))
+	assert.Equal(t, []string{"TRIGGER:-P7D"}, linesWithPrefix(events[1], "TRIGGER:"), "the warning reminder is already past")
+	assert.Contains(t, events[1], "DESCRIPTION:Certificate enters the critical window: warning.example.com")
+	assert.Empty(t, linesWithPrefix(events[2], "TRIGGER:"))
+	assert.Empty(t, linesWithPrefix(events[3], "TRIGGER:"))
+}
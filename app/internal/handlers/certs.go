@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"vcv/internal/certs"
 	"vcv/internal/logger"
 	"vcv/internal/middleware"
+	"vcv/internal/stats"
 	"vcv/internal/vault"
 )
 
@@ -19,10 +21,16 @@ const mountsAllSentinel = "__all__"
 
 // certsEnvelope is the response shape for GET /api/certs. Errors carries
 // per-vault failures so the UI can surface partial-success state instead of
-// blanking out when one of several vaults is unreachable.
+// blanking out when one of several vaults is unreachable. Total and
+// StatusCounts describe every match, not just the returned page; Page and
+// PageSize are omitted when the request is not paged.
 type certsEnvelope struct {
 	Certificates []certs.Certificate `json:"certificates"`
 	Errors       []vault.VaultError  `json:"errors"`
+	Total        int                 `json:"total"`
+	StatusCounts map[string]int      `json:"statusCounts"`
+	Page         int                 `json:"page,omitempty"`
+	PageSize     int                 `json:"pageSize,omitempty"`
 }
 
 // listCertificatesWithErrors prefers the envelope-aware API when the client
@@ -40,11 +48,21 @@ func listCertificatesWithErrors(ctx context.Context, client vault.Client) ([]cer
 	return certificates, []vault.VaultError{}, nil
 }
 
-func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
-	r.Get("/api/certs", func(w http.ResponseWriter, req *http.Request) {
+// RegisterCertRoutes mounts the certificate read API. thresholds decide the
+// warning and critical statuses used by the status filter of /api/certs.
+func RegisterCertRoutes(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/certs", func(w http.ResponseWriter, req *http.Request) {
 		// Parse mount filter from query parameters
 		selectedMounts := parseMountsQueryParam(req.URL.Query())
 		requestID := middleware.GetRequestID(req.Context())
+		listQuery, queryErr := parseCertListQuery(req.URL.Query())
+		if queryErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, queryErr).
+				Str("request_id", requestID).
+				Msg("invalid certificate list query")
+			writeAPIError(w, req, invalidRequest(queryErr.Error()))
+			return
+		}
 
 		logger.Get().Debug().
 			Str("request_id", requestID).
@@ -53,10 +71,11 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 
 		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
 		if err != nil {
-			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
 				Str("request_id", requestID).
 				Msg("failed to list certificates")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeAPIError(w, req, apiErr)
 			return
 		}
 
@@ -66,33 +85,65 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			Int("vault_errors", len(vaultErrors)).
 			Msg("retrieved certificates from vault")
 
-		filteredCertificates := filterCertificatesByMounts(certificates, selectedMounts)
-		envelope := certsEnvelope{Certificates: filteredCertificates, Errors: vaultErrors}
+		// Annotate before filtering so sharedKeyWith still points at
+		// certificates in mounts the caller did not select.
+		filteredCertificates := filterCertificatesByMounts(certs.AnnotateSharedKeys(certificates), selectedMounts)
+		result := listQuery.apply(filteredCertificates, thresholds, time.Now())
+		envelope := certsEnvelope{
+			Certificates: result.Certificates,
+			Errors:       vaultErrors,
+			Total:        result.Total,
+			StatusCounts: result.StatusCounts,
+			Page:         listQuery.page,
+			PageSize:     listQuery.pageSize,
+		}
+
+		// Read after listing: a listing that filled the cache has just
+		// moved the sync time.
+		if synced := lastSync(vaultClient); !synced.IsZero() {
+			etag := inventoryETag(synced, req.URL.RawQuery, envelope)
+			setValidators(w, etag, synced)
+			if etagMatches(req, etag) {
+				w.WriteHeader(http.StatusNotModified)
+				logger.HTTPEvent(req.Method, req.URL.Path, http.StatusNotModified, 0).
+					Str("request_id", requestID).
+					Msg("certificates not modified")
+				return
+			}
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if encodeErr := json.NewEncoder(w).Encode(envelope); encodeErr != nil {
 			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, encodeErr).
 				Str("request_id", requestID).
 				Msg("failed to encode certificates response")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
 			Str("request_id", requestID).
-			Int("count", len(filteredCertificates)).
+			Int("count", len(result.Certificates)).
+			Int("total", result.Total).
 			Int("vault_errors", len(vaultErrors)).
 			Strs("mounts", selectedMounts).
 			Msg("certificates listed successfully")
 	})
 
-	r.Get("/api/certs/{id}/details", func(w http.ResponseWriter, req *http.Request) {
+	registerCertExportRoute(r, vaultClient, thresholds)
+	registerCertBundleRoutes(r, vaultClient, thresholds)
+	registerStatsRoute(r, vaultClient, thresholds)
+	registerForecastRoute(r, vaultClient, thresholds)
+	registerCalendarRoute(r, vaultClient, thresholds)
+	registerFeedRoutes(r, vaultClient, thresholds)
+	registerCompareRoute(r, vaultClient)
+
+	HandleAPI(r, http.MethodGet, "/certs/{id}/details", func(w http.ResponseWriter, req *http.Request) {
 		certificateID, statusCode, decodeErr := decodeCertificateIDParam(req)
 		if statusCode != http.StatusOK {
 			requestID := middleware.GetRequestID(req.Context())
 			logger.HTTPError(req.Method, req.URL.Path, statusCode, decodeErr).
 				Str("request_id", requestID).
 				Msg("missing certificate id in path")
-			http.Error(w, http.StatusText(statusCode), statusCode)
+			writeAPIError(w, req, invalidRequest("certificate id is missing or malformed"))
 			return
 		}
 
@@ -104,11 +155,12 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 
 		details, err := vaultClient.GetCertificateDetails(req.Context(), certificateID)
 		if err != nil {
-			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
 				Str("request_id", requestID).
 				Str("serial_number", certificateID).
 				Msg("failed to get certificate details")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeAPIError(w, req, apiErr)
 			return
 		}
 
@@ -117,7 +169,6 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
 				Str("request_id", requestID).
 				Msg("failed to encode certificate details response")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
@@ -126,25 +177,26 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			Msg("fetched certificate details")
 	})
 
-	r.Get("/api/certs/{id}/ca", func(w http.ResponseWriter, req *http.Request) {
+	HandleAPI(r, http.MethodGet, "/certs/{id}/ca", func(w http.ResponseWriter, req *http.Request) {
 		certificateID, statusCode, decodeErr := decodeCertificateIDParam(req)
 		if statusCode != http.StatusOK {
 			requestID := middleware.GetRequestID(req.Context())
 			logger.HTTPError(req.Method, req.URL.Path, statusCode, decodeErr).
 				Str("request_id", requestID).
 				Msg("missing certificate id in ca path")
-			http.Error(w, http.StatusText(statusCode), statusCode)
+			writeAPIError(w, req, invalidRequest("certificate id is missing or malformed"))
 			return
 		}
 		vaultMountKey, _ := extractVaultMountFromCertificateID(certificateID)
 		caDetails, err := vaultClient.GetIntermediateCA(req.Context(), vaultMountKey)
 		if err != nil {
 			requestID := middleware.GetRequestID(req.Context())
-			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
 				Str("request_id", requestID).
 				Str("certificate_id", certificateID).
 				Msg("failed to get intermediate CA")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeAPIError(w, req, apiErr)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -153,7 +205,6 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
 				Str("request_id", requestID).
 				Msg("failed to encode CA response")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 		requestID := middleware.GetRequestID(req.Context())
@@ -163,25 +214,26 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			Msg("served intermediate CA")
 	})
 
-	r.Get("/api/certs/{id}/pem", func(w http.ResponseWriter, req *http.Request) {
+	HandleAPI(r, http.MethodGet, "/certs/{id}/pem", func(w http.ResponseWriter, req *http.Request) {
 		certificateID, statusCode, decodeErr := decodeCertificateIDParam(req)
 		if statusCode != http.StatusOK {
 			requestID := middleware.GetRequestID(req.Context())
 			logger.HTTPError(req.Method, req.URL.Path, statusCode, decodeErr).
 				Str("request_id", requestID).
 				Msg("missing certificate id in path")
-			http.Error(w, http.StatusText(statusCode), statusCode)
+			writeAPIError(w, req, invalidRequest("certificate id is missing or malformed"))
 			return
 		}
 
 		pemResponse, err := vaultClient.GetCertificatePEM(req.Context(), certificateID)
 		if err != nil {
 			requestID := middleware.GetRequestID(req.Context())
-			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
 				Str("request_id", requestID).
 				Str("serial_number", certificateID).
 				Msg("failed to get certificate PEM")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeAPIError(w, req, apiErr)
 			return
 		}
 
@@ -191,7 +243,6 @@ func RegisterCertRoutes(r chi.Router, vaultClient vault.Client) {
 			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
 				Str("request_id", requestID).
 				Msg("failed to encode certificate PEM response")
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 		requestID := middleware.GetRequestID(req.Context())
@@ -243,43 +294,122 @@ func parseMountsQueryParam(query url.Values) []string {
 	return mounts
 }
 
+// parseListQueryParam splits a comma-separated query parameter into trimmed,
+// non-empty values. Returns nil when the parameter is absent or blank.
+func parseListQueryParam(query url.Values, name string) []string {
+	raw := strings.TrimSpace(query.Get(name))
+	if raw == "" {
+		return nil
+	}
+	values := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+// filterCertificatesByCertTypes keeps certificates whose CertType is one of
+// certTypes. A nil or empty list disables the filter.
+func filterCertificatesByCertTypes(certificates []certs.Certificate, certTypes []string) []certs.Certificate {
+	if len(certTypes) == 0 {
+		return certificates
+	}
+	filtered := make([]certs.Certificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		for _, certType := range certTypes {
+			if strings.EqualFold(certificate.CertType, certType) {
+				filtered = append(filtered, certificate)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 // filterCertificatesByMounts filters certificates by the specified mounts
 func filterCertificatesByMounts(certificates []certs.Certificate, selectedMounts []string) []certs.Certificate {
 	if selectedMounts == nil {
 		return certificates
 	}
-	if len(selectedMounts) == 0 {
-		return []certs.Certificate{}
+	filter := newMountFilter(selectedMounts)
+	filtered := make([]certs.Certificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		if filter.matchesCertificate(certificate.ID) {
+			filtered = append(filtered, certificate)
+		}
 	}
+	return filtered
+}
+
+// mountFilter matches certificate IDs against a parsed mounts parameter.
+// Entries are "vault_id|mount" keys or bare mount names, which match the
+// mount in every vault. A nil filter matches everything.
+type mountFilter struct {
+	keys         map[string]struct{}
+	legacyMounts map[string]struct{}
+}
 
-	selectedSet := make(map[string]struct{}, len(selectedMounts))
-	selectedLegacyMounts := make(map[string]struct{}, len(selectedMounts))
+func newMountFilter(selectedMounts []string) *mountFilter {
+	if selectedMounts == nil {
+		return nil
+	}
+	filter := &mountFilter{
+		keys:         make(map[string]struct{}, len(selectedMounts)),
+		legacyMounts: make(map[string]struct{}, len(selectedMounts)),
+	}
 	for _, selectedMount := range selectedMounts {
 		trimmed := strings.TrimSpace(selectedMount)
 		if trimmed == "" {
 			continue
 		}
-		selectedSet[trimmed] = struct{}{}
+		filter.keys[trimmed] = struct{}{}
 		if !strings.Contains(trimmed, "|") {
-			selectedLegacyMounts[trimmed] = struct{}{}
+			filter.legacyMounts[trimmed] = struct{}{}
 		}
 	}
+	return filter
+}
 
-	filtered := make([]certs.Certificate, 0, len(certificates))
-	for _, certificate := range certificates {
-		vaultMountKey, mountName := extractVaultMountFromCertificateID(certificate.ID)
-		if vaultMountKey == "" && mountName == "" {
-			continue
-		}
-		if _, ok := selectedSet[vaultMountKey]; ok {
-			filtered = append(filtered, certificate)
-			continue
-		}
-		if _, ok := selectedLegacyMounts[mountName]; ok {
-			filtered = append(filtered, certificate)
+func (filter *mountFilter) matchesCertificate(certificateID string) bool {
+	if filter == nil {
+		return true
+	}
+	vaultMountKey, mountName := extractVaultMountFromCertificateID(certificateID)
+	if vaultMountKey == "" && mountName == "" {
+		return false
+	}
+	if _, ok := filter.keys[vaultMountKey]; ok {
+		return true
+	}
+	_, ok := filter.legacyMounts[mountName]
+	return ok
+}
+
+// matchesMount reports whether the mount of vaultID is selected.
+func (filter *mountFilter) matchesMount(vaultID, mount string) bool {
+	if filter == nil {
+		return true
+	}
+	if _, ok := filter.keys[stats.MountKey(vaultID, mount)]; ok {
+		return true
+	}
+	_, ok := filter.legacyMounts[mount]
+	return ok
+}
+
+// matchesVault reports whether any selected mount can belong to vaultID.
+func (filter *mountFilter) matchesVault(vaultID string) bool {
+	if filter == nil || len(filter.legacyMounts) > 0 {
+		return true
+	}
+	for key := range filter.keys {
+		if strings.HasPrefix(key, vaultID+"|") {
+			return true
 		}
 	}
-	return filtered
+	return false
 }
 
 func extractVaultMountFromCertificateID(value string) (string, string) {
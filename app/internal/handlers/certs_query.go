@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vcv/internal/certquery"
+	"vcv/internal/certs"
+)
+
+const maxCertsPageSize = 1000
+
+var certListStatuses = []string{certs.StatusValid, certs.StatusWarning, certs.StatusCritical, certs.StatusExpired, certs.StatusRevoked}
+
+// certListQuery holds the filter, sort and paging parameters of GET
+// /api/certs. Zero values disable the corresponding step.
+type certListQuery struct {
+	search         string
+	expression     *certquery.Query
+	statuses       []string
+	expiringWithin time.Duration
+	issuer         string
+	keyAlgorithms  []string
+	certTypes      []string
+	sortKey        string
+	descending     bool
+	page           int
+	pageSize       int
+}
+
+// certListResult is one page of filtered certificates. Total counts every
+// match; StatusCounts counts matches per status as if no status filter had
+// been given, so the UI can label its status chips.
+type certListResult struct {
+	Certificates []certs.Certificate
+	Total        int
+	StatusCounts map[string]int
+}
+
+// parseCertListQuery validates the query parameters and reports the first
+// invalid one.
+func parseCertListQuery(query url.Values) (certListQuery, error) {
+	parsed := certListQuery{
+		search:        strings.ToLower(strings.TrimSpace(query.Get("q"))),
+		issuer:        strings.ToLower(strings.TrimSpace(query.Get("issuer"))),
+		keyAlgorithms: parseListQueryParam(query, "key_algorithm"),
+		certTypes:     parseListQueryParam(query, "cert_type"),
+	}
+	if raw := strings.TrimSpace(query.Get("query")); raw != "" {
+		expression, err := certquery.Parse(raw)
+		if err != nil {
+			return certListQuery{}, err
+		}
+		parsed.expression = expression
+	}
+	for _, status := range parseListQueryParam(query, "status") {
+		normalized := strings.ToLower(status)
+		if !slices.Contains(certListStatuses, normalized) {
+			return certListQuery{}, fmt.Errorf("status: unknown status %q", status)
+		}
+		parsed.statuses = append(parsed.statuses, normalized)
+	}
+	if raw := strings.TrimSpace(query.Get("expiring_within")); raw != "" {
+		within, err := certs.ParseThresholdDuration(raw)
+		if err != nil {
+			return certListQuery{}, fmt.Errorf("expiring_within: %w", err)
+		}
+		parsed.expiringWithin = within
+	}
+	if raw := strings.TrimSpace(query.Get("sort")); raw != "" {
+		if _, ok := certSortKeys[raw]; !ok {
+			return certListQuery{}, fmt.Errorf("sort: unknown key %q", raw)
+		}
+		parsed.sortKey = raw
+	}
+	switch order := strings.ToLower(strings.TrimSpace(query.Get("order"))); order {
+	case "", "asc":
+	case "desc":
+		parsed.descending = true
+	default:
+		return certListQuery{}, fmt.Errorf("order: must be asc or desc")
+	}
+	if raw := strings.TrimSpace(query.Get("page_size")); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > maxCertsPageSize {
+			return certListQuery{}, fmt.Errorf("page_size: must be between 1 and %d", maxCertsPageSize)
+		}
+		parsed.pageSize = size
+		parsed.page = 1
+	}
+	if raw := strings.TrimSpace(query.Get("page")); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return certListQuery{}, fmt.Errorf("page: must be a positive integer")
+		}
+		if parsed.pageSize == 0 {
+			return certListQuery{}, fmt.Errorf("page: requires page_size")
+		}
+		parsed.page = page
+	}
+	return parsed, nil
+}
+
+// apply filters, sorts and pages certificates. The input is not modified.
+func (query certListQuery) apply(certificates []certs.Certificate, thresholds *certs.ExpiryThresholds, now time.Time) certListResult {
+	result := certListResult{StatusCounts: make(map[string]int, len(certListStatuses))}
+	for _, status := range certListStatuses {
+		result.StatusCounts[status] = 0
+	}
+	env := certquery.Env{Now: now, Thresholds: thresholds}
+	matched := make([]certs.Certificate, 0, len(certificates))
+	for _, certificate := range filterCertificatesByCertTypes(certificates, query.certTypes) {
+		if !query.matches(certificate, now) || !query.expression.Match(certificate, env) {
+			continue
+		}
+		status := thresholds.Status(certificate, now)
+		result.StatusCounts[status]++
+		if len(query.statuses) > 0 && !slices.Contains(query.statuses, status) {
+			continue
+		}
+		matched = append(matched, certificate)
+	}
+	if query.sortKey != "" {
+		less := certSortKeys[query.sortKey]
+		sort.SliceStable(matched, func(i, j int) bool {
+			if query.descending {
+				return less(matched[j], matched[i])
+			}
+			return less(matched[i], matched[j])
+		})
+	}
+	result.Total = len(matched)
+	if query.pageSize > 0 {
+		start := (query.page - 1) * query.pageSize
+		if start > len(matched) {
+			start = len(matched)
+		}
+		end := start + query.pageSize
+		if end > len(matched) {
+			end = len(matched)
+		}
+		matched = matched[start:end]
+	}
+	result.Certificates = matched
+	return result
+}
+
+func (query certListQuery) matches(certificate certs.Certificate, now time.Time) bool {
+	if query.search != "" && !matchesCertSearch(certificate, query.search) {
+		return false
+	}
+	if query.issuer != "" && !strings.Contains(strings.ToLower(certificate.IssuerCN), query.issuer) {
+		return false
+	}
+	if len(query.keyAlgorithms) > 0 && !matchesKeyAlgorithm(certificate, query.keyAlgorithms) {
+		return false
+	}
+	if query.expiringWithin > 0 {
+		if certificate.Revoked || certificate.ExpiresAt.Before(now) || certificate.ExpiresAt.After(now.Add(query.expiringWithin)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesCertSearch mirrors the UI search box: a case-insensitive substring
+// of the common name, serial number or any SAN.
+func matchesCertSearch(certificate certs.Certificate, search string) bool {
+	if strings.Contains(strings.ToLower(certificate.CommonName), search) ||
+		strings.Contains(strings.ToLower(certificate.SerialNumber), search) {
+		return true
+	}
+	for _, san := range certificate.Sans {
+		if strings.Contains(strings.ToLower(san), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesKeyAlgorithm accepts a bare algorithm ("ECDSA") or an algorithm and
+// size ("RSA-2048").
+func matchesKeyAlgorithm(certificate certs.Certificate, algorithms []string) bool {
+	withSize := certificate.KeyAlgorithm + "-" + strconv.Itoa(certificate.KeySize)
+	for _, algorithm := range algorithms {
+		if strings.EqualFold(algorithm, certificate.KeyAlgorithm) || strings.EqualFold(algorithm, withSize) {
+			return true
+		}
+	}
+	return false
+}
+
+var certSortKeys = map[string]func(a, b certs.Certificate) bool{
+	"commonName": func(a, b certs.Certificate) bool {
+		return strings.ToLower(a.CommonName) < strings.ToLower(b.CommonName)
+	},
+	"expiresAt": func(a, b certs.Certificate) bool { return a.ExpiresAt.Before(b.ExpiresAt) },
+	"createdAt": func(a, b certs.Certificate) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	"serialNumber": func(a, b certs.Certificate) bool {
+		return strings.ToLower(a.SerialNumber) < strings.ToLower(b.SerialNumber)
+	},
+	"vault": func(a, b certs.Certificate) bool {
+		aVault, _ := certs.VaultAndMount(a.ID)
+		bVault, _ := certs.VaultAndMount(b.ID)
+		return strings.ToLower(aVault) < strings.ToLower(bVault)
+	},
+	"pki": func(a, b certs.Certificate) bool {
+		_, aMount := certs.VaultAndMount(a.ID)
+		_, bMount := certs.VaultAndMount(b.ID)
+		return strings.ToLower(aMount) < strings.ToLower(bMount)
+	},
+	"issuer": func(a, b certs.Certificate) bool {
+		return strings.ToLower(a.IssuerCN) < strings.ToLower(b.IssuerCN)
+	},
+}
@@ -6,14 +6,17 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"vcv/internal/certs"
+	"vcv/internal/config"
 	"vcv/internal/handlers"
 	"vcv/internal/middleware"
 	"vcv/internal/vault"
@@ -22,13 +25,17 @@ import (
 func setupRouter(mockVault *vault.MockClient) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	handlers.RegisterCertRoutes(r, mockVault)
+	handlers.RegisterCertRoutes(r, mockVault, nil)
 	return r
 }
 
 type certsEnvelopeResponse struct {
 	Certificates []certs.Certificate `json:"certificates"`
 	Errors       []vault.VaultError  `json:"errors"`
+	Total        int                 `json:"total"`
+	StatusCounts map[string]int      `json:"statusCounts"`
+	Page         int                 `json:"page"`
+	PageSize     int                 `json:"pageSize"`
 }
 
 func TestListCertificates_Success(t *testing.T) {
@@ -70,7 +77,7 @@ func TestListCertificates_Envelope_PartialSuccess(t *testing.T) {
 	}
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	handlers.RegisterCertRoutes(r, envClient)
+	handlers.RegisterCertRoutes(r, envClient, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/certs", nil)
 	rec := httptest.NewRecorder()
@@ -240,6 +247,94 @@ func TestListCertificates_MountsQuery_EmptyReturnsEmpty(t *testing.T) {
 	mockVault.AssertExpectations(t)
 }
 
+func TestListCertificates_CertTypeQuery_FiltersCaseInsensitive(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	certsList := []certs.Certificate{
+		{ID: "pki:a", CommonName: "a", CertType: "machine", ExpiresAt: time.Now()},
+		{ID: "pki:b", CommonName: "b", CertType: "code-signing", ExpiresAt: time.Now()},
+		{ID: "pki:c", CommonName: "c", CertType: "user", ExpiresAt: time.Now()},
+	}
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	router := setupRouter(mockVault)
+	req := httptest.NewRequest(http.MethodGet, "/api/certs?cert_type=Code-Signing,user", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got certsEnvelopeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Certificates, 2)
+	assert.Equal(t, "pki:b", got.Certificates[0].ID)
+	assert.Equal(t, "pki:c", got.Certificates[1].ID)
+}
+
+func TestListCertificates_QueryFilterSortAndPage(t *testing.T) {
+	now := time.Now()
+	mockVault := new(vault.MockClient)
+	certsList := []certs.Certificate{
+		{ID: "pki:a", CommonName: "api.example.com", IssuerCN: "Issuing CA", KeyAlgorithm: "RSA", KeySize: 2048, ExpiresAt: now.Add(3 * 24 * time.Hour)},
+		{ID: "pki:b", CommonName: "web.example.com", IssuerCN: "Issuing CA", KeyAlgorithm: "ECDSA", KeySize: 256, ExpiresAt: now.Add(20 * 24 * time.Hour)},
+		{ID: "pki:c", CommonName: "db.example.com", IssuerCN: "Other CA", KeyAlgorithm: "RSA", KeySize: 4096, ExpiresAt: now.Add(200 * 24 * time.Hour)},
+		{ID: "pki:d", CommonName: "old.example.com", IssuerCN: "Issuing CA", KeyAlgorithm: "RSA", KeySize: 2048, ExpiresAt: now.Add(-24 * time.Hour)},
+		{ID: "pki:e", CommonName: "mail.internal", IssuerCN: "Issuing CA", KeyAlgorithm: "RSA", KeySize: 2048, ExpiresAt: now.Add(10 * 24 * time.Hour), Revoked: true},
+	}
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	handlers.RegisterCertRoutes(router, mockVault, thresholds)
+
+	tests := []struct {
+		name         string
+		query        string
+		wantIDs      []string
+		wantTotal    int
+		wantCritical int
+	}{
+		{name: "no parameters", query: "", wantIDs: []string{"pki:a", "pki:b", "pki:c", "pki:d", "pki:e"}, wantTotal: 5, wantCritical: 1},
+		{name: "search", query: "q=EXAMPLE.com", wantIDs: []string{"pki:a", "pki:b", "pki:c", "pki:d"}, wantTotal: 4, wantCritical: 1},
+		{name: "status", query: "status=critical,warning", wantIDs: []string{"pki:a", "pki:b"}, wantTotal: 2, wantCritical: 1},
+		{name: "expiring within", query: "expiring_within=30d", wantIDs: []string{"pki:a", "pki:b"}, wantTotal: 2, wantCritical: 1},
+		{name: "issuer", query: "issuer=other", wantIDs: []string{"pki:c"}, wantTotal: 1, wantCritical: 0},
+		{name: "key algorithm", query: "key_algorithm=RSA-2048,ecdsa", wantIDs: []string{"pki:a", "pki:b", "pki:d", "pki:e"}, wantTotal: 4, wantCritical: 1},
+		{name: "sort desc", query: "sort=commonName&order=desc", wantIDs: []string{"pki:b", "pki:d", "pki:e", "pki:c", "pki:a"}, wantTotal: 5, wantCritical: 1},
+		{name: "page", query: "sort=expiresAt&page=2&page_size=2", wantIDs: []string{"pki:e", "pki:b"}, wantTotal: 5, wantCritical: 1},
+		{name: "query language", query: "query=" + url.QueryEscape("issuer:issuing AND (key:ECDSA OR status:critical)"), wantIDs: []string{"pki:a", "pki:b"}, wantTotal: 2, wantCritical: 1},
+		{name: "page past the end", query: "page=9&page_size=2", wantIDs: []string{}, wantTotal: 5, wantCritical: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/certs?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			var got certsEnvelopeResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			ids := make([]string, 0, len(got.Certificates))
+			for _, certificate := range got.Certificates {
+				ids = append(ids, certificate.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTotal, got.Total)
+			assert.Equal(t, tt.wantCritical, got.StatusCounts["critical"])
+		})
+	}
+}
+
+func TestListCertificates_InvalidQuery(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	router := setupRouter(mockVault)
+	for _, query := range []string{"status=unknown", "expiring_within=soon", "sort=color", "order=up", "page_size=0", "page_size=5000", "page=2", "page=0&page_size=10", "query=cn%3A"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/certs?"+query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+	mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+}
+
 func TestListCertificates_EncodingError_DoesNotPanic(t *testing.T) {
 	mockVault := new(vault.MockClient)
 	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, nil)
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// compareResponse is the body of /api/certs/compare: the inventory entries
+// of both certificates and their field-by-field comparison.
+type compareResponse struct {
+	A certs.Certificate `json:"a"`
+	B certs.Certificate `json:"b"`
+	certs.Comparison
+}
+
+// registerCompareRoute mounts /api/certs/compare?a=<id>&b=<id>, which
+// diffs certificate b against certificate a. The IDs may belong to
+// different vaults.
+func registerCompareRoute(r chi.Router, vaultClient vault.Client) {
+	HandleAPI(r, http.MethodGet, "/certs/compare", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		idA, idB := strings.TrimSpace(query.Get("a")), strings.TrimSpace(query.Get("b"))
+		if idA == "" || idB == "" {
+			err := fmt.Errorf("a and b: both certificate IDs are required")
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, err).
+				Str("request_id", requestID).
+				Msg("invalid certificate comparison query")
+			writeAPIError(w, req, invalidRequest(err.Error()))
+			return
+		}
+
+		var response compareResponse
+		var parsed [2]*x509.Certificate
+		for i, id := range []string{idA, idB} {
+			details, certificate, err := loadCertificateForComparison(req.Context(), vaultClient, id)
+			if err != nil {
+				apiErr := classifyError(err)
+				logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+					Str("request_id", requestID).
+					Str("certificate_id", id).
+					Msg("failed to load certificate for comparison")
+				writeAPIError(w, req, apiErr)
+				return
+			}
+			if i == 0 {
+				response.A = details.Certificate
+			} else {
+				response.B = details.Certificate
+			}
+			parsed[i] = certificate
+		}
+		response.Comparison = certs.CompareCertificates(parsed[0], parsed[1])
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode certificate comparison")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("certificate_a", idA).
+			Str("certificate_b", idB).
+			Strs("changed", response.Changed).
+			Msg("compared certificates")
+	})
+}
+
+// loadCertificateForComparison reads the details of a certificate and
+// parses the leaf of its PEM.
+func loadCertificateForComparison(ctx context.Context, vaultClient vault.Client, id string) (certs.DetailedCertificate, *x509.Certificate, error) {
+	details, err := vaultClient.GetCertificateDetails(ctx, id)
+	if err != nil {
+		return certs.DetailedCertificate{}, nil, err
+	}
+	ders, err := certs.DecodePEMCertificates(details.PEM)
+	if err != nil {
+		return certs.DetailedCertificate{}, nil, fmt.Errorf("%w: certificate %s: %w", vault.ErrVaultResponse, id, err)
+	}
+	certificate, err := x509.ParseCertificate(ders[0])
+	if err != nil {
+		return certs.DetailedCertificate{}, nil, fmt.Errorf("%w: failed to parse certificate %s: %w", vault.ErrVaultResponse, id, err)
+	}
+	return details, certificate, nil
+}
@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/vault"
+)
+
+func TestCompareCertificates_AcrossVaults(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificateDetails", mock.Anything, "v1|pki:aa").Return(certs.DetailedCertificate{
+		Certificate: certs.Certificate{ID: "v1|pki:aa", CommonName: "old.example.com"},
+		PEM:         newBundleTestPEM(t, "old.example.com"),
+	}, nil)
+	mockVault.On("GetCertificateDetails", mock.Anything, "v2|pki:bb").Return(certs.DetailedCertificate{
+		Certificate: certs.Certificate{ID: "v2|pki:bb", CommonName: "new.example.com"},
+		PEM:         newBundleTestPEM(t, "new.example.com"),
+	}, nil)
+	router := setupRouter(mockVault)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs/compare?a=v1%7Cpki%3Aaa&b=v2%7Cpki%3Abb", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		A         certs.Certificate
+		B         certs.Certificate
+		Identical bool
+		Subject   certs.ValueChange
+		Changed   []string
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "v1|pki:aa", body.A.ID)
+	assert.Equal(t, "v2|pki:bb", body.B.ID)
+	assert.False(t, body.Identical)
+	assert.Equal(t, certs.ValueChange{A: "CN=old.example.com", B: "CN=new.example.com", Changed: true}, body.Subject)
+	assert.Contains(t, body.Changed, "subject")
+	assert.NotContains(t, body.Changed, "keyAlgorithm")
+}
+
+func TestCompareCertificates_Errors(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("GetCertificateDetails", mock.Anything, "v1|pki:aa").Return(certs.DetailedCertificate{
+		PEM: newBundleTestPEM(t, "a.example.com"),
+	}, nil)
+	mockVault.On("GetCertificateDetails", mock.Anything, "v1|pki:missing").Return(certs.DetailedCertificate{}, vault.ErrCertificateNotFound)
+	mockVault.On("GetCertificateDetails", mock.Anything, "v1|pki:nopem").Return(certs.DetailedCertificate{}, nil)
+	router := setupRouter(mockVault)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{target: "/api/v1/certs/compare?a=v1%7Cpki%3Aaa", status: http.StatusBadRequest},
+		{target: "/api/v1/certs/compare?a=v1%7Cpki%3Aaa&b=v1%7Cpki%3Amissing", status: http.StatusNotFound},
+		{target: "/api/v1/certs/compare?a=v1%7Cpki%3Anopem&b=v1%7Cpki%3Aaa", status: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.Equal(t, tt.status, rec.Code, tt.target)
+	}
+}
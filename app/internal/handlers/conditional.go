@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"hash"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vcv/internal/certs"
+	"vcv/internal/vault"
+)
+
+// lastSync returns when client last read its listing from Vault, or the
+// zero time when the client does not report it. Without a sync time there
+// is no snapshot version, so responses carry no validators.
+func lastSync(client vault.Client) time.Time {
+	if reporter, ok := client.(vault.SyncReporter); ok {
+		return reporter.LastSync()
+	}
+	return time.Time{}
+}
+
+// inventoryETag identifies a /api/certs response without encoding it. It
+// hashes the snapshot version (the last sync), the raw query and the parts
+// of the response that can change between syncs: classification, mount
+// policies, acknowledgements and expiry statuses depend on configuration
+// and on the current time, not only on what was read from Vault.
+//
+// The tag is weak because the compression middleware may re-encode the
+// body; the decoded JSON is the same.
+func inventoryETag(synced time.Time, rawQuery string, envelope certsEnvelope) string {
+	h := fnv.New128a()
+	writeETagField(h, strconv.FormatInt(synced.UnixNano(), 10))
+	writeETagField(h, rawQuery)
+	writeETagField(h, strconv.Itoa(envelope.Total))
+	statuses := make([]string, 0, len(envelope.StatusCounts))
+	for status := range envelope.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	for _, status := range statuses {
+		writeETagField(h, status+"="+strconv.Itoa(envelope.StatusCounts[status]))
+	}
+	for _, vaultErr := range envelope.Errors {
+		writeETagField(h, vaultErr.VaultID+"="+vaultErr.Message)
+	}
+	for _, certificate := range envelope.Certificates {
+		writeETagCertificate(h, certificate)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+func writeETagCertificate(h hash.Hash, certificate certs.Certificate) {
+	writeETagField(h, certificate.ID)
+	writeETagField(h, certificate.CertType)
+	writeETagField(h, certificate.CertLabel)
+	writeETagField(h, strconv.Itoa(certificate.RollupCount))
+	writeETagField(h, strings.Join(certificate.SharedKeyWith, ","))
+	if ack := certificate.Acknowledgement; ack != nil {
+		writeETagField(h, ack.ID)
+		writeETagField(h, ack.Reason)
+		if ack.ExpiresAt != nil {
+			writeETagField(h, strconv.FormatInt(ack.ExpiresAt.UnixNano(), 10))
+		}
+	}
+}
+
+func writeETagField(h hash.Hash, value string) {
+	_, _ = h.Write([]byte(value))
+	_, _ = h.Write([]byte{0})
+}
+
+// setValidators sets the ETag and Last-Modified headers. no-cache lets
+// browsers store the response but makes them revalidate it on every poll.
+func setValidators(w http.ResponseWriter, etag string, synced time.Time) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", synced.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "private, no-cache")
+}
+
+// etagMatches reports whether the If-None-Match header of req matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+// If-Modified-Since is not evaluated: Last-Modified only moves on a sync, so
+// it cannot see acknowledgement or configuration changes the ETag covers.
+func etagMatches(req *http.Request, etag string) bool {
+	header := strings.TrimSpace(req.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
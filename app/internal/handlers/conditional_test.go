@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// syncedMockClient is a MockClient that reports a sync time.
+type syncedMockClient struct {
+	*vault.MockClient
+	synced time.Time
+}
+
+func (c *syncedMockClient) LastSync() time.Time {
+	return c.synced
+}
+
+func setupSyncedRouter(client vault.Client) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterCertRoutes(r, client, nil)
+	return r
+}
+
+func getCerts(router http.Handler, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListCertificates_ConditionalRequests(t *testing.T) {
+	synced := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockVault := new(vault.MockClient)
+	certificates := []certs.Certificate{{ID: "v1|pki:aa", CommonName: "a", ExpiresAt: synced.Add(90 * 24 * time.Hour)}}
+	mockVault.On("ListCertificates", mock.Anything).Return(certificates, nil)
+	client := &syncedMockClient{MockClient: mockVault, synced: synced}
+	router := setupSyncedRouter(client)
+
+	first := getCerts(router, "/api/v1/certs", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+	assert.Equal(t, "Sun, 01 Mar 2026 12:00:00 GMT", first.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	t.Run("matching tag", func(t *testing.T) {
+		rec := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+		assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+	})
+	t.Run("tag list and strong form", func(t *testing.T) {
+		strong := etag[len("W/"):]
+		rec := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": `"other", ` + strong})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+	t.Run("wildcard", func(t *testing.T) {
+		rec := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": "*"})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+	t.Run("stale tag", func(t *testing.T) {
+		rec := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": `W/"stale"`})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+	t.Run("other query", func(t *testing.T) {
+		rec := getCerts(router, "/api/v1/certs?q=a", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+	t.Run("new sync", func(t *testing.T) {
+		client.synced = synced.Add(time.Minute)
+		defer func() { client.synced = synced }()
+		rec := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Sun, 01 Mar 2026 12:01:00 GMT", rec.Header().Get("Last-Modified"))
+	})
+}
+
+func TestListCertificates_ETagTracksAcknowledgements(t *testing.T) {
+	synced := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	certificate := certs.Certificate{ID: "v1|pki:aa", CommonName: "a", ExpiresAt: synced.Add(time.Hour)}
+	acknowledged := certificate
+	acknowledged.Acknowledgement = &certs.Acknowledgement{ID: "ack-1", Reason: "decommissioned"}
+
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{certificate}, nil).Once()
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{acknowledged}, nil).Once()
+	router := setupSyncedRouter(&syncedMockClient{MockClient: mockVault, synced: synced})
+
+	before := getCerts(router, "/api/v1/certs", nil)
+	after := getCerts(router, "/api/v1/certs", map[string]string{"If-None-Match": before.Header().Get("ETag")})
+	assert.Equal(t, http.StatusOK, after.Code)
+	assert.NotEqual(t, before.Header().Get("ETag"), after.Header().Get("ETag"))
+}
+
+func TestListCertificates_NoValidatorsWithoutSyncTime(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, nil)
+	rec := getCerts(setupRouter(mockVault), "/api/v1/certs", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+}
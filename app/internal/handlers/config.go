@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strings"
 
+	"vcv/internal/certs"
 	"vcv/internal/config"
 	"vcv/internal/logger"
 	"vcv/internal/middleware"
@@ -13,16 +15,72 @@ import (
 
 // ConfigResponse holds the public configuration exposed to the frontend.
 type ConfigResponse struct {
-	ExpirationThresholds struct {
-		Critical int `json:"critical"`
-		Warning  int `json:"warning"`
-	} `json:"expirationThresholds"`
-	Metrics struct {
+	ExpirationThresholds ExpirationThresholdsResponse `json:"expirationThresholds"`
+	Metrics              struct {
 		PerCertificate  bool `json:"per_certificate"`
 		EnhancedMetrics bool `json:"enhanced_metrics"`
 	} `json:"metrics"`
 	PKIMounts []string              `json:"pkiMounts"`
 	Vaults    []VaultConfigResponse `json:"vaults"`
+	// CertTypes lists every certType value the inventory can carry, built-in
+	// types first, so the UI filter can offer custom classification types.
+	CertTypes []certs.CertTypeInfo `json:"certTypes"`
+}
+
+// ExpirationThresholdsResponse is the resolved form of one thresholds entry.
+// Critical and Warning stay in whole days (rounded up) for older clients;
+// the seconds and percent fields carry the exact windows.
+type ExpirationThresholdsResponse struct {
+	Critical        int                                     `json:"critical"`
+	Warning         int                                     `json:"warning"`
+	CriticalSeconds int64                                   `json:"criticalSeconds"`
+	WarningSeconds  int64                                   `json:"warningSeconds"`
+	CriticalPercent float64                                 `json:"criticalPercent,omitempty"`
+	WarningPercent  float64                                 `json:"warningPercent,omitempty"`
+	Mounts          map[string]ExpirationThresholdsResponse `json:"mounts,omitempty"`
+}
+
+func newExpirationThresholdsResponse(window certs.ExpiryWindow) ExpirationThresholdsResponse {
+	return ExpirationThresholdsResponse{
+		Critical:        int(math.Ceil(window.Critical.Hours() / 24)),
+		Warning:         int(math.Ceil(window.Warning.Hours() / 24)),
+		CriticalSeconds: int64(window.Critical.Seconds()),
+		WarningSeconds:  int64(window.Warning.Seconds()),
+		CriticalPercent: window.CriticalPercent,
+		WarningPercent:  window.WarningPercent,
+	}
+}
+
+// expirationThresholdsResponse resolves the windows the UI needs: the global
+// window, the global mount overrides and, for every configured vault mount
+// whose window differs from the global one, a resolved "vault_id|mount"
+// entry, so the client only has to look up "vault|mount", then mount.
+func expirationThresholdsResponse(expiry *certs.ExpiryThresholds, vaults []config.VaultInstance) ExpirationThresholdsResponse {
+	resp := newExpirationThresholdsResponse(expiry.Global())
+	mountWindows := expiry.MountWindows()
+	mounts := make(map[string]ExpirationThresholdsResponse)
+	for key, window := range mountWindows {
+		mounts[key] = newExpirationThresholdsResponse(window)
+	}
+	for _, instance := range vaults {
+		for _, mount := range publicVaultPKIMounts(instance) {
+			key := instance.ID + "|" + mount
+			if _, ok := mounts[key]; ok {
+				continue
+			}
+			fallback, ok := mountWindows[mount]
+			if !ok {
+				fallback = expiry.Global()
+			}
+			if window := expiry.WindowFor(instance.ID, mount); window != fallback {
+				mounts[key] = newExpirationThresholdsResponse(window)
+			}
+		}
+	}
+	if len(mounts) > 0 {
+		resp.Mounts = mounts
+	}
+	return resp
 }
 
 type VaultConfigResponse struct {
@@ -43,26 +101,28 @@ func publicVaultPKIMounts(instance config.VaultInstance) []string {
 	return []string{}
 }
 
-// GetConfig returns the application configuration.
-func GetConfig(cfg config.Config, vaultRegistry *vault.Registry) http.HandlerFunc {
+// GetConfig returns the application configuration. classifier and
+// thresholds are the ones built from cfg at startup; a nil classifier lists
+// the built-in certificate types only.
+func GetConfig(cfg config.Config, vaultRegistry *vault.Registry, classifier *certs.Classifier, thresholds *certs.ExpiryThresholds) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetRequestID(r.Context())
 
 		resp := ConfigResponse{}
-		resp.ExpirationThresholds.Critical = cfg.ExpirationThresholds.Critical
-		resp.ExpirationThresholds.Warning = cfg.ExpirationThresholds.Warning
+		allVaults := cfg.AllVaults
+		if len(allVaults) == 0 {
+			allVaults = cfg.Vaults
+		}
+		resp.ExpirationThresholds = expirationThresholdsResponse(thresholds, allVaults)
 		resp.Metrics.PerCertificate = cfg.Metrics.PerCertificate
 		resp.Metrics.EnhancedMetrics = cfg.Metrics.EnhancedMetrics
+		resp.CertTypes = classifier.Types()
 		// Top-level PKIMounts remains the legacy primary snapshot when present.
 		// Otherwise empty (do not invent defaults for the public API).
 		resp.PKIMounts = cfg.Vault.PKIMounts
 		if resp.PKIMounts == nil {
 			resp.PKIMounts = []string{}
 		}
-		allVaults := cfg.AllVaults
-		if len(allVaults) == 0 {
-			allVaults = cfg.Vaults
-		}
 		resp.Vaults = make([]VaultConfigResponse, 0, len(allVaults))
 		for _, instance := range allVaults {
 			vaultID := instance.ID
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"vcv/internal/certs"
 	"vcv/internal/config"
 )
 
@@ -29,6 +30,16 @@ func (w *failingResponseWriter) Write([]byte) (int, error) {
 func (w *failingResponseWriter) WriteHeader(statusCode int) {
 }
 
+// mustExpiryThresholds builds the thresholds main.go passes to GetConfig.
+func mustExpiryThresholds(t *testing.T, cfg config.Config) *certs.ExpiryThresholds {
+	t.Helper()
+	thresholds, err := certs.NewExpiryThresholds(cfg.ExpirationThresholds, cfg.AllVaults)
+	if err != nil {
+		t.Fatalf("failed to build expiry thresholds: %v", err)
+	}
+	return thresholds
+}
+
 func TestGetConfig_Success(t *testing.T) {
 	cfg := config.Config{
 		ExpirationThresholds: config.ExpirationThresholds{
@@ -37,7 +48,7 @@ func TestGetConfig_Success(t *testing.T) {
 		},
 	}
 
-	handler := GetConfig(cfg, nil)
+	handler := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
 	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
 	w := httptest.NewRecorder()
 
@@ -72,7 +83,7 @@ func TestGetConfig_CustomValues(t *testing.T) {
 		},
 	}
 
-	handler := GetConfig(cfg, nil)
+	handler := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
 	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
 	w := httptest.NewRecorder()
 
@@ -101,7 +112,7 @@ func TestGetConfig_EncodingError(t *testing.T) {
 		Vault:                config.VaultConfig{PKIMounts: []string{"pki"}},
 	}
 
-	handler := GetConfig(cfg, nil)
+	handler := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
 
 	// Create a response writer that will fail on write
 	w := &failingResponseWriter{}
@@ -122,7 +133,7 @@ func TestGetConfig_NilSlicesAndVaultFiltering(t *testing.T) {
 		},
 	}
 
-	h := GetConfig(cfg, nil)
+	h := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
 	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
 	res := httptest.NewRecorder()
 	h(res, req)
@@ -152,3 +163,106 @@ func TestGetConfig_NilSlicesAndVaultFiltering(t *testing.T) {
 		t.Fatalf("expected empty pki mounts")
 	}
 }
+
+func TestGetConfig_CertTypesIncludeClassificationRules(t *testing.T) {
+	cfg := config.Config{
+		ClassificationRules: []config.ClassificationRule{{Type: "code-signing", Label: "Code signing", EKUs: []string{"codeSigning"}}},
+	}
+
+	classifier, err := certs.NewClassifier(cfg.ClassificationRules)
+	if err != nil {
+		t.Fatalf("failed to build classifier: %v", err)
+	}
+	handler := GetConfig(cfg, nil, classifier, mustExpiryThresholds(t, cfg))
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	var resp ConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.CertTypes) != 5 {
+		t.Fatalf("expected 5 cert types, got %d", len(resp.CertTypes))
+	}
+	if resp.CertTypes[4].Type != "code-signing" || resp.CertTypes[4].Label != "Code signing" {
+		t.Errorf("unexpected custom cert type: %+v", resp.CertTypes[4])
+	}
+}
+
+func TestGetConfig_DurationPercentAndMountThresholds(t *testing.T) {
+	cfg := config.Config{
+		ExpirationThresholds: config.ExpirationThresholds{
+			Critical:         7,
+			Warning:          30,
+			CriticalDuration: "36h",
+			WarningPercent:   25,
+			Mounts:           map[string]config.ExpirationThresholds{"pki_mesh": {CriticalDuration: "6h", WarningPercent: 50}},
+		},
+	}
+
+	handler := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	var resp ConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	got := resp.ExpirationThresholds
+	if got.Critical != 2 || got.CriticalSeconds != 36*3600 {
+		t.Errorf("expected 36h critical window rounded to 2 days, got %+v", got)
+	}
+	if got.Warning != 30 || got.WarningPercent != 25 {
+		t.Errorf("unexpected warning window: %+v", got)
+	}
+	mesh, ok := got.Mounts["pki_mesh"]
+	if !ok {
+		t.Fatalf("expected pki_mesh override, got %+v", got.Mounts)
+	}
+	if mesh.CriticalSeconds != 6*3600 || mesh.Critical != 1 || mesh.WarningSeconds != 0 || mesh.WarningPercent != 50 {
+		t.Errorf("unexpected pki_mesh override: %+v", mesh)
+	}
+}
+
+func TestGetConfig_VaultThresholdOverridesResolvedPerMount(t *testing.T) {
+	cfg := config.Config{
+		ExpirationThresholds: config.ExpirationThresholds{Critical: 7, Warning: 30},
+		AllVaults: []config.VaultInstance{
+			{
+				ID:        "vault-a",
+				PKIMounts: []string{"pki_mtls", "pki_public"},
+				ExpirationThresholds: &config.ExpirationThresholds{
+					Critical: 14,
+					Warning:  45,
+					Mounts:   map[string]config.ExpirationThresholds{"pki_mtls": {Critical: 3, Warning: 10}},
+				},
+			},
+			{ID: "vault-b", PKIMounts: []string{"pki"}},
+		},
+	}
+
+	handler := GetConfig(cfg, nil, nil, mustExpiryThresholds(t, cfg))
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	var resp ConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	mounts := resp.ExpirationThresholds.Mounts
+	if len(mounts) != 2 {
+		t.Fatalf("expected only the two overridden vault-a mounts, got %+v", mounts)
+	}
+	if got := mounts["vault-a|pki_mtls"]; got.Critical != 3 || got.Warning != 10 {
+		t.Errorf("unexpected vault-a|pki_mtls thresholds: %+v", got)
+	}
+	if got := mounts["vault-a|pki_public"]; got.Critical != 14 || got.Warning != 45 {
+		t.Errorf("unexpected vault-a|pki_public thresholds: %+v", got)
+	}
+}
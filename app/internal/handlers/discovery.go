@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/probe"
+)
+
+// unmanagedResponse is the response shape for GET /api/findings/unmanaged.
+// LastRun is unset until the first discovery scan completes.
+type unmanagedResponse struct {
+	Enabled          bool             `json:"enabled"`
+	LastRun          *time.Time       `json:"lastRun,omitempty"`
+	ScannedEndpoints int              `json:"scannedEndpoints"`
+	TLSEndpoints     int              `json:"tlsEndpoints"`
+	Unmanaged        []probe.Endpoint `json:"unmanaged"`
+}
+
+// DiscoveryResults is the source of GET /api/findings/unmanaged. Satisfied
+// by *probe.Scanner.
+type DiscoveryResults interface {
+	Endpoints() []probe.Endpoint
+	Scanned() int
+	LastRun() time.Time
+}
+
+// RegisterDiscoveryRoutes exposes the TLS endpoints of the last discovery
+// scan whose certificate the inventory does not manage. source is nil when
+// discovery is disabled; the route then reports enabled=false.
+func RegisterDiscoveryRoutes(r chi.Router, source DiscoveryResults) {
+	HandleAPI(r, http.MethodGet, "/findings/unmanaged", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		response := unmanagedResponse{Enabled: source != nil, Unmanaged: []probe.Endpoint{}}
+		if source != nil {
+			endpoints := source.Endpoints()
+			response.ScannedEndpoints = source.Scanned()
+			response.TLSEndpoints = len(endpoints)
+			if lastRun := source.LastRun(); !lastRun.IsZero() {
+				response.LastRun = &lastRun
+			}
+			for _, endpoint := range endpoints {
+				if !endpoint.Managed {
+					response.Unmanaged = append(response.Unmanaged, endpoint)
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode unmanaged certificates response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("unmanaged", len(response.Unmanaged)).
+			Msg("unmanaged certificates served")
+	})
+}
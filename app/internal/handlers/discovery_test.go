@@ -0,0 +1,92 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/probe"
+	"vcv/internal/vault"
+)
+
+// fakeDiscoveryResults serves a fixed report like a scanner after a scan.
+type fakeDiscoveryResults struct {
+	endpoints []probe.Endpoint
+	scanned   int
+	lastRun   time.Time
+}
+
+func (f fakeDiscoveryResults) Endpoints() []probe.Endpoint { return f.endpoints }
+func (f fakeDiscoveryResults) Scanned() int                { return f.scanned }
+func (f fakeDiscoveryResults) LastRun() time.Time          { return f.lastRun }
+
+func getUnmanaged(t *testing.T, source handlers.DiscoveryResults) map[string]any {
+	t.Helper()
+	r := chi.NewRouter()
+	handlers.RegisterDiscoveryRoutes(r, source)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/findings/unmanaged", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestUnmanaged_Disabled(t *testing.T) {
+	body := getUnmanaged(t, nil)
+
+	assert.Equal(t, false, body["enabled"])
+	assert.Equal(t, []any{}, body["unmanaged"])
+	assert.Equal(t, float64(0), body["scannedEndpoints"])
+	assert.NotContains(t, body, "lastRun")
+}
+
+func TestUnmanaged_BeforeFirstScan(t *testing.T) {
+	scanner, err := probe.NewScanner(config.DiscoverySettings{Enabled: true, CIDRs: []string{"10.0.0.0/24"}}, new(vault.MockClient))
+	require.NoError(t, err)
+
+	body := getUnmanaged(t, scanner)
+
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, []any{}, body["unmanaged"])
+	assert.NotContains(t, body, "lastRun")
+}
+
+func TestUnmanaged_Endpoints(t *testing.T) {
+	lastRun := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+	source := fakeDiscoveryResults{
+		scanned: 512,
+		lastRun: lastRun,
+		endpoints: []probe.Endpoint{
+			{Address: "10.0.0.1:443", CheckedAt: lastRun, Managed: true, CertificateID: "v1|pki:0a", Certificate: probe.PresentedCertificate{Subject: "CN=api.example.com"}},
+			{Address: "10.0.0.2:8443", CheckedAt: lastRun, Managed: true, IssuedBy: "v1|pki", Certificate: probe.PresentedCertificate{Subject: "CN=hidden.example.com"}},
+			{Address: "10.0.0.3:443", CheckedAt: lastRun, Certificate: probe.PresentedCertificate{Subject: "CN=printer.local", SerialNumber: "0b"}},
+		},
+	}
+
+	body := getUnmanaged(t, source)
+
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, "2026-06-01T03:00:00Z", body["lastRun"])
+	assert.Equal(t, float64(512), body["scannedEndpoints"])
+	assert.Equal(t, float64(3), body["tlsEndpoints"], "managed endpoints count toward tlsEndpoints")
+	unmanaged, ok := body["unmanaged"].([]any)
+	require.True(t, ok)
+	require.Len(t, unmanaged, 1, "only unmanaged endpoints are listed")
+	endpoint := unmanaged[0].(map[string]any)
+	assert.Equal(t, "10.0.0.3:443", endpoint["address"])
+	assert.Equal(t, false, endpoint["managed"])
+	assert.NotContains(t, endpoint, "certificateId")
+	assert.NotContains(t, endpoint, "issuedBy")
+	certificate := endpoint["certificate"].(map[string]any)
+	assert.Equal(t, "CN=printer.local", certificate["subject"])
+	assert.Equal(t, "0b", certificate["serialNumber"])
+}
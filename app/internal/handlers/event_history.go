@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/events"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+)
+
+const (
+	defaultEventHistoryPageSize = 100
+	maxEventHistoryPageSize     = 1000
+)
+
+// eventHistoryResponse is the response shape for GET /api/events/history.
+// Total counts every matching event, not just the returned page.
+type eventHistoryResponse struct {
+	Enabled  bool           `json:"enabled"`
+	Events   []events.Event `json:"events"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+}
+
+// eventHistoryQuery holds the parsed parameters of GET /api/events/history.
+type eventHistoryQuery struct {
+	journal  events.JournalQuery
+	ndjson   bool
+	page     int
+	pageSize int
+}
+
+// RegisterEventHistoryRoutes exposes the event journal. journal is nil when
+// it is disabled; the route then reports enabled=false and no events.
+func RegisterEventHistoryRoutes(r chi.Router, journal *events.Journal) {
+	HandleAPI(r, http.MethodGet, "/events/history", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query, err := parseEventHistoryQuery(req.URL.Query())
+		if err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, err).
+				Str("request_id", requestID).
+				Msg("invalid event history query")
+			writeAPIError(w, req, invalidRequest(err.Error()))
+			return
+		}
+		if query.ndjson {
+			writeEventHistoryNDJSON(w, req, journal, query)
+			return
+		}
+
+		response := eventHistoryResponse{Enabled: journal != nil, Events: []events.Event{}, Page: query.page, PageSize: query.pageSize}
+		// Only the page is held: earlier matches are skipped and later ones
+		// only counted.
+		skip := (query.page - 1) * query.pageSize
+		scanErr := journal.Scan(query.journal, func(event events.Event) error {
+			response.Total++
+			if response.Total > skip && len(response.Events) < query.pageSize {
+				response.Events = append(response.Events, event)
+			}
+			return nil
+		})
+		if scanErr != nil {
+			apiErr := classifyError(scanErr)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, scanErr).
+				Str("request_id", requestID).
+				Msg("failed to read event journal")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode event history response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("total", response.Total).
+			Int("events", len(response.Events)).
+			Msg("event history served")
+	})
+}
+
+// writeEventHistoryNDJSON streams every matching event, oldest first, one
+// JSON object per line; order and paging do not apply.
+func writeEventHistoryNDJSON(w http.ResponseWriter, req *http.Request, journal *events.Journal, query eventHistoryQuery) {
+	requestID := middleware.GetRequestID(req.Context())
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="vcv-events-%s.ndjson"`, time.Now().UTC().Format("2006-01-02")))
+	controller := http.NewResponseController(w)
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	count := 0
+	journalQuery := query.journal
+	journalQuery.Descending = false
+	writeErr := journal.Scan(journalQuery, func(event events.Event) error {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := buffered.Flush(); err != nil {
+				return err
+			}
+			if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+		return nil
+	})
+	if writeErr == nil {
+		writeErr = buffered.Flush()
+	}
+	if writeErr != nil {
+		// Headers are already sent; the client sees a truncated body.
+		logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, writeErr).
+			Str("request_id", requestID).
+			Msg("failed to write event history export")
+		return
+	}
+	logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+		Str("request_id", requestID).
+		Str("format", "ndjson").
+		Int("count", count).
+		Msg("exported event history")
+}
+
+func parseEventHistoryQuery(query url.Values) (eventHistoryQuery, error) {
+	parsed := eventHistoryQuery{journal: events.JournalQuery{Descending: true}, page: 1, pageSize: defaultEventHistoryPageSize}
+	for _, eventType := range parseListQueryParam(query, "type") {
+		if !slices.Contains(events.Types, eventType) {
+			return eventHistoryQuery{}, fmt.Errorf("type: must be one of %s", strings.Join(events.Types, ", "))
+		}
+		parsed.journal.Types = append(parsed.journal.Types, eventType)
+	}
+	parsed.journal.CertificateID = strings.TrimSpace(query.Get("certificate_id"))
+	if filter := newMountFilter(parseMountsQueryParam(query)); filter != nil {
+		parsed.journal.Include = filter.matchesEvent
+	}
+	if raw := strings.TrimSpace(query.Get("from")); raw != "" {
+		from, _, err := parseHistoryTime(raw)
+		if err != nil {
+			return eventHistoryQuery{}, fmt.Errorf("from: %w", err)
+		}
+		parsed.journal.From = from
+	}
+	if raw := strings.TrimSpace(query.Get("to")); raw != "" {
+		to, isDate, err := parseHistoryTime(raw)
+		if err != nil {
+			return eventHistoryQuery{}, fmt.Errorf("to: %w", err)
+		}
+		if isDate {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		parsed.journal.To = to
+	}
+	if !parsed.journal.From.IsZero() && !parsed.journal.To.IsZero() && parsed.journal.From.After(parsed.journal.To) {
+		return eventHistoryQuery{}, fmt.Errorf("from must not be after to")
+	}
+	switch order := strings.ToLower(strings.TrimSpace(query.Get("order"))); order {
+	case "", "desc":
+	case "asc":
+		parsed.journal.Descending = false
+	default:
+		return eventHistoryQuery{}, fmt.Errorf("order: must be asc or desc")
+	}
+	switch format := strings.ToLower(strings.TrimSpace(query.Get("format"))); format {
+	case "", "json":
+	case "ndjson":
+		parsed.ndjson = true
+	default:
+		return eventHistoryQuery{}, fmt.Errorf("format: must be json or ndjson")
+	}
+	if raw := strings.TrimSpace(query.Get("page_size")); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > maxEventHistoryPageSize {
+			return eventHistoryQuery{}, fmt.Errorf("page_size: must be between 1 and %d", maxEventHistoryPageSize)
+		}
+		parsed.pageSize = size
+	}
+	if raw := strings.TrimSpace(query.Get("page")); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return eventHistoryQuery{}, fmt.Errorf("page: must be a positive integer")
+		}
+		parsed.page = page
+	}
+	return parsed, nil
+}
@@ -0,0 +1,134 @@
+package handlers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+	"vcv/internal/events"
+	"vcv/internal/handlers"
+)
+
+func newJournalRouter(t *testing.T, published ...events.Event) *chi.Mux {
+	t.Helper()
+	journal, err := events.NewJournal(config.JournalSettings{Enabled: true}, t.TempDir())
+	require.NoError(t, err)
+	events.NewBroker(10, events.WithJournal(journal)).Publish(published...)
+	r := chi.NewRouter()
+	handlers.RegisterEventHistoryRoutes(r, journal)
+	return r
+}
+
+func getEventHistory(t *testing.T, r http.Handler, query string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/history"+query, nil))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body
+}
+
+func eventIDs(body map[string]any) []string {
+	var ids []string
+	for _, event := range body["events"].([]any) {
+		fields := event.(map[string]any)
+		subject, _ := fields["certificateId"].(string)
+		if subject == "" {
+			subject, _ = fields["vaultId"].(string)
+		}
+		ids = append(ids, fields["type"].(string)+" "+subject)
+	}
+	return ids
+}
+
+func TestEventHistory_Disabled(t *testing.T) {
+	r := chi.NewRouter()
+	handlers.RegisterEventHistoryRoutes(r, nil)
+
+	code, body := getEventHistory(t, r, "")
+
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, body["enabled"])
+	assert.Equal(t, []any{}, body["events"])
+	assert.Equal(t, float64(0), body["total"])
+}
+
+func TestEventHistory_FiltersAndPages(t *testing.T) {
+	now := time.Now().UTC()
+	r := newJournalRouter(t,
+		events.Event{Type: events.TypeCertificateAdded, Time: now.AddDate(0, 0, -2), VaultID: "v1", Mount: "pki", CertificateID: "v1|pki:aa"},
+		events.Event{Type: events.TypeCertificateAdded, Time: now.AddDate(0, 0, -1), VaultID: "v2", Mount: "pki", CertificateID: "v2|pki:bb"},
+		events.Event{Type: events.TypeVaultDisconnected, Time: now, VaultID: "v2"},
+		events.Event{Type: events.TypeCertificateRevoked, Time: now, VaultID: "v1", Mount: "pki", CertificateID: "v1|pki:aa"},
+	)
+
+	code, body := getEventHistory(t, r, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, float64(4), body["total"])
+	assert.Equal(t, []string{"certificate_revoked v1|pki:aa", "vault_disconnected v2", "certificate_added v2|pki:bb", "certificate_added v1|pki:aa"}, eventIDs(body), "newest first")
+
+	_, body = getEventHistory(t, r, "?order=asc&page_size=1&page=2")
+	assert.Equal(t, float64(4), body["total"])
+	assert.Equal(t, []string{"certificate_added v2|pki:bb"}, eventIDs(body))
+
+	_, body = getEventHistory(t, r, "?page_size=1&page=2")
+	assert.Equal(t, []string{"vault_disconnected v2"}, eventIDs(body), "desc pages walk back within a day")
+
+	_, body = getEventHistory(t, r, "?page_size=3&page=2")
+	assert.Equal(t, []string{"certificate_added v1|pki:aa"}, eventIDs(body))
+
+	_, body = getEventHistory(t, r, "?page_size=3&page=3")
+	assert.Equal(t, float64(4), body["total"], "a page past the end still counts every match")
+	assert.Equal(t, []any{}, body["events"])
+
+	_, body = getEventHistory(t, r, "?mounts=v2|pki")
+	assert.Equal(t, []string{"vault_disconnected v2", "certificate_added v2|pki:bb"}, eventIDs(body))
+
+	_, body = getEventHistory(t, r, "?type=certificate_added,certificate_revoked&certificate_id=v1|pki:aa")
+	assert.Equal(t, []string{"certificate_revoked v1|pki:aa", "certificate_added v1|pki:aa"}, eventIDs(body))
+
+	_, body = getEventHistory(t, r, "?to="+now.AddDate(0, 0, -1).Format(time.DateOnly))
+	assert.Equal(t, float64(2), body["total"])
+}
+
+func TestEventHistory_NDJSON(t *testing.T) {
+	now := time.Now().UTC()
+	r := newJournalRouter(t,
+		events.Event{Type: events.TypeCertificateAdded, Time: now.Add(-time.Hour), VaultID: "v1", CertificateID: "v1|pki:aa"},
+		events.Event{Type: events.TypeCertificateTidied, Time: now, VaultID: "v1", CertificateID: "v1|pki:aa"},
+	)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/history?format=ndjson&page_size=1", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	var types []string
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	for scanner.Scan() {
+		var event events.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{events.TypeCertificateAdded, events.TypeCertificateTidied}, types, "oldest first, not paged")
+}
+
+func TestEventHistory_InvalidQuery(t *testing.T) {
+	r := chi.NewRouter()
+	handlers.RegisterEventHistoryRoutes(r, nil)
+	for _, query := range []string{"?type=certificate_renamed", "?from=soon", "?order=up", "?format=csv", "?page_size=5000", "?page=0", "?from=2026-02-01&to=2026-01-01"} {
+		code, body := getEventHistory(t, r, query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+		assert.Equal(t, "invalid_request", body["code"], query)
+	}
+}
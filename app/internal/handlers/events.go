@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/events"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+)
+
+// eventsRetry is the reconnection delay suggested to EventSource clients.
+const eventsRetry = 5 * time.Second
+
+// defaultEventsHeartbeat is used when the server has no write timeout.
+const defaultEventsHeartbeat = 15 * time.Second
+
+// RegisterEventRoutes mounts the Server-Sent Events stream of inventory
+// changes. writeTimeout is the server's WriteTimeout: every write pushes
+// the connection deadline that far out, and heartbeats are sent at half of
+// it so an idle stream is not cut off. A nil broker serves streams that
+// only carry heartbeats.
+func RegisterEventRoutes(r chi.Router, broker *events.Broker, writeTimeout time.Duration) {
+	heartbeat := defaultEventsHeartbeat
+	if writeTimeout > 0 {
+		heartbeat = writeTimeout / 2
+	}
+	HandleAPI(r, http.MethodGet, "/events", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		lastID, resume, parseErr := parseLastEventID(req.Header.Get("Last-Event-ID"))
+		if parseErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, parseErr).
+				Str("request_id", requestID).
+				Msg("invalid Last-Event-ID")
+			writeAPIError(w, req, invalidRequest("Last-Event-ID must be an event id"))
+			return
+		}
+		filter := newMountFilter(parseMountsQueryParam(req.URL.Query()))
+		backlog, complete, updates, cancel := broker.Subscribe(lastID, resume)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		// Keeps nginx-style proxies from buffering the stream.
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		stream := &eventStream{w: w, controller: http.NewResponseController(w), writeTimeout: writeTimeout}
+		start := time.Now()
+		stream.write("retry: %d\n\n", eventsRetry.Milliseconds())
+		if !complete {
+			// The client missed events we no longer have; it must reload.
+			stream.write("event: resync\ndata: {}\n\n")
+		}
+		for _, event := range backlog {
+			stream.send(event, filter)
+		}
+		stream.flush()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for stream.err == nil {
+			select {
+			case <-req.Context().Done():
+				stream.err = req.Context().Err()
+			case event, ok := <-updates:
+				if !ok {
+					stream.err = io.EOF
+					continue
+				}
+				stream.send(event, filter)
+				stream.flush()
+			case <-ticker.C:
+				stream.write(": heartbeat\n\n")
+				stream.flush()
+			}
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, float64(time.Since(start).Milliseconds())).
+			Str("request_id", requestID).
+			Int("events", stream.sent).
+			Str("reason", stream.err.Error()).
+			Msg("event stream closed")
+	})
+}
+
+// parseLastEventID reads the Last-Event-ID header EventSource sends when it
+// reconnects. resume is false when the header is absent.
+func parseLastEventID(value string) (uint64, bool, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(trimmed, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid Last-Event-ID %q: %w", trimmed, err)
+	}
+	return id, true, nil
+}
+
+// matchesEvent selects certificate events by mount and vault events by
+// vault.
+func (filter *mountFilter) matchesEvent(event events.Event) bool {
+	if event.CertificateID != "" {
+		return filter.matchesCertificate(event.CertificateID)
+	}
+	return filter.matchesVault(event.VaultID)
+}
+
+// eventStream writes SSE frames, extending the write deadline before each
+// write. The first write error is kept in err and ends the stream.
+type eventStream struct {
+	w            http.ResponseWriter
+	controller   *http.ResponseController
+	writeTimeout time.Duration
+	sent         int
+	err          error
+}
+
+func (stream *eventStream) send(event events.Event, filter *mountFilter) {
+	if !filter.matchesEvent(event) {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		stream.err = err
+		return
+	}
+	stream.write("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	if stream.err == nil {
+		stream.sent++
+	}
+}
+
+func (stream *eventStream) write(format string, args ...any) {
+	if stream.err != nil {
+		return
+	}
+	if stream.writeTimeout > 0 {
+		if err := stream.controller.SetWriteDeadline(time.Now().Add(stream.writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			stream.err = err
+			return
+		}
+	}
+	_, stream.err = fmt.Fprintf(stream.w, format, args...)
+}
+
+func (stream *eventStream) flush() {
+	if stream.err != nil {
+		return
+	}
+	if err := stream.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		stream.err = err
+	}
+}
@@ -0,0 +1,145 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/events"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+)
+
+func newEventsServer(t *testing.T, broker *events.Broker, writeTimeout time.Duration) *httptest.Server {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Compress(middleware.DefaultCompressConfig()))
+	handlers.RegisterEventRoutes(r, broker, writeTimeout)
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = writeTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// openStream connects to the stream and returns a reader of its lines.
+func openStream(t *testing.T, target string, lastEventID string) (*http.Response, *bufio.Scanner) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp, bufio.NewScanner(resp.Body)
+}
+
+// nextFrame returns the next non-empty SSE frame, skipping heartbeats
+// unless keepComments is set.
+func nextFrame(t *testing.T, scanner *bufio.Scanner, keepComments bool) string {
+	t.Helper()
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(lines) == 0 {
+				continue
+			}
+			frame := strings.Join(lines, "\n")
+			if !keepComments && strings.HasPrefix(frame, ":") {
+				lines = nil
+				continue
+			}
+			return frame
+		}
+		lines = append(lines, line)
+	}
+	t.Fatalf("stream ended: %v", scanner.Err())
+	return ""
+}
+
+func TestEvents_StreamsWithMountFilter(t *testing.T) {
+	broker := events.NewBroker(10)
+	server := newEventsServer(t, broker, 15*time.Second)
+	resp, scanner := openStream(t, server.URL+"/api/v1/events?mounts=v1%7Cpki", "")
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, "retry: 5000", nextFrame(t, scanner, false))
+
+	broker.Publish(
+		events.Event{Type: events.TypeCertificateAdded, CertificateID: "v1|pki_int:aa"},
+		events.Event{Type: events.TypeCertificateRevoked, CertificateID: "v1|pki:bb", VaultID: "v1", Mount: "pki"},
+		events.Event{Type: events.TypeVaultDisconnected, VaultID: "v2"},
+		events.Event{Type: events.TypeVaultConnected, VaultID: "v1"},
+	)
+	frame := nextFrame(t, scanner, false)
+	assert.Contains(t, frame, "event: certificate_revoked\n")
+	assert.Contains(t, frame, `"certificateId":"v1|pki:bb"`)
+	assert.Regexp(t, `^id: \d+\n`, frame)
+	assert.Contains(t, nextFrame(t, scanner, false), "event: vault_connected\n")
+}
+
+func TestEvents_ResumeAndResync(t *testing.T) {
+	broker := events.NewBroker(2)
+	server := newEventsServer(t, broker, 15*time.Second)
+	_, live := openStream(t, server.URL+"/api/v1/events", "")
+	nextFrame(t, live, false)
+	broker.Publish(events.Event{Type: events.TypeCertificateAdded, CertificateID: "v1|pki:aa"})
+	first := nextFrame(t, live, false)
+	firstID := strings.TrimPrefix(strings.SplitN(first, "\n", 2)[0], "id: ")
+	broker.Publish(events.Event{Type: events.TypeCertificateExpired, CertificateID: "v1|pki:bb"})
+
+	_, resumed := openStream(t, server.URL+"/api/v1/events", firstID)
+	nextFrame(t, resumed, false)
+	assert.Contains(t, nextFrame(t, resumed, false), "event: certificate_expired\n")
+
+	broker.Publish(events.Event{Type: events.TypeCertificateAdded}, events.Event{Type: events.TypeCertificateAdded})
+	_, stale := openStream(t, server.URL+"/api/v1/events", firstID)
+	nextFrame(t, stale, false)
+	assert.Equal(t, "event: resync\ndata: {}", nextFrame(t, stale, false))
+}
+
+func TestEvents_HeartbeatOutlivesWriteTimeout(t *testing.T) {
+	broker := events.NewBroker(10)
+	server := newEventsServer(t, broker, 200*time.Millisecond)
+	_, scanner := openStream(t, server.URL+"/api/v1/events", "")
+	nextFrame(t, scanner, false)
+	for range 4 {
+		assert.Equal(t, ": heartbeat", nextFrame(t, scanner, true))
+	}
+	broker.Publish(events.Event{Type: events.TypeVaultConnected, VaultID: "v1"})
+	assert.Contains(t, nextFrame(t, scanner, false), "event: vault_connected\n")
+}
+
+func TestEvents_NilBrokerKeepsStreamOpen(t *testing.T) {
+	server := newEventsServer(t, nil, 200*time.Millisecond)
+	_, scanner := openStream(t, server.URL+"/api/v1/events", "")
+	assert.Equal(t, "retry: 5000", nextFrame(t, scanner, false))
+	assert.Equal(t, ": heartbeat", nextFrame(t, scanner, true))
+}
+
+func TestEvents_InvalidLastEventID(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterEventRoutes(r, events.NewBroker(10), time.Second)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_request")
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/i18n"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// vaultErrorsHeader lists, comma-separated, the vaults that failed to list
+// and are missing from an export.
+const vaultErrorsHeader = "X-Vault-Errors"
+
+// exportFlushEvery bounds how many rows are buffered before flushing to the
+// client, so large inventories stream instead of building in memory.
+const exportFlushEvery = 500
+
+// exportColumn is one selectable export column: its stable key (JSON field
+// and default CSV header), the localized header and the cell value.
+type exportColumn struct {
+	key    string
+	header func(i18n.Messages) string
+	value  func(certs.Certificate, string) string
+}
+
+var exportColumns = []exportColumn{
+	{key: "commonName", header: func(m i18n.Messages) string { return m.ColumnCommonName }, value: func(c certs.Certificate, _ string) string { return c.CommonName }},
+	{key: "sans", header: func(m i18n.Messages) string { return m.ColumnSAN }, value: func(c certs.Certificate, _ string) string { return strings.Join(c.Sans, " ") }},
+	{key: "vault", header: func(m i18n.Messages) string { return m.LabelVault }, value: func(c certs.Certificate, _ string) string {
+		vaultID, _ := certs.VaultAndMount(c.ID)
+		return vaultID
+	}},
+	{key: "mount", header: func(m i18n.Messages) string { return m.LabelPKI }, value: func(c certs.Certificate, _ string) string {
+		_, mount := certs.VaultAndMount(c.ID)
+		return mount
+	}},
+	{key: "certType", header: func(m i18n.Messages) string { return m.LabelCertificateType }, value: func(c certs.Certificate, _ string) string { return c.CertType }},
+	{key: "status", header: func(m i18n.Messages) string { return m.ColumnStatus }, value: func(_ certs.Certificate, status string) string { return status }},
+	{key: "expiresAt", header: func(m i18n.Messages) string { return m.ColumnExpiresAt }, value: func(c certs.Certificate, _ string) string { return formatExportTime(c.ExpiresAt) }},
+	{key: "serialNumber", header: func(m i18n.Messages) string { return m.LabelSerialNumber }, value: func(c certs.Certificate, _ string) string { return c.SerialNumber }},
+	{key: "createdAt", header: func(m i18n.Messages) string { return m.ColumnCreatedAt }, value: func(c certs.Certificate, _ string) string { return formatExportTime(c.CreatedAt) }},
+	{key: "issuer", header: func(m i18n.Messages) string { return m.LabelIssuer }, value: func(c certs.Certificate, _ string) string { return c.IssuerCN }},
+	{key: "keyAlgorithm", header: func(m i18n.Messages) string { return m.LabelKeyAlgorithm }, value: func(c certs.Certificate, _ string) string {
+		if c.KeySize == 0 {
+			return c.KeyAlgorithm
+		}
+		return c.KeyAlgorithm + "-" + strconv.Itoa(c.KeySize)
+	}},
+	{key: "id", header: func(_ i18n.Messages) string { return "ID" }, value: func(c certs.Certificate, _ string) string { return c.ID }},
+}
+
+// defaultExportColumns matches the columns of the browser export.
+var defaultExportColumns = []string{"commonName", "sans", "vault", "mount", "certType", "status", "expiresAt", "serialNumber"}
+
+// exportRows calls emit once per exported certificate, in order.
+type exportRows func(emit func([]string) error) error
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	write       func(w io.Writer, headers []string, rows exportRows) error
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":    {contentType: "text/csv; charset=utf-8", extension: "csv", write: writeCSVExport},
+	"json":   {contentType: "application/json", extension: "json", write: writeJSONExport},
+	"ndjson": {contentType: "application/x-ndjson", extension: "ndjson", write: writeNDJSONExport},
+	"xlsx":   {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extension: "xlsx", write: writeXLSXExport},
+}
+
+func registerCertExportRoute(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/certs/export", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		formatName := strings.ToLower(strings.TrimSpace(query.Get("format")))
+		if formatName == "" {
+			formatName = "csv"
+		}
+		format, ok := exportFormats[formatName]
+		if !ok {
+			writeAPIError(w, req, invalidRequest("format: must be one of csv, json, ndjson, xlsx"))
+			return
+		}
+		columns, columnsErr := parseExportColumns(parseListQueryParam(query, "columns"))
+		if columnsErr != nil {
+			writeAPIError(w, req, invalidRequest(columnsErr.Error()))
+			return
+		}
+		listQuery, queryErr := parseCertListQuery(query)
+		if queryErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, queryErr).
+				Str("request_id", requestID).
+				Msg("invalid certificate export query")
+			writeAPIError(w, req, invalidRequest(queryErr.Error()))
+			return
+		}
+
+		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for export")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		now := time.Now()
+		result := listQuery.apply(filterCertificatesByMounts(certificates, parseMountsQueryParam(query)), thresholds, now)
+
+		// JSON keys stay stable; spreadsheet headers follow the UI language
+		// unless headers=keys asks for the raw column keys.
+		localized := (formatName == "csv" || formatName == "xlsx") && query.Get("headers") != "keys"
+		messages := i18n.MessagesForLanguage(i18n.ResolveLanguage(req))
+		headers := make([]string, len(columns))
+		for i, column := range columns {
+			headers[i] = column.key
+			if label := column.header(messages); localized && label != "" {
+				headers[i] = label
+			}
+		}
+
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="vcv-certificates-%s.%s"`, now.UTC().Format("2006-01-02"), format.extension))
+		// The body has no room for errors: name the vaults missing from a
+		// partial export before it starts.
+		if len(vaultErrors) > 0 {
+			failed := make([]string, 0, len(vaultErrors))
+			for _, vaultErr := range vaultErrors {
+				failed = append(failed, vaultErr.VaultID)
+			}
+			w.Header().Set(vaultErrorsHeader, strings.Join(failed, ","))
+		}
+		controller := http.NewResponseController(w)
+		buffered := bufio.NewWriter(w)
+		rows := func(emit func([]string) error) error {
+			for i, certificate := range result.Certificates {
+				status := thresholds.Status(certificate, now)
+				row := make([]string, len(columns))
+				for j, column := range columns {
+					row[j] = column.value(certificate, status)
+				}
+				if err := emit(row); err != nil {
+					return err
+				}
+				if (i+1)%exportFlushEvery == 0 {
+					if err := buffered.Flush(); err != nil {
+						return err
+					}
+					if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		writeErr := format.write(buffered, headers, rows)
+		if writeErr == nil {
+			writeErr = buffered.Flush()
+		}
+		if writeErr != nil {
+			// Headers are already sent; the client sees a truncated body.
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, writeErr).
+				Str("request_id", requestID).
+				Msg("failed to write certificate export")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("format", formatName).
+			Int("count", len(result.Certificates)).
+			Int("vault_errors", len(vaultErrors)).
+			Msg("exported certificates")
+	})
+}
+
+// parseExportColumns resolves column keys (case-insensitive) in the order
+// given; an empty list selects the default columns.
+func parseExportColumns(keys []string) ([]exportColumn, error) {
+	if len(keys) == 0 {
+		keys = defaultExportColumns
+	}
+	columns := make([]exportColumn, 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, column := range exportColumns {
+			if strings.EqualFold(column.key, key) {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("columns: unknown column %q", key)
+		}
+	}
+	return columns, nil
+}
+
+func formatExportTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+// sanitizeSpreadsheetCell prefixes a single quote when a cell could be read
+// as a formula by Excel, Sheets or LibreOffice (same rule as the browser
+// export).
+func sanitizeSpreadsheetCell(value string) string {
+	trimmed := strings.TrimLeft(value, " ")
+	if trimmed == "" {
+		return value
+	}
+	switch trimmed[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+func writeCSVExport(w io.Writer, headers []string, rows exportRows) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	err := rows(func(row []string) error {
+		for i := range row {
+			row[i] = sanitizeSpreadsheetCell(row[i])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeNDJSONExport(w io.Writer, headers []string, rows exportRows) error {
+	encoder := json.NewEncoder(w)
+	return rows(func(row []string) error {
+		return encoder.Encode(exportObject{keys: headers, values: row})
+	})
+}
+
+func writeJSONExport(w io.Writer, headers []string, rows exportRows) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	err := rows(func(row []string) error {
+		payload, err := json.Marshal(exportObject{keys: headers, values: row})
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(payload)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// exportObject encodes one row as a JSON object keyed by column, keeping the
+// selected column order.
+type exportObject struct {
+	keys   []string
+	values []string
+}
+
+func (o exportObject) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(encodedKey)
+		b.WriteByte(':')
+		b.Write(encodedValue)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Certificates" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// writeXLSXExport writes a minimal single-sheet workbook with inline strings.
+// The worksheet is the last zip entry so rows stream straight into it.
+func writeXLSXExport(w io.Writer, headers []string, rows exportRows) error {
+	archive := zip.NewWriter(w)
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, part.body); err != nil {
+			return err
+		}
+	}
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+	if err := writeXLSXRow(sheet, headers); err != nil {
+		return err
+	}
+	if err := rows(func(row []string) error { return writeXLSXRow(sheet, row) }); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeXLSXRow(w io.Writer, cells []string) error {
+	if _, err := io.WriteString(w, "<row>"); err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		if _, err := io.WriteString(w, `<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(w, []byte(cell)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "</t></is></c>"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</row>")
+	return err
+}
@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+func exportFixtures() []certs.Certificate {
+	now := time.Now()
+	return []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "api.example.com", Sans: []string{"api.example.com", "api"}, SerialNumber: "aa", CertType: "leaf", ExpiresAt: now.Add(90 * 24 * time.Hour)},
+		{ID: "v1|pki_dev:bb", CommonName: "=HYPERLINK(\"x\")", SerialNumber: "bb", CertType: "leaf", ExpiresAt: now.Add(-time.Hour)},
+		{ID: "v2|pki:cc", CommonName: "web & co", SerialNumber: "cc", CertType: "ca", KeyAlgorithm: "RSA", KeySize: 2048, ExpiresAt: now.Add(24 * time.Hour)},
+	}
+}
+
+func serveExport(t *testing.T, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(exportFixtures(), nil)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	setupRouter(mockVault).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportCertificates_CSVDefault(t *testing.T) {
+	rec := serveExport(t, "/api/certs/export?lang=en", nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename="vcv-certificates-`)
+	assert.Contains(t, rec.Body.String(), "\r\n")
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Len(t, records[0], 8)
+	assert.Equal(t, []string{"api.example.com", "api.example.com api", "v1", "pki", "leaf", "valid"}, records[1][:6])
+	assert.Equal(t, `'=HYPERLINK("x")`, records[2][0], "formula cells are neutralized")
+	assert.Equal(t, "expired", records[2][5])
+}
+
+func TestExportCertificates_LocalizedHeaders(t *testing.T) {
+	english := serveExport(t, "/api/certs/export?columns=commonName&lang=en", nil)
+	french := serveExport(t, "/api/certs/export?columns=commonName", http.Header{"Accept-Language": {"fr-FR"}})
+	keys := serveExport(t, "/api/certs/export?columns=commonName&headers=keys&lang=fr", nil)
+
+	englishHeader, _, _ := strings.Cut(english.Body.String(), "\r\n")
+	frenchHeader, _, _ := strings.Cut(french.Body.String(), "\r\n")
+	keysHeader, _, _ := strings.Cut(keys.Body.String(), "\r\n")
+	assert.NotEmpty(t, englishHeader)
+	assert.NotEqual(t, englishHeader, frenchHeader)
+	assert.Equal(t, "commonName", keysHeader)
+}
+
+func TestExportCertificates_JSONAndNDJSONHonorFilters(t *testing.T) {
+	rec := serveExport(t, "/api/certs/export?format=json&columns=id,keyAlgorithm,status&mounts=v2|pki", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var rows []map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rows))
+	assert.Equal(t, []map[string]string{{"id": "v2|pki:cc", "keyAlgorithm": "RSA-2048", "status": "valid"}}, rows)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), `[{"id":`), "column order is kept")
+
+	rec = serveExport(t, "/api/certs/export?format=ndjson&columns=serialNumber&sort=serialNumber&order=desc&status=valid", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var serials []string
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var row map[string]string
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		serials = append(serials, row["serialNumber"])
+	}
+	assert.Equal(t, []string{"cc", "aa"}, serials)
+}
+
+func TestExportCertificates_EmptyJSONIsArray(t *testing.T) {
+	rec := serveExport(t, "/api/certs/export?format=json&q=nothing-matches", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+}
+
+func TestExportCertificates_XLSX(t *testing.T) {
+	rec := serveExport(t, "/api/certs/export?format=xlsx&columns=commonName,serialNumber&headers=keys", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(archive.File))
+	var sheet string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, openErr := file.Open()
+			require.NoError(t, openErr)
+			content, readErr := io.ReadAll(reader)
+			require.NoError(t, readErr)
+			sheet = string(content)
+		}
+	}
+	assert.Contains(t, names, "[Content_Types].xml")
+	assert.Contains(t, names, "xl/workbook.xml")
+	assert.Equal(t, 4, strings.Count(sheet, "<row>"))
+	assert.Contains(t, sheet, "<t xml:space=\"preserve\">serialNumber</t>")
+	assert.Contains(t, sheet, "web &amp; co")
+}
+
+func TestExportCertificates_ReportsFailedVaults(t *testing.T) {
+	client := &envelopeMockClient{
+		MockClient: new(vault.MockClient),
+		certs:      exportFixtures(),
+		errors:     []vault.VaultError{{VaultID: "v3", Message: "sealed"}, {VaultID: "v4", Message: "timeout"}},
+	}
+	r := chi.NewRouter()
+	handlers.RegisterCertRoutes(r, client, nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/certs/export?format=ndjson", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v3,v4", rec.Header().Get("X-Vault-Errors"))
+	assert.Equal(t, 3, strings.Count(rec.Body.String(), "\n"))
+
+	assert.Empty(t, serveExport(t, "/api/certs/export", nil).Header().Get("X-Vault-Errors"), "set only for a partial export")
+}
+
+func TestExportCertificates_StreamsThroughCompression(t *testing.T) {
+	certificates := make([]certs.Certificate, 0, 600)
+	for i := range 600 {
+		certificates = append(certificates, certs.Certificate{ID: fmt.Sprintf("v1|pki:%03d", i), CommonName: "host.example.com", ExpiresAt: time.Now().Add(time.Hour)})
+	}
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certificates, nil)
+	r := chi.NewRouter()
+	r.Use(middleware.Compress(middleware.DefaultCompressConfig()))
+	handlers.RegisterCertRoutes(r, mockVault, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/certs/export?format=csv", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed, "rows are flushed through the compression wrapper")
+}
+
+func TestExportCertificates_InvalidParameters(t *testing.T) {
+	for _, target := range []string{
+		"/api/certs/export?format=pdf",
+		"/api/certs/export?columns=commonName,nope",
+		"/api/certs/export?status=unknown",
+	} {
+		rec := serveExport(t, target, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/i18n"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+const (
+	// defaultFeedChangeDays is how far back changes.atom looks by default.
+	defaultFeedChangeDays = 7
+	// maxFeedChangeDays bounds the days parameter of changes.atom.
+	maxFeedChangeDays = 365
+	// feedDateFormat renders dates inside localized entry titles.
+	feedDateFormat = "2006-01-02"
+)
+
+// Entry categories of the certificate feeds.
+const (
+	feedEntryIssued  = "issued"
+	feedEntryRevoked = "revoked"
+)
+
+// atomFeed is an RFC 4287 feed document.
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang      string      `xml:"xml:lang,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+
+	at time.Time
+}
+
+// registerFeedRoutes mounts the Atom feeds: /api/feeds/expiring.atom lists
+// certificates inside their mount's warning window, soonest first, and
+// /api/feeds/changes.atom lists certificates issued or revoked in the last
+// days (default 7). Both accept the mounts filter and follow the UI
+// language.
+func registerFeedRoutes(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/feeds/expiring.atom", func(w http.ResponseWriter, req *http.Request) {
+		serveAtomFeed(w, req, vaultClient, "expiring", func(certificates []certs.Certificate, messages i18n.Messages, now time.Time) atomFeed {
+			return expiringFeed(certificates, thresholds, messages, now)
+		})
+	})
+	HandleAPI(r, http.MethodGet, "/feeds/changes.atom", func(w http.ResponseWriter, req *http.Request) {
+		days := defaultFeedChangeDays
+		if raw := strings.TrimSpace(req.URL.Query().Get("days")); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > maxFeedChangeDays {
+				err = fmt.Errorf("days: must be between 1 and %d", maxFeedChangeDays)
+				logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, err).
+					Str("request_id", middleware.GetRequestID(req.Context())).
+					Msg("invalid certificate feed query")
+				writeAPIError(w, req, invalidRequest(err.Error()))
+				return
+			}
+			days = parsed
+		}
+		serveAtomFeed(w, req, vaultClient, "changes", func(certificates []certs.Certificate, messages i18n.Messages, now time.Time) atomFeed {
+			return changesFeed(certificates, messages, now.Add(-time.Duration(days)*24*time.Hour), now)
+		})
+	})
+}
+
+// serveAtomFeed lists the certificates of the selected mounts and writes
+// the feed built from them.
+func serveAtomFeed(w http.ResponseWriter, req *http.Request, vaultClient vault.Client, name string, build func([]certs.Certificate, i18n.Messages, time.Time) atomFeed) {
+	requestID := middleware.GetRequestID(req.Context())
+	certificates, _, err := listCertificatesWithErrors(req.Context(), vaultClient)
+	if err != nil {
+		apiErr := classifyError(err)
+		logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+			Str("request_id", requestID).
+			Msg("failed to list certificates for feed")
+		writeAPIError(w, req, apiErr)
+		return
+	}
+	language := i18n.ResolveLanguage(req)
+	now := time.Now()
+	feed := build(filterCertificatesByMounts(certificates, parseMountsQueryParam(req.URL.Query())), i18n.MessagesForLanguage(language), now)
+	feed.Lang = string(language)
+	feed.ID = "urn:vcv:feed:" + name
+	feed.Author = atomPerson{Name: "VaultCertsViewer"}
+	feed.Generator = "VaultCertsViewer"
+	feed.Links = []atomLink{
+		{Rel: "self", Href: req.URL.RequestURI(), Type: "application/atom+xml"},
+		{Rel: "alternate", Href: "/", Type: "text/html"},
+	}
+	// The feed changes when its newest entry does, so readers are not told
+	// about an update on every poll.
+	updated := now
+	if len(feed.Entries) > 0 {
+		updated = feed.Entries[0].at
+		for _, entry := range feed.Entries[1:] {
+			if entry.at.After(updated) {
+				updated = entry.at
+			}
+		}
+	}
+	feed.Updated = atomTime(updated)
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+			Str("request_id", requestID).
+			Msg("failed to encode certificate feed")
+		writeAPIError(w, req, classifyError(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+	logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+		Str("request_id", requestID).
+		Str("feed", name).
+		Int("entries", len(feed.Entries)).
+		Str("language", string(language)).
+		Msg("certificate feed served")
+}
+
+// expiringFeed has one entry per certificate inside its warning or
+// critical window. An entry is dated when the certificate entered its
+// current level, so it resurfaces in readers when it turns critical.
+func expiringFeed(certificates []certs.Certificate, thresholds *certs.ExpiryThresholds, messages i18n.Messages, now time.Time) atomFeed {
+	feed := atomFeed{Title: messages.FeedExpiringTitle}
+	var expiring []certs.Certificate
+	for _, certificate := range certificates {
+		if thresholds.Tier(certificate, now) != certs.ExpiryTierNone {
+			expiring = append(expiring, certificate)
+		}
+	}
+	slices.SortStableFunc(expiring, func(a, b certs.Certificate) int {
+		return cmp.Or(a.ExpiresAt.Compare(b.ExpiresAt), strings.Compare(a.ID, b.ID))
+	})
+	for _, certificate := range expiring {
+		tier := thresholds.Tier(certificate, now)
+		vaultID, mount := certs.VaultAndMount(certificate.ID)
+		warning, critical := thresholds.WindowFor(vaultID, mount).Lead(certificate)
+		lead := warning
+		if tier == certs.ExpiryTierCritical {
+			lead = critical
+		}
+		entered := certificate.ExpiresAt.Add(-lead)
+		if entered.Before(certificate.CreatedAt) {
+			entered = certificate.CreatedAt
+		}
+		entry := feedEntry(certificate, tier, entered, messages)
+		entry.Title = localize(messages.FeedEntryExpiring, "name", certificate.CommonName, "date", certificate.ExpiresAt.UTC().Format(feedDateFormat))
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+// changesFeed has an entry for each certificate issued since the given
+// time and one for each certificate revoked since then, newest first.
+// Revocations Vault did not date are left out.
+func changesFeed(certificates []certs.Certificate, messages i18n.Messages, since, now time.Time) atomFeed {
+	feed := atomFeed{Title: messages.FeedChangesTitle}
+	inWindow := func(at time.Time) bool {
+		return !at.IsZero() && !at.Before(since) && !at.After(now)
+	}
+	for _, certificate := range certificates {
+		if inWindow(certificate.CreatedAt) {
+			entry := feedEntry(certificate, feedEntryIssued, certificate.CreatedAt, messages)
+			entry.Title = localize(messages.FeedEntryIssued, "name", certificate.CommonName)
+			entry.Published = entry.Updated
+			feed.Entries = append(feed.Entries, entry)
+		}
+		if certificate.Revoked && certificate.RevokedAt != nil && inWindow(*certificate.RevokedAt) {
+			entry := feedEntry(certificate, feedEntryRevoked, *certificate.RevokedAt, messages)
+			entry.Title = localize(messages.FeedEntryRevoked, "name", certificate.CommonName)
+			entry.Published = entry.Updated
+			feed.Entries = append(feed.Entries, entry)
+		}
+	}
+	slices.SortStableFunc(feed.Entries, func(a, b atomEntry) int {
+		return cmp.Or(b.at.Compare(a.at), strings.Compare(a.ID, b.ID))
+	})
+	return feed
+}
+
+// feedEntry builds the common part of an entry. Its ID depends on the
+// certificate and the kind of entry only, so readers do not show it twice.
+func feedEntry(certificate certs.Certificate, kind string, at time.Time, messages i18n.Messages) atomEntry {
+	vaultID, mount := certs.VaultAndMount(certificate.ID)
+	sum := sha256.Sum256([]byte(certificate.ID))
+	return atomEntry{
+		ID:         "urn:vcv:" + kind + ":" + hex.EncodeToString(sum[:16]),
+		Updated:    atomTime(at),
+		Link:       atomLink{Rel: "alternate", Href: "/?q=" + url.QueryEscape(certificate.SerialNumber), Type: "text/html"},
+		Categories: []atomCategory{{Term: kind}},
+		Summary: localize(messages.FeedEntrySummary,
+			"serial", certificate.SerialNumber,
+			"mount", stats.MountKey(vaultID, mount),
+			"date", certificate.ExpiresAt.UTC().Format(feedDateFormat)),
+		at: at,
+	}
+}
+
+// localize fills the {{name}} placeholders of a translated message.
+func localize(message string, pairs ...string) string {
+	replacements := make([]string, 0, len(pairs))
+	for i := 0; i+1 < len(pairs); i += 2 {
+		replacements = append(replacements, "{{"+pairs[i]+"}}", pairs[i+1])
+	}
+	return strings.NewReplacer(replacements...).Replace(message)
+}
+
+// atomTime formats an RFC 3339 date in UTC, as Atom requires.
+func atomTime(at time.Time) string {
+	return at.UTC().Format(time.RFC3339)
+}
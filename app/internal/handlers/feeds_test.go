@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+)
+
+type testAtomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string   `xml:"lang,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		ID       string `xml:"id"`
+		Title    string `xml:"title"`
+		Updated  string `xml:"updated"`
+		Summary  string `xml:"summary"`
+		Category struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+func getFeed(t *testing.T, router http.Handler, target string) testAtomFeed {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	var feed testAtomFeed
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+	return feed
+}
+
+func TestFeeds_Expiring(t *testing.T) {
+	now := time.Now()
+	critical := now.Add(3 * 24 * time.Hour).UTC()
+	router := calendarRouter(t, []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "warning.example.com", SerialNumber: "aa", ExpiresAt: now.Add(20 * 24 * time.Hour)},
+		{ID: "v1|pki:bb", CommonName: "critical.example.com", SerialNumber: "bb", ExpiresAt: critical},
+		{ID: "v1|pki:cc", CommonName: "later.example.com", ExpiresAt: now.Add(60 * 24 * time.Hour)},
+		{ID: "v1|pki:dd", CommonName: "revoked.example.com", ExpiresAt: now.Add(3 * 24 * time.Hour), Revoked: true},
+		{ID: "v1|pki:ee", CommonName: "acknowledged.example.com", ExpiresAt: now.Add(3 * 24 * time.Hour), Acknowledgement: &certs.Acknowledgement{ID: "ack-1"}},
+		{ID: "v2|pki_int:ff", CommonName: "other.example.com", ExpiresAt: now.Add(5 * 24 * time.Hour)},
+	})
+
+	feed := getFeed(t, router, "/api/feeds/expiring.atom?mounts=v1|pki")
+	assert.Equal(t, "en", feed.Lang)
+	assert.Equal(t, "urn:vcv:feed:expiring", feed.ID)
+	assert.Equal(t, "Certificates expiring soon", feed.Title)
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "critical.example.com expires on "+critical.Format("2006-01-02"), feed.Entries[0].Title)
+	assert.Equal(t, "critical", feed.Entries[0].Category.Term)
+	assert.Equal(t, critical.Add(-7*24*time.Hour).Format(time.RFC3339), feed.Entries[0].Updated, "dated when it entered the critical window")
+	assert.Equal(t, "Serial bb on mount v1|pki, valid until "+critical.Format("2006-01-02"), feed.Entries[0].Summary)
+	assert.Equal(t, "warning", feed.Entries[1].Category.Term)
+	assert.Equal(t, feed.Entries[0].Updated, feed.Updated, "the feed is as recent as its newest entry")
+	require.NotEmpty(t, feed.Links)
+	assert.Equal(t, "self", feed.Links[0].Rel)
+	assert.Equal(t, "/api/feeds/expiring.atom?mounts=v1|pki", feed.Links[0].Href)
+
+	again := getFeed(t, router, "/api/v1/feeds/expiring.atom?mounts=v1|pki")
+	assert.Equal(t, feed.Entries[0].ID, again.Entries[0].ID, "entry IDs are stable across fetches")
+	assert.NotEqual(t, feed.Entries[0].ID, feed.Entries[1].ID)
+
+	localized := getFeed(t, router, "/api/feeds/expiring.atom?lang=fr")
+	assert.Equal(t, "fr", localized.Lang)
+	assert.Equal(t, "Certificats arrivant à expiration", localized.Title)
+	require.Len(t, localized.Entries, 3)
+	assert.Equal(t, "critical.example.com expire le "+critical.Format("2006-01-02"), localized.Entries[0].Title)
+}
+
+func TestFeeds_Changes(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	revokedAt := now.Add(-24 * time.Hour)
+	router := calendarRouter(t, []certs.Certificate{
+		{ID: "v1|pki:aa", CommonName: "new.example.com", CreatedAt: now.Add(-2 * 24 * time.Hour), ExpiresAt: now.Add(90 * 24 * time.Hour)},
+		{ID: "v1|pki:bb", CommonName: "old.example.com", CreatedAt: now.Add(-30 * 24 * time.Hour), ExpiresAt: now.Add(60 * 24 * time.Hour)},
+		{ID: "v1|pki:cc", CommonName: "revoked.example.com", CreatedAt: now.Add(-60 * 24 * time.Hour), ExpiresAt: now.Add(30 * 24 * time.Hour), Revoked: true, RevokedAt: &revokedAt},
+		{ID: "v1|pki:dd", CommonName: "undated-revocation.example.com", CreatedAt: now.Add(-60 * 24 * time.Hour), ExpiresAt: now.Add(30 * 24 * time.Hour), Revoked: true},
+		{ID: "v2|pki:ee", CommonName: "other.example.com", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(90 * 24 * time.Hour)},
+	})
+
+	titles := func(feed testAtomFeed) []string {
+		var result []string
+		for _, entry := range feed.Entries {
+			result = append(result, entry.Title)
+		}
+		return result
+	}
+
+	feed := getFeed(t, router, "/api/feeds/changes.atom")
+	assert.Equal(t, "Certificate changes", feed.Title)
+	assert.Equal(t, []string{
+		"Certificate issued: other.example.com",
+		"Certificate revoked: revoked.example.com",
+		"Certificate issued: new.example.com",
+	}, titles(feed))
+	assert.Equal(t, revokedAt.Format(time.RFC3339), feed.Entries[1].Updated)
+	assert.Equal(t, "revoked", feed.Entries[1].Category.Term)
+	assert.Equal(t, "issued", feed.Entries[2].Category.Term)
+
+	assert.Equal(t, []string{
+		"Certificate revoked: revoked.example.com",
+		"Certificate issued: new.example.com",
+		"Certificate issued: old.example.com",
+	}, titles(getFeed(t, router, "/api/feeds/changes.atom?days=45&mounts=v1|pki")))
+	assert.Equal(t, []string{"Zertifikat ausgestellt: other.example.com"}, titles(getFeed(t, router, "/api/feeds/changes.atom?mounts=v2|pki&lang=de")))
+
+	for _, days := range []string{"0", "366", "week"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/feeds/changes.atom?days="+days, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, days)
+	}
+}
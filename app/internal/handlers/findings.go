@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// keyReuseResponse is the response shape for GET /api/findings/key-reuse.
+// Errors mirrors certsEnvelope so a partially reachable inventory still
+// reports the groups it could see.
+type keyReuseResponse struct {
+	Groups        []certs.KeyReuseGroup `json:"groups"`
+	ReusedKeys    int                   `json:"reusedKeys"`
+	AffectedCerts int                   `json:"affectedCertificates"`
+	Errors        []vault.VaultError    `json:"errors"`
+}
+
+// RegisterFindingsRoutes exposes inventory-wide hygiene reports.
+func RegisterFindingsRoutes(r chi.Router, vaultClient vault.Client) {
+	HandleAPI(r, http.MethodGet, "/findings/key-reuse", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for key reuse report")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		selectedMounts := parseMountsQueryParam(req.URL.Query())
+		groups := certs.FindKeyReuse(filterCertificatesByMounts(certificates, selectedMounts))
+		affected := 0
+		for _, group := range groups {
+			affected += len(group.Certificates)
+		}
+		response := keyReuseResponse{Groups: groups, ReusedKeys: len(groups), AffectedCerts: affected, Errors: vaultErrors}
+
+		w.Header().Set("Content-Type", "application/json")
+		if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, encodeErr).
+				Str("request_id", requestID).
+				Msg("failed to encode key reuse response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("reused_keys", len(groups)).
+			Int("affected_certificates", affected).
+			Msg("key reuse report served")
+	})
+}
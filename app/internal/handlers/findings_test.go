@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+func setupFindingsRouter(client vault.Client) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterFindingsRoutes(r, client)
+	return r
+}
+
+func TestKeyReuseReport(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "vault-a|pki:1", CommonName: "a", PublicKeyFingerprint: "k1", ExpiresAt: time.Now()},
+		{ID: "vault-b|pki_int:2", CommonName: "a", PublicKeyFingerprint: "k1", ExpiresAt: time.Now()},
+		{ID: "vault-a|pki:3", CommonName: "b", PublicKeyFingerprint: "k2", ExpiresAt: time.Now()},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/findings/key-reuse", nil)
+	rec := httptest.NewRecorder()
+	setupFindingsRouter(mockVault).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Groups               []certs.KeyReuseGroup `json:"groups"`
+		ReusedKeys           int                   `json:"reusedKeys"`
+		AffectedCertificates int                   `json:"affectedCertificates"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Groups, 1)
+	assert.Equal(t, "k1", got.Groups[0].Fingerprint)
+	assert.Equal(t, 1, got.ReusedKeys)
+	assert.Equal(t, 2, got.AffectedCertificates)
+}
+
+func TestKeyReuseReport_MountFilter(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "vault-a|pki:1", PublicKeyFingerprint: "k1"},
+		{ID: "vault-b|pki_int:2", PublicKeyFingerprint: "k1"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/findings/key-reuse?mounts=vault-a|pki", nil)
+	rec := httptest.NewRecorder()
+	setupFindingsRouter(mockVault).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		ReusedKeys int `json:"reusedKeys"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, 0, got.ReusedKeys)
+}
+
+func TestKeyReuseReport_Error(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, errors.New("boom"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/findings/key-reuse", nil)
+	rec := httptest.NewRecorder()
+	setupFindingsRouter(mockVault).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestListCertificates_SharedKeyWith(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "vault-a|pki:1", PublicKeyFingerprint: "k1"},
+		{ID: "vault-b|pki:2", PublicKeyFingerprint: "k1"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/certs?mounts=vault-a|pki", nil)
+	rec := httptest.NewRecorder()
+	setupRouter(mockVault).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got certsEnvelopeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Certificates, 1)
+	assert.Equal(t, []string{"vault-b|pki:2"}, got.Certificates[0].SharedKeyWith)
+}
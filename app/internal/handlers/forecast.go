@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+const (
+	defaultForecastWeeks = 12
+	maxForecastWeeks     = 104
+	maxForecastMonths    = 24
+)
+
+// forecastEnvelope is the response shape for GET /api/forecast. Errors
+// carries per-vault failures like statsEnvelope.
+type forecastEnvelope struct {
+	stats.Forecast
+	Errors []vault.VaultError `json:"errors"`
+}
+
+// registerForecastRoute mounts /api/forecast, which counts the upcoming
+// expirations of the certificates /api/certs would return for the same
+// filters, per week (weeks=N) or month (months=N) ahead.
+func registerForecastRoute(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/forecast", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		period, count, periodErr := parseForecastPeriod(query)
+		listQuery, queryErr := parseCertListQuery(query)
+		if queryErr == nil {
+			queryErr = periodErr
+		}
+		if queryErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, queryErr).
+				Str("request_id", requestID).
+				Msg("invalid forecast query")
+			writeAPIError(w, req, invalidRequest(queryErr.Error()))
+			return
+		}
+		listQuery.page, listQuery.pageSize = 0, 0
+
+		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for forecast")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		now := time.Now()
+		mountCertificates := filterCertificatesByMounts(certificates, parseMountsQueryParam(query))
+		// A renewal the filters leave out still supersedes what it renewed.
+		superseded := certs.Superseded(mountCertificates)
+		result := listQuery.apply(mountCertificates, thresholds, now)
+		forecast, err := stats.ComputeForecast(result.Certificates, superseded, period, count, now)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to compute forecast")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		envelope := forecastEnvelope{Forecast: forecast, Errors: vaultErrors}
+
+		w.Header().Set("Content-Type", "application/json")
+		if encodeErr := json.NewEncoder(w).Encode(envelope); encodeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, encodeErr).
+				Str("request_id", requestID).
+				Msg("failed to encode forecast response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("period", period).
+			Int("windows", count).
+			Int("total", envelope.Total).
+			Int("vault_errors", len(vaultErrors)).
+			Msg("expiry forecast computed")
+	})
+}
+
+// parseForecastPeriod reads weeks or months, which are mutually exclusive;
+// neither means 12 weeks.
+func parseForecastPeriod(query url.Values) (string, int, error) {
+	weeks := strings.TrimSpace(query.Get("weeks"))
+	months := strings.TrimSpace(query.Get("months"))
+	switch {
+	case weeks != "" && months != "":
+		return "", 0, fmt.Errorf("weeks and months cannot be combined")
+	case months != "":
+		count, err := strconv.Atoi(months)
+		if err != nil || count < 1 || count > maxForecastMonths {
+			return "", 0, fmt.Errorf("months: must be between 1 and %d", maxForecastMonths)
+		}
+		return stats.PeriodMonth, count, nil
+	case weeks != "":
+		count, err := strconv.Atoi(weeks)
+		if err != nil || count < 1 || count > maxForecastWeeks {
+			return "", 0, fmt.Errorf("weeks: must be between 1 and %d", maxForecastWeeks)
+		}
+		return stats.PeriodWeek, count, nil
+	default:
+		return stats.PeriodWeek, defaultForecastWeeks, nil
+	}
+}
@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+type forecastEnvelopeResponse struct {
+	stats.Forecast
+	Errors []vault.VaultError `json:"errors"`
+}
+
+func forecastRouter() http.Handler {
+	now := time.Now()
+	day := 24 * time.Hour
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "v1|pki:1", CommonName: "api.example.com", CreatedAt: now.Add(-300 * day), ExpiresAt: now.Add(3 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki:2", CommonName: "api.example.com", CreatedAt: now.Add(-day), ExpiresAt: now.Add(380 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki:3", CommonName: "web.example.com", ExpiresAt: now.Add(10 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki_int:4", CommonName: "db.internal", ExpiresAt: now.Add(40 * day)},
+		{ID: "v2|pki:5", CommonName: "gone.example.com", ExpiresAt: now.Add(5 * day), Revoked: true},
+	}, nil)
+	return setupRouter(mockVault)
+}
+
+func getForecast(t *testing.T, router http.Handler, target string) forecastEnvelopeResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body forecastEnvelopeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestForecast_Weekly(t *testing.T) {
+	body := getForecast(t, forecastRouter(), "/api/v1/forecast?weeks=8")
+
+	assert.Equal(t, stats.PeriodWeek, body.Period)
+	require.Len(t, body.Windows, 8)
+	assert.Equal(t, []int{0, 1, 0, 0, 0, 1, 0, 0}, body.Totals)
+	assert.Equal(t, 2, body.Total)
+	assert.Equal(t, 1, body.Superseded)
+	require.Len(t, body.Groups, 2)
+	assert.Equal(t, "pki", body.Groups[0].Mount)
+	assert.Equal(t, "Root CA", body.Groups[0].Issuer)
+	assert.Equal(t, "pki_int", body.Groups[1].Mount)
+	assert.Equal(t, "unknown", body.Groups[1].Issuer)
+	assert.NotNil(t, body.Errors)
+}
+
+func TestForecast_MonthsAndFilters(t *testing.T) {
+	router := forecastRouter()
+
+	body := getForecast(t, router, "/api/forecast")
+	assert.Len(t, body.Windows, 12, "defaults to 12 weeks")
+
+	body = getForecast(t, router, "/api/v1/forecast?months=13")
+	assert.Equal(t, stats.PeriodMonth, body.Period)
+	assert.Equal(t, 1, body.Totals[12])
+
+	// expiring_within leaves the renewal of pki:1 out; pki:1 stays superseded.
+	body = getForecast(t, router, "/api/v1/forecast?weeks=8&expiring_within=30d")
+	assert.Equal(t, 1, body.Total)
+	assert.Equal(t, 1, body.Superseded)
+
+	body = getForecast(t, router, "/api/v1/forecast?weeks=8&mounts=v1|pki_int")
+	assert.Equal(t, 1, body.Total)
+	assert.Zero(t, body.Superseded)
+}
+
+func TestForecast_InvalidQuery(t *testing.T) {
+	for _, target := range []string{
+		"/api/v1/forecast?weeks=0",
+		"/api/v1/forecast?weeks=105",
+		"/api/v1/forecast?months=x",
+		"/api/v1/forecast?weeks=4&months=2",
+		"/api/v1/forecast?status=unknown",
+	} {
+		mockVault := new(vault.MockClient)
+		rec := httptest.NewRecorder()
+		setupRouter(mockVault).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/history"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/stats"
+)
+
+// defaultHistoryDays is the range of GET /api/history without from.
+const defaultHistoryDays = 30
+
+// historyResponse is the response shape for GET /api/history.
+type historyResponse struct {
+	Enabled    bool           `json:"enabled"`
+	Resolution string         `json:"resolution"`
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Points     []historyPoint `json:"points"`
+}
+
+// historyPoint sums a snapshot over the selected mounts. FailedVaults lists
+// the vaults of the selection that could not be listed at that time.
+type historyPoint struct {
+	At           time.Time      `json:"at"`
+	Total        int            `json:"total"`
+	Statuses     map[string]int `json:"statuses"`
+	Expiring     stats.Expiring `json:"expiring"`
+	FailedVaults []string       `json:"failedVaults,omitempty"`
+}
+
+// RegisterHistoryRoutes exposes the recorded inventory snapshots. recorder
+// is nil when history is disabled; the route then reports enabled=false.
+func RegisterHistoryRoutes(r chi.Router, recorder *history.Recorder) {
+	HandleAPI(r, http.MethodGet, "/history", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		response, err := parseHistoryQuery(query.Get("from"), query.Get("to"), query.Get("resolution"), time.Now().UTC())
+		if err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, err).
+				Str("request_id", requestID).
+				Msg("invalid history query")
+			writeAPIError(w, req, invalidRequest(err.Error()))
+			return
+		}
+		response.Enabled = recorder != nil
+		filter := newMountFilter(parseMountsQueryParam(query))
+		for _, snapshot := range recorder.Snapshots(response.From, response.To, response.Resolution) {
+			response.Points = append(response.Points, sumSnapshot(snapshot, filter))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode history response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("resolution", response.Resolution).
+			Int("points", len(response.Points)).
+			Msg("history served")
+	})
+}
+
+// parseHistoryQuery applies the defaults of GET /api/history: the last 30
+// days at day resolution. from and to are RFC 3339 times or dates; a date
+// as to includes that whole day.
+func parseHistoryQuery(rawFrom, rawTo, rawResolution string, now time.Time) (historyResponse, error) {
+	response := historyResponse{Resolution: history.ResolutionDay, To: now, Points: []historyPoint{}}
+	switch resolution := strings.TrimSpace(rawResolution); resolution {
+	case "", history.ResolutionDay:
+	case history.ResolutionHour:
+		response.Resolution = resolution
+	default:
+		return historyResponse{}, fmt.Errorf("resolution: must be %s or %s", history.ResolutionHour, history.ResolutionDay)
+	}
+	if raw := strings.TrimSpace(rawTo); raw != "" {
+		to, isDate, err := parseHistoryTime(raw)
+		if err != nil {
+			return historyResponse{}, fmt.Errorf("to: %w", err)
+		}
+		if isDate {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		response.To = to
+	}
+	response.From = response.To.AddDate(0, 0, -defaultHistoryDays)
+	if raw := strings.TrimSpace(rawFrom); raw != "" {
+		from, _, err := parseHistoryTime(raw)
+		if err != nil {
+			return historyResponse{}, fmt.Errorf("from: %w", err)
+		}
+		response.From = from
+	}
+	if response.From.After(response.To) {
+		return historyResponse{}, fmt.Errorf("from must not be after to")
+	}
+	return response, nil
+}
+
+func parseHistoryTime(raw string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed.UTC(), false, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	return parsed, true, nil
+}
+
+func sumSnapshot(snapshot history.Snapshot, filter *mountFilter) historyPoint {
+	point := historyPoint{At: snapshot.At, Statuses: make(map[string]int, len(stats.Statuses))}
+	for _, status := range stats.Statuses {
+		point.Statuses[status] = 0
+	}
+	for _, mount := range snapshot.Mounts {
+		if !filter.matchesMount(mount.Vault, mount.Mount) {
+			continue
+		}
+		point.Total += mount.Total
+		for status, count := range mount.Statuses {
+			point.Statuses[status] += count
+		}
+		point.Expiring.Warning += mount.Expiring.Warning
+		point.Expiring.Critical += mount.Expiring.Critical
+	}
+	for _, vaultID := range snapshot.FailedVaults {
+		if filter.matchesVault(vaultID) {
+			point.FailedVaults = append(point.FailedVaults, vaultID)
+		}
+	}
+	return point
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/history"
+	"vcv/internal/vault"
+)
+
+func getHistory(t *testing.T, recorder *history.Recorder, query string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	r := chi.NewRouter()
+	handlers.RegisterHistoryRoutes(r, recorder)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/history"+query, nil))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec, body
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	rec, body := getHistory(t, nil, "")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, false, body["enabled"])
+	assert.Equal(t, "day", body["resolution"])
+	assert.Equal(t, []any{}, body["points"])
+}
+
+func TestHistory_Points(t *testing.T) {
+	now := time.Now().UTC()
+	client := new(vault.MockClient)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "v1|pki:01", ExpiresAt: now.AddDate(1, 0, 0)},
+		{ID: "v1|pki:02", ExpiresAt: now.AddDate(0, 0, 3)},
+		{ID: "v2|pki:03", ExpiresAt: now.AddDate(1, 0, 0)},
+	}, nil)
+	recorder, err := history.New(config.HistorySettings{Enabled: true}, t.TempDir(), client, nil)
+	require.NoError(t, err)
+	recorder.Record(context.Background())
+
+	rec, body := getHistory(t, recorder, "?resolution=hour")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, "hour", body["resolution"])
+	points := body["points"].([]any)
+	require.Len(t, points, 1)
+	assert.Equal(t, float64(3), points[0].(map[string]any)["total"])
+
+	_, body = getHistory(t, recorder, "?mounts=v1|pki")
+	points = body["points"].([]any)
+	require.Len(t, points, 1)
+	assert.Equal(t, float64(2), points[0].(map[string]any)["total"])
+	assert.Equal(t, float64(2), points[0].(map[string]any)["statuses"].(map[string]any)["valid"])
+
+	_, body = getHistory(t, recorder, "?to="+now.AddDate(0, 0, -1).Format(time.DateOnly))
+	assert.Equal(t, []any{}, body["points"])
+}
+
+func TestHistory_InvalidQuery(t *testing.T) {
+	for _, query := range []string{"?resolution=week", "?from=yesterday", "?to=2026-13-01", "?from=2026-02-01&to=2026-01-01"} {
+		rec, body := getHistory(t, nil, query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Equal(t, "invalid_request", body["code"], query)
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 // RegisterI18nRoutes exposes a small JSON API for UI translations.
 func RegisterI18nRoutes(router chi.Router) {
-	router.Get("/api/i18n", func(writer http.ResponseWriter, request *http.Request) {
+	HandleAPI(router, http.MethodGet, "/i18n", func(writer http.ResponseWriter, request *http.Request) {
 		language := i18n.ResolveLanguage(request)
 		payload := i18n.Response{
 			Language: language,
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// maxInspectBodyBytes bounds POST /api/inspect; a long chain in PEM is a
+// few tens of KiB.
+const maxInspectBodyBytes = 256 << 10
+
+// inspectResponse is the body of POST /api/inspect. Verified is true when
+// verify=true asked for IssuedBy to be filled.
+type inspectResponse struct {
+	Certificates []inspectedCertificate `json:"certificates"`
+	Requests     []inspectedRequest     `json:"requests"`
+	Verified     bool                   `json:"verified"`
+}
+
+type inspectedCertificate struct {
+	certs.DetailedCertificate
+	Findings []certs.LintFinding `json:"findings"`
+	// IssuedBy lists the vault|mount keys whose issuing CA signed the
+	// certificate.
+	IssuedBy []string `json:"issuedBy"`
+}
+
+type inspectedRequest struct {
+	certs.CertificateRequest
+	Findings []certs.LintFinding `json:"findings"`
+}
+
+// RegisterInspectRoutes mounts POST /api/inspect, which parses certificates,
+// chains and CSRs sent in the body (PEM, DER or PKCS #7) with the code
+// behind /api/certs/{id}/details and lints them. With verify=true, each
+// certificate is checked against the issuers of every configured mount.
+// Nothing is stored.
+func RegisterInspectRoutes(r chi.Router, vaultClient vault.Client) {
+	HandleAPI(r.With(middleware.BodyLimit(maxInspectBodyBytes)), http.MethodPost, "/inspect", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			status, message := http.StatusBadRequest, "failed to read body"
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status, message = http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d bytes", maxInspectBodyBytes)
+			}
+			logger.HTTPError(req.Method, req.URL.Path, status, err).
+				Str("request_id", requestID).
+				Msg("failed to read inspect body")
+			writeAPIError(w, req, apiError{status: status, Code: errCodeInvalidRequest, Message: message})
+			return
+		}
+		parsedCertificates, parsedRequests, err := decodeInspectBody(body)
+		if err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, err).
+				Str("request_id", requestID).
+				Msg("invalid inspect body")
+			writeAPIError(w, req, invalidRequest(err.Error()))
+			return
+		}
+
+		now := time.Now()
+		response := inspectResponse{
+			Certificates: make([]inspectedCertificate, 0, len(parsedCertificates)),
+			Requests:     make([]inspectedRequest, 0, len(parsedRequests)),
+			Verified:     req.URL.Query().Get("verify") == "true",
+		}
+		var issuers map[string][]*x509.Certificate
+		if response.Verified {
+			issuers, err = vault.MountIssuers(req.Context(), vaultClient)
+			if err != nil {
+				apiErr := classifyError(err)
+				logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+					Str("request_id", requestID).
+					Msg("failed to read mount CAs for inspect")
+				writeAPIError(w, req, apiErr)
+				return
+			}
+		}
+		for _, certificate := range parsedCertificates {
+			details := certs.NewDetailedCertificate(certificate, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})))
+			inspected := inspectedCertificate{DetailedCertificate: details, Findings: certs.LintCertificate(certificate, now), IssuedBy: []string{}}
+			inspected.IssuedBy = append(inspected.IssuedBy, vault.IssuedBy(certificate, issuers)...)
+			sort.Strings(inspected.IssuedBy)
+			response.Certificates = append(response.Certificates, inspected)
+		}
+		for _, request := range parsedRequests {
+			response.Requests = append(response.Requests, inspectedRequest{
+				CertificateRequest: certs.NewCertificateRequest(request, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request.Raw}))),
+				Findings:           certs.LintCertificateRequest(request),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode inspect response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("certificates", len(response.Certificates)).
+			Int("requests", len(response.Requests)).
+			Bool("verified", response.Verified).
+			Msg("inspected certificates")
+	})
+}
+
+// decodeInspectBody reads PEM blocks when the body has any, else DER: a
+// certificate, concatenated certificates, a CSR or a PKCS #7 bundle.
+// Private keys are refused rather than ignored, so a pasted key is noticed.
+func decodeInspectBody(body []byte) ([]*x509.Certificate, []*x509.CertificateRequest, error) {
+	var certificates []*x509.Certificate
+	var requests []*x509.CertificateRequest
+	if bytes.Contains(body, []byte("-----BEGIN ")) {
+		rest := body
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch {
+			case block.Type == "CERTIFICATE":
+				certificate, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, nil, fmt.Errorf("certificate %d: %w", len(certificates)+1, err)
+				}
+				certificates = append(certificates, certificate)
+			case block.Type == "CERTIFICATE REQUEST" || block.Type == "NEW CERTIFICATE REQUEST":
+				request, err := x509.ParseCertificateRequest(block.Bytes)
+				if err != nil {
+					return nil, nil, fmt.Errorf("certificate request %d: %w", len(requests)+1, err)
+				}
+				requests = append(requests, request)
+			case block.Type == "PKCS7":
+				bundled, err := parsePKCS7Certificates(block.Bytes)
+				if err != nil {
+					return nil, nil, err
+				}
+				certificates = append(certificates, bundled...)
+			case strings.Contains(block.Type, "PRIVATE KEY"):
+				return nil, nil, fmt.Errorf("private keys are not accepted; send only certificates and CSRs")
+			}
+		}
+	} else if parsed, err := x509.ParseCertificates(body); err == nil {
+		certificates = parsed
+	} else if request, err := x509.ParseCertificateRequest(body); err == nil {
+		requests = append(requests, request)
+	} else if bundled, err := parsePKCS7Certificates(body); err == nil {
+		certificates = bundled
+	}
+	if len(certificates) == 0 && len(requests) == 0 {
+		return nil, nil, fmt.Errorf("no certificate or certificate request found")
+	}
+	return certificates, requests, nil
+}
+
+func parsePKCS7Certificates(data []byte) ([]*x509.Certificate, error) {
+	ders, err := certs.DecodePKCS7(data)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS #7: %w", err)
+	}
+	certificates := make([]*x509.Certificate, 0, len(ders))
+	for _, der := range ders {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("PKCS #7: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, nil
+}
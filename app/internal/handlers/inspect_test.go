@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+type inspectBody struct {
+	Certificates []struct {
+		certs.DetailedCertificate
+		Findings []certs.LintFinding `json:"findings"`
+		IssuedBy []string            `json:"issuedBy"`
+	} `json:"certificates"`
+	Requests []struct {
+		certs.CertificateRequest
+		Findings []certs.LintFinding `json:"findings"`
+	} `json:"requests"`
+	Verified bool `json:"verified"`
+}
+
+func inspectRouter(vaultClient vault.Client) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterInspectRoutes(r, vaultClient)
+	return r
+}
+
+// mountsMockClient reports configured mounts like the multi-vault client.
+type mountsMockClient struct {
+	*vault.MockClient
+	mounts []string
+}
+
+func (c *mountsMockClient) Mounts() []string {
+	return c.mounts
+}
+
+// newInspectCA returns a CA certificate in PEM and a leaf it signed, in DER.
+func newInspectCA(t *testing.T, name string) (string, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	require.NoError(t, err)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "api.example.com"},
+		DNSNames:     []string{"api.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &leafKey.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})), leafDER
+}
+
+func postInspect(t *testing.T, router http.Handler, target, body string) (*httptest.ResponseRecorder, inspectBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+	var decoded inspectBody
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	}
+	return rec, decoded
+}
+
+func TestInspect_ChainAndRequest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "app.example.com"},
+		DNSNames: []string{"app.example.com"},
+	}, key)
+	require.NoError(t, err)
+	body := newBundleTestPEM(t, "leaf.example.com") + string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+	mockVault := new(vault.MockClient)
+
+	rec, decoded := postInspect(t, inspectRouter(mockVault), "/api/v1/inspect", body)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.False(t, decoded.Verified)
+	require.Len(t, decoded.Certificates, 1)
+	assert.Equal(t, "leaf.example.com", decoded.Certificates[0].CommonName)
+	assert.NotEmpty(t, decoded.Certificates[0].FingerprintSHA256)
+	assert.Contains(t, decoded.Certificates[0].PEM, "BEGIN CERTIFICATE")
+	assert.Contains(t, decoded.Certificates[0].Findings, certs.LintFinding{Rule: "missing_san", Severity: certs.LintWarning, Message: "no subject alternative name"})
+	assert.Empty(t, decoded.Certificates[0].IssuedBy)
+	require.Len(t, decoded.Requests, 1)
+	assert.Equal(t, []string{"app.example.com"}, decoded.Requests[0].Sans)
+	assert.Empty(t, decoded.Requests[0].Findings)
+	mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+}
+
+func TestInspect_VerifyAgainstMounts(t *testing.T) {
+	caPEM, leafDER := newInspectCA(t, "Example Issuing CA")
+	otherPEM, _ := newInspectCA(t, "Example Issuing CA")
+	mockVault := &mountsMockClient{MockClient: new(vault.MockClient), mounts: []string{"v1|pki", "v1|other", "v2|broken"}}
+	mockVault.On("GetIntermediateCA", mock.Anything, "v1|pki").Return(certs.DetailedCertificate{PEM: caPEM}, nil).Once()
+	mockVault.On("GetIntermediateCA", mock.Anything, "v1|other").Return(certs.DetailedCertificate{PEM: otherPEM}, nil).Once()
+	mockVault.On("GetIntermediateCA", mock.Anything, "v2|broken").Return(certs.DetailedCertificate{}, vault.ErrVaultUnavailable).Once()
+
+	// DER input is accepted as is.
+	rec, decoded := postInspect(t, inspectRouter(mockVault), "/api/v1/inspect?verify=true", string(leafDER))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, decoded.Verified)
+	require.Len(t, decoded.Certificates, 1)
+	// The other CA has the same name but a different key.
+	assert.Equal(t, []string{"v1|pki"}, decoded.Certificates[0].IssuedBy)
+	mockVault.AssertExpectations(t)
+	mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+}
+
+func TestInspect_Errors(t *testing.T) {
+	mockVault := &mountsMockClient{MockClient: new(vault.MockClient), mounts: []string{"v1|pki"}}
+	mockVault.On("GetIntermediateCA", mock.Anything, "v1|pki").Return(certs.DetailedCertificate{}, vault.ErrVaultUnavailable)
+	router := inspectRouter(mockVault)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte{1}}))
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{name: "empty", target: "/api/v1/inspect", body: "", status: http.StatusBadRequest},
+		{name: "garbage", target: "/api/v1/inspect", body: "not a certificate", status: http.StatusBadRequest},
+		{name: "private key", target: "/api/v1/inspect", body: newBundleTestPEM(t, "a") + keyPEM, status: http.StatusBadRequest},
+		{name: "too large", target: "/api/v1/inspect", body: strings.Repeat("A", 256<<10+1), status: http.StatusRequestEntityTooLarge},
+		{name: "vault down", target: "/api/v1/inspect?verify=true", body: newBundleTestPEM(t, "a"), status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := postInspect(t, router, tt.target, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
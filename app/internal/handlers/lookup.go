@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+// lookupResponse is the response shape for GET /api/lookup. Errors mirrors
+// certsEnvelope so an unreachable vault does not hide the other matches.
+type lookupResponse struct {
+	Host         string             `json:"host"`
+	Certificates []lookupMatch      `json:"certificates"`
+	Errors       []vault.VaultError `json:"errors"`
+}
+
+// lookupMatch is a covering certificate with the name that matched.
+type lookupMatch struct {
+	certs.Certificate
+	Status      string `json:"status"`
+	MatchedName string `json:"matchedName"`
+	Wildcard    bool   `json:"wildcard"`
+}
+
+// lookupStatusRank orders matches from the most to the least usable.
+var lookupStatusRank = map[string]int{
+	certs.StatusValid:    0,
+	certs.StatusWarning:  1,
+	certs.StatusCritical: 2,
+	certs.StatusExpired:  3,
+	certs.StatusRevoked:  4,
+}
+
+// RegisterLookupRoutes mounts GET /api/lookup, which answers "which
+// certificates cover this host name or IP address?".
+func RegisterLookupRoutes(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/lookup", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		host, hostErr := certs.NormalizeHost(req.URL.Query().Get("host"))
+		if hostErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, hostErr).
+				Str("request_id", requestID).
+				Msg("invalid lookup host")
+			writeAPIError(w, req, invalidRequest("host: must be a DNS name or an IP address"))
+			return
+		}
+		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for lookup")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		now := time.Now()
+		selectedMounts := parseMountsQueryParam(req.URL.Query())
+		index := certs.NewHostIndex(filterCertificatesByMounts(certificates, selectedMounts))
+		hostMatches := index.Lookup(host)
+		matches := make([]lookupMatch, 0, len(hostMatches))
+		for _, match := range hostMatches {
+			matches = append(matches, lookupMatch{
+				Certificate: match.Certificate,
+				Status:      thresholds.Status(match.Certificate, now),
+				MatchedName: match.MatchedName,
+				Wildcard:    match.Wildcard,
+			})
+		}
+		sortLookupMatches(matches)
+		response := lookupResponse{Host: host, Certificates: matches, Errors: vaultErrors}
+
+		w.Header().Set("Content-Type", "application/json")
+		if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, encodeErr).
+				Str("request_id", requestID).
+				Msg("failed to encode lookup response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Str("host", host).
+			Int("count", len(matches)).
+			Msg("host lookup served")
+	})
+}
+
+// sortLookupMatches ranks by status (valid first), then by remaining
+// validity (longest first), then by ID for a stable order.
+func sortLookupMatches(matches []lookupMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if lookupStatusRank[a.Status] != lookupStatusRank[b.Status] {
+			return lookupStatusRank[a.Status] < lookupStatusRank[b.Status]
+		}
+		if !a.ExpiresAt.Equal(b.ExpiresAt) {
+			return a.ExpiresAt.After(b.ExpiresAt)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/vault"
+)
+
+type lookupResponse struct {
+	Host         string `json:"host"`
+	Certificates []struct {
+		ID          string `json:"id"`
+		Status      string `json:"status"`
+		MatchedName string `json:"matchedName"`
+		Wildcard    bool   `json:"wildcard"`
+	} `json:"certificates"`
+	Errors []vault.VaultError `json:"errors"`
+}
+
+func setupLookupRouter(t *testing.T, certificates []certs.Certificate) *chi.Mux {
+	t.Helper()
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certificates, nil)
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterLookupRoutes(r, mockVault, thresholds)
+	return r
+}
+
+func TestLookup_RanksCoveringCertificates(t *testing.T) {
+	now := time.Now()
+	router := setupLookupRouter(t, []certs.Certificate{
+		{ID: "v1|pki:expired", Sans: []string{"api.payments.example.com"}, ExpiresAt: now.Add(-time.Hour)},
+		{ID: "v1|pki:short", Sans: []string{"api.payments.example.com"}, ExpiresAt: now.Add(60 * 24 * time.Hour)},
+		{ID: "v2|pki:wild", Sans: []string{"*.payments.example.com"}, ExpiresAt: now.Add(300 * 24 * time.Hour)},
+		{ID: "v2|pki:critical", Sans: []string{"*.payments.example.com"}, ExpiresAt: now.Add(2 * 24 * time.Hour)},
+		{ID: "v2|pki:other", Sans: []string{"*.example.com"}, ExpiresAt: now.Add(300 * 24 * time.Hour)},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/lookup?host=https://API.payments.example.com:443/", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got lookupResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "api.payments.example.com", got.Host)
+	ids := make([]string, 0, len(got.Certificates))
+	for _, match := range got.Certificates {
+		ids = append(ids, match.ID)
+	}
+	assert.Equal(t, []string{"v2|pki:wild", "v1|pki:short", "v2|pki:critical", "v1|pki:expired"}, ids)
+	assert.Equal(t, "*.payments.example.com", got.Certificates[0].MatchedName)
+	assert.True(t, got.Certificates[0].Wildcard)
+	assert.Equal(t, "critical", got.Certificates[2].Status)
+	assert.NotNil(t, got.Errors)
+}
+
+func TestLookup_IPAddress(t *testing.T) {
+	router := setupLookupRouter(t, []certs.Certificate{
+		{ID: "pki:ip", Sans: []string{"10.0.0.5"}, ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "pki:dns", Sans: []string{"*.0.0.5"}, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/lookup?host=10.0.0.5", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got lookupResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Certificates, 1)
+	assert.Equal(t, "pki:ip", got.Certificates[0].ID)
+}
+
+func TestLookup_InvalidHost(t *testing.T) {
+	router := setupLookupRouter(t, nil)
+	for _, target := range []string{"/api/lookup", "/api/lookup?host=bad%20host"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"vcv/internal/ack"
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/events"
+	"vcv/internal/history"
+	"vcv/internal/i18n"
+	"vcv/internal/openapi"
+)
+
+// jsonError is the body written by writeJSONError.
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+const adminSecurityScheme = "adminSession"
+
+var adminSecurity = []map[string][]string{{adminSecurityScheme: {}}}
+
+func plainTextResponse(description string) openapi.Response {
+	return openapi.Response{Description: description, Content: map[string]openapi.MediaType{"text/plain": {Schema: openapi.String("")}}}
+}
+
+func binaryResponse(description string, contentTypes ...string) openapi.Response {
+	content := make(map[string]openapi.MediaType, len(contentTypes))
+	for _, contentType := range contentTypes {
+		content[contentType] = openapi.MediaType{Schema: openapi.Binary()}
+	}
+	return openapi.Response{Description: description, Content: content}
+}
+
+// AddAPIOperation documents op under APIPrefix+path and as the deprecated
+// /api+path alias registered by HandleAPI.
+func AddAPIOperation(doc *openapi.Document, method, path string, op openapi.Operation) {
+	doc.Add(method, APIPrefix+path, op)
+	alias := op
+	alias.Deprecated = true
+	alias.Description = "Deprecated alias of `" + APIPrefix + path + "`."
+	doc.Add(method, legacyAPIPrefix+path, alias)
+}
+
+// errorResponses documents the error envelope for each status in statuses.
+func errorResponses(doc *openapi.Document, responses map[string]openapi.Response, statuses ...int) map[string]openapi.Response {
+	descriptions := map[int]string{
+		http.StatusBadRequest:            "Invalid parameters (`invalid_request`)",
+		http.StatusRequestEntityTooLarge: "Request body too large (`invalid_request`)",
+		http.StatusNotFound:              "Unknown certificate, mount or vault (`certificate_not_found`, `mount_not_configured`, `vault_not_found`, `not_found`)",
+		http.StatusInternalServerError:   "Internal error (`internal_error`)",
+		http.StatusBadGateway:            "Vault answered with an error (`vault_error`)",
+		http.StatusServiceUnavailable:    "Vault is unreachable, sealed or not configured (`vault_unavailable`)",
+	}
+	for _, status := range statuses {
+		responses[strconv.Itoa(status)] = openapi.Response{Description: descriptions[status], Content: doc.JSON(apiError{})}
+	}
+	return responses
+}
+
+// vaultErrorStatuses are the error statuses of routes that read from Vault.
+var vaultErrorStatuses = []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+
+func certificateIDParameter() openapi.Parameter {
+	return openapi.Parameter{Name: "id", In: "path", Required: true, Description: "Certificate ID, `vault-id|mount:serial`, URL-encoded", Schema: openapi.String("")}
+}
+
+func mountsParameter() openapi.Parameter {
+	return openapi.Parameter{Name: "mounts", In: "query", Description: "Comma-separated `vault|mount` or mount names; `__all__` selects every mount", Schema: openapi.String("")}
+}
+
+// certListParameters are the filter, sort and paging parameters shared by
+// /api/certs, its export and bundle endpoints.
+func certListParameters() []openapi.Parameter {
+	query := func(name, description string, schema *openapi.Schema) openapi.Parameter {
+		return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+	}
+	return []openapi.Parameter{
+		mountsParameter(),
+		query("q", "Case-insensitive substring of the common name, serial or a SAN", openapi.String("")),
+		query("query", "Certificate query language expression", openapi.String("")),
+		query("status", "Comma-separated statuses: valid, warning, critical, expired, revoked", openapi.String("")),
+		query("expiring_within", "Duration such as `30d` or `72h`", openapi.String("")),
+		query("issuer", "Case-insensitive substring of the issuer common name", openapi.String("")),
+		query("key_algorithm", "Comma-separated key algorithms, optionally with size (`RSA-2048`)", openapi.String("")),
+		query("cert_type", "Comma-separated certificate types", openapi.String("")),
+		query("sort", "Sort key", openapi.Enum("", "commonName", "expiresAt", "createdAt", "serialNumber", "vault", "pki", "issuer")),
+		query("order", "Sort order", openapi.Enum("", "asc", "desc")),
+		query("page_size", "Page size, 1 to 1000", openapi.Integer("")),
+		query("page", "1-based page number; requires page_size", openapi.Integer("")),
+	}
+}
+
+// OpenAPIDocument describes every route registered by this package. The
+// server adds the routes it defines itself (status, version, metrics).
+func OpenAPIDocument(version string) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "VaultCertsViewer API",
+		Version:     version,
+		Description: "Read-only inventory of certificates issued by HashiCorp Vault PKI mounts, and the optional admin API.",
+	})
+	doc.Tags = []openapi.Tag{
+		{Name: "certificates", Description: "Certificate inventory"},
+		{Name: "system", Description: "Health, configuration and translations"},
+		{Name: "admin", Description: "Settings management; requires an admin session"},
+	}
+	doc.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+		adminSecurityScheme: {Type: "apiKey", In: "cookie", Name: adminCookieName, Description: "Session cookie set by POST /api/admin/login"},
+	}
+	AddAPIOperation(doc, http.MethodGet, "/certs", openapi.Operation{
+		Summary: "List certificates",
+		Tags:    []string{"certificates"},
+		Parameters: append(certListParameters(),
+			openapi.Parameter{Name: "If-None-Match", In: "header", Description: "ETag of a previous response", Schema: openapi.String("")},
+		),
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Matching certificates and per-vault errors", Content: doc.JSON(certsEnvelope{})},
+			"304": {Description: "Unchanged since the ETag sent in If-None-Match"},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/export", openapi.Operation{
+		Summary:     "Export certificates",
+		Description: "Streams the certificates /certs returns with the same filters. Vaults that failed to list are named, comma-separated, in the `X-Vault-Errors` response header.",
+		Tags:        []string{"certificates"},
+		Parameters: append(certListParameters(),
+			openapi.Parameter{Name: "format", In: "query", Schema: openapi.Enum("", "csv", "json", "ndjson", "xlsx")},
+			openapi.Parameter{Name: "columns", In: "query", Description: "Comma-separated column keys", Schema: openapi.String("")},
+			openapi.Parameter{Name: "headers", In: "query", Description: "`keys` disables localized CSV/XLSX headers", Schema: openapi.Enum("", "keys")},
+			openapi.Parameter{Name: "lang", In: "query", Description: "Header language", Schema: openapi.String("")},
+		),
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": binaryResponse("Streamed export", "text/csv", "application/json", "application/x-ndjson", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"),
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/calendar.ics", openapi.Operation{
+		Summary:     "iCalendar feed of certificate expirations",
+		Description: "RFC 5545 feed with one event per certificate expiry, for the certificates /certs returns with the same filters. Event UIDs are derived from certificate IDs.",
+		Tags:        []string{"certificates"},
+		Parameters: append(certListParameters(),
+			openapi.Parameter{Name: "alarms", In: "query", Description: "`true` adds reminders when a certificate enters its warning and critical windows", Schema: openapi.Enum("", "true", "false")},
+		),
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": binaryResponse("iCalendar feed", "text/calendar"),
+		}, vaultErrorStatuses...),
+	})
+	feedLanguage := openapi.Parameter{Name: "lang", In: "query", Description: "Feed language; defaults to the cookie or Accept-Language", Schema: openapi.Enum("", "en", "fr", "es", "de", "it")}
+	AddAPIOperation(doc, http.MethodGet, "/feeds/expiring.atom", openapi.Operation{
+		Summary:     "Atom feed of certificates expiring soon",
+		Description: "One entry per certificate inside its mount's warning or critical window, soonest expiry first. An entry is dated when the certificate entered its current level.",
+		Tags:        []string{"certificates"},
+		Parameters:  []openapi.Parameter{mountsParameter(), feedLanguage},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": binaryResponse("Atom feed", "application/atom+xml"),
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/feeds/changes.atom", openapi.Operation{
+		Summary:     "Atom feed of issued and revoked certificates",
+		Description: "One entry per certificate issued and per certificate revoked in the last days, newest first. Revocations are dated with the revocation time Vault reports.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			mountsParameter(),
+			feedLanguage,
+			{Name: "days", In: "query", Description: "How many days back to look, 1 to 365 (default 7)", Schema: openapi.Integer("")},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": binaryResponse("Atom feed", "application/atom+xml"),
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/stats", openapi.Operation{
+		Summary:     "Aggregate certificate counts",
+		Description: "Counts by status, expiry bucket, issuer and key type, overall and per vault and mount, for the certificates /certs returns with the same filters. Paging and sorting parameters are ignored.",
+		Tags:        []string{"certificates"},
+		Parameters:  certListParameters(),
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Counts and per-vault errors", Content: doc.JSON(statsEnvelope{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/forecast", openapi.Operation{
+		Summary:     "Upcoming expirations per week or month",
+		Description: "Counts, per vault, mount and issuer, the certificates /certs returns with the same filters that expire in each week or month ahead. Revoked certificates and ones a later certificate with the same common name in the same mount has renewed (`superseded`) are left out. Paging and sorting parameters are ignored.",
+		Tags:        []string{"certificates"},
+		Parameters: append(certListParameters(),
+			openapi.Parameter{Name: "weeks", In: "query", Description: "Number of weekly windows, 1-104; defaults to 12", Schema: openapi.Integer("")},
+			openapi.Parameter{Name: "months", In: "query", Description: "Number of monthly windows, 1-24; cannot be combined with weeks", Schema: openapi.Integer("")},
+		),
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Windows with total and per-group counts, and per-vault errors", Content: doc.JSON(forecastEnvelope{})},
+		}, vaultErrorStatuses...),
+	})
+	bundleResponses := errorResponses(doc, map[string]openapi.Response{
+		"200": binaryResponse("ZIP of PEM files; unreadable certificates are listed in errors.txt", "application/zip"),
+	}, vaultErrorStatuses...)
+	AddAPIOperation(doc, http.MethodGet, "/certs/bundle", openapi.Operation{
+		Summary: "Download a ZIP bundle of certificates",
+		Tags:    []string{"certificates"},
+		Parameters: append(certListParameters(),
+			openapi.Parameter{Name: "ids", In: "query", Description: "Comma-separated certificate IDs; overrides the filters", Schema: openapi.String("")},
+			openapi.Parameter{Name: "chain", In: "query", Description: "Append the CA chain to each file", Schema: openapi.Enum("", "true", "false")},
+		),
+		Responses: bundleResponses,
+	})
+	AddAPIOperation(doc, http.MethodPost, "/certs/bundle", openapi.Operation{
+		Summary:     "Download a ZIP bundle of listed certificates",
+		Tags:        []string{"certificates"},
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(bundleRequest{})},
+		Responses:   bundleResponses,
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/compare", openapi.Operation{
+		Summary:     "Compare two certificates",
+		Description: "Diffs certificate `b` against certificate `a`, which may be in different vaults: subject, issuer, key, signature algorithm, SANs, key usages, validity and extensions. `changed` names the fields that differ.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			{Name: "a", In: "query", Required: true, Description: "ID of the reference certificate, usually the one being replaced", Schema: openapi.String("")},
+			{Name: "b", In: "query", Required: true, Description: "ID of the certificate compared with it", Schema: openapi.String("")},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Both inventory entries and their comparison", Content: doc.JSON(compareResponse{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/{id}/details", openapi.Operation{
+		Summary:    "Certificate details",
+		Tags:       []string{"certificates"},
+		Parameters: []openapi.Parameter{certificateIDParameter()},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Parsed certificate", Content: doc.JSON(certs.DetailedCertificate{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/{id}/pem", openapi.Operation{
+		Summary:    "Certificate PEM wrapped in JSON",
+		Tags:       []string{"certificates"},
+		Parameters: []openapi.Parameter{certificateIDParameter()},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "PEM certificate", Content: doc.JSON(certs.PEMResponse{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/{id}/ca", openapi.Operation{
+		Summary:    "Issuing CA of the certificate's mount",
+		Tags:       []string{"certificates"},
+		Parameters: []openapi.Parameter{certificateIDParameter()},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Parsed CA certificate", Content: doc.JSON(certs.DetailedCertificate{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/certs/{id}/download", openapi.Operation{
+		Summary: "Download a certificate",
+		Tags:    []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			certificateIDParameter(),
+			{Name: "format", In: "query", Schema: openapi.Enum("", "pem", "der", "fullchain", "p7b")},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": binaryResponse("Certificate file", "application/x-pem-file", "application/pkix-cert", "application/x-pkcs7-certificates"),
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/findings/key-reuse", openapi.Operation{
+		Summary:    "Certificates sharing a public key",
+		Tags:       []string{"certificates"},
+		Parameters: []openapi.Parameter{mountsParameter()},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Key reuse groups", Content: doc.JSON(keyReuseResponse{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/lookup", openapi.Operation{
+		Summary: "Certificates covering a host name or IP address",
+		Tags:    []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			{Name: "host", In: "query", Required: true, Description: "DNS name, IP address, `host:port` or URL", Schema: openapi.String("")},
+			mountsParameter(),
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Covering certificates, best first", Content: doc.JSON(lookupResponse{})},
+		}, vaultErrorStatuses...),
+	})
+	AddAPIOperation(doc, http.MethodPost, "/inspect", openapi.Operation{
+		Summary:     "Inspect and lint certificates or CSRs",
+		Description: "Parses the PEM, DER or PKCS #7 body like `/certs/{id}/details` and lints each certificate and certificate request. Bodies are limited to 256 KiB and private keys are rejected. Nothing is stored.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			{Name: "verify", In: "query", Description: "`true` lists in `issuedBy` the configured mounts one of whose issuers signed each certificate", Schema: openapi.Enum("", "true", "false")},
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: binaryResponse("", "application/x-pem-file", "application/pkix-cert", "application/pkcs10", "application/x-pkcs7-certificates", "text/plain").Content},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Parsed certificates and requests with their lint findings", Content: doc.JSON(inspectResponse{})},
+		}, append([]int{http.StatusRequestEntityTooLarge}, vaultErrorStatuses...)...),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/probes", openapi.Operation{
+		Summary:     "Live TLS probe results",
+		Description: "Latest handshake with each probed endpoint, when `probes` are enabled in settings: the presented chain, the inventory certificate it matches (`certificateId`) and a newer one that should be deployed instead (`currentId`). `status` is `ok`, `outdated`, `unknown`, `revoked`, `expired` or `error`.",
+		Tags:        []string{"certificates"},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "Probe results", Content: doc.JSON(probesResponse{})},
+		},
+	})
+	AddAPIOperation(doc, http.MethodGet, "/findings/unmanaged", openapi.Operation{
+		Summary:     "Certificates found on the network but not managed by Vault",
+		Description: "TLS endpoints from the last scan of the `discovery` ranges in settings whose leaf is neither in the inventory nor signed by an issuer of a configured mount. `scannedEndpoints` counts the address and port pairs tried and `tlsEndpoints` those that completed a handshake.",
+		Tags:        []string{"certificates"},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "Unmanaged certificates report", Content: doc.JSON(unmanagedResponse{})},
+		},
+	})
+	AddAPIOperation(doc, http.MethodGet, "/history", openapi.Operation{
+		Summary:     "Inventory trends",
+		Description: "Certificate counts by status over time, from the snapshots recorded when `history` is enabled in settings, summed over the selected mounts. Each point is the last snapshot of its hour or day; `failedVaults` lists the selected vaults that could not be listed then. Days past `hourly_retention_days` keep one snapshot.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			mountsParameter(),
+			{Name: "from", In: "query", Description: "RFC 3339 time or `YYYY-MM-DD` date; defaults to 30 days before `to`", Schema: openapi.String("")},
+			{Name: "to", In: "query", Description: "RFC 3339 time or `YYYY-MM-DD` date, that whole day included; defaults to now", Schema: openapi.String("")},
+			{Name: "resolution", In: "query", Description: "One point per `hour` or per `day` (default)", Schema: openapi.Enum("", history.ResolutionHour, history.ResolutionDay)},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Snapshots in the range", Content: doc.JSON(historyResponse{})},
+		}, http.StatusBadRequest),
+	})
+	AddAPIOperation(doc, http.MethodGet, "/events", openapi.Operation{
+		Summary:     "Stream inventory changes",
+		Description: "Server-Sent Events. Each event carries an `id`, its type as `event` and an inventory event as JSON `data`; `resync` asks the client to reload the inventory. Comment lines are heartbeats.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			mountsParameter(),
+			{Name: "Last-Event-ID", In: "header", Description: "Resume after this event id", Schema: openapi.String("")},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "Event stream", Content: map[string]openapi.MediaType{"text/event-stream": {Schema: doc.Schema(events.Event{})}}},
+		}, http.StatusBadRequest),
+	})
+
+	eventHistoryContent := doc.JSON(eventHistoryResponse{})
+	eventHistoryContent["application/x-ndjson"] = openapi.MediaType{Schema: doc.Schema(events.Event{})}
+	AddAPIOperation(doc, http.MethodGet, "/events/history", openapi.Operation{
+		Summary:     "Journal of inventory events",
+		Description: "Events recorded when the `journal` is enabled in settings: the types of `/events` plus `certificate_tidied` (a revoked or expired certificate no longer listed) and `certificate_removed`. JSON pages newest first by default; `format=ndjson` streams every match oldest first, ignoring paging.",
+		Tags:        []string{"certificates"},
+		Parameters: []openapi.Parameter{
+			mountsParameter(),
+			{Name: "type", In: "query", Description: "Comma-separated event types", Schema: openapi.String("")},
+			{Name: "certificate_id", In: "query", Description: "Events of this certificate only", Schema: openapi.String("")},
+			{Name: "from", In: "query", Description: "RFC 3339 time or `YYYY-MM-DD` date", Schema: openapi.String("")},
+			{Name: "to", In: "query", Description: "RFC 3339 time or `YYYY-MM-DD` date, that whole day included", Schema: openapi.String("")},
+			{Name: "order", In: "query", Description: "`desc` (default) or `asc` by time", Schema: openapi.Enum("", "asc", "desc")},
+			{Name: "page_size", In: "query", Description: "Page size, 1 to 1000 (default 100)", Schema: openapi.Integer("")},
+			{Name: "page", In: "query", Description: "1-based page number", Schema: openapi.Integer("")},
+			{Name: "format", In: "query", Schema: openapi.Enum("", "json", "ndjson")},
+		},
+		Responses: errorResponses(doc, map[string]openapi.Response{
+			"200": {Description: "A page of events, or every event as NDJSON", Content: eventHistoryContent},
+		}, http.StatusBadRequest, http.StatusInternalServerError),
+	})
+
+	doc.Add(http.MethodGet, "/api/health", openapi.Operation{
+		Summary:   "Liveness probe",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "Process is up"}},
+	})
+	doc.Add(http.MethodGet, "/api/ready", openapi.Operation{
+		Summary:   "Readiness probe",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "Ready to serve"}},
+	})
+	AddAPIOperation(doc, http.MethodGet, "/config", openapi.Operation{
+		Summary:   "Public UI configuration",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "Thresholds, vaults and certificate types", Content: doc.JSON(ConfigResponse{})}},
+	})
+	AddAPIOperation(doc, http.MethodGet, "/i18n", openapi.Operation{
+		Summary:    "UI translations",
+		Tags:       []string{"system"},
+		Parameters: []openapi.Parameter{{Name: "lang", In: "query", Schema: openapi.Enum("", "en", "fr", "es", "de", "it")}},
+		Responses:  map[string]openapi.Response{"200": {Description: "Resolved language and messages", Content: doc.JSON(i18n.Response{})}},
+	})
+	doc.Add(http.MethodGet, "/api/openapi.json", openapi.Operation{
+		Summary:   "This document",
+		Tags:      []string{"system"},
+		Responses: map[string]openapi.Response{"200": {Description: "OpenAPI 3.1 document", Content: map[string]openapi.MediaType{"application/json": {Schema: &openapi.Schema{Type: "object"}}}}},
+	})
+
+	addAdminOperations(doc)
+	return doc
+}
+
+func addAdminOperations(doc *openapi.Document) {
+	jsonErrorResponse := func(description string) openapi.Response {
+		return openapi.Response{Description: description, Content: doc.JSON(jsonError{})}
+	}
+	unauthorized := jsonErrorResponse("No admin session")
+	admin := func(summary string, requestBody any, responses map[string]openapi.Response, parameters ...openapi.Parameter) openapi.Operation {
+		responses["401"] = unauthorized
+		op := openapi.Operation{Summary: summary, Tags: []string{"admin"}, Security: adminSecurity, Parameters: parameters, Responses: responses}
+		if requestBody != nil {
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: doc.JSON(requestBody)}
+		}
+		return op
+	}
+	noContent := openapi.Response{Description: "Done"}
+
+	doc.Add(http.MethodGet, "/api/admin/session", openapi.Operation{
+		Summary:   "Whether the caller has an admin session",
+		Tags:      []string{"admin"},
+		Responses: map[string]openapi.Response{"200": {Description: "Session state", Content: doc.JSON(adminSessionResponse{})}},
+	})
+	doc.Add(http.MethodPost, "/api/admin/login", openapi.Operation{
+		Summary:     "Log in and receive the session cookie",
+		Tags:        []string{"admin"},
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(adminLoginRequest{})},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "Logged in", Content: doc.JSON(adminSessionResponse{})},
+			"400": jsonErrorResponse("Invalid body"),
+			"401": jsonErrorResponse("Invalid credentials or rate limited"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/admin/logout", openapi.Operation{
+		Summary:   "End the admin session",
+		Tags:      []string{"admin"},
+		Responses: map[string]openapi.Response{"204": noContent},
+	})
+	doc.Add(http.MethodGet, "/api/admin/docs", admin("Admin guide rendered as HTML", nil, map[string]openapi.Response{
+		"200": {Description: "Rendered guide", Content: doc.JSON(adminDocsResponse{})},
+	}))
+	doc.Add(http.MethodGet, "/api/admin/settings", admin("Current settings with secrets masked", nil, errorResponses(doc, map[string]openapi.Response{
+		"200": {Description: "Settings and vault connectivity", Content: doc.JSON(adminSettingsResponse{})},
+	}, vaultErrorStatuses...)))
+	doc.Add(http.MethodPut, "/api/admin/settings", admin("Validate, save and apply settings", config.SettingsFile{}, errorResponses(doc, map[string]openapi.Response{
+		"200": {Description: "Saved settings", Content: doc.JSON(adminSettingsResponse{})},
+	}, vaultErrorStatuses...)))
+	doc.Add(http.MethodPost, "/api/admin/vault", admin("Template for a new vault entry", nil, errorResponses(doc, map[string]openapi.Response{
+		"200": {Description: "New vault key and defaults", Content: doc.JSON(adminVaultAddedResponse{})},
+	}, vaultErrorStatuses...)))
+	doc.Add(http.MethodDelete, "/api/admin/vault/{id}", admin("Remove a vault", nil, map[string]openapi.Response{
+		"204": noContent,
+		"400": jsonErrorResponse("Missing vault ID"),
+		"404": jsonErrorResponse("Unknown vault"),
+		"500": jsonErrorResponse("Settings could not be saved"),
+	}, openapi.Parameter{Name: "id", In: "path", Required: true, Schema: openapi.String("")}))
+	doc.Add(http.MethodGet, "/api/admin/acknowledgements", admin("List acknowledgements", nil, map[string]openapi.Response{
+		"200": {Description: "Every acknowledgement, lapsed ones included", Content: doc.JSON(adminAcknowledgementsResponse{})},
+	}))
+	doc.Add(http.MethodPost, "/api/admin/acknowledgements", admin("Acknowledge certificates", adminAcknowledgementRequest{}, map[string]openapi.Response{
+		"201": {Description: "Created acknowledgement", Content: doc.JSON(ack.Entry{})},
+		"400": jsonErrorResponse("Invalid acknowledgement"),
+		"500": jsonErrorResponse("Acknowledgements could not be saved"),
+	}))
+	doc.Add(http.MethodDelete, "/api/admin/acknowledgements/{id}", admin("Remove an acknowledgement", nil, map[string]openapi.Response{
+		"204": noContent,
+		"404": jsonErrorResponse("Unknown acknowledgement"),
+		"500": jsonErrorResponse("Acknowledgements could not be saved"),
+	}, openapi.Parameter{Name: "id", In: "path", Required: true, Schema: openapi.String("")}))
+	doc.Add(http.MethodPost, "/api/cache/invalidate", admin("Drop cached Vault listings", nil, map[string]openapi.Response{
+		"204": noContent,
+		"503": plainTextResponse("No cache configured"),
+	}))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/probe"
+)
+
+// probesResponse is the response shape for GET /api/probes. LastRun is
+// unset until the first probe run completes.
+type probesResponse struct {
+	Enabled bool           `json:"enabled"`
+	LastRun *time.Time     `json:"lastRun,omitempty"`
+	Results []probe.Result `json:"results"`
+}
+
+// ProbeResults is the source of GET /api/probes. Satisfied by
+// *probe.Prober.
+type ProbeResults interface {
+	Results() []probe.Result
+	LastRun() time.Time
+}
+
+// RegisterProbeRoutes exposes the latest TLS probe results. source is nil
+// when probing is disabled; the route then reports enabled=false.
+func RegisterProbeRoutes(r chi.Router, source ProbeResults) {
+	HandleAPI(r, http.MethodGet, "/probes", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		response := probesResponse{Enabled: source != nil, Results: []probe.Result{}}
+		if source != nil {
+			if results := source.Results(); results != nil {
+				response.Results = results
+			}
+			if lastRun := source.LastRun(); !lastRun.IsZero() {
+				response.LastRun = &lastRun
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, err).
+				Str("request_id", requestID).
+				Msg("failed to encode probes response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("results", len(response.Results)).
+			Msg("probe results served")
+	})
+}
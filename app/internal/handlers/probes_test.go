@@ -0,0 +1,103 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/probe"
+	"vcv/internal/vault"
+)
+
+// fakeProbeResults serves fixed results like a prober after a run.
+type fakeProbeResults struct {
+	results []probe.Result
+	lastRun time.Time
+}
+
+func (f fakeProbeResults) Results() []probe.Result { return f.results }
+func (f fakeProbeResults) LastRun() time.Time      { return f.lastRun }
+
+func getProbes(t *testing.T, source handlers.ProbeResults) map[string]any {
+	t.Helper()
+	r := chi.NewRouter()
+	handlers.RegisterProbeRoutes(r, source)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/probes", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestProbes_Disabled(t *testing.T) {
+	body := getProbes(t, nil)
+
+	assert.Equal(t, false, body["enabled"])
+	assert.Equal(t, []any{}, body["results"])
+	assert.NotContains(t, body, "lastRun")
+}
+
+func TestProbes_BeforeFirstRun(t *testing.T) {
+	prober, err := probe.New(config.ProbeSettings{Enabled: true, Targets: []config.ProbeTarget{{Address: "api.example.com"}}}, new(vault.MockClient))
+	require.NoError(t, err)
+
+	body := getProbes(t, prober)
+
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, []any{}, body["results"])
+	assert.NotContains(t, body, "lastRun")
+}
+
+func TestProbes_Results(t *testing.T) {
+	lastRun := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	source := fakeProbeResults{
+		lastRun: lastRun,
+		results: []probe.Result{
+			{
+				Target:        probe.Target{Address: "api.example.com:443", ServerName: "api.example.com", Source: probe.SourceSAN},
+				Status:        probe.StatusOutdated,
+				CheckedAt:     lastRun,
+				Chain:         []probe.PresentedCertificate{{Subject: "CN=api.example.com", SerialNumber: "02", NotAfter: lastRun.AddDate(0, 0, 10)}},
+				CertificateID: "v1|pki:02",
+				CurrentID:     "v1|pki:03",
+			},
+			{
+				Target:    probe.Target{Address: "down.example.com:443", Source: probe.SourceConfig},
+				Status:    probe.StatusError,
+				Error:     "connection refused",
+				CheckedAt: lastRun,
+				Chain:     []probe.PresentedCertificate{},
+			},
+		},
+	}
+
+	body := getProbes(t, source)
+
+	assert.Equal(t, true, body["enabled"])
+	assert.Equal(t, "2026-06-01T12:00:00Z", body["lastRun"])
+	results, ok := body["results"].([]any)
+	require.True(t, ok)
+	require.Len(t, results, 2)
+	outdated := results[0].(map[string]any)
+	assert.Equal(t, "api.example.com:443", outdated["address"])
+	assert.Equal(t, "api.example.com", outdated["serverName"])
+	assert.Equal(t, "san", outdated["source"])
+	assert.Equal(t, "outdated", outdated["status"])
+	assert.Equal(t, "v1|pki:02", outdated["certificateId"])
+	assert.Equal(t, "v1|pki:03", outdated["currentId"])
+	assert.Len(t, outdated["chain"], 1)
+	failed := results[1].(map[string]any)
+	assert.Equal(t, "error", failed["status"])
+	assert.Equal(t, "connection refused", failed["error"])
+	assert.Equal(t, []any{}, failed["chain"])
+	assert.NotContains(t, failed, "certificateId")
+}
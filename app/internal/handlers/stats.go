@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+	"vcv/internal/middleware"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+// statsEnvelope is the response shape for GET /api/stats. Errors carries
+// per-vault failures like certsEnvelope, since the counts then leave out
+// the vaults that could not be listed.
+type statsEnvelope struct {
+	stats.Stats
+	Errors []vault.VaultError `json:"errors"`
+}
+
+// registerStatsRoute mounts /api/stats, which aggregates the certificates
+// /api/certs would return for the same filters. Paging and sorting
+// parameters are accepted and ignored.
+func registerStatsRoute(r chi.Router, vaultClient vault.Client, thresholds *certs.ExpiryThresholds) {
+	HandleAPI(r, http.MethodGet, "/stats", func(w http.ResponseWriter, req *http.Request) {
+		requestID := middleware.GetRequestID(req.Context())
+		query := req.URL.Query()
+		listQuery, queryErr := parseCertListQuery(query)
+		if queryErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusBadRequest, queryErr).
+				Str("request_id", requestID).
+				Msg("invalid certificate stats query")
+			writeAPIError(w, req, invalidRequest(queryErr.Error()))
+			return
+		}
+		listQuery.page, listQuery.pageSize = 0, 0
+
+		certificates, vaultErrors, err := listCertificatesWithErrors(req.Context(), vaultClient)
+		if err != nil {
+			apiErr := classifyError(err)
+			logger.HTTPError(req.Method, req.URL.Path, apiErr.status, err).
+				Str("request_id", requestID).
+				Msg("failed to list certificates for stats")
+			writeAPIError(w, req, apiErr)
+			return
+		}
+		now := time.Now()
+		result := listQuery.apply(filterCertificatesByMounts(certificates, parseMountsQueryParam(query)), thresholds, now)
+		envelope := statsEnvelope{
+			Stats:  stats.Compute(result.Certificates, thresholds, now),
+			Errors: vaultErrors,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if encodeErr := json.NewEncoder(w).Encode(envelope); encodeErr != nil {
+			logger.HTTPError(req.Method, req.URL.Path, http.StatusInternalServerError, encodeErr).
+				Str("request_id", requestID).
+				Msg("failed to encode stats response")
+			return
+		}
+		logger.HTTPEvent(req.Method, req.URL.Path, http.StatusOK, 0).
+			Str("request_id", requestID).
+			Int("total", envelope.Total).
+			Int("vault_errors", len(vaultErrors)).
+			Msg("certificate stats computed")
+	})
+}
@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/handlers"
+	"vcv/internal/middleware"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+type statsEnvelopeResponse struct {
+	stats.Stats
+	Errors []vault.VaultError `json:"errors"`
+}
+
+func statsFixture(t *testing.T) *chi.Mux {
+	t.Helper()
+	now := time.Now()
+	day := 24 * time.Hour
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "v1|pki:1", CommonName: "api.example.com", ExpiresAt: now.Add(3 * day), IssuerCN: "Root CA", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "v1|pki:2", CommonName: "web.example.com", ExpiresAt: now.Add(20 * day), IssuerCN: "Root CA", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "v1|pki_int:3", CommonName: "db.internal", ExpiresAt: now.Add(200 * day), KeyAlgorithm: "ECDSA", KeySize: 256},
+		{ID: "v2|pki:4", CommonName: "old.example.com", ExpiresAt: now.Add(-day)},
+		{ID: "v2|pki:5", CommonName: "gone.example.com", ExpiresAt: now.Add(200 * day), Revoked: true},
+	}, nil)
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handlers.RegisterCertRoutes(r, mockVault, thresholds)
+	return r
+}
+
+func getStats(t *testing.T, router http.Handler, target string) statsEnvelopeResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body statsEnvelopeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestStats_AggregatesInventory(t *testing.T) {
+	body := getStats(t, statsFixture(t), "/api/v1/stats")
+
+	assert.Equal(t, 5, body.Total)
+	assert.Equal(t, map[string]int{"valid": 1, "warning": 1, "critical": 1, "expired": 1, "revoked": 1}, body.Statuses)
+	assert.Equal(t, stats.Expiring{Warning: 2, Critical: 1}, body.Expiring)
+	assert.Equal(t, 1, body.Buckets[stats.Bucket0To7d])
+	assert.Equal(t, 1, body.Buckets[stats.Bucket7To30d])
+	assert.Equal(t, 2, body.Issuers["Root CA"])
+	assert.Equal(t, 2, body.KeyTypes["RSA-2048"])
+	assert.Equal(t, 3, body.Vaults["v1"].Total)
+	assert.Equal(t, 2, body.Mounts["v1|pki"].Total)
+	assert.NotNil(t, body.Errors)
+}
+
+func TestStats_UsesCertificateFilters(t *testing.T) {
+	router := statsFixture(t)
+
+	t.Run("mounts", func(t *testing.T) {
+		body := getStats(t, router, "/api/v1/stats?mounts=v1|pki")
+		assert.Equal(t, 2, body.Total)
+		assert.Len(t, body.Mounts, 1)
+	})
+	t.Run("search and paging", func(t *testing.T) {
+		body := getStats(t, router, "/api/v1/stats?q=example.com&page_size=1&page=1&sort=commonName")
+		assert.Equal(t, 4, body.Total)
+	})
+	t.Run("status counts match /certs", func(t *testing.T) {
+		body := getStats(t, router, "/api/v1/stats?issuer=root")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/certs?issuer=root", nil))
+		var listed certsEnvelopeResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+		assert.Equal(t, listed.Total, body.Total)
+		assert.Equal(t, listed.StatusCounts, body.Statuses)
+	})
+}
+
+func TestStats_InvalidQuery(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	rec := httptest.NewRecorder()
+	setupRouter(mockVault).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats?status=unknown", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockVault.AssertNotCalled(t, "ListCertificates", mock.Anything)
+}
+
+func TestStats_VaultError(t *testing.T) {
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, errors.New("boom"))
+	rec := httptest.NewRecorder()
+	setupRouter(mockVault).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,299 @@
+// Package history keeps aggregated snapshots of the inventory on disk so
+// trends survive restarts: certificate counts by status for every vault and
+// mount, and the vaults that failed to list. Snapshots are appended to a
+// daylog store in the data directory. Days older than the hourly retention
+// are thinned to their last snapshot and days older than the retention are
+// deleted.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/daylog"
+	"vcv/internal/logger"
+	"vcv/internal/stats"
+	"vcv/internal/vault"
+)
+
+// DirName is the default data directory, next to settings.json.
+const DirName = "history"
+
+const (
+	defaultInterval            = time.Hour
+	minInterval                = time.Minute
+	defaultHourlyRetentionDays = 30
+	defaultRetentionDays       = 365
+	// maxSnapshotLine bounds one stored snapshot, which grows with the
+	// number of mounts.
+	maxSnapshotLine = 16 << 20
+)
+
+// Resolutions of Snapshots.
+const (
+	ResolutionHour = "hour"
+	ResolutionDay  = "day"
+)
+
+// MountCounts are the counts of one mount in a snapshot. Vault is empty for
+// certificates without a vault.
+type MountCounts struct {
+	Vault    string         `json:"vault,omitempty"`
+	Mount    string         `json:"mount"`
+	Total    int            `json:"total"`
+	Statuses map[string]int `json:"statuses"`
+	Expiring stats.Expiring `json:"expiring"`
+}
+
+// Snapshot is the state of the inventory at At. A vault in FailedVaults has
+// no mounts in the snapshot.
+type Snapshot struct {
+	At           time.Time     `json:"at"`
+	Mounts       []MountCounts `json:"mounts"`
+	FailedVaults []string      `json:"failedVaults,omitempty"`
+}
+
+// Recorder takes snapshots and answers trend queries from memory; the data
+// directory is only read at startup. A nil *Recorder records nothing.
+type Recorder struct {
+	client          vault.Client
+	thresholds      *certs.ExpiryThresholds
+	store           *daylog.Store
+	interval        time.Duration
+	hourlyRetention int
+	retention       int
+	now             func() time.Time
+
+	mu sync.RWMutex
+	// snapshots are ordered by At.
+	snapshots []Snapshot
+}
+
+// DirForSettings resolves the data directory of settings for a settings
+// file: a relative dir, or the default DirName, is taken from the settings
+// file's directory.
+func DirForSettings(settings config.HistorySettings, settingsPath string) string {
+	return daylog.Dir(settings.Dir, DirName, settingsPath)
+}
+
+// New validates settings, creates dir when missing and loads the snapshots
+// it holds. It returns nil when history is disabled.
+func New(settings config.HistorySettings, dir string, client vault.Client, thresholds *certs.ExpiryThresholds) (*Recorder, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	recorder := &Recorder{
+		client:          client,
+		thresholds:      thresholds,
+		interval:        defaultInterval,
+		hourlyRetention: defaultHourlyRetentionDays,
+		retention:       defaultRetentionDays,
+		now:             time.Now,
+	}
+	if value := strings.TrimSpace(settings.Interval); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < minInterval {
+			return nil, fmt.Errorf("history: invalid interval %q (at least %s)", settings.Interval, minInterval)
+		}
+		recorder.interval = interval
+	}
+	if settings.HourlyRetentionDays < 0 || settings.RetentionDays < 0 {
+		return nil, fmt.Errorf("history: retention days must not be negative")
+	}
+	if settings.HourlyRetentionDays > 0 {
+		recorder.hourlyRetention = settings.HourlyRetentionDays
+	}
+	if settings.RetentionDays > 0 {
+		recorder.retention = settings.RetentionDays
+	}
+	if recorder.hourlyRetention > recorder.retention {
+		return nil, fmt.Errorf("history: hourly_retention_days (%d) exceeds retention_days (%d)", recorder.hourlyRetention, recorder.retention)
+	}
+	store, err := daylog.Open(dir, maxSnapshotLine)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	recorder.store = store
+	if err := recorder.load(); err != nil {
+		return nil, err
+	}
+	recorder.prune()
+	return recorder, nil
+}
+
+// Interval is how often Record should be called. Zero for a nil Recorder.
+func (r *Recorder) Interval() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.interval
+}
+
+// Record lists the inventory and stores a snapshot of it. A failed listing
+// is logged and records nothing; a vault that failed is recorded as such.
+func (r *Recorder) Record(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	certificates, vaultErrors, err := r.list(ctx)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("history: failed to list certificates")
+		return
+	}
+	now := r.now().UTC()
+	snapshot := Snapshot{At: now, Mounts: []MountCounts{}}
+	computed := stats.Compute(certificates, r.thresholds, now)
+	for key, counts := range computed.Mounts {
+		vaultID, mount := stats.SplitMountKey(key)
+		snapshot.Mounts = append(snapshot.Mounts, MountCounts{
+			Vault:    vaultID,
+			Mount:    mount,
+			Total:    counts.Total,
+			Statuses: counts.Statuses,
+			Expiring: counts.Expiring,
+		})
+	}
+	sort.Slice(snapshot.Mounts, func(i, j int) bool {
+		return stats.MountKey(snapshot.Mounts[i].Vault, snapshot.Mounts[i].Mount) < stats.MountKey(snapshot.Mounts[j].Vault, snapshot.Mounts[j].Mount)
+	})
+	failed := make(map[string]struct{}, len(vaultErrors))
+	for _, vaultErr := range vaultErrors {
+		if _, ok := failed[vaultErr.VaultID]; !ok {
+			failed[vaultErr.VaultID] = struct{}{}
+			snapshot.FailedVaults = append(snapshot.FailedVaults, vaultErr.VaultID)
+		}
+	}
+	sort.Strings(snapshot.FailedVaults)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if appendErr := r.append(snapshot); appendErr != nil {
+		// Kept in memory anyway: the trend stays complete until a restart.
+		logger.Get().Warn().Err(appendErr).Msg("history: failed to store snapshot")
+	}
+	r.snapshots = append(r.snapshots, snapshot)
+	r.prune()
+}
+
+func (r *Recorder) list(ctx context.Context) ([]certs.Certificate, []vault.VaultError, error) {
+	if envelope, ok := r.client.(vault.CertificatesEnvelopeLister); ok {
+		certificates, vaultErrors := envelope.ListCertificatesEnvelope(ctx)
+		return certificates, vaultErrors, nil
+	}
+	certificates, err := r.client.ListCertificates(ctx)
+	return certificates, nil, err
+}
+
+// Snapshots returns, oldest first, the last snapshot of every hour or day
+// (resolution, in UTC) that has one between from and to inclusive.
+func (r *Recorder) Snapshots(from, to time.Time, resolution string) []Snapshot {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []Snapshot
+	var lastBucket time.Time
+	for _, snapshot := range r.snapshots {
+		if snapshot.At.Before(from) || snapshot.At.After(to) {
+			continue
+		}
+		bucket := truncate(snapshot.At, resolution)
+		if len(result) > 0 && bucket.Equal(lastBucket) {
+			result[len(result)-1] = snapshot
+			continue
+		}
+		result = append(result, snapshot)
+		lastBucket = bucket
+	}
+	return result
+}
+
+func truncate(at time.Time, resolution string) time.Time {
+	if resolution == ResolutionHour {
+		return at.UTC().Truncate(time.Hour)
+	}
+	return daylog.Day(at)
+}
+
+func (r *Recorder) append(snapshot Snapshot) error {
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return r.store.Append(snapshot.At, append(line, '\n'))
+}
+
+// load reads every day of the data directory. A line that does not parse,
+// such as one cut short by a crash, is logged and skipped.
+func (r *Recorder) load() error {
+	days, err := r.store.Days()
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	for _, day := range days {
+		readErr := r.store.Lines(day, func(line int, data []byte) error {
+			var snapshot Snapshot
+			if jsonErr := json.Unmarshal(data, &snapshot); jsonErr != nil {
+				logger.Get().Warn().Err(jsonErr).Str("file", r.store.Path(day)).Int("line", line).Msg("history: skipping unreadable snapshot")
+				return nil
+			}
+			r.snapshots = append(r.snapshots, snapshot)
+			return nil
+		})
+		if readErr != nil {
+			return fmt.Errorf("history: %w", readErr)
+		}
+	}
+	sort.SliceStable(r.snapshots, func(i, j int) bool {
+		return r.snapshots[i].At.Before(r.snapshots[j].At)
+	})
+	return nil
+}
+
+// prune applies the retentions to memory and to the data directory. Disk
+// failures are logged; the next prune retries them. Callers hold r.mu or
+// have not published r yet.
+func (r *Recorder) prune() {
+	today := daylog.Day(r.now())
+	expired := today.AddDate(0, 0, -r.retention)
+	hourly := today.AddDate(0, 0, -r.hourlyRetention)
+
+	kept := make([]Snapshot, 0, len(r.snapshots))
+	thinned := make(map[time.Time]bool)
+	for i, snapshot := range r.snapshots {
+		snapshotDay := daylog.Day(snapshot.At)
+		if snapshotDay.Before(expired) {
+			continue
+		}
+		if snapshotDay.Before(hourly) && i+1 < len(r.snapshots) && daylog.Day(r.snapshots[i+1].At).Equal(snapshotDay) {
+			thinned[snapshotDay] = true
+			continue
+		}
+		kept = append(kept, snapshot)
+	}
+	r.snapshots = kept
+
+	if err := r.store.Prune(expired); err != nil {
+		logger.Get().Warn().Err(err).Msg("history: failed to remove expired snapshots")
+	}
+	for _, snapshot := range kept {
+		if !thinned[daylog.Day(snapshot.At)] {
+			continue
+		}
+		line, err := json.Marshal(snapshot)
+		if err == nil {
+			err = r.store.Replace(snapshot.At, append(line, '\n'))
+		}
+		if err != nil {
+			logger.Get().Warn().Err(err).Time("day", daylog.Day(snapshot.At)).Msg("history: failed to thin snapshots")
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/vault"
+)
+
+// fakeEnvelopeClient lists certificates through the per-vault envelope.
+type fakeEnvelopeClient struct {
+	vault.MockClient
+	certificates []certs.Certificate
+	vaultErrors  []vault.VaultError
+}
+
+func (c *fakeEnvelopeClient) ListCertificatesEnvelope(context.Context) ([]certs.Certificate, []vault.VaultError) {
+	return c.certificates, c.vaultErrors
+}
+
+func newRecorder(t *testing.T, dir string, client vault.Client, settings config.HistorySettings) *Recorder {
+	t.Helper()
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	settings.Enabled = true
+	recorder, err := New(settings, dir, client, thresholds)
+	require.NoError(t, err)
+	return recorder
+}
+
+func TestNew(t *testing.T) {
+	recorder, err := New(config.HistorySettings{}, t.TempDir(), nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, recorder)
+	assert.Nil(t, recorder.Snapshots(time.Time{}, time.Now(), ResolutionDay))
+	assert.Zero(t, recorder.Interval())
+
+	dir := filepath.Join(t.TempDir(), "nested", "history")
+	recorder = newRecorder(t, dir, nil, config.HistorySettings{Interval: "15m"})
+	assert.Equal(t, 15*time.Minute, recorder.Interval())
+	assert.DirExists(t, dir)
+
+	invalid := []config.HistorySettings{
+		{Enabled: true, Interval: "hourly"},
+		{Enabled: true, Interval: "10s"},
+		{Enabled: true, RetentionDays: -1},
+		{Enabled: true, HourlyRetentionDays: 60, RetentionDays: 30},
+	}
+	for _, settings := range invalid {
+		_, err := New(settings, t.TempDir(), nil, nil)
+		assert.Error(t, err, "%+v", settings)
+	}
+}
+
+func TestDirForSettings(t *testing.T) {
+	assert.Equal(t, filepath.Join("/etc/vcv", DirName), DirForSettings(config.HistorySettings{}, "/etc/vcv/settings.json"))
+	assert.Equal(t, filepath.Join("/etc/vcv", "data"), DirForSettings(config.HistorySettings{Dir: "data"}, "/etc/vcv/settings.json"))
+	assert.Equal(t, "/var/lib/vcv", DirForSettings(config.HistorySettings{Dir: "/var/lib/vcv"}, "/etc/vcv/settings.json"))
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+	client := &fakeEnvelopeClient{
+		certificates: []certs.Certificate{
+			{ID: "v1|pki:01", ExpiresAt: now.AddDate(1, 0, 0)},
+			{ID: "v1|pki:02", ExpiresAt: now.AddDate(0, 0, 3)},
+			{ID: "v1|pki_int:03", ExpiresAt: now.AddDate(0, 0, 20)},
+			{ID: "v1|pki_int:04", ExpiresAt: now.AddDate(1, 0, 0), Revoked: true},
+		},
+		vaultErrors: []vault.VaultError{{VaultID: "v2", Message: "sealed"}, {VaultID: "v2", Message: "sealed"}},
+	}
+	dir := t.TempDir()
+	recorder := newRecorder(t, dir, client, config.HistorySettings{})
+	recorder.now = func() time.Time { return now }
+
+	recorder.Record(context.Background())
+
+	snapshots := recorder.Snapshots(now.Add(-time.Hour), now, ResolutionHour)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, now, snapshots[0].At)
+	assert.Equal(t, []string{"v2"}, snapshots[0].FailedVaults)
+	require.Len(t, snapshots[0].Mounts, 2)
+	pki, pkiInt := snapshots[0].Mounts[0], snapshots[0].Mounts[1]
+	assert.Equal(t, "v1", pki.Vault)
+	assert.Equal(t, "pki", pki.Mount)
+	assert.Equal(t, 2, pki.Total)
+	assert.Equal(t, 1, pki.Statuses[certs.StatusValid])
+	assert.Equal(t, 1, pki.Statuses[certs.StatusCritical])
+	assert.Equal(t, 1, pki.Expiring.Critical)
+	assert.Equal(t, "pki_int", pkiInt.Mount)
+	assert.Equal(t, 1, pkiInt.Statuses[certs.StatusWarning])
+	assert.Equal(t, 1, pkiInt.Statuses[certs.StatusRevoked])
+
+	reopened := newRecorder(t, dir, client, config.HistorySettings{})
+	assert.Equal(t, snapshots, reopened.Snapshots(now.Add(-time.Hour), now, ResolutionHour), "snapshots survive a restart")
+}
+
+func TestSnapshots_Resolution(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	recorder := newRecorder(t, t.TempDir(), &fakeEnvelopeClient{}, config.HistorySettings{})
+	for _, at := range []time.Time{
+		today.AddDate(0, 0, -2).Add(1 * time.Hour),
+		today.AddDate(0, 0, -2).Add(1*time.Hour + 30*time.Minute),
+		today.AddDate(0, 0, -2).Add(5 * time.Hour),
+		today.AddDate(0, 0, -1).Add(3 * time.Hour),
+	} {
+		recorder.now = func() time.Time { return at }
+		recorder.Record(context.Background())
+	}
+
+	atOf := func(snapshots []Snapshot) []time.Time {
+		result := make([]time.Time, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			result = append(result, snapshot.At)
+		}
+		return result
+	}
+	from, to := today.AddDate(0, 0, -3), today
+	assert.Equal(t, []time.Time{
+		today.AddDate(0, 0, -2).Add(5 * time.Hour),
+		today.AddDate(0, 0, -1).Add(3 * time.Hour),
+	}, atOf(recorder.Snapshots(from, to, ResolutionDay)))
+	assert.Equal(t, []time.Time{
+		today.AddDate(0, 0, -2).Add(1*time.Hour + 30*time.Minute),
+		today.AddDate(0, 0, -2).Add(5 * time.Hour),
+		today.AddDate(0, 0, -1).Add(3 * time.Hour),
+	}, atOf(recorder.Snapshots(from, to, ResolutionHour)))
+	assert.Empty(t, recorder.Snapshots(today.AddDate(0, 0, -1).Add(4*time.Hour), to, ResolutionDay))
+}
+
+func TestRetention(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	dir := t.TempDir()
+	settings := config.HistorySettings{HourlyRetentionDays: 2, RetentionDays: 5}
+	recorder := newRecorder(t, dir, &fakeEnvelopeClient{}, settings)
+	for days := 7; days >= 0; days-- {
+		for _, hour := range []int{6, 18} {
+			at := today.AddDate(0, 0, -days).Add(time.Duration(hour) * time.Hour)
+			recorder.now = func() time.Time { return at }
+			recorder.Record(context.Background())
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("kept"), 0o600))
+
+	reopened := newRecorder(t, dir, &fakeEnvelopeClient{}, settings)
+	var got []time.Time
+	for _, snapshot := range reopened.Snapshots(time.Time{}, today.AddDate(0, 0, 1), ResolutionHour) {
+		got = append(got, snapshot.At)
+	}
+	assert.Equal(t, []time.Time{
+		today.AddDate(0, 0, -5).Add(18 * time.Hour),
+		today.AddDate(0, 0, -4).Add(18 * time.Hour),
+		today.AddDate(0, 0, -3).Add(18 * time.Hour),
+		today.AddDate(0, 0, -2).Add(6 * time.Hour),
+		today.AddDate(0, 0, -2).Add(18 * time.Hour),
+		today.AddDate(0, 0, -1).Add(6 * time.Hour),
+		today.AddDate(0, 0, -1).Add(18 * time.Hour),
+		today.Add(6 * time.Hour),
+		today.Add(18 * time.Hour),
+	}, got)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Len(t, names, 7, "six day files and notes.txt: %v", names)
+	data, err := os.ReadFile(filepath.Join(dir, today.AddDate(0, 0, -3).Format("2006-01-02")+".ndjson"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"), "thinned day files hold one snapshot")
+}
+
+func TestLoad_SkipsTruncatedLine(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	dir := t.TempDir()
+	recorder := newRecorder(t, dir, &fakeEnvelopeClient{}, config.HistorySettings{})
+	recorder.now = func() time.Time { return today.Add(time.Hour) }
+	recorder.Record(context.Background())
+	file, err := os.OpenFile(filepath.Join(dir, today.Format("2006-01-02")+".ndjson"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"at":"` + today.Add(2*time.Hour).Format(time.RFC3339))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened := newRecorder(t, dir, &fakeEnvelopeClient{}, config.HistorySettings{})
+	assert.Len(t, reopened.Snapshots(today, today.AddDate(0, 0, 1), ResolutionHour), 1)
+
+	reopened.now = func() time.Time { return today.Add(3 * time.Hour) }
+	reopened.Record(context.Background())
+	again := newRecorder(t, dir, &fakeEnvelopeClient{}, config.HistorySettings{})
+	assert.Len(t, again.Snapshots(today, today.AddDate(0, 0, 1), ResolutionHour), 2, "the next snapshot starts on a new line")
+}
@@ -114,6 +114,19 @@ type Messages struct {
 	TimelineWithinDays          string `json:"timelineWithinDays"`
 	TimelineRangeDays           string `json:"timelineRangeDays"`
 	TimelineBeyondDays          string `json:"timelineBeyondDays"`
+	ButtonCompare               string `json:"buttonCompare"`
+	CompareTitle                string `json:"compareTitle"`
+	CompareNoCandidates         string `json:"compareNoCandidates"`
+	CompareIdentical            string `json:"compareIdentical"`
+	CompareSameKey              string `json:"compareSameKey"`
+	CompareNewKey               string `json:"compareNewKey"`
+	LabelSignatureAlgorithm     string `json:"labelSignatureAlgorithm"`
+	LabelKeySize                string `json:"labelKeySize"`
+	LabelNotBefore              string `json:"labelNotBefore"`
+	LabelLifetimeDays           string `json:"labelLifetimeDays"`
+	LabelKeyUsage               string `json:"labelKeyUsage"`
+	LabelExtKeyUsage            string `json:"labelExtKeyUsage"`
+	LabelExtensions             string `json:"labelExtensions"`
 	SearchPlaceholder           string `json:"searchPlaceholder"`
 	SearchShortcutHint          string `json:"searchShortcutHint"`
 	SelectAll                   string `json:"selectAll"`
@@ -229,6 +242,12 @@ type Messages struct {
 	AdminWebhookURL            string `json:"adminWebhookURL"`
 	AdminWebhookURLHint        string `json:"adminWebhookURLHint"`
 	AdminWebhookURLPlaceholder string `json:"adminWebhookURLPlaceholder"`
+	FeedExpiringTitle          string `json:"feedExpiringTitle"`
+	FeedChangesTitle           string `json:"feedChangesTitle"`
+	FeedEntryExpiring          string `json:"feedEntryExpiring"`
+	FeedEntryIssued            string `json:"feedEntryIssued"`
+	FeedEntryRevoked           string `json:"feedEntryRevoked"`
+	FeedEntrySummary           string `json:"feedEntrySummary"`
 }
 
 // Response is the payload returned by the /api/i18n endpoint.
@@ -333,6 +352,19 @@ var englishMessages = Messages{
 	TimelineWithinDays:             "≤ {days} days",
 	TimelineRangeDays:              "{from}–{to} days",
 	TimelineBeyondDays:             "> {days} days",
+	ButtonCompare:                  "Compare",
+	CompareTitle:                   "Compare with another certificate",
+	CompareNoCandidates:            "No other certificate with this common name",
+	CompareIdentical:               "No differences",
+	CompareSameKey:                 "Same key pair",
+	CompareNewKey:                  "New key pair",
+	LabelSignatureAlgorithm:        "Signature algorithm",
+	LabelKeySize:                   "Key size",
+	LabelNotBefore:                 "Valid from",
+	LabelLifetimeDays:              "Lifetime (days)",
+	LabelKeyUsage:                  "Key usage",
+	LabelExtKeyUsage:               "Extended key usage",
+	LabelExtensions:                "Extensions",
 	SearchPlaceholder:              "Search by Serial Number, Common Name (CN) or SAN",
 	SelectAll:                      "Select all",
 	FilterChipSearch:               "Search",
@@ -443,6 +475,12 @@ var englishMessages = Messages{
 	AdminWebhookURL:            "Webhook URL",
 	AdminWebhookURLHint:        "POSTed a JSON alert when a certificate crosses the warning or critical threshold. Leave blank to disable.",
 	AdminWebhookURLPlaceholder: "Enter a new webhook URL to replace the stored one",
+	FeedExpiringTitle:          "Certificates expiring soon",
+	FeedChangesTitle:           "Certificate changes",
+	FeedEntryExpiring:          "{{name}} expires on {{date}}",
+	FeedEntryIssued:            "Certificate issued: {{name}}",
+	FeedEntryRevoked:           "Certificate revoked: {{name}}",
+	FeedEntrySummary:           "Serial {{serial}} on mount {{mount}}, valid until {{date}}",
 }
 
 var frenchMessages = Messages{
@@ -541,6 +579,19 @@ var frenchMessages = Messages{
 	TimelineWithinDays:             "≤ {days} jours",
 	TimelineRangeDays:              "{from}–{to} jours",
 	TimelineBeyondDays:             "> {days} jours",
+	ButtonCompare:                  "Comparer",
+	CompareTitle:                   "Comparer avec un autre certificat",
+	CompareNoCandidates:            "Aucun autre certificat avec ce nom commun",
+	CompareIdentical:               "Aucune différence",
+	CompareSameKey:                 "Même paire de clés",
+	CompareNewKey:                  "Nouvelle paire de clés",
+	LabelSignatureAlgorithm:        "Algorithme de signature",
+	LabelKeySize:                   "Taille de clé",
+	LabelNotBefore:                 "Valide à partir du",
+	LabelLifetimeDays:              "Durée de vie (jours)",
+	LabelKeyUsage:                  "Usage de la clé",
+	LabelExtKeyUsage:               "Usage étendu de la clé",
+	LabelExtensions:                "Extensions",
 	SearchPlaceholder:              "Rechercher par numéro de série, nom commun (CN) ou SAN",
 	SelectAll:                      "Tout sélectionner",
 	FilterChipSearch:               "Recherche",
@@ -651,6 +702,12 @@ var frenchMessages = Messages{
 	AdminWebhookURL:            "URL du webhook",
 	AdminWebhookURLHint:        "Reçoit une alerte JSON par POST lorsqu'un certificat franchit le seuil d'avertissement ou critique. Laisser vide pour désactiver.",
 	AdminWebhookURLPlaceholder: "Saisir une nouvelle URL de webhook pour remplacer celle enregistrée",
+	FeedExpiringTitle:          "Certificats arrivant à expiration",
+	FeedChangesTitle:           "Modifications des certificats",
+	FeedEntryExpiring:          "{{name}} expire le {{date}}",
+	FeedEntryIssued:            "Certificat émis : {{name}}",
+	FeedEntryRevoked:           "Certificat révoqué : {{name}}",
+	FeedEntrySummary:           "Numéro de série {{serial}} sur le montage {{mount}}, valide jusqu'au {{date}}",
 }
 
 var spanishMessages = Messages{
@@ -749,6 +806,19 @@ var spanishMessages = Messages{
 	TimelineWithinDays:             "≤ {days} días",
 	TimelineRangeDays:              "{from}–{to} días",
 	TimelineBeyondDays:             "> {days} días",
+	ButtonCompare:                  "Comparar",
+	CompareTitle:                   "Comparar con otro certificado",
+	CompareNoCandidates:            "No hay otro certificado con este nombre común",
+	CompareIdentical:               "Sin diferencias",
+	CompareSameKey:                 "Mismo par de claves",
+	CompareNewKey:                  "Nuevo par de claves",
+	LabelSignatureAlgorithm:        "Algoritmo de firma",
+	LabelKeySize:                   "Tamaño de clave",
+	LabelNotBefore:                 "Válido desde",
+	LabelLifetimeDays:              "Vigencia (días)",
+	LabelKeyUsage:                  "Uso de clave",
+	LabelExtKeyUsage:               "Uso extendido de clave",
+	LabelExtensions:                "Extensiones",
 	SearchPlaceholder:              "Buscar por Número de Serie, Nombre Común (CN) o SAN",
 	SelectAll:                      "Seleccionar todo",
 	FilterChipSearch:               "Search",
@@ -859,6 +929,12 @@ var spanishMessages = Messages{
 	AdminWebhookURL:            "URL del webhook",
 	AdminWebhookURLHint:        "Recibe una alerta JSON por POST cuando un certificado cruza el umbral de advertencia o crítico. Dejar en blanco para desactivar.",
 	AdminWebhookURLPlaceholder: "Introduce una nueva URL de webhook para reemplazar la almacenada",
+	FeedExpiringTitle:          "Certificados próximos a caducar",
+	FeedChangesTitle:           "Cambios en los certificados",
+	FeedEntryExpiring:          "{{name}} caduca el {{date}}",
+	FeedEntryIssued:            "Certificado emitido: {{name}}",
+	FeedEntryRevoked:           "Certificado revocado: {{name}}",
+	FeedEntrySummary:           "Serie {{serial}} en el montaje {{mount}}, válido hasta el {{date}}",
 }
 
 var germanMessages = Messages{
@@ -957,6 +1033,19 @@ var germanMessages = Messages{
 	TimelineWithinDays:             "≤ {days} Tage",
 	TimelineRangeDays:              "{from}–{to} Tage",
 	TimelineBeyondDays:             "> {days} Tage",
+	ButtonCompare:                  "Vergleichen",
+	CompareTitle:                   "Mit einem anderen Zertifikat vergleichen",
+	CompareNoCandidates:            "Kein anderes Zertifikat mit diesem Common Name",
+	CompareIdentical:               "Keine Unterschiede",
+	CompareSameKey:                 "Gleiches Schlüsselpaar",
+	CompareNewKey:                  "Neues Schlüsselpaar",
+	LabelSignatureAlgorithm:        "Signaturalgorithmus",
+	LabelKeySize:                   "Schlüssellänge",
+	LabelNotBefore:                 "Gültig ab",
+	LabelLifetimeDays:              "Laufzeit (Tage)",
+	LabelKeyUsage:                  "Schlüsselverwendung",
+	LabelExtKeyUsage:               "Erweiterte Schlüsselverwendung",
+	LabelExtensions:                "Erweiterungen",
 	SearchPlaceholder:              "Suche nach Seriennummer, Common Name (CN) oder SAN",
 	SelectAll:                      "Alle auswählen",
 	FilterChipSearch:               "Search",
@@ -1067,6 +1156,12 @@ var germanMessages = Messages{
 	AdminWebhookURL:            "Webhook-URL",
 	AdminWebhookURLHint:        "Erhält einen JSON-Alarm per POST, wenn ein Zertifikat die Warn- oder kritische Schwelle überschreitet. Leer lassen zum Deaktivieren.",
 	AdminWebhookURLPlaceholder: "Neue Webhook-URL eingeben, um die gespeicherte zu ersetzen",
+	FeedExpiringTitle:          "Bald ablaufende Zertifikate",
+	FeedChangesTitle:           "Zertifikatsänderungen",
+	FeedEntryExpiring:          "{{name}} läuft am {{date}} ab",
+	FeedEntryIssued:            "Zertifikat ausgestellt: {{name}}",
+	FeedEntryRevoked:           "Zertifikat widerrufen: {{name}}",
+	FeedEntrySummary:           "Seriennummer {{serial}} im Mount {{mount}}, gültig bis {{date}}",
 }
 
 var italianMessages = Messages{
@@ -1165,6 +1260,19 @@ var italianMessages = Messages{
 	TimelineWithinDays:             "≤ {days} giorni",
 	TimelineRangeDays:              "{from}–{to} giorni",
 	TimelineBeyondDays:             "> {days} giorni",
+	ButtonCompare:                  "Confronta",
+	CompareTitle:                   "Confronta con un altro certificato",
+	CompareNoCandidates:            "Nessun altro certificato con questo nome comune",
+	CompareIdentical:               "Nessuna differenza",
+	CompareSameKey:                 "Stessa coppia di chiavi",
+	CompareNewKey:                  "Nuova coppia di chiavi",
+	LabelSignatureAlgorithm:        "Algoritmo di firma",
+	LabelKeySize:                   "Dimensione chiave",
+	LabelNotBefore:                 "Valido dal",
+	LabelLifetimeDays:              "Durata (giorni)",
+	LabelKeyUsage:                  "Utilizzo chiave",
+	LabelExtKeyUsage:               "Utilizzo esteso chiave",
+	LabelExtensions:                "Estensioni",
 	SearchPlaceholder:              "Cerca per Numero di Serie, Nome Comune (CN) o SAN",
 	SelectAll:                      "Seleziona tutto",
 	FilterChipSearch:               "Search",
@@ -1275,6 +1383,12 @@ var italianMessages = Messages{
 	AdminWebhookURL:            "URL webhook",
 	AdminWebhookURLHint:        "Riceve un avviso JSON via POST quando un certificato supera la soglia di avviso o critica. Lasciare vuoto per disabilitare.",
 	AdminWebhookURLPlaceholder: "Inserisci un nuovo URL webhook per sostituire quello memorizzato",
+	FeedExpiringTitle:          "Certificati in scadenza",
+	FeedChangesTitle:           "Modifiche ai certificati",
+	FeedEntryExpiring:          "{{name}} scade il {{date}}",
+	FeedEntryIssued:            "Certificato emesso: {{name}}",
+	FeedEntryRevoked:           "Certificato revocato: {{name}}",
+	FeedEntrySummary:           "Seriale {{serial}} sul mount {{mount}}, valido fino al {{date}}",
 }
 
 // MessagesForLanguage returns the translations for a given language code.
@@ -14,6 +14,7 @@ import (
 	"vcv/internal/certs"
 	"vcv/internal/config"
 	"vcv/internal/logger"
+	"vcv/internal/stats"
 	"vcv/internal/vault"
 )
 
@@ -55,12 +56,17 @@ var (
 	issuedLast30dDesc          = prometheus.NewDesc("vcv_certificates_issued_last_30d", "Number of certificates issued in the last 30 days", []string{"vault_id", "pki"}, nil)
 	pinnedCertExpiryDesc       = prometheus.NewDesc("vcv_pinned_certificate_expiry_timestamp_seconds", "Expiration timestamp for pinned certificates", []string{"certificate_id", "common_name", "status", "vault_id", "pki"}, nil)
 	pinnedCertDaysDesc         = prometheus.NewDesc("vcv_pinned_certificate_days_until_expiry", "Days until expiry for pinned certificates", []string{"certificate_id", "common_name", "status", "vault_id", "pki"}, nil)
+	certsByTypeDesc            = prometheus.NewDesc("vcv_certificates_by_type_total", "Total certificates grouped by certificate type (built-in inference or classification rule)", []string{"vault_id", "pki", "cert_type"}, nil)
+	rolledUpDesc               = prometheus.NewDesc("vcv_certificates_rolled_up", "Short-lived certificates folded into rollup rows by a mount policy (not counted in other series)", []string{"vault_id", "pki"}, nil)
+	reusedKeysDesc             = prometheus.NewDesc("vcv_certificates_reused_keys", "Number of distinct public keys in a mount that are shared by more than one certificate across the inventory", []string{"vault_id", "pki"}, nil)
+	expiringInWindowDesc       = prometheus.NewDesc("vcv_certificates_expiring_in_window", "Number of certificates expiring in a week (week_N) or month (month_N) window ahead, excluding revoked and superseded certificates", []string{"vault_id", "pki", "issuer_cn", "window"}, nil)
 )
 
 type certificateCollector struct {
 	vaultClient        vault.Client
 	statusClients      map[string]vault.Client
 	thresholds         config.ExpirationThresholds
+	expiry             *certs.ExpiryThresholds
 	perCertificate     bool
 	enhancedMetrics    bool
 	pinnedCertificates []string
@@ -71,14 +77,7 @@ type certificateCollector struct {
 // NewCertificateCollector returns a Prometheus collector exposing certificate inventory and expiry status.
 
 func NewCertificateCollector(vaultClient vault.Client, statusClients map[string]vault.Client, thresholds config.ExpirationThresholds, metricsConfig config.MetricsConfig) prometheus.Collector {
-	critical := thresholds.Critical
-	if critical <= 0 {
-		critical = 7
-	}
-	warning := thresholds.Warning
-	if warning <= 0 {
-		warning = 30
-	}
+	normalized, expiry := compileExpiryThresholds(thresholds, nil)
 	clients := statusClients
 	if clients == nil {
 		clients = map[string]vault.Client{}
@@ -86,7 +85,8 @@ func NewCertificateCollector(vaultClient vault.Client, statusClients map[string]
 	return &certificateCollector{
 		vaultClient:        vaultClient,
 		statusClients:      clients,
-		thresholds:         config.ExpirationThresholds{Critical: critical, Warning: warning},
+		thresholds:         normalized,
+		expiry:             expiry,
 		perCertificate:     metricsConfig.PerCertificate,
 		enhancedMetrics:    metricsConfig.EnhancedMetrics,
 		pinnedCertificates: metricsConfig.PinnedCertificates,
@@ -102,9 +102,25 @@ func NewCertificateCollectorWithVaults(vaultClient vault.Client, statusClients m
 		return collector
 	}
 	typed.configuredVaults = append([]config.VaultInstance{}, vaults...)
+	typed.thresholds, typed.expiry = compileExpiryThresholds(thresholds, vaults)
 	return typed
 }
 
+// compileExpiryThresholds applies the config defaults and compiles the
+// per-vault and per-mount windows used by the expiring-soon gauges.
+func compileExpiryThresholds(thresholds config.ExpirationThresholds, vaults []config.VaultInstance) (config.ExpirationThresholds, *certs.ExpiryThresholds) {
+	normalized := thresholds.WithDefaults()
+	expiry, err := certs.NewExpiryThresholds(normalized, vaults)
+	if err != nil {
+		// Settings are validated on load and save; fall back to whole days
+		// rather than dropping the expiring-soon gauges altogether.
+		logger.Get().Warn().Err(err).Msg("metrics: invalid expiration thresholds, using day thresholds only")
+		normalized = config.ExpirationThresholds{Critical: max(normalized.Critical, 0), Warning: max(normalized.Warning, 0)}
+		expiry, _ = certs.NewExpiryThresholds(normalized, nil)
+	}
+	return normalized, expiry
+}
+
 func (collector *certificateCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- cacheSizeDesc
 	ch <- certificatesLastFetchDesc
@@ -136,6 +152,10 @@ func (collector *certificateCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- issuedLast30dDesc
 	ch <- pinnedCertExpiryDesc
 	ch <- pinnedCertDaysDesc
+	ch <- certsByTypeDesc
+	ch <- reusedKeysDesc
+	ch <- expiringInWindowDesc
+	ch <- rolledUpDesc
 }
 
 func (collector *certificateCollector) Collect(ch chan<- prometheus.Metric) {
@@ -162,8 +182,8 @@ func (collector *certificateCollector) Collect(ch chan<- prometheus.Metric) {
 	collector.emitVaultConnectionMetrics(ch)
 	collector.emitVaultListingMetrics(ch, listResults, scrapeDuration)
 
-	validCount, revokedCount, expiredCount := collector.countStatuses(certificates, now)
-	warningSoonCount, criticalSoonCount := collector.countExpiringSoon(certificates, now)
+	inventory := stats.Compute(certificates, collector.expiry, now)
+	validCount, revokedCount, expiredCount := statusTotals(inventory.Counts)
 	cacheSize := collector.getCacheSize()
 
 	ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(cacheSize))
@@ -172,19 +192,23 @@ func (collector *certificateCollector) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(revokedCount), allLabelValue, allLabelValue, "revoked")
 	ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(expiredCount), allLabelValue, allLabelValue, "expired")
 	ch <- prometheus.MustNewConstMetric(expiredCountDesc, prometheus.GaugeValue, float64(expiredCount))
-	ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(warningSoonCount), allLabelValue, allLabelValue, "warning")
-	ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(criticalSoonCount), allLabelValue, allLabelValue, "critical")
+	ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(inventory.Expiring.Warning), allLabelValue, allLabelValue, "warning")
+	ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(inventory.Expiring.Critical), allLabelValue, allLabelValue, "critical")
 	ch <- prometheus.MustNewConstMetric(thresholdCriticalDesc, prometheus.GaugeValue, float64(collector.thresholds.Critical))
 	ch <- prometheus.MustNewConstMetric(thresholdWarningDesc, prometheus.GaugeValue, float64(collector.thresholds.Warning))
-	collector.emitCertificateAggregationMetrics(ch, certificates, now)
+	collector.emitCertificateAggregationMetrics(ch, inventory)
 	collector.emitPerCertificateMetrics(ch, certificates, now)
+	collector.emitRollupMetrics(ch, certificates)
 	if collector.enhancedMetrics {
-		collector.emitEnhancedMetrics(ch, certificates, now)
-		collector.emitIssuerMetrics(ch, certificates)
-		collector.emitKeyTypeMetrics(ch, certificates)
+		collector.emitEnhancedMetrics(ch, inventory)
+		collector.emitIssuerMetrics(ch, inventory)
+		collector.emitKeyTypeMetrics(ch, inventory)
+		collector.emitCertTypeMetrics(ch, certificates)
 		collector.emitSANMetrics(ch, certificates)
 		collector.emitAgeMetrics(ch, certificates, now)
 		collector.emitRenewalMetrics(ch, certificates, now)
+		collector.emitKeyReuseMetrics(ch, certificates)
+		collector.emitForecastMetrics(ch, certificates, now)
 	}
 	collector.emitPinnedCertificateMetrics(ch, certificates, now)
 }
@@ -246,28 +270,6 @@ func (collector *certificateCollector) emitVaultConnectionMetrics(ch chan<- prom
 	}
 }
 
-func (collector *certificateCollector) countStatuses(certificates []certs.Certificate, now time.Time) (int, int, int) {
-	validCount := 0
-	revokedCount := 0
-	expiredCount := 0
-	for _, certificate := range certificates {
-		status := collector.statusLabel(certificate, now)
-		switch status {
-		case "revoked":
-			revokedCount++
-		case "expired":
-			expiredCount++
-		default:
-			validCount++
-		}
-	}
-	return validCount, revokedCount, expiredCount
-}
-
-func (collector *certificateCollector) countExpiringSoon(certificates []certs.Certificate, now time.Time) (int, int) {
-	return certs.CountExpiring(certificates, collector.thresholds.Warning, collector.thresholds.Critical, now)
-}
-
 func (collector *certificateCollector) getCacheSize() int {
 	if sizer, ok := collector.vaultClient.(vault.CacheSizer); ok {
 		return sizer.CacheSize()
@@ -349,54 +351,51 @@ func (collector *certificateCollector) emitVaultListingFailureMetrics(ch chan<-
 	ch <- prometheus.MustNewConstMetric(partialScrapeDesc, prometheus.GaugeValue, 1, allLabelValue)
 }
 
-func (collector *certificateCollector) emitCertificateAggregationMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate, now time.Time) {
-	totals := make(map[string]map[string]int)
-	soon := make(map[string]map[string]int)
+// emitCertificateAggregationMetrics emits the per-mount status totals and
+// expiring-soon counts.
+func (collector *certificateCollector) emitCertificateAggregationMetrics(ch chan<- prometheus.Metric, inventory stats.Stats) {
+	for _, key := range sortedStringKeys(inventory.Mounts) {
+		counts := inventory.Mounts[key]
+		vaultID, pki := mountLabels(key)
+		valid, revoked, expired := statusTotals(counts)
+		ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(valid), vaultID, pki, "valid")
+		ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(revoked), vaultID, pki, "revoked")
+		ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(expired), vaultID, pki, "expired")
+		ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(counts.Expiring.Warning), vaultID, pki, "warning")
+		ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(counts.Expiring.Critical), vaultID, pki, "critical")
+	}
+}
+
+// statusTotals folds the warning and critical statuses into valid, the only
+// unexpired status the status labels know.
+func statusTotals(counts stats.Counts) (valid, revoked, expired int) {
+	valid = counts.Statuses[certs.StatusValid] + counts.Statuses[certs.StatusWarning] + counts.Statuses[certs.StatusCritical]
+	return valid, counts.Statuses[certs.StatusRevoked], counts.Statuses[certs.StatusExpired]
+}
+
+// mountLabels returns the vault_id and pki labels of a stats mount key.
+func mountLabels(key string) (string, string) {
+	vaultID, pki := stats.SplitMountKey(key)
+	if vaultID == "" {
+		vaultID = allLabelValue
+	}
+	return vaultID, pki
+}
+
+// emitRollupMetrics reports, per mount with a rollup policy in effect, how
+// many certificates are hidden behind rollup rows.
+func (collector *certificateCollector) emitRollupMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate) {
+	folded := make(map[string]int)
 	for _, certificate := range certificates {
-		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
-		key := buildAggregationKey(vaultID, pki)
-		status := collector.statusLabel(certificate, now)
-		if _, ok := totals[key]; !ok {
-			totals[key] = map[string]int{"valid": 0, "revoked": 0, "expired": 0}
-		}
-		totals[key][status] = totals[key][status] + 1
-		if status != "valid" {
-			continue
-		}
-		if certificate.ExpiresAt.IsZero() {
-			continue
-		}
-		daysRemaining := daysUntil(certificate.ExpiresAt.UTC(), now.UTC())
-		if daysRemaining < 0 {
+		if certificate.RollupCount == 0 {
 			continue
 		}
-		if _, ok := soon[key]; !ok {
-			soon[key] = map[string]int{"warning": 0, "critical": 0}
-		}
-		if collector.thresholds.Warning > 0 && daysRemaining <= collector.thresholds.Warning {
-			soon[key]["warning"] = soon[key]["warning"] + 1
-		}
-		if collector.thresholds.Critical > 0 && daysRemaining <= collector.thresholds.Critical {
-			soon[key]["critical"] = soon[key]["critical"] + 1
-		}
-	}
-
-	keys := make([]string, 0, len(totals))
-	for key := range totals {
-		keys = append(keys, key)
+		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
+		folded[buildAggregationKey(vaultID, pki)] += certificate.RollupCount - 1
 	}
-	sort.Strings(keys)
-	for _, key := range keys {
+	for _, key := range sortedStringKeys(folded) {
 		vaultID, pki := splitAggregationKey(key)
-		for _, status := range []string{"valid", "revoked", "expired"} {
-			ch <- prometheus.MustNewConstMetric(certificatesTotalDesc, prometheus.GaugeValue, float64(totals[key][status]), vaultID, pki, status)
-		}
-		counts, ok := soon[key]
-		if !ok {
-			counts = map[string]int{"warning": 0, "critical": 0}
-		}
-		ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(counts["warning"]), vaultID, pki, "warning")
-		ch <- prometheus.MustNewConstMetric(expiringSoonCountDesc, prometheus.GaugeValue, float64(counts["critical"]), vaultID, pki, "critical")
+		ch <- prometheus.MustNewConstMetric(rolledUpDesc, prometheus.GaugeValue, float64(folded[key]), vaultID, pki)
 	}
 }
 
@@ -417,7 +416,7 @@ func (collector *certificateCollector) emitPerCertificateMetrics(ch chan<- prome
 	}
 	emitEnhanced := collector.enhancedMetrics
 	for _, certificate := range certificates {
-		if certificate.ExpiresAt.IsZero() {
+		if certificate.ExpiresAt.IsZero() || certificate.ExcludeFromPerCertificateMetrics {
 			continue
 		}
 		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
@@ -431,14 +430,15 @@ func (collector *certificateCollector) emitPerCertificateMetrics(ch chan<- prome
 	}
 }
 
+// statusLabel maps the certificate status onto the valid, revoked and
+// expired labels of the per-certificate series.
 func (collector *certificateCollector) statusLabel(certificate certs.Certificate, now time.Time) string {
-	if certificate.Revoked {
-		return "revoked"
+	switch status := collector.expiry.Status(certificate, now); status {
+	case certs.StatusRevoked, certs.StatusExpired:
+		return status
+	default:
+		return certs.StatusValid
 	}
-	if !certificate.ExpiresAt.IsZero() && certificate.ExpiresAt.Before(now) {
-		return "expired"
-	}
-	return "valid"
 }
 
 func buildAggregationKey(vaultID string, pki string) string {
@@ -482,62 +482,14 @@ func daysUntil(expiresAt time.Time, now time.Time) int {
 	return int(math.Ceil(diff.Hours() / 24))
 }
 
-func (collector *certificateCollector) emitEnhancedMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate, now time.Time) {
-	buckets := make(map[string]map[string]map[string]int)
-	for _, certificate := range certificates {
-		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
-		if _, ok := buckets[vaultID]; !ok {
-			buckets[vaultID] = make(map[string]map[string]int)
-		}
-		if _, ok := buckets[vaultID][pki]; !ok {
-			buckets[vaultID][pki] = map[string]int{"0-7d": 0, "7-30d": 0, "30-90d": 0, "90d+": 0, "expired": 0, "revoked": 0}
-		}
-		if certificate.Revoked {
-			buckets[vaultID][pki]["revoked"]++
-			continue
-		}
-		if certificate.ExpiresAt.IsZero() {
-			continue
-		}
-		daysRemaining := daysUntil(certificate.ExpiresAt.UTC(), now.UTC())
-		if daysRemaining < 0 {
-			buckets[vaultID][pki]["expired"]++
-		} else if daysRemaining <= 7 {
-			buckets[vaultID][pki]["0-7d"]++
-		} else if daysRemaining <= 30 {
-			buckets[vaultID][pki]["7-30d"]++
-		} else if daysRemaining <= 90 {
-			buckets[vaultID][pki]["30-90d"]++
-		} else {
-			buckets[vaultID][pki]["90d+"]++
-		}
-	}
-	vaultIDs := make([]string, 0, len(buckets))
-	for vaultID := range buckets {
-		vaultIDs = append(vaultIDs, vaultID)
-	}
-	sort.Strings(vaultIDs)
-	for _, vaultID := range vaultIDs {
-		pkis := make([]string, 0, len(buckets[vaultID]))
-		for pki := range buckets[vaultID] {
-			pkis = append(pkis, pki)
-		}
-		sort.Strings(pkis)
-		for _, pki := range pkis {
-			for _, bucket := range []string{"0-7d", "7-30d", "30-90d", "90d+", "expired", "revoked"} {
-				ch <- prometheus.MustNewConstMetric(expiryBucketDesc, prometheus.GaugeValue, float64(buckets[vaultID][pki][bucket]), vaultID, pki, bucket)
-			}
-		}
-	}
-	allBuckets := map[string]int{"0-7d": 0, "7-30d": 0, "30-90d": 0, "90d+": 0, "expired": 0, "revoked": 0}
-	for vaultID := range buckets {
-		for pki := range buckets[vaultID] {
-			for bucket, count := range buckets[vaultID][pki] {
-				allBuckets[bucket] += count
-			}
+func (collector *certificateCollector) emitEnhancedMetrics(ch chan<- prometheus.Metric, inventory stats.Stats) {
+	for _, key := range sortedStringKeys(inventory.Mounts) {
+		vaultID, pki := mountLabels(key)
+		for _, bucket := range stats.Buckets {
+			ch <- prometheus.MustNewConstMetric(expiryBucketDesc, prometheus.GaugeValue, float64(inventory.Mounts[key].Buckets[bucket]), vaultID, pki, bucket)
 		}
 	}
-	for _, bucket := range []string{"0-7d", "7-30d", "30-90d", "90d+", "expired", "revoked"} {
-		ch <- prometheus.MustNewConstMetric(expiryBucketDesc, prometheus.GaugeValue, float64(allBuckets[bucket]), allLabelValue, allLabelValue, bucket)
+	for _, bucket := range stats.Buckets {
+		ch <- prometheus.MustNewConstMetric(expiryBucketDesc, prometheus.GaugeValue, float64(inventory.Buckets[bucket]), allLabelValue, allLabelValue, bucket)
 	}
 }
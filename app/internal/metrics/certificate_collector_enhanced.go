@@ -3,12 +3,14 @@ package metrics
 import (
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"vcv/internal/certs"
+	"vcv/internal/stats"
 )
 
 // sortedStringKeys returns sorted keys from a string-keyed map.
@@ -31,67 +33,56 @@ func matchesPattern(pattern, value string) bool {
 }
 
 // emitIssuerMetrics emits metrics grouped by certificate issuer CN.
-func (collector *certificateCollector) emitIssuerMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate) {
-	issuerCounts := make(map[string]map[string]map[string]int)
-	for _, certificate := range certificates {
-		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
-		issuerCN := extractIssuerCN(certificate)
-		if _, ok := issuerCounts[vaultID]; !ok {
-			issuerCounts[vaultID] = make(map[string]map[string]int)
-		}
-		if _, ok := issuerCounts[vaultID][pki]; !ok {
-			issuerCounts[vaultID][pki] = make(map[string]int)
+func (collector *certificateCollector) emitIssuerMetrics(ch chan<- prometheus.Metric, inventory stats.Stats) {
+	for _, key := range sortedStringKeys(inventory.Mounts) {
+		vaultID, pki := mountLabels(key)
+		issuers := inventory.Mounts[key].Issuers
+		for _, issuer := range sortedStringKeys(issuers) {
+			ch <- prometheus.MustNewConstMetric(certsByIssuerDesc, prometheus.GaugeValue, float64(issuers[issuer]), vaultID, pki, issuer)
 		}
-		issuerCounts[vaultID][pki][issuerCN]++
 	}
-	for _, vaultID := range sortedStringKeys(issuerCounts) {
-		for _, pki := range sortedStringKeys(issuerCounts[vaultID]) {
-			for _, issuer := range sortedStringKeys(issuerCounts[vaultID][pki]) {
-				ch <- prometheus.MustNewConstMetric(certsByIssuerDesc, prometheus.GaugeValue, float64(issuerCounts[vaultID][pki][issuer]), vaultID, pki, issuer)
+}
+
+// emitKeyTypeMetrics emits metrics grouped by key algorithm and size, including weak key detection.
+func (collector *certificateCollector) emitKeyTypeMetrics(ch chan<- prometheus.Metric, inventory stats.Stats) {
+	for _, key := range sortedStringKeys(inventory.Mounts) {
+		vaultID, pki := mountLabels(key)
+		keyTypes := inventory.Mounts[key].KeyTypes
+		weakCount := 0
+		for _, keyType := range sortedStringKeys(keyTypes) {
+			algorithm, keySize := stats.SplitKeyType(keyType)
+			ch <- prometheus.MustNewConstMetric(certsByKeyTypeDesc, prometheus.GaugeValue, float64(keyTypes[keyType]), vaultID, pki, algorithm, keySize)
+			if isWeakKey(algorithm, keySize) {
+				weakCount += keyTypes[keyType]
 			}
 		}
+		ch <- prometheus.MustNewConstMetric(weakKeysDesc, prometheus.GaugeValue, float64(weakCount), vaultID, pki)
 	}
 }
 
-// emitKeyTypeMetrics emits metrics grouped by key algorithm and size, including weak key detection.
-func (collector *certificateCollector) emitKeyTypeMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate) {
-	keyTypeCounts := make(map[string]map[string]map[string]int)
-	weakKeyCounts := make(map[string]map[string]int)
+// emitCertTypeMetrics emits metrics grouped by certificate type, so custom
+// classification rules show up under the same labels the UI filters on.
+func (collector *certificateCollector) emitCertTypeMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate) {
+	typeCounts := make(map[string]map[string]map[string]int)
 	for _, certificate := range certificates {
 		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
-		algorithm, keySize := extractKeyInfo(certificate)
-		if _, ok := keyTypeCounts[vaultID]; !ok {
-			keyTypeCounts[vaultID] = make(map[string]map[string]int)
-		}
-		if _, ok := keyTypeCounts[vaultID][pki]; !ok {
-			keyTypeCounts[vaultID][pki] = make(map[string]int)
-		}
-		keyTypeLabel := algorithm + "_" + keySize
-		keyTypeCounts[vaultID][pki][keyTypeLabel]++
-		if isWeakKey(algorithm, keySize) {
-			if _, ok := weakKeyCounts[vaultID]; !ok {
-				weakKeyCounts[vaultID] = make(map[string]int)
-			}
-			weakKeyCounts[vaultID][pki]++
+		certType := strings.TrimSpace(certificate.CertType)
+		if certType == "" {
+			certType = "unknown"
+		}
+		if _, ok := typeCounts[vaultID]; !ok {
+			typeCounts[vaultID] = make(map[string]map[string]int)
 		}
+		if _, ok := typeCounts[vaultID][pki]; !ok {
+			typeCounts[vaultID][pki] = make(map[string]int)
+		}
+		typeCounts[vaultID][pki][certType]++
 	}
-	for _, vaultID := range sortedStringKeys(keyTypeCounts) {
-		for _, pki := range sortedStringKeys(keyTypeCounts[vaultID]) {
-			for _, keyType := range sortedStringKeys(keyTypeCounts[vaultID][pki]) {
-				parts := strings.SplitN(keyType, "_", 2)
-				algorithm := "unknown"
-				keySize := "0"
-				if len(parts) == 2 {
-					algorithm = parts[0]
-					keySize = parts[1]
-				}
-				ch <- prometheus.MustNewConstMetric(certsByKeyTypeDesc, prometheus.GaugeValue, float64(keyTypeCounts[vaultID][pki][keyType]), vaultID, pki, algorithm, keySize)
+	for _, vaultID := range sortedStringKeys(typeCounts) {
+		for _, pki := range sortedStringKeys(typeCounts[vaultID]) {
+			for _, certType := range sortedStringKeys(typeCounts[vaultID][pki]) {
+				ch <- prometheus.MustNewConstMetric(certsByTypeDesc, prometheus.GaugeValue, float64(typeCounts[vaultID][pki][certType]), vaultID, pki, certType)
 			}
-			weakCount := 0
-			if counts, ok := weakKeyCounts[vaultID]; ok {
-				weakCount = counts[pki]
-			}
-			ch <- prometheus.MustNewConstMetric(weakKeysDesc, prometheus.GaugeValue, float64(weakCount), vaultID, pki)
 		}
 	}
 }
@@ -258,6 +249,36 @@ func (collector *certificateCollector) emitRenewalMetrics(ch chan<- prometheus.M
 	}
 }
 
+// emitKeyReuseMetrics emits, per mount, how many distinct public keys are
+// shared by two or more certificates anywhere in the inventory. A key reused
+// across mounts or vaults counts once in each mount it appears in, and once
+// in the __all__ series.
+func (collector *certificateCollector) emitKeyReuseMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate) {
+	reused := make(map[string]bool)
+	for _, group := range certs.FindKeyReuse(certificates) {
+		reused[group.Fingerprint] = true
+	}
+	keysByMount := make(map[string]map[string]map[string]bool)
+	for _, certificate := range certificates {
+		vaultID, pki := extractVaultIDAndPKI(certificate.ID)
+		if _, ok := keysByMount[vaultID]; !ok {
+			keysByMount[vaultID] = make(map[string]map[string]bool)
+		}
+		if _, ok := keysByMount[vaultID][pki]; !ok {
+			keysByMount[vaultID][pki] = make(map[string]bool)
+		}
+		if reused[certificate.PublicKeyFingerprint] {
+			keysByMount[vaultID][pki][certificate.PublicKeyFingerprint] = true
+		}
+	}
+	for _, vaultID := range sortedStringKeys(keysByMount) {
+		for _, pki := range sortedStringKeys(keysByMount[vaultID]) {
+			ch <- prometheus.MustNewConstMetric(reusedKeysDesc, prometheus.GaugeValue, float64(len(keysByMount[vaultID][pki])), vaultID, pki)
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(reusedKeysDesc, prometheus.GaugeValue, float64(len(reused)), allLabelValue, allLabelValue)
+}
+
 // emitPinnedCertificateMetrics emits per-certificate metrics only for pinned certificates.
 func (collector *certificateCollector) emitPinnedCertificateMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate, now time.Time) {
 	if len(collector.pinnedCertificates) == 0 {
@@ -307,24 +328,6 @@ func (collector *certificateCollector) emitPinnedCertificateMetrics(ch chan<- pr
 	}
 }
 
-// extractIssuerCN returns the issuer Common Name from list-time certificate fields.
-func extractIssuerCN(certificate certs.Certificate) string {
-	issuerCN := strings.TrimSpace(certificate.IssuerCN)
-	if issuerCN != "" {
-		return issuerCN
-	}
-	return "unknown"
-}
-
-// extractKeyInfo returns key algorithm and size labels from list-time certificate fields.
-func extractKeyInfo(certificate certs.Certificate) (string, string) {
-	algorithm := strings.TrimSpace(certificate.KeyAlgorithm)
-	if algorithm == "" {
-		algorithm = "unknown"
-	}
-	return algorithm, certs.KeySizeLabel(certificate.KeySize)
-}
-
 // isWeakKey determines if a key is considered weak based on algorithm and size.
 func isWeakKey(algorithm string, keySize string) bool {
 	if algorithm == "RSA" {
@@ -337,3 +340,39 @@ func isWeakKey(algorithm string, keySize string) bool {
 	}
 	return false
 }
+
+// forecastWindows is how many weeks and months ahead
+// vcv_certificates_expiring_in_window covers.
+const forecastWindows = 12
+
+// emitForecastMetrics emits, per vault, mount and issuer, the certificates
+// expiring in each of the next forecastWindows weeks and months, as
+// GET /api/forecast reports them. Week and month series overlap: sum them
+// only within one period.
+func (collector *certificateCollector) emitForecastMetrics(ch chan<- prometheus.Metric, certificates []certs.Certificate, now time.Time) {
+	superseded := certs.Superseded(certificates)
+	for _, period := range []string{stats.PeriodWeek, stats.PeriodMonth} {
+		forecast, err := stats.ComputeForecast(certificates, superseded, period, forecastWindows, now)
+		if err != nil {
+			continue
+		}
+		for _, group := range forecast.Groups {
+			vaultID := group.Vault
+			if vaultID == "" {
+				vaultID = allLabelValue
+			}
+			for index, count := range group.Counts {
+				ch <- prometheus.MustNewConstMetric(expiringInWindowDesc, prometheus.GaugeValue, float64(count), vaultID, group.Mount, group.Issuer, forecastWindowLabel(period, index))
+			}
+		}
+		for index, count := range forecast.Totals {
+			ch <- prometheus.MustNewConstMetric(expiringInWindowDesc, prometheus.GaugeValue, float64(count), allLabelValue, allLabelValue, allLabelValue, forecastWindowLabel(period, index))
+		}
+	}
+}
+
+// forecastWindowLabel returns the window label of the zero-based index:
+// week_1 is the coming seven days.
+func forecastWindowLabel(period string, index int) string {
+	return period + "_" + strconv.Itoa(index+1)
+}
@@ -8,24 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"vcv/internal/certs"
+	"vcv/internal/stats"
 )
 
-func TestExtractIssuerCN_UsesIssuerField(t *testing.T) {
-	got := extractIssuerCN(certs.Certificate{CommonName: "app.example.com", IssuerCN: "Internal Intermediate CA"})
-	assert.Equal(t, "Internal Intermediate CA", got)
-	assert.Equal(t, "unknown", extractIssuerCN(certs.Certificate{CommonName: "app.example.com"}))
-}
-
-func TestExtractKeyInfo_UsesCertificateFields(t *testing.T) {
-	algo, size := extractKeyInfo(certs.Certificate{KeyAlgorithm: "RSA", KeySize: 1024})
-	assert.Equal(t, "RSA", algo)
-	assert.Equal(t, "1024", size)
-	assert.True(t, isWeakKey(algo, size))
-	algo, size = extractKeyInfo(certs.Certificate{})
-	assert.Equal(t, "unknown", algo)
-	assert.Equal(t, "0", size)
-}
-
 func TestEmitKeyTypeMetrics_WeakRSA1024(t *testing.T) {
 	collector := &certificateCollector{enhancedMetrics: true}
 	ch := make(chan prometheus.Metric, 16)
@@ -36,7 +21,7 @@ func TestEmitKeyTypeMetrics_WeakRSA1024(t *testing.T) {
 		KeySize:      1024,
 		ExpiresAt:    time.Now().Add(30 * 24 * time.Hour),
 	}}
-	collector.emitKeyTypeMetrics(ch, certificates)
+	collector.emitKeyTypeMetrics(ch, stats.Compute(certificates, nil, time.Now()))
 	close(ch)
 	metricCount := 0
 	for range ch {
@@ -55,7 +40,7 @@ func TestEmitIssuerMetrics_UsesIssuerCN(t *testing.T) {
 		IssuerCN:   "My Issuer CA",
 		ExpiresAt:  time.Now().Add(30 * 24 * time.Hour),
 	}}
-	collector.emitIssuerMetrics(ch, certificates)
+	collector.emitIssuerMetrics(ch, stats.Compute(certificates, nil, time.Now()))
 	close(ch)
 	count := 0
 	for range ch {
@@ -63,3 +48,79 @@ func TestEmitIssuerMetrics_UsesIssuerCN(t *testing.T) {
 	}
 	assert.GreaterOrEqual(t, count, 1)
 }
+
+func TestEmitKeyReuseMetrics_CountsKeysPerMount(t *testing.T) {
+	collector := &certificateCollector{enhancedMetrics: true}
+	registry := prometheus.NewRegistry()
+	certificates := []certs.Certificate{
+		{ID: "vault-a|pki:1", PublicKeyFingerprint: "k1"},
+		{ID: "vault-a|pki:2", PublicKeyFingerprint: "k1"},
+		{ID: "vault-b|pki_int:3", PublicKeyFingerprint: "k1"},
+		{ID: "vault-b|pki_int:4", PublicKeyFingerprint: "k2"},
+		{ID: "vault-b|pki_int:5", PublicKeyFingerprint: "k2"},
+		{ID: "vault-b|pki_int:6", PublicKeyFingerprint: "k3"},
+	}
+	registry.MustRegister(collectorFunc(func(ch chan<- prometheus.Metric) {
+		collector.emitKeyReuseMetrics(ch, certificates)
+	}))
+
+	assertGauge(t, registry, "vcv_certificates_reused_keys", map[string]string{"vault_id": "vault-a", "pki": "pki"}, 1)
+	assertGauge(t, registry, "vcv_certificates_reused_keys", map[string]string{"vault_id": "vault-b", "pki": "pki_int"}, 2)
+	assertGauge(t, registry, "vcv_certificates_reused_keys", map[string]string{"vault_id": "__all__", "pki": "__all__"}, 2)
+}
+
+func TestEmitCertTypeMetrics_GroupsByMountAndType(t *testing.T) {
+	collector := &certificateCollector{enhancedMetrics: true}
+	registry := prometheus.NewRegistry()
+	certificates := []certs.Certificate{
+		{ID: "vault-a|pki:1", CertType: "machine"},
+		{ID: "vault-a|pki:2", CertType: "machine"},
+		{ID: "vault-a|pki:3", CertType: "code-signing"},
+		{ID: "vault-b|pki:4"},
+	}
+	registry.MustRegister(collectorFunc(func(ch chan<- prometheus.Metric) {
+		collector.emitCertTypeMetrics(ch, certificates)
+	}))
+
+	assertGauge(t, registry, "vcv_certificates_by_type_total", map[string]string{"vault_id": "vault-a", "pki": "pki", "cert_type": "machine"}, 2)
+	assertGauge(t, registry, "vcv_certificates_by_type_total", map[string]string{"vault_id": "vault-a", "pki": "pki", "cert_type": "code-signing"}, 1)
+	assertGauge(t, registry, "vcv_certificates_by_type_total", map[string]string{"vault_id": "vault-b", "pki": "pki", "cert_type": "unknown"}, 1)
+}
+
+func TestEmitForecastMetrics_CountsPerWindow(t *testing.T) {
+	collector := &certificateCollector{enhancedMetrics: true}
+	registry := prometheus.NewRegistry()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	certificates := []certs.Certificate{
+		{ID: "vault-a|pki:1", IssuerCN: "Root CA", ExpiresAt: now.AddDate(0, 0, 3)},
+		{ID: "vault-a|pki:2", IssuerCN: "Root CA", ExpiresAt: now.AddDate(0, 0, 10)},
+		{ID: "vault-a|pki:3", IssuerCN: "Root CA", ExpiresAt: now.AddDate(0, 0, 10), Revoked: true},
+		{ID: "vault-b|pki:4", ExpiresAt: now.AddDate(0, 2, 1)},
+	}
+	registry.MustRegister(collectorFunc(func(ch chan<- prometheus.Metric) {
+		collector.emitForecastMetrics(ch, certificates, now)
+	}))
+
+	labels := func(vaultID, pki, issuer, window string) map[string]string {
+		return map[string]string{"vault_id": vaultID, "pki": pki, "issuer_cn": issuer, "window": window}
+	}
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("vault-a", "pki", "Root CA", "week_1"), 1)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("vault-a", "pki", "Root CA", "week_2"), 1)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("vault-a", "pki", "Root CA", "week_12"), 0)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("vault-a", "pki", "Root CA", "month_1"), 2)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("vault-b", "pki", "unknown", "month_3"), 1)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("__all__", "__all__", "__all__", "week_2"), 1)
+	assertGauge(t, registry, "vcv_certificates_expiring_in_window", labels("__all__", "__all__", "__all__", "month_3"), 1)
+}
+
+// collectorFunc adapts a single emit helper to prometheus.Collector for
+// registry-based assertions.
+type collectorFunc func(ch chan<- prometheus.Metric)
+
+func (f collectorFunc) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(f, ch)
+}
+
+func (f collectorFunc) Collect(ch chan<- prometheus.Metric) {
+	f(ch)
+}
@@ -221,7 +221,138 @@ func TestCollector_ThresholdMetrics(t *testing.T) {
 	mockVault.AssertExpectations(t)
 }
 
-func TestCollector_ZeroExpiresAtExcludedFromBuckets(t *testing.T) {
+func TestCollector_AcknowledgedCertificatesNotExpiringSoon(t *testing.T) {
+	t.Setenv("VCV_METRICS_PER_CERTIFICATE", "false")
+	t.Setenv("VCV_METRICS_ENHANCED", "false")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	certsList := []certs.Certificate{
+		{ID: "vault-a|pki:cert1", CommonName: "cert1", ExpiresAt: now.Add(1 * 24 * time.Hour)},
+		{ID: "vault-a|pki:cert2", CommonName: "cert2", ExpiresAt: now.Add(1 * 24 * time.Hour), Acknowledgement: &certs.Acknowledgement{ID: "ack-1"}},
+	}
+
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	mockVault.On("CheckConnection", mock.Anything).Return(nil)
+
+	registry := prometheus.NewRegistry()
+	collector := NewCertificateCollector(mockVault, map[string]vault.Client{}, config.ExpirationThresholds{Critical: 2, Warning: 10}, config.MetricsConfig{})
+	typed, ok := collector.(*certificateCollector)
+	require.True(t, ok)
+	typed.now = func() time.Time { return now }
+	require.NoError(t, registry.Register(collector))
+
+	testutil.CollectAndCount(collector)
+
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "__all__", "pki": "__all__", "level": "critical"}, 1.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki", "level": "critical"}, 1.0)
+}
+
+func TestCollector_DurationAndPercentThresholdsPerMount(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	certsList := []certs.Certificate{
+		// 72h mesh certificates: only the one with under 10% of its lifetime left is critical.
+		{ID: "vault-a|pki_mesh:1", CommonName: "svc1", CreatedAt: now.Add(-70 * time.Hour), ExpiresAt: now.Add(2 * time.Hour)},
+		{ID: "vault-a|pki_mesh:2", CommonName: "svc2", CreatedAt: now.Add(-12 * time.Hour), ExpiresAt: now.Add(60 * time.Hour)},
+		{ID: "vault-a|pki:3", CommonName: "web", CreatedAt: now.Add(-360 * 24 * time.Hour), ExpiresAt: now.Add(5 * 24 * time.Hour)},
+	}
+
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	mockVault.On("CheckConnection", mock.Anything).Return(nil)
+
+	registry := prometheus.NewRegistry()
+	thresholds := config.ExpirationThresholds{
+		Critical: 7,
+		Warning:  30,
+		Mounts:   map[string]config.ExpirationThresholds{"pki_mesh": {CriticalPercent: 10, WarningDuration: "12h"}},
+	}
+	collector := NewCertificateCollector(mockVault, map[string]vault.Client{}, thresholds, config.MetricsConfig{})
+	typed, ok := collector.(*certificateCollector)
+	require.True(t, ok)
+	typed.now = func() time.Time { return now }
+	require.NoError(t, registry.Register(collector))
+
+	testutil.CollectAndCount(collector)
+
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "__all__", "pki": "__all__", "level": "critical"}, 2.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "__all__", "pki": "__all__", "level": "warning"}, 2.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki_mesh", "level": "critical"}, 1.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki_mesh", "level": "warning"}, 1.0)
+}
+
+func TestCollector_VaultThresholdOverrides(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	certsList := []certs.Certificate{
+		{ID: "vault-a|pki_mtls:1", CommonName: "svc", ExpiresAt: now.Add(5 * 24 * time.Hour)},
+		{ID: "vault-a|pki_public:2", CommonName: "www", ExpiresAt: now.Add(40 * 24 * time.Hour)},
+		{ID: "vault-b|pki_public:3", CommonName: "api", ExpiresAt: now.Add(40 * 24 * time.Hour)},
+	}
+
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	mockVault.On("CheckConnection", mock.Anything).Return(nil)
+
+	registry := prometheus.NewRegistry()
+	vaults := []config.VaultInstance{
+		{
+			ID:        "vault-a",
+			PKIMounts: []string{"pki_mtls", "pki_public"},
+			ExpirationThresholds: &config.ExpirationThresholds{
+				Critical: 14,
+				Warning:  45,
+				Mounts:   map[string]config.ExpirationThresholds{"pki_mtls": {Critical: 3, Warning: 10}},
+			},
+		},
+		{ID: "vault-b", PKIMounts: []string{"pki_public"}},
+	}
+	collector := NewCertificateCollectorWithVaults(mockVault, map[string]vault.Client{}, config.ExpirationThresholds{Critical: 7, Warning: 30}, config.MetricsConfig{}, vaults)
+	typed, ok := collector.(*certificateCollector)
+	require.True(t, ok)
+	typed.now = func() time.Time { return now }
+	require.NoError(t, registry.Register(collector))
+
+	testutil.CollectAndCount(collector)
+
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki_mtls", "level": "warning"}, 1.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki_mtls", "level": "critical"}, 0.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-a", "pki": "pki_public", "level": "warning"}, 1.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "vault-b", "pki": "pki_public", "level": "warning"}, 0.0)
+	assertGauge(t, registry, "vcv_certificates_expiring_soon_count", map[string]string{"vault_id": "__all__", "pki": "__all__", "level": "warning"}, 2.0)
+}
+
+func TestCollector_MountPolicyRollupsAndExclusions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	certsList := []certs.Certificate{
+		{ID: "vault-a|pki_mesh:1", CommonName: "svc", ExpiresAt: now.Add(48 * time.Hour), RollupCount: 40, ExcludeFromPerCertificateMetrics: true},
+		{ID: "vault-a|pki_mesh:2", CommonName: "svc2", ExpiresAt: now.Add(48 * time.Hour), RollupCount: 1, ExcludeFromPerCertificateMetrics: true},
+		{ID: "vault-a|pki:3", CommonName: "web", ExpiresAt: now.Add(90 * 24 * time.Hour)},
+	}
+
+	mockVault := new(vault.MockClient)
+	mockVault.On("ListCertificates", mock.Anything).Return(certsList, nil)
+	mockVault.On("CheckConnection", mock.Anything).Return(nil)
+
+	registry := prometheus.NewRegistry()
+	collector := NewCertificateCollector(mockVault, map[string]vault.Client{}, config.ExpirationThresholds{}, config.MetricsConfig{PerCertificate: true})
+	typed, ok := collector.(*certificateCollector)
+	require.True(t, ok)
+	typed.now = func() time.Time { return now }
+	require.NoError(t, registry.Register(collector))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	perCertificate := 0
+	for _, family := range families {
+		if family.GetName() == "vcv_certificate_expiry_timestamp_seconds" {
+			perCertificate = len(family.GetMetric())
+		}
+	}
+	assert.Equal(t, 1, perCertificate)
+	assertGauge(t, registry, "vcv_certificates_rolled_up", map[string]string{"vault_id": "vault-a", "pki": "pki_mesh"}, 39.0)
+	assertGauge(t, registry, "vcv_certificates_total", map[string]string{"vault_id": "vault-a", "pki": "pki_mesh", "status": "valid"}, 2.0)
+}
+
+func TestCollector_UndatedCertificatesCountAsExpired(t *testing.T) {
 	t.Setenv("VCV_METRICS_PER_CERTIFICATE", "false")
 	t.Setenv("VCV_METRICS_ENHANCED", "true")
 	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -249,9 +380,12 @@ func TestCollector_ZeroExpiresAtExcludedFromBuckets(t *testing.T) {
 	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "7-30d"}, 0.0)
 	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "30-90d"}, 0.0)
 	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "90d+"}, 0.0)
-	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "expired"}, 0.0)
+	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "expired"}, 2.0)
 	assertGauge(t, registry, "vcv_certificates_expiry_bucket", map[string]string{"vault_id": "__all__", "pki": "__all__", "bucket": "revoked"}, 0.0)
-	assertGauge(t, registry, "vcv_certificates_total", map[string]string{"vault_id": "__all__", "pki": "__all__", "status": "valid"}, 3.0)
+	// Same as the UI: a certificate without an expiry date is expired.
+	assertGauge(t, registry, "vcv_certificates_total", map[string]string{"vault_id": "__all__", "pki": "__all__", "status": "valid"}, 1.0)
+	assertGauge(t, registry, "vcv_certificates_total", map[string]string{"vault_id": "__all__", "pki": "__all__", "status": "expired"}, 2.0)
+	assertGauge(t, registry, "vcv_certificates_expired_count", nil, 2.0)
 
 	mockVault.AssertExpectations(t)
 }
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"vcv/internal/probe"
+)
+
+var (
+	discoveryEndpointsDesc = prometheus.NewDesc("vcv_discovery_tls_endpoints", "TLS endpoints found by the last discovery scan, by whether their certificate is managed by the inventory", []string{"managed"}, nil)
+	discoveryUnmanagedDesc = prometheus.NewDesc("vcv_discovery_unmanaged_certificates", "Distinct certificates served by discovered endpoints that are not managed by the inventory", nil, nil)
+	discoveryScannedDesc   = prometheus.NewDesc("vcv_discovery_scanned_endpoints", "Address and port pairs tried by the last discovery scan", nil, nil)
+	discoveryLastRunDesc   = prometheus.NewDesc("vcv_discovery_last_run_timestamp_seconds", "Timestamp of the last completed discovery scan", nil, nil)
+)
+
+// DiscoveryResults is the source of the discovery metrics. Satisfied by
+// *probe.Scanner.
+type DiscoveryResults interface {
+	Endpoints() []probe.Endpoint
+	Scanned() int
+	LastRun() time.Time
+}
+
+type discoveryCollector struct {
+	source DiscoveryResults
+}
+
+// NewDiscoveryCollector returns a Prometheus collector exposing the report
+// of the last discovery scan. It never scans on scrape.
+func NewDiscoveryCollector(source DiscoveryResults) prometheus.Collector {
+	return &discoveryCollector{source: source}
+}
+
+func (collector *discoveryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- discoveryEndpointsDesc
+	ch <- discoveryUnmanagedDesc
+	ch <- discoveryScannedDesc
+	ch <- discoveryLastRunDesc
+}
+
+func (collector *discoveryCollector) Collect(ch chan<- prometheus.Metric) {
+	lastRun := collector.source.LastRun()
+	if lastRun.IsZero() {
+		return
+	}
+	managed, unmanaged := 0, 0
+	fingerprints := make(map[string]struct{})
+	for _, endpoint := range collector.source.Endpoints() {
+		if endpoint.Managed {
+			managed++
+			continue
+		}
+		unmanaged++
+		fingerprints[endpoint.Certificate.FingerprintSHA256] = struct{}{}
+	}
+	ch <- prometheus.MustNewConstMetric(discoveryEndpointsDesc, prometheus.GaugeValue, float64(managed), "true")
+	ch <- prometheus.MustNewConstMetric(discoveryEndpointsDesc, prometheus.GaugeValue, float64(unmanaged), "false")
+	ch <- prometheus.MustNewConstMetric(discoveryUnmanagedDesc, prometheus.GaugeValue, float64(len(fingerprints)))
+	ch <- prometheus.MustNewConstMetric(discoveryScannedDesc, prometheus.GaugeValue, float64(collector.source.Scanned()))
+	ch <- prometheus.MustNewConstMetric(discoveryLastRunDesc, prometheus.GaugeValue, float64(lastRun.Unix()))
+}
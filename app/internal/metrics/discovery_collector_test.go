@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"vcv/internal/probe"
+)
+
+type staticDiscoveryResults struct {
+	endpoints []probe.Endpoint
+	scanned   int
+	lastRun   time.Time
+}
+
+func (s staticDiscoveryResults) Endpoints() []probe.Endpoint { return s.endpoints }
+func (s staticDiscoveryResults) Scanned() int                { return s.scanned }
+func (s staticDiscoveryResults) LastRun() time.Time          { return s.lastRun }
+
+func TestDiscoveryCollector(t *testing.T) {
+	assert.Equal(t, 0, testutil.CollectAndCount(NewDiscoveryCollector(staticDiscoveryResults{})))
+
+	collector := NewDiscoveryCollector(staticDiscoveryResults{
+		scanned: 512,
+		lastRun: time.Unix(1700000000, 0),
+		endpoints: []probe.Endpoint{
+			{Address: "10.0.0.1:443", Managed: true, CertificateID: "v1|pki:0a"},
+			{Address: "10.0.0.2:443", Certificate: probe.PresentedCertificate{FingerprintSHA256: "aa"}},
+			{Address: "10.0.0.2:8443", Certificate: probe.PresentedCertificate{FingerprintSHA256: "aa"}},
+			{Address: "10.0.0.3:443", Certificate: probe.PresentedCertificate{FingerprintSHA256: "bb"}},
+		},
+	})
+
+	expected := `
+# HELP vcv_discovery_last_run_timestamp_seconds Timestamp of the last completed discovery scan
+# TYPE vcv_discovery_last_run_timestamp_seconds gauge
+vcv_discovery_last_run_timestamp_seconds 1.7e+09
+# HELP vcv_discovery_scanned_endpoints Address and port pairs tried by the last discovery scan
+# TYPE vcv_discovery_scanned_endpoints gauge
+vcv_discovery_scanned_endpoints 512
+# HELP vcv_discovery_tls_endpoints TLS endpoints found by the last discovery scan, by whether their certificate is managed by the inventory
+# TYPE vcv_discovery_tls_endpoints gauge
+vcv_discovery_tls_endpoints{managed="false"} 3
+vcv_discovery_tls_endpoints{managed="true"} 1
+# HELP vcv_discovery_unmanaged_certificates Distinct certificates served by discovered endpoints that are not managed by the inventory
+# TYPE vcv_discovery_unmanaged_certificates gauge
+vcv_discovery_unmanaged_certificates 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"vcv/internal/probe"
+)
+
+var (
+	probeStatusDesc  = prometheus.NewDesc("vcv_tls_probe_status", "Status of the last TLS probe of an endpoint, 1 for its current status (ok|outdated|unknown|revoked|expired|error)", []string{"address", "server_name", "status"}, nil)
+	probeExpiryDesc  = prometheus.NewDesc("vcv_tls_probe_certificate_expiry_timestamp_seconds", "Expiration timestamp of the leaf certificate an endpoint serves", []string{"address", "server_name"}, nil)
+	probeLastRunDesc = prometheus.NewDesc("vcv_tls_probe_last_run_timestamp_seconds", "Timestamp of the last completed TLS probe run", nil, nil)
+)
+
+// ProbeResults is the source of the TLS probe metrics. Satisfied by
+// *probe.Prober.
+type ProbeResults interface {
+	Results() []probe.Result
+	LastRun() time.Time
+}
+
+type probeCollector struct {
+	source ProbeResults
+}
+
+// NewProbeCollector returns a Prometheus collector exposing the latest TLS
+// probe results. It reads stored results and never probes on scrape.
+func NewProbeCollector(source ProbeResults) prometheus.Collector {
+	return &probeCollector{source: source}
+}
+
+func (collector *probeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- probeStatusDesc
+	ch <- probeExpiryDesc
+	ch <- probeLastRunDesc
+}
+
+func (collector *probeCollector) Collect(ch chan<- prometheus.Metric) {
+	lastRun := collector.source.LastRun()
+	if lastRun.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(probeLastRunDesc, prometheus.GaugeValue, float64(lastRun.Unix()))
+	for _, result := range collector.source.Results() {
+		ch <- prometheus.MustNewConstMetric(probeStatusDesc, prometheus.GaugeValue, 1, result.Address, result.ServerName, result.Status)
+		if len(result.Chain) > 0 {
+			ch <- prometheus.MustNewConstMetric(probeExpiryDesc, prometheus.GaugeValue, float64(result.Chain[0].NotAfter.Unix()), result.Address, result.ServerName)
+		}
+	}
+}
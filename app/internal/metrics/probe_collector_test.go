@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"vcv/internal/probe"
+)
+
+type staticProbeResults struct {
+	results []probe.Result
+	lastRun time.Time
+}
+
+func (s staticProbeResults) Results() []probe.Result { return s.results }
+func (s staticProbeResults) LastRun() time.Time      { return s.lastRun }
+
+func TestProbeCollector(t *testing.T) {
+	assert.Equal(t, 0, testutil.CollectAndCount(NewProbeCollector(staticProbeResults{})))
+
+	notAfter := time.Unix(1800000000, 0)
+	collector := NewProbeCollector(staticProbeResults{
+		lastRun: time.Unix(1700000000, 0),
+		results: []probe.Result{
+			{Target: probe.Target{Address: "api.example.com:443", ServerName: "api.example.com"}, Status: probe.StatusOutdated, Chain: []probe.PresentedCertificate{{NotAfter: notAfter}}},
+			{Target: probe.Target{Address: "down.example.com:443", ServerName: "down.example.com"}, Status: probe.StatusError},
+		},
+	})
+
+	expected := `
+# HELP vcv_tls_probe_certificate_expiry_timestamp_seconds Expiration timestamp of the leaf certificate an endpoint serves
+# TYPE vcv_tls_probe_certificate_expiry_timestamp_seconds gauge
+vcv_tls_probe_certificate_expiry_timestamp_seconds{address="api.example.com:443",server_name="api.example.com"} 1.8e+09
+# HELP vcv_tls_probe_last_run_timestamp_seconds Timestamp of the last completed TLS probe run
+# TYPE vcv_tls_probe_last_run_timestamp_seconds gauge
+vcv_tls_probe_last_run_timestamp_seconds 1.7e+09
+# HELP vcv_tls_probe_status Status of the last TLS probe of an endpoint, 1 for its current status (ok|outdated|unknown|revoked|expired|error)
+# TYPE vcv_tls_probe_status gauge
+vcv_tls_probe_status{address="api.example.com:443",server_name="api.example.com",status="outdated"} 1
+vcv_tls_probe_status{address="down.example.com:443",server_name="down.example.com",status="error"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
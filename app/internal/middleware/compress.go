@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressConfig holds response compression configuration.
+type CompressConfig struct {
+	// Level is the gzip compression level.
+	Level int
+	// MinSize is the smallest body worth compressing, in bytes. Smaller
+	// bodies are sent as-is.
+	MinSize int
+	// ContentTypes lists the compressible media types. Other responses,
+	// such as images, fonts and archives, are already compressed.
+	ContentTypes       []string
+	ExemptPaths        []string
+	ExemptPathPrefixes []string
+}
+
+// DefaultCompressConfig returns a configuration compressing JSON and text
+// responses of 1 KiB or more.
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		Level:   gzip.DefaultCompression,
+		MinSize: 1024,
+		ContentTypes: []string{
+			"application/atom+xml",
+			"application/json",
+			"application/x-ndjson",
+			"application/javascript",
+			"application/xml",
+			"image/svg+xml",
+			"text/calendar",
+			"text/css",
+			"text/csv",
+			"text/html",
+			"text/javascript",
+			"text/plain",
+		},
+	}
+}
+
+// Compress gzip-encodes compressible responses for clients that accept it.
+// Only gzip is negotiated: zstd needs a third-party encoder, which the
+// module does not depend on. Partial (206) and bodiless responses, and
+// responses that already set Content-Encoding, pass through.
+func Compress(config CompressConfig) func(http.Handler) http.Handler {
+	pool := &sync.Pool{New: func() any {
+		writer, err := gzip.NewWriterLevel(nil, config.Level)
+		if err != nil {
+			writer = gzip.NewWriter(nil)
+		}
+		return writer
+	}}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || shouldSkipCompression(r, config) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, config: config, pool: pool}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+func shouldSkipCompression(r *http.Request, config CompressConfig) bool {
+	if slices.Contains(config.ExemptPaths, r.URL.Path) {
+		return true
+	}
+	for _, prefix := range config.ExemptPathPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either
+// by name or through "*", with a non-zero quality.
+func acceptsGzip(header string) bool {
+	accepted := false
+	for part := range strings.SplitSeq(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		allowed := true
+		if _, quality, found := strings.Cut(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(quality), 64); err == nil && value == 0 {
+				allowed = false
+			}
+		}
+		if coding == "gzip" {
+			return allowed
+		}
+		accepted = allowed
+	}
+	return accepted
+}
+
+// compressWriter buffers the start of a body until it knows whether the
+// response is worth compressing: the status and headers are held back until
+// MinSize bytes arrived, the handler flushed, or the handler returned.
+type compressWriter struct {
+	http.ResponseWriter
+	config  CompressConfig
+	pool    *sync.Pool
+	status  int
+	buffer  []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = code
+	if !cw.compressible() {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buffer = append(cw.buffer, p...)
+		if len(cw.buffer) < cw.config.MinSize {
+			return len(p), nil
+		}
+		if err := cw.startCompression(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.gz != nil {
+		return cw.gz.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered data, compressing it when the response qualifies, so
+// streaming handlers keep working behind the middleware.
+func (cw *compressWriter) Flush() {
+	if cw.status != 0 && !cw.decided {
+		_ = cw.startCompression()
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the response status and headers allow
+// compressing the body.
+func (cw *compressWriter) compressible() bool {
+	switch {
+	case cw.status < http.StatusOK, cw.status == http.StatusNoContent,
+		cw.status == http.StatusPartialContent, cw.status == http.StatusNotModified:
+		return false
+	}
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < cw.config.MinSize {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return slices.Contains(cw.config.ContentTypes, mediaType)
+}
+
+// passThrough sends the held status and the buffer unchanged.
+func (cw *compressWriter) passThrough() {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buffer) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buffer)
+		cw.buffer = nil
+	}
+}
+
+func (cw *compressWriter) startCompression() error {
+	cw.decided = true
+	header := cw.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", "gzip")
+	cw.ResponseWriter.WriteHeader(cw.status)
+	cw.gz = cw.pool.Get().(*gzip.Writer)
+	cw.gz.Reset(cw.ResponseWriter)
+	buffered := cw.buffer
+	cw.buffer = nil
+	_, err := cw.gz.Write(buffered)
+	return err
+}
+
+// close finishes the response once the handler returned.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if cw.status == 0 {
+			return
+		}
+		cw.passThrough()
+		return
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Close()
+		cw.pool.Put(cw.gz)
+		cw.gz = nil
+	}
+}
@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"vcv/internal/middleware"
+)
+
+func serveCompressed(t *testing.T, config middleware.CompressConfig, handler http.HandlerFunc, path, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	middleware.Compress(config)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func writeBody(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	}
+}
+
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("expected gzip body, got %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to decode gzip body: %v", err)
+	}
+	return decoded
+}
+
+func TestCompress_GzipsJSON(t *testing.T) {
+	body := []byte(`{"certificates":[` + strings.Repeat(`{"id":"pki:aa"},`, 200) + `{}]}`)
+	rec := serveCompressed(t, middleware.DefaultCompressConfig(), writeBody("application/json; charset=utf-8", body), "/api/v1/certs", "br, gzip;q=0.8")
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("Content-Length") != "" {
+		t.Fatalf("expected Content-Length to be dropped")
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+	}
+	if rec.Body.Len() >= len(body) {
+		t.Fatalf("expected a smaller body, got %d bytes for %d", rec.Body.Len(), len(body))
+	}
+	if decoded := gunzip(t, rec.Body.Bytes()); !bytes.Equal(decoded, body) {
+		t.Fatalf("decoded body differs from the original")
+	}
+}
+
+func TestCompress_PassesThrough(t *testing.T) {
+	large := bytes.Repeat([]byte("a"), 4096)
+	config := middleware.DefaultCompressConfig()
+	config.ExemptPaths = []string{"/metrics"}
+	config.ExemptPathPrefixes = []string{"/raw/"}
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		path           string
+		acceptEncoding string
+	}{
+		{name: "no accept-encoding", handler: writeBody("application/json", large), path: "/api/v1/certs"},
+		{name: "gzip refused", handler: writeBody("application/json", large), path: "/api/v1/certs", acceptEncoding: "gzip;q=0, *"},
+		{name: "unsupported coding only", handler: writeBody("application/json", large), path: "/api/v1/certs", acceptEncoding: "zstd, br"},
+		{name: "small body", handler: writeBody("application/json", []byte(`{}`)), path: "/api/v1/certs", acceptEncoding: "gzip"},
+		{name: "compressed asset", handler: writeBody("font/woff2", large), path: "/assets/app.woff2", acceptEncoding: "gzip"},
+		{name: "image", handler: writeBody("image/png", large), path: "/favicon.png", acceptEncoding: "gzip"},
+		{name: "exempt path", handler: writeBody("text/plain", large), path: "/metrics", acceptEncoding: "gzip"},
+		{name: "exempt prefix", handler: writeBody("text/plain", large), path: "/raw/file", acceptEncoding: "gzip"},
+		{name: "already encoded", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			writeBody("application/json", large)(w, r)
+		}, path: "/api/v1/certs", acceptEncoding: "gzip"},
+		{name: "partial content", handler: func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(large)
+		}, path: "/assets/app.js", acceptEncoding: "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCompressed(t, config, tt.handler, tt.path, tt.acceptEncoding)
+			want := httptest.NewRecorder()
+			tt.handler(want, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Header().Get("Content-Encoding") != want.Header().Get("Content-Encoding") {
+				t.Fatalf("expected Content-Encoding %q, got %q", want.Header().Get("Content-Encoding"), rec.Header().Get("Content-Encoding"))
+			}
+			if !bytes.Equal(rec.Body.Bytes(), want.Body.Bytes()) {
+				t.Fatalf("expected the original body, got %d bytes", rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestCompress_NotModified(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `W/"abc"`)
+		w.WriteHeader(http.StatusNotModified)
+	}
+	rec := serveCompressed(t, middleware.DefaultCompressConfig(), handler, "/api/v1/certs", "gzip")
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 0 {
+		t.Fatalf("expected an empty, unencoded 304")
+	}
+}
+
+func TestCompress_FlushStartsStream(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(" second"))
+	}
+	rec := serveCompressed(t, middleware.DefaultCompressConfig(), handler, "/stream", "gzip")
+	if !rec.Flushed {
+		t.Fatalf("expected the flush to reach the underlying writer")
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a flushed compressible response to be gzipped")
+	}
+	if decoded := gunzip(t, rec.Body.Bytes()); string(decoded) != "first second" {
+		t.Fatalf("unexpected decoded body %q", decoded)
+	}
+}
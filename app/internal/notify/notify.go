@@ -1,7 +1,7 @@
 // Package notify delivers outbound webhook alerts when certificate expiry
 // crosses into the warning or critical threshold, independent of anyone
-// having the dashboard open. See internal/certs.CountExpiring for the
-// threshold math (shared with the Prometheus collector).
+// having the dashboard open. The expiring counts come from internal/stats,
+// shared with /api/stats and the Prometheus collector.
 package notify
 
 import (
@@ -15,9 +15,12 @@ import (
 	"sync"
 	"time"
 
+	"vcv/internal/certquery"
 	"vcv/internal/certs"
 	"vcv/internal/config"
 	"vcv/internal/logger"
+	"vcv/internal/probe"
+	"vcv/internal/stats"
 )
 
 // CertLister lists all certificates the notifier should evaluate. Satisfied
@@ -82,6 +85,8 @@ type Notifier struct {
 
 	mu       sync.Mutex
 	lastTier tier
+	// probeStatus is the last status notified per probed endpoint.
+	probeStatus map[string]string
 }
 
 // New builds a Notifier. certLister and settingsLoader are read on every
@@ -117,7 +122,20 @@ func (n *Notifier) Check(ctx context.Context) {
 	}
 
 	thresholds := settings.ExpirationThresholds
-	warning, critical := certs.CountExpiring(certificates, thresholds.Warning, thresholds.Critical, n.now())
+	expiry, err := certs.NewExpiryThresholds(thresholds, settings.AllVaults)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("notify: invalid expiration thresholds")
+		return
+	}
+	rule, err := certquery.Parse(settings.Notifications.Query)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("notify: invalid notification query")
+		return
+	}
+	now := n.now()
+	certificates = rule.Filter(certificates, certquery.Env{Now: now, Thresholds: expiry})
+	expiring := stats.Compute(certificates, expiry, now).Expiring
+	warning, critical := expiring.Warning, expiring.Critical
 	current := currentTier(warning, critical)
 
 	n.mu.Lock()
@@ -131,7 +149,7 @@ func (n *Notifier) Check(ctx context.Context) {
 		return
 	}
 
-	if deliverErr := n.deliver(ctx, webhookURL, current, warning, critical, thresholds); deliverErr != nil {
+	if deliverErr := n.deliver(ctx, webhookURL, current, warning, critical, thresholds, expiry, rule.String()); deliverErr != nil {
 		logger.Get().Warn().Err(deliverErr).Str("tier", current.String()).Msg("notify: webhook delivery failed, will retry next check")
 		return
 	}
@@ -146,26 +164,58 @@ type webhookPayload struct {
 	WarningCount  int               `json:"warning_count"`
 	CriticalCount int               `json:"critical_count"`
 	Thresholds    webhookThresholds `json:"thresholds"`
+	// Query is the notification query the counts were restricted to.
+	Query string `json:"query,omitempty"`
 }
 
 type webhookThresholds struct {
-	WarningDays  int `json:"warning_days"`
-	CriticalDays int `json:"critical_days"`
+	WarningDays      int     `json:"warning_days"`
+	CriticalDays     int     `json:"critical_days"`
+	WarningDuration  string  `json:"warning_duration,omitempty"`
+	CriticalDuration string  `json:"critical_duration,omitempty"`
+	WarningPercent   float64 `json:"warning_percent,omitempty"`
+	CriticalPercent  float64 `json:"critical_percent,omitempty"`
+	MountOverrides   bool    `json:"mount_overrides,omitempty"`
 }
 
-func (n *Notifier) deliver(ctx context.Context, webhookURL string, current tier, warning, critical int, thresholds config.ExpirationThresholds) error {
-	count, days := warning, thresholds.Warning
+func (n *Notifier) deliver(ctx context.Context, webhookURL string, current tier, warning, critical int, thresholds config.ExpirationThresholds, expiry *certs.ExpiryThresholds, query string) error {
+	count := warning
 	if current == tierCritical {
-		count, days = critical, thresholds.Critical
+		count = critical
+	}
+	within := expiry.Global().Describe(current.String())
+	if within == "" {
+		within = "the configured window"
+	}
+	text := fmt.Sprintf("%d certificate(s) expiring within %s (%s)", count, within, current)
+	if expiry.HasOverrides() {
+		text += ", per-mount thresholds apply"
+	}
+	if query != "" {
+		text += fmt.Sprintf(" matching %q", query)
 	}
 	payload := webhookPayload{
-		Text:          fmt.Sprintf("%d certificate(s) expiring within %d days or fewer (%s)", count, days, current),
+		Text:          text,
 		Tier:          current.String(),
 		WarningCount:  warning,
 		CriticalCount: critical,
-		Thresholds:    webhookThresholds{WarningDays: thresholds.Warning, CriticalDays: thresholds.Critical},
+		Thresholds: webhookThresholds{
+			WarningDays:      thresholds.Warning,
+			CriticalDays:     thresholds.Critical,
+			WarningDuration:  thresholds.WarningDuration,
+			CriticalDuration: thresholds.CriticalDuration,
+			WarningPercent:   thresholds.WarningPercent,
+			CriticalPercent:  thresholds.CriticalPercent,
+			MountOverrides:   expiry.HasOverrides(),
+		},
+		Query: query,
 	}
 
+	return n.post(ctx, webhookURL, payload)
+}
+
+// post delivers one JSON webhook.
+func (n *Notifier) post(ctx context.Context, webhookURL string, payload any) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal webhook payload: %w", err)
@@ -187,3 +237,79 @@ func (n *Notifier) deliver(ctx context.Context, webhookURL string, current tier,
 	}
 	return nil
 }
+
+// alertingProbeStatuses are the probe statuses that mean the endpoint
+// serves the wrong certificate. A failed handshake is left to uptime
+// monitoring.
+var alertingProbeStatuses = map[string]bool{
+	probe.StatusOutdated: true,
+	probe.StatusUnknown:  true,
+	probe.StatusRevoked:  true,
+	probe.StatusExpired:  true,
+}
+
+type probeWebhookPayload struct {
+	Text   string       `json:"text"`
+	Event  string       `json:"event"`
+	Probes []probeAlert `json:"probes"`
+}
+
+type probeAlert struct {
+	Address       string `json:"address"`
+	ServerName    string `json:"server_name,omitempty"`
+	Status        string `json:"status"`
+	CertificateID string `json:"certificate_id,omitempty"`
+	CurrentID     string `json:"current_id,omitempty"`
+}
+
+// CheckProbes delivers one webhook listing the endpoints whose probe status
+// turned outdated, unknown, revoked or expired since the last delivery. An
+// endpoint is reported again only when its status changes; one that
+// recovers, or fails its handshake, is forgotten. Failures are logged and
+// swallowed like in Check.
+func (n *Notifier) CheckProbes(ctx context.Context, results []probe.Result) {
+	settings, err := n.settings()
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("notify: failed to load settings")
+		return
+	}
+	webhookURL := strings.TrimSpace(settings.Notifications.WebhookURL)
+	if webhookURL == "" {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	next := make(map[string]string)
+	var alerts []probeAlert
+	for _, result := range results {
+		if !alertingProbeStatuses[result.Status] {
+			continue
+		}
+		key := result.Address + "|" + result.ServerName
+		next[key] = result.Status
+		if n.probeStatus[key] == result.Status {
+			continue
+		}
+		alerts = append(alerts, probeAlert{
+			Address:       result.Address,
+			ServerName:    result.ServerName,
+			Status:        result.Status,
+			CertificateID: result.CertificateID,
+			CurrentID:     result.CurrentID,
+		})
+	}
+	if len(alerts) > 0 {
+		payload := probeWebhookPayload{
+			Text:   fmt.Sprintf("%d TLS endpoint(s) serving an outdated, unknown, revoked or expired certificate", len(alerts)),
+			Event:  "tls_probe",
+			Probes: alerts,
+		}
+		if deliverErr := n.post(ctx, webhookURL, payload); deliverErr != nil {
+			logger.Get().Warn().Err(deliverErr).Int("endpoints", len(alerts)).Msg("notify: probe webhook delivery failed, will retry next check")
+			return
+		}
+	}
+	n.probeStatus = next
+}
@@ -14,6 +14,7 @@ import (
 
 	"vcv/internal/certs"
 	"vcv/internal/config"
+	"vcv/internal/probe"
 )
 
 type fakeCertLister struct {
@@ -73,6 +74,94 @@ func TestNotifier_EscalatesFromNoneToWarning_Delivers(t *testing.T) {
 	assert.Contains(t, received.Text, "1 certificate(s)")
 }
 
+func TestNotifier_DurationThresholds_DescribeWindow(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	soon := certs.Certificate{ID: "pki_mesh:a", ExpiresAt: time.Now().Add(3 * time.Hour)}
+	lister := fakeCertLister{certificates: []certs.Certificate{soon}}
+	settings := func() (config.Config, error) {
+		cfg := settingsWithWebhook(server.URL)
+		cfg.ExpirationThresholds.Mounts = map[string]config.ExpirationThresholds{"pki_mesh": {CriticalDuration: "6h", WarningDuration: "24h"}}
+		cfg.ExpirationThresholds.CriticalDuration = "12h"
+		return cfg, nil
+	}
+	n := New(lister, settings)
+
+	n.Check(context.Background())
+
+	assert.Equal(t, "critical", received.Tier)
+	assert.Equal(t, "12h", received.Thresholds.CriticalDuration)
+	assert.True(t, received.Thresholds.MountOverrides)
+	assert.Contains(t, received.Text, "within 12h0m0s (critical), per-mount thresholds apply")
+}
+
+func TestNotifier_Query_RestrictsCountedCertificates(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	lister := fakeCertLister{certificates: []certs.Certificate{
+		certExpiringIn("v1|pki_prod:a", 20),
+		certExpiringIn("v1|pki_dev:b", 1),
+	}}
+	settings := func() (config.Config, error) {
+		cfg := settingsWithWebhook(server.URL)
+		cfg.Notifications.Query = "mount:pki_prod"
+		return cfg, nil
+	}
+	New(lister, settings).Check(context.Background())
+
+	assert.Equal(t, "warning", received.Tier)
+	assert.Equal(t, 1, received.WarningCount)
+	assert.Equal(t, 0, received.CriticalCount)
+	assert.Equal(t, "mount:pki_prod", received.Query)
+	assert.Contains(t, received.Text, `matching "mount:pki_prod"`)
+}
+
+func TestNotifier_InvalidQuery_NoOp(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	lister := fakeCertLister{certificates: []certs.Certificate{certExpiringIn("a", 1)}}
+	settings := func() (config.Config, error) {
+		cfg := settingsWithWebhook(server.URL)
+		cfg.Notifications.Query = "color:red"
+		return cfg, nil
+	}
+	New(lister, settings).Check(context.Background())
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestNotifier_InvalidThresholds_NoOp(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	lister := fakeCertLister{certificates: []certs.Certificate{certExpiringIn("a", 1)}}
+	settings := func() (config.Config, error) {
+		cfg := settingsWithWebhook(server.URL)
+		cfg.ExpirationThresholds.WarningDuration = "soon"
+		return cfg, nil
+	}
+	New(lister, settings).Check(context.Background())
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
 func TestNotifier_SameTierTwice_DeliversOnce(t *testing.T) {
 	var callCount atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -182,13 +271,56 @@ func TestNotifier_DeliveryErrorNeverLeaksURL(t *testing.T) {
 	settings := func() (config.Config, error) { return settingsWithWebhook(secretURL), nil }
 	n := New(lister, settings)
 
-	err := n.deliver(context.Background(), secretURL, tierWarning, 1, 0, config.ExpirationThresholds{Warning: 30, Critical: 7})
+	err := n.deliver(context.Background(), secretURL, tierWarning, 1, 0, config.ExpirationThresholds{Warning: 30, Critical: 7}, nil, "")
 
 	require.Error(t, err)
 	assert.NotContains(t, err.Error(), "SECRET")
 	assert.NotContains(t, err.Error(), secretURL)
 }
 
+func TestNotifier_CheckProbes_ReportsStatusChangesOnce(t *testing.T) {
+	var received []probeWebhookPayload
+	var failNext atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failNext.Swap(false) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var payload probeWebhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received = append(received, payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	settings := func() (config.Config, error) { return settingsWithWebhook(server.URL), nil }
+	n := New(fakeCertLister{}, settings)
+	result := func(address, status string) probe.Result {
+		return probe.Result{Target: probe.Target{Address: address}, Status: status}
+	}
+
+	n.CheckProbes(context.Background(), []probe.Result{
+		result("a:443", probe.StatusOutdated),
+		result("b:443", probe.StatusOK),
+		result("c:443", probe.StatusError),
+	})
+	n.CheckProbes(context.Background(), []probe.Result{result("a:443", probe.StatusOutdated)})
+	failNext.Store(true)
+	n.CheckProbes(context.Background(), []probe.Result{result("a:443", probe.StatusExpired)})
+	n.CheckProbes(context.Background(), []probe.Result{result("a:443", probe.StatusExpired)})
+	n.CheckProbes(context.Background(), []probe.Result{result("a:443", probe.StatusOK)})
+	n.CheckProbes(context.Background(), []probe.Result{result("a:443", probe.StatusExpired)})
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "tls_probe", received[0].Event)
+	assert.Equal(t, []probeAlert{{Address: "a:443", Status: probe.StatusOutdated}}, received[0].Probes)
+	assert.Contains(t, received[0].Text, "1 TLS endpoint(s)")
+	// The failed delivery of the expired status is retried.
+	assert.Equal(t, probe.StatusExpired, received[1].Probes[0].Status)
+	// Recovering resets the endpoint, so expiring again is reported again.
+	assert.Equal(t, probe.StatusExpired, received[2].Probes[0].Status)
+}
+
 type mutableCertLister struct {
 	certificates []certs.Certificate
 }
@@ -0,0 +1,356 @@
+// Package openapi builds OpenAPI 3.1 documents whose schemas are derived from
+// Go types by reflection, so the published contract follows the structs the
+// handlers actually encode.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document. Build it with New and Add; schemas
+// referenced by operations land in Components.Schemas.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	names      map[reflect.Type]string
+	types      map[string]reflect.Type
+}
+
+// Info is the document metadata.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps a lower-case HTTP method to its operation.
+type PathItem map[string]*Operation
+
+// Operation describes one method on one path.
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path, query, header or cookie parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's request payload.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one status code of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas and security schemes.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how an operation authenticates.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is the subset of JSON Schema 2020-12 the generator emits.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// New returns an empty document.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+		names:      make(map[reflect.Type]string),
+		types:      make(map[string]reflect.Type),
+	}
+}
+
+// Add registers op for method and path. Paths use chi's "{param}" syntax,
+// which is also OpenAPI's.
+func (d *Document) Add(method, path string, op Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	if op.Responses == nil {
+		op.Responses = make(map[string]Response)
+	}
+	item[strings.ToLower(method)] = &op
+}
+
+// Operations lists every documented "METHOD path" pair, sorted.
+func (d *Document) Operations() []string {
+	operations := make([]string, 0, len(d.Paths))
+	for path, item := range d.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// JSON returns an application/json content map for the Go value's type.
+func (d *Document) JSON(v any) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: d.Schema(v)}}
+}
+
+// Schema returns the schema of the Go value's type. Named struct types are
+// stored once under Components.Schemas and referenced.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaFor(reflect.TypeOf(v))
+}
+
+// String is a plain string schema, for parameters.
+func String(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+// Integer is an integer schema, for parameters.
+func Integer(description string) *Schema {
+	return &Schema{Type: "integer", Description: description}
+}
+
+// Enum is a string schema restricted to values.
+func Enum(description string, values ...string) *Schema {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+	return &Schema{Type: "string", Description: description, Enum: enum}
+}
+
+// Binary is the schema of a raw, non-JSON body.
+func Binary() *Schema {
+	return &Schema{Type: "string", Format: "binary"}
+}
+
+// Resolve follows a $ref to its component schema.
+func (d *Document) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Description: "nanoseconds"}
+	case t == rawJSONType:
+		return &Schema{}
+	case t.Kind() != reflect.Struct && t.Kind() != reflect.Slice && t.Kind() != reflect.Map &&
+		t.Implements(marshalerType):
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		return d.ref(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (d *Document) ref(t reflect.Type) *Schema {
+	name, ok := d.names[t]
+	if !ok {
+		name = componentName(t)
+		if other, taken := d.types[name]; taken && other != t {
+			name = componentName(t) + exportName(pkgName(t))
+		}
+		d.names[t] = name
+		d.types[name] = t
+		d.Components.Schemas[name] = &Schema{}
+		*d.Components.Schemas[name] = *d.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// structSchema follows encoding/json: exported fields, json tag names,
+// embedded structs flattened. Fields without omitempty are required.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	d.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			embedded := fieldType
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				d.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := d.schemaFor(fieldType)
+		if strings.Contains(options, "string") {
+			property = &Schema{Type: "string"}
+		}
+		omitted := strings.Contains(options, "omitempty") || strings.Contains(options, "omitzero")
+		if !omitted {
+			schema.Required = append(schema.Required, name)
+			if fieldType.Kind() == reflect.Pointer {
+				property = nullable(property)
+			}
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// nullable lets a schema also accept null, which is how encoding/json writes
+// a nil pointer field without omitempty.
+func nullable(schema *Schema) *Schema {
+	if typeName, ok := schema.Type.(string); ok && schema.Ref == "" {
+		copied := *schema
+		copied.Type = []any{typeName, "null"}
+		return &copied
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return exportName(name)
+}
+
+func exportName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func pkgName(t reflect.Type) string {
+	path := t.PkgPath()
+	return path[strings.LastIndexByte(path, '/')+1:]
+}
+
+// Handler serves the document as JSON. The document is encoded once.
+func Handler(d *Document) http.HandlerFunc {
+	payload, err := json.MarshalIndent(d, "", "  ")
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payload)
+	}
+}
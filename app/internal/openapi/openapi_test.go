@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Name      string            `json:"name"`
+	Tags      []string          `json:"tags,omitempty"`
+	Enabled   *bool             `json:"enabled"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Child     *testItem         `json:"child,omitempty"`
+	hidden    string
+	Skipped   string `json:"-"`
+}
+
+func TestSchema_FollowsEncodingJSON(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	ref := doc.Schema([]testItem{})
+	if ref.Type != "array" || ref.Items.Ref != "#/components/schemas/TestItem" {
+		t.Fatalf("unexpected array schema: %+v", ref)
+	}
+	item := doc.Components.Schemas["TestItem"]
+	if item == nil {
+		t.Fatalf("expected TestItem component")
+	}
+	for _, name := range []string{"id", "name", "tags", "enabled", "createdAt", "labels", "child"} {
+		if item.Properties[name] == nil {
+			t.Fatalf("expected property %q", name)
+		}
+	}
+	if len(item.Properties) != 7 {
+		t.Fatalf("expected 7 properties, got %d", len(item.Properties))
+	}
+	want := []string{"createdAt", "enabled", "id", "name"}
+	if len(item.Required) != len(want) {
+		t.Fatalf("expected required %v, got %v", want, item.Required)
+	}
+	for i := range want {
+		if item.Required[i] != want[i] {
+			t.Fatalf("expected required %v, got %v", want, item.Required)
+		}
+	}
+	if item.Properties["createdAt"].Format != "date-time" {
+		t.Fatalf("expected date-time format, got %+v", item.Properties["createdAt"])
+	}
+	if item.Properties["child"].Ref != "#/components/schemas/TestItem" {
+		t.Fatalf("expected recursive reference, got %+v", item.Properties["child"])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	schema := doc.Schema(testItem{})
+	tests := []struct {
+		name    string
+		payload string
+		valid   bool
+	}{
+		{name: "encoded value", payload: mustEncode(t, testItem{testBase: testBase{ID: "a"}, Name: "n"}), valid: true},
+		{name: "null pointer", payload: `{"id":"a","name":"n","enabled":null,"createdAt":"2024-01-01T00:00:00Z"}`, valid: true},
+		{name: "missing required", payload: `{"id":"a","enabled":true,"createdAt":"2024-01-01T00:00:00Z"}`},
+		{name: "undocumented property", payload: `{"id":"a","name":"n","enabled":true,"createdAt":"x","extra":1}`},
+		{name: "wrong type", payload: `{"id":1,"name":"n","enabled":true,"createdAt":"x"}`},
+		{name: "nested wrong type", payload: `{"id":"a","name":"n","enabled":true,"createdAt":"x","child":{"id":"b","name":"m","enabled":"yes","createdAt":"x"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(tt.payload), &value); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			err := doc.Validate(schema, value)
+			if tt.valid && err != nil {
+				t.Fatalf("expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatalf("expected validation error")
+			}
+		})
+	}
+}
+
+func mustEncode(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks a decoded JSON value (from json.Unmarshal into any)
+// against schema. Objects with declared properties reject unknown keys, so
+// a field added to a Go type but not to the document, or the reverse, is
+// reported. Go encodes nil slices and maps as null, which arrays and maps
+// accept.
+func (d *Document) Validate(schema *Schema, value any) error {
+	return d.validate(schema, value, "$")
+}
+
+func (d *Document) validate(schema *Schema, value any, at string) error {
+	schema = d.Resolve(schema)
+	if schema == nil {
+		return fmt.Errorf("%s: unresolved schema reference", at)
+	}
+	if len(schema.AnyOf) > 0 {
+		for _, candidate := range schema.AnyOf {
+			if d.validate(candidate, value, at) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %T matches none of the allowed schemas", at, value)
+	}
+	typeName, _ := schema.Type.(string)
+	if types, ok := schema.Type.([]any); ok {
+		if value == nil && enumContains(types, "null") {
+			return nil
+		}
+		typeName, _ = types[0].(string)
+	}
+	if value == nil {
+		if typeName == "" || typeName == "null" || typeName == "array" || (typeName == "object" && schema.AdditionalProperties != nil) {
+			return nil
+		}
+		return fmt.Errorf("%s: null, want %s", at, typeName)
+	}
+	switch typeName {
+	case "":
+		return nil
+	case "null":
+		return fmt.Errorf("%s: %T, want null", at, value)
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %T, want string", at, value)
+		}
+		if len(schema.Enum) > 0 && !enumContains(schema.Enum, text) {
+			return fmt.Errorf("%s: %q is not one of %v", at, text, schema.Enum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %T, want boolean", at, value)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: %T, want %s", at, value, typeName)
+		}
+		if typeName == "integer" && number != float64(int64(number)) {
+			return fmt.Errorf("%s: %v, want integer", at, number)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %T, want array", at, value)
+		}
+		for i, item := range items {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %T, want object", at, value)
+		}
+		return d.validateObject(schema, object, at)
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", at, typeName)
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]any, at string) error {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", at, name)
+		}
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, declared := schema.Properties[key]
+		if !declared {
+			property = schema.AdditionalProperties
+		}
+		if property == nil {
+			if len(schema.Properties) == 0 {
+				continue
+			}
+			return fmt.Errorf("%s: undocumented property %q", at, key)
+		}
+		if err := d.validate(property, object[key], at+"."+strings.ReplaceAll(key, ".", "\\.")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func enumContains(values []any, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
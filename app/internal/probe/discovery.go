@@ -0,0 +1,272 @@
+package probe
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/logger"
+	"vcv/internal/vault"
+)
+
+const (
+	defaultDiscoveryInterval    = 24 * time.Hour
+	defaultDiscoveryTimeout     = 2 * time.Second
+	defaultDiscoveryConcurrency = 32
+	maxDiscoveryConcurrency     = 512
+	// maxDiscoveryAddresses bounds a scan to the size of a /16.
+	maxDiscoveryAddresses = 1 << 16
+)
+
+// Endpoint is a TLS service found by a discovery scan. Managed is true when
+// its leaf is in the inventory (CertificateID) or was signed by an issuer of
+// a configured mount (IssuedBy).
+type Endpoint struct {
+	Address       string               `json:"address"`
+	CheckedAt     time.Time            `json:"checkedAt"`
+	Certificate   PresentedCertificate `json:"certificate"`
+	Managed       bool                 `json:"managed"`
+	CertificateID string               `json:"certificateId,omitempty"`
+	IssuedBy      string               `json:"issuedBy,omitempty"`
+}
+
+// Scanner connects to every address of the configured ranges on every
+// configured port and keeps the TLS endpoints of the last scan. A nil
+// *Scanner is a disabled scanner.
+type Scanner struct {
+	client      vault.Client
+	prefixes    []netip.Prefix
+	ports       []int
+	concurrency int
+	interval    time.Duration
+	timeout     time.Duration
+	dial        func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error)
+	now         func() time.Time
+
+	mu        sync.RWMutex
+	endpoints []Endpoint
+	scanned   int
+	lastRun   time.Time
+}
+
+// NewScanner validates settings and builds a Scanner. It returns nil when
+// discovery is disabled.
+func NewScanner(settings config.DiscoverySettings, client vault.Client) (*Scanner, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	scanner := &Scanner{client: client, concurrency: defaultDiscoveryConcurrency, now: time.Now}
+	scanner.dial = func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+		return dialTLS(ctx, address, serverName, scanner.timeout)
+	}
+	var err error
+	if scanner.interval, err = parseDuration("discovery", "interval", settings.Interval, defaultDiscoveryInterval); err != nil {
+		return nil, err
+	}
+	if scanner.timeout, err = parseDuration("discovery", "timeout", settings.Timeout, defaultDiscoveryTimeout); err != nil {
+		return nil, err
+	}
+	if settings.Concurrency < 0 || settings.Concurrency > maxDiscoveryConcurrency {
+		return nil, fmt.Errorf("discovery: concurrency must be between 1 and %d", maxDiscoveryConcurrency)
+	} else if settings.Concurrency > 0 {
+		scanner.concurrency = settings.Concurrency
+	}
+	total := 0
+	for _, cidr := range settings.CIDRs {
+		prefix, parseErr := parsePrefix(cidr)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		hostBits := prefix.Addr().BitLen() - prefix.Bits()
+		if hostBits > 16 || total+(1<<hostBits) > maxDiscoveryAddresses {
+			return nil, fmt.Errorf("discovery: cidrs cover more than %d addresses", maxDiscoveryAddresses)
+		}
+		total += 1 << hostBits
+		scanner.prefixes = append(scanner.prefixes, prefix)
+	}
+	if len(scanner.prefixes) == 0 {
+		return nil, fmt.Errorf("discovery: enabled without cidrs")
+	}
+	for _, port := range settings.Ports {
+		if port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("discovery: port %d out of range", port)
+		}
+		scanner.ports = append(scanner.ports, port)
+	}
+	if len(scanner.ports) == 0 {
+		scanner.ports = []int{defaultSANPort}
+	}
+	return scanner, nil
+}
+
+// list prefers the per-vault envelope so a vault that failed to list is
+// told apart from one without certificates.
+func (s *Scanner) list(ctx context.Context) ([]certs.Certificate, []vault.VaultError, error) {
+	if envelope, ok := s.client.(vault.CertificatesEnvelopeLister); ok {
+		certificates, vaultErrors := envelope.ListCertificatesEnvelope(ctx)
+		return certificates, vaultErrors, nil
+	}
+	certificates, err := s.client.ListCertificates(ctx)
+	return certificates, nil, err
+}
+
+// parsePrefix accepts a CIDR or a single address.
+func parsePrefix(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("discovery: invalid cidr %q", value)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Interval is how often Run should be called. Zero for a nil Scanner.
+func (s *Scanner) Interval() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.interval
+}
+
+// Endpoints returns the TLS endpoints found by the last scan, ordered by
+// address and port.
+func (s *Scanner) Endpoints() []Endpoint {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Endpoint(nil), s.endpoints...)
+}
+
+// Scanned is the number of address and port pairs the last scan tried.
+func (s *Scanner) Scanned() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.scanned
+}
+
+// LastRun is when the last scan finished; zero before the first one.
+func (s *Scanner) LastRun() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastRun
+}
+
+// Run scans the configured ranges and correlates every presented leaf with
+// the inventory and the issuers of the configured mounts. An inventory
+// failure, even of a single vault, is logged and keeps the previous report:
+// the endpoints of a missing vault would otherwise read as unmanaged. A
+// cancelled scan is discarded.
+func (s *Scanner) Run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	inventory, vaultErrors, err := s.list(ctx)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("discovery: failed to list certificates")
+		return
+	}
+	if len(vaultErrors) > 0 {
+		logger.Get().Warn().Int("failed_vaults", len(vaultErrors)).Msg("discovery: vaults failed to list, keeping the previous report")
+		return
+	}
+	issuers, err := vault.MountIssuers(ctx, s.client)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("discovery: failed to read mount issuers, keeping the previous report")
+		return
+	}
+	started := s.now()
+
+	var (
+		mu        sync.Mutex
+		endpoints []Endpoint
+		wg        sync.WaitGroup
+	)
+	work := make(chan netip.AddrPort)
+	for range s.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range work {
+				chain, dialErr := s.dial(ctx, target.String(), "")
+				if dialErr != nil {
+					continue
+				}
+				endpoint := Endpoint{Address: target.String(), CheckedAt: s.now(), Certificate: present(chain[0])}
+				if served := matchInventory(chain[0], inventory); served != nil {
+					endpoint.CertificateID = served.ID
+				}
+				if keys := vault.IssuedBy(chain[0], issuers); len(keys) > 0 {
+					sort.Strings(keys)
+					endpoint.IssuedBy = keys[0]
+				}
+				endpoint.Managed = endpoint.CertificateID != "" || endpoint.IssuedBy != ""
+				mu.Lock()
+				endpoints = append(endpoints, endpoint)
+				mu.Unlock()
+			}
+		}()
+	}
+	scanned := 0
+	seen := make(map[netip.Addr]struct{})
+feed:
+	for _, prefix := range s.prefixes {
+		for addr := prefix.Addr(); addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+			if _, ok := seen[addr]; ok {
+				continue
+			}
+			seen[addr] = struct{}{}
+			for _, port := range s.ports {
+				select {
+				case work <- netip.AddrPortFrom(addr, uint16(port)):
+					scanned++
+				case <-ctx.Done():
+					break feed
+				}
+			}
+		}
+	}
+	close(work)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return netip.MustParseAddrPort(endpoints[i].Address).Compare(netip.MustParseAddrPort(endpoints[j].Address)) < 0
+	})
+	s.mu.Lock()
+	s.endpoints = endpoints
+	s.scanned = scanned
+	s.lastRun = s.now()
+	s.mu.Unlock()
+	unmanaged := 0
+	for _, endpoint := range endpoints {
+		if !endpoint.Managed {
+			unmanaged++
+		}
+	}
+	logger.Get().Info().
+		Int("scanned", scanned).
+		Int("tls_endpoints", len(endpoints)).
+		Int("unmanaged", unmanaged).
+		Dur("duration", s.now().Sub(started)).
+		Msg("discovery scan finished")
+}
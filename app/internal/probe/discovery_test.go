@@ -0,0 +1,182 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/vault"
+)
+
+// mountsClient reports configured mounts like the multi-vault client.
+type mountsClient struct {
+	vault.MockClient
+	mounts []string
+}
+
+func (c *mountsClient) Mounts() []string {
+	return c.mounts
+}
+
+func TestNewScanner(t *testing.T) {
+	scanner, err := NewScanner(config.DiscoverySettings{CIDRs: []string{"10.0.0.0/24"}}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, scanner)
+	assert.Nil(t, scanner.Endpoints())
+	assert.Zero(t, scanner.Scanned())
+
+	scanner, err = NewScanner(config.DiscoverySettings{Enabled: true, CIDRs: []string{"10.0.0.7/24", "10.1.0.5", "fd00::/120"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24"), netip.MustParsePrefix("10.1.0.5/32"), netip.MustParsePrefix("fd00::/120")}, scanner.prefixes)
+	assert.Equal(t, []int{443}, scanner.ports)
+	assert.Equal(t, 24*time.Hour, scanner.Interval())
+	assert.Equal(t, 32, scanner.concurrency)
+
+	invalid := []config.DiscoverySettings{
+		{Enabled: true},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/33"}},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/15"}},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/16", "10.1.0.0/31"}},
+		{Enabled: true, CIDRs: []string{"fd00::/64"}},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/24"}, Ports: []int{0}},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/24"}, Concurrency: 1000},
+		{Enabled: true, CIDRs: []string{"10.0.0.0/24"}, Interval: "daily"},
+	}
+	for _, settings := range invalid {
+		_, err := NewScanner(settings, nil)
+		assert.Error(t, err, "%+v", settings)
+	}
+}
+
+func TestScannerRun(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Example Issuing CA"},
+		NotBefore:             now.AddDate(-1, 0, 0),
+		NotAfter:              now.AddDate(5, 0, 0),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "hidden.example.com"},
+		NotBefore:    now.AddDate(0, -1, 0),
+		NotAfter:     now.AddDate(0, 1, 0),
+	}, ca, &leafKey.PublicKey, caKey)
+	require.NoError(t, err)
+	fromMount, err := x509.ParseCertificate(leafDER)
+	require.NoError(t, err)
+	inInventory, entry := issue(t, "v1|pki:0a", "api.example.com", 10, now.AddDate(0, -1, 0), now.AddDate(0, 1, 0))
+	stranger, _ := issue(t, "", "printer.local", 11, now.AddDate(0, -1, 0), now.AddDate(1, 0, 0))
+
+	client := &mountsClient{mounts: []string{"v1|pki"}}
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{entry}, nil)
+	client.On("GetIntermediateCA", mock.Anything, "v1|pki").Return(certs.DetailedCertificate{
+		PEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+	}, nil)
+	scanner, err := NewScanner(config.DiscoverySettings{Enabled: true, CIDRs: []string{"10.0.0.0/30", "10.0.0.1"}, Ports: []int{443, 8443}, Concurrency: 3}, client)
+	require.NoError(t, err)
+	scanner.now = func() time.Time { return now }
+	served := map[string]*x509.Certificate{
+		"10.0.0.1:443":  inInventory,
+		"10.0.0.2:8443": fromMount,
+		"10.0.0.3:443":  stranger,
+	}
+	scanner.dial = func(_ context.Context, address, _ string) ([]*x509.Certificate, error) {
+		if leaf := served[address]; leaf != nil {
+			return []*x509.Certificate{leaf}, nil
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	scanner.Run(context.Background())
+
+	assert.Equal(t, 8, scanner.Scanned(), "overlapping ranges are scanned once")
+	assert.Equal(t, now, scanner.LastRun())
+	endpoints := scanner.Endpoints()
+	require.Len(t, endpoints, 3)
+	assert.Equal(t, "10.0.0.1:443", endpoints[0].Address)
+	assert.True(t, endpoints[0].Managed)
+	assert.Equal(t, "v1|pki:0a", endpoints[0].CertificateID)
+	assert.Equal(t, "10.0.0.2:8443", endpoints[1].Address)
+	assert.True(t, endpoints[1].Managed)
+	assert.Empty(t, endpoints[1].CertificateID)
+	assert.Equal(t, "v1|pki", endpoints[1].IssuedBy)
+	assert.Equal(t, "10.0.0.3:443", endpoints[2].Address)
+	assert.False(t, endpoints[2].Managed)
+	assert.Equal(t, "CN=printer.local", endpoints[2].Certificate.Subject)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scanner.Run(ctx)
+	assert.Equal(t, endpoints, scanner.Endpoints(), "a cancelled scan keeps the previous report")
+}
+
+func TestScannerRun_Loopback(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	address := netip.MustParseAddrPort(server.Listener.Addr().String())
+	client := new(vault.MockClient)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{}, nil)
+	scanner, err := NewScanner(config.DiscoverySettings{Enabled: true, CIDRs: []string{address.Addr().String()}, Ports: []int{int(address.Port())}, Timeout: "1s"}, client)
+	require.NoError(t, err)
+
+	scanner.Run(context.Background())
+
+	endpoints := scanner.Endpoints()
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, address.String(), endpoints[0].Address)
+	assert.False(t, endpoints[0].Managed)
+}
+
+func TestScannerRun_FailedVault(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	leaf, entry := issue(t, "v2|pki:0a", "api.example.com", 10, now.AddDate(0, -1, 0), now.AddDate(0, 1, 0))
+	client := &envelopeLister{certificates: []certs.Certificate{entry}}
+	scanner, err := NewScanner(config.DiscoverySettings{Enabled: true, CIDRs: []string{"10.0.0.1"}}, client)
+	require.NoError(t, err)
+	scanner.now = func() time.Time { return now }
+	scanner.dial = func(context.Context, string, string) ([]*x509.Certificate, error) {
+		return []*x509.Certificate{leaf}, nil
+	}
+
+	scanner.Run(context.Background())
+	endpoints := scanner.Endpoints()
+	require.Len(t, endpoints, 1)
+	assert.True(t, endpoints[0].Managed)
+
+	// The leaf's vault is down: it must not turn into an unmanaged endpoint.
+	client.certificates = nil
+	client.vaultErrors = []vault.VaultError{{VaultID: "v2", Message: "vault down"}}
+	scanner.now = func() time.Time { return now.Add(time.Hour) }
+	scanner.Run(context.Background())
+	assert.Equal(t, endpoints, scanner.Endpoints(), "a failed vault keeps the previous report")
+	assert.Equal(t, now, scanner.LastRun())
+}
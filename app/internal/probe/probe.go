@@ -0,0 +1,453 @@
+// Package probe connects to deployed TLS endpoints and checks that they
+// serve the certificate Vault last issued for them. The presented leaf is
+// matched to the inventory by public key fingerprint and serial number; a
+// leaf that is in the inventory but has a newer valid replacement covering
+// the same host is reported as outdated.
+package probe
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/logger"
+	"vcv/internal/vault"
+)
+
+// Statuses of a Result, from worst to best. Error means the handshake
+// failed, so nothing is known about the certificate.
+const (
+	StatusError    = "error"
+	StatusExpired  = "expired"
+	StatusRevoked  = "revoked"
+	StatusUnknown  = "unknown"
+	StatusOutdated = "outdated"
+	StatusOK       = "ok"
+)
+
+// Sources of a Target.
+const (
+	SourceConfig = "config"
+	SourceSAN    = "san"
+)
+
+const (
+	defaultInterval = 15 * time.Minute
+	defaultTimeout  = 5 * time.Second
+	defaultSANPort  = 443
+	// maxSANTargets caps the endpoints derived from SANs, so a broad
+	// pattern does not turn the prober into a scanner.
+	maxSANTargets = 256
+	// concurrency is the number of handshakes in flight.
+	concurrency = 8
+)
+
+// CertLister lists the inventory probes are matched against. Satisfied by
+// vault.Client; a lister that is also a vault.CertificatesEnvelopeLister
+// reports the vaults that failed.
+type CertLister interface {
+	ListCertificates(ctx context.Context) ([]certs.Certificate, error)
+}
+
+// Target is one endpoint to probe.
+type Target struct {
+	Address    string `json:"address"`
+	ServerName string `json:"serverName"`
+	Source     string `json:"source"`
+}
+
+// PresentedCertificate is one certificate of the chain an endpoint sent.
+type PresentedCertificate struct {
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	Sans              []string  `json:"sans"`
+	SerialNumber      string    `json:"serialNumber"`
+	FingerprintSHA256 string    `json:"fingerprintSHA256"`
+	NotBefore         time.Time `json:"notBefore"`
+	NotAfter          time.Time `json:"notAfter"`
+}
+
+// Result is the outcome of probing a Target. CertificateID is the
+// inventory certificate the endpoint serves, if any; CurrentID is a newer
+// valid inventory certificate for the same host, the one that should be
+// deployed.
+type Result struct {
+	Target
+	Status        string                 `json:"status"`
+	Error         string                 `json:"error,omitempty"`
+	CheckedAt     time.Time              `json:"checkedAt"`
+	Chain         []PresentedCertificate `json:"chain"`
+	CertificateID string                 `json:"certificateId,omitempty"`
+	CurrentID     string                 `json:"currentId,omitempty"`
+}
+
+// Prober probes the configured endpoints on demand and keeps the latest
+// results. A nil *Prober is a disabled prober: it has no results.
+type Prober struct {
+	certs      CertLister
+	targets    []Target
+	sanPattern *regexp.Regexp
+	sanPort    int
+	interval   time.Duration
+	timeout    time.Duration
+	dial       func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error)
+	now        func() time.Time
+
+	// inventory is the listing of the last Run, only used by Run.
+	inventory []certs.Certificate
+
+	mu      sync.RWMutex
+	results []Result
+	lastRun time.Time
+}
+
+// New validates settings and builds a Prober. It returns nil when probing
+// is disabled.
+func New(settings config.ProbeSettings, lister CertLister) (*Prober, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	prober := &Prober{certs: lister, sanPort: defaultSANPort, interval: defaultInterval, timeout: defaultTimeout, now: time.Now}
+	prober.dial = func(ctx context.Context, address, serverName string) ([]*x509.Certificate, error) {
+		return dialTLS(ctx, address, serverName, prober.timeout)
+	}
+	var err error
+	if prober.interval, err = parseDuration("probes", "interval", settings.Interval, defaultInterval); err != nil {
+		return nil, err
+	}
+	if prober.timeout, err = parseDuration("probes", "timeout", settings.Timeout, defaultTimeout); err != nil {
+		return nil, err
+	}
+	if pattern := strings.TrimSpace(settings.SANPattern); pattern != "" {
+		if prober.sanPattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("probes: san_pattern: %w", err)
+		}
+	}
+	if settings.SANPort < 0 || settings.SANPort > 65535 {
+		return nil, fmt.Errorf("probes: san_port %d out of range", settings.SANPort)
+	} else if settings.SANPort > 0 {
+		prober.sanPort = settings.SANPort
+	}
+	for _, configured := range settings.Targets {
+		target, targetErr := configTarget(configured)
+		if targetErr != nil {
+			return nil, targetErr
+		}
+		prober.targets = append(prober.targets, target)
+	}
+	if len(prober.targets) == 0 && prober.sanPattern == nil {
+		return nil, fmt.Errorf("probes: enabled without targets or san_pattern")
+	}
+	return prober, nil
+}
+
+func parseDuration(section, name, value string, fallback time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%s: invalid %s %q", section, name, value)
+	}
+	return duration, nil
+}
+
+func configTarget(configured config.ProbeTarget) (Target, error) {
+	address := strings.TrimSpace(configured.Address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = strings.Trim(address, "[]"), strconv.Itoa(defaultSANPort)
+	}
+	if host == "" {
+		return Target{}, fmt.Errorf("probes: invalid target address %q", configured.Address)
+	}
+	if number, portErr := strconv.Atoi(port); portErr != nil || number <= 0 || number > 65535 {
+		return Target{}, fmt.Errorf("probes: invalid port in target address %q", configured.Address)
+	}
+	serverName := strings.TrimSpace(configured.ServerName)
+	if serverName == "" && net.ParseIP(host) == nil {
+		serverName = host
+	}
+	return Target{Address: net.JoinHostPort(host, port), ServerName: serverName, Source: SourceConfig}, nil
+}
+
+// Interval is how often Run should be called. Zero for a nil Prober.
+func (p *Prober) Interval() time.Duration {
+	if p == nil {
+		return 0
+	}
+	return p.interval
+}
+
+// Results returns the results of the last Run, ordered by address and
+// server name.
+func (p *Prober) Results() []Result {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]Result(nil), p.results...)
+}
+
+// LastRun is when the last Run finished; zero before the first one.
+func (p *Prober) LastRun() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastRun
+}
+
+// Run lists the inventory, probes every target and replaces the stored
+// results. An inventory failure is logged and keeps the previous results.
+// A vault that fails to list keeps its certificates of the last Run, so its
+// endpoints do not turn unknown; before a first listing there are none to
+// keep and the previous results stay.
+func (p *Prober) Run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	inventory, vaultErrors, err := p.list(ctx)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("probe: failed to list certificates")
+		return
+	}
+	if len(vaultErrors) > 0 {
+		if p.inventory == nil {
+			logger.Get().Warn().Int("failed_vaults", len(vaultErrors)).Msg("probe: vaults failed to list before a first listing, keeping previous results")
+			return
+		}
+		inventory = keepFailedVaults(inventory, p.inventory, vaultErrors)
+	}
+	p.inventory = append([]certs.Certificate{}, inventory...)
+	now := p.now()
+	targets := p.allTargets(inventory, now)
+	index := certs.NewHostIndex(inventory)
+	results := make([]Result, len(targets))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = p.probe(ctx, targets[i], inventory, index)
+			}
+		}()
+	}
+	for i := range targets {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	p.mu.Lock()
+	p.results = results
+	p.lastRun = p.now()
+	p.mu.Unlock()
+	logger.Get().Debug().Int("targets", len(results)).Msg("probed TLS endpoints")
+}
+
+// list prefers the per-vault envelope so one unreachable vault does not
+// hide the others.
+func (p *Prober) list(ctx context.Context) ([]certs.Certificate, []vault.VaultError, error) {
+	if envelope, ok := p.certs.(vault.CertificatesEnvelopeLister); ok {
+		certificates, vaultErrors := envelope.ListCertificatesEnvelope(ctx)
+		return certificates, vaultErrors, nil
+	}
+	certificates, err := p.certs.ListCertificates(ctx)
+	return certificates, nil, err
+}
+
+// keepFailedVaults adds to inventory the previous certificates of the
+// vaults that failed to list.
+func keepFailedVaults(inventory, previous []certs.Certificate, vaultErrors []vault.VaultError) []certs.Certificate {
+	failed := make(map[string]bool, len(vaultErrors))
+	for _, vaultErr := range vaultErrors {
+		failed[vaultErr.VaultID] = true
+	}
+	merged := append([]certs.Certificate(nil), inventory...)
+	for _, certificate := range previous {
+		if vaultID, _ := certs.VaultAndMount(certificate.ID); failed[vaultID] {
+			merged = append(merged, certificate)
+		}
+	}
+	return merged
+}
+
+// allTargets returns the configured targets followed by the SAN-derived
+// ones, sorted and without duplicates.
+func (p *Prober) allTargets(inventory []certs.Certificate, now time.Time) []Target {
+	seen := make(map[string]struct{})
+	var targets []Target
+	add := func(target Target) {
+		key := target.Address + "|" + target.ServerName
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		targets = append(targets, target)
+	}
+	for _, target := range p.targets {
+		add(target)
+	}
+	if p.sanPattern != nil {
+		port := strconv.Itoa(p.sanPort)
+		derived := 0
+		for _, certificate := range inventory {
+			if !certificate.IsValidAt(now) {
+				continue
+			}
+			for _, san := range certificate.Sans {
+				name := strings.ToLower(strings.TrimSuffix(san, "."))
+				if strings.Contains(name, "*") || strings.Contains(name, "@") || net.ParseIP(name) != nil || !p.sanPattern.MatchString(name) {
+					continue
+				}
+				if derived == maxSANTargets {
+					logger.Get().Warn().Int("limit", maxSANTargets).Msg("probe: too many SANs match san_pattern, ignoring the rest")
+					break
+				}
+				before := len(targets)
+				add(Target{Address: net.JoinHostPort(name, port), ServerName: name, Source: SourceSAN})
+				if len(targets) > before {
+					derived++
+				}
+			}
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		if targets[i].Address != targets[j].Address {
+			return targets[i].Address < targets[j].Address
+		}
+		return targets[i].ServerName < targets[j].ServerName
+	})
+	return targets
+}
+
+func (p *Prober) probe(ctx context.Context, target Target, inventory []certs.Certificate, index *certs.HostIndex) Result {
+	chain, err := p.dial(ctx, target.Address, target.ServerName)
+	result := Result{Target: target, CheckedAt: p.now(), Chain: []PresentedCertificate{}}
+	if err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	for _, presented := range chain {
+		result.Chain = append(result.Chain, present(presented))
+	}
+	host := target.ServerName
+	if host == "" {
+		host, _, _ = net.SplitHostPort(target.Address)
+	}
+	evaluate(&result, chain[0], inventory, index, host)
+	return result
+}
+
+// evaluate sets the status of a successful probe from the presented leaf.
+func evaluate(result *Result, leaf *x509.Certificate, inventory []certs.Certificate, index *certs.HostIndex, host string) {
+	now := result.CheckedAt
+	served := matchInventory(leaf, inventory)
+	// The newest valid certificate covering the host replaces the served
+	// one when it expires later. A served inventory certificate is only
+	// replaced by one with the same common name, so a wildcard issued for
+	// something else does not flag it.
+	notAfter := leaf.NotAfter
+	if normalized, err := certs.NormalizeHost(host); err == nil {
+		for _, match := range index.Lookup(normalized) {
+			candidate := match.Certificate
+			if served != nil && (candidate.ID == served.ID || !strings.EqualFold(candidate.CommonName, served.CommonName)) {
+				continue
+			}
+			if candidate.IsValidAt(now) && candidate.ExpiresAt.After(notAfter) {
+				notAfter = candidate.ExpiresAt
+				result.CurrentID = candidate.ID
+			}
+		}
+	}
+	if served != nil {
+		result.CertificateID = served.ID
+	}
+	switch {
+	case now.After(leaf.NotAfter):
+		result.Status = StatusExpired
+	case served != nil && served.Revoked:
+		result.Status = StatusRevoked
+	case served == nil:
+		result.Status = StatusUnknown
+	case result.CurrentID != "":
+		result.Status = StatusOutdated
+	default:
+		result.Status = StatusOK
+	}
+}
+
+// matchInventory returns the inventory entry of leaf, matched by public key
+// fingerprint and serial number, or nil.
+func matchInventory(leaf *x509.Certificate, inventory []certs.Certificate) *certs.Certificate {
+	fingerprint := certs.PublicKeyFingerprint(leaf)
+	serial := leaf.SerialNumber.Text(16)
+	for i := range inventory {
+		if inventory[i].PublicKeyFingerprint == fingerprint && normalizeSerial(inventory[i].SerialNumber) == serial {
+			return &inventory[i]
+		}
+	}
+	return nil
+}
+
+// normalizeSerial turns Vault's "0a:1b" or "0a-1b" serials into the
+// lower-case hex of big.Int.Text(16).
+func normalizeSerial(serial string) string {
+	replacer := strings.NewReplacer(":", "", "-", "")
+	trimmed := strings.TrimLeft(strings.ToLower(replacer.Replace(serial)), "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+func present(certificate *x509.Certificate) PresentedCertificate {
+	sum := sha256.Sum256(certificate.Raw)
+	return PresentedCertificate{
+		Subject:           certificate.Subject.String(),
+		Issuer:            certificate.Issuer.String(),
+		Sans:              certs.SubjectAltNames(certificate),
+		SerialNumber:      certs.FormatSerial(certificate),
+		FingerprintSHA256: hex.EncodeToString(sum[:]),
+		NotBefore:         certificate.NotBefore.UTC(),
+		NotAfter:          certificate.NotAfter.UTC(),
+	}
+}
+
+// dialTLS completes a handshake and returns the presented chain. The chain
+// is not verified: an expired or untrusted certificate is exactly what the
+// probe should report rather than fail on.
+func dialTLS(ctx context.Context, address, serverName string, timeout time.Duration) ([]*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}} // #nosec G402 -- chains are matched against the inventory, not trusted
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate presented")
+	}
+	return chain, nil
+}
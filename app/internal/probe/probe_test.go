@@ -0,0 +1,223 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+	"vcv/internal/vault"
+)
+
+type staticLister struct {
+	certificates []certs.Certificate
+	err          error
+}
+
+func (l staticLister) ListCertificates(context.Context) ([]certs.Certificate, error) {
+	return l.certificates, l.err
+}
+
+// envelopeLister lists through the per-vault envelope like the multi-vault
+// client.
+type envelopeLister struct {
+	vault.MockClient
+	certificates []certs.Certificate
+	vaultErrors  []vault.VaultError
+}
+
+func (l *envelopeLister) ListCertificatesEnvelope(context.Context) ([]certs.Certificate, []vault.VaultError) {
+	return l.certificates, l.vaultErrors
+}
+
+// issue returns a self-signed leaf for name and its inventory entry.
+func issue(t *testing.T, id, name string, serial int64, notBefore, notAfter time.Time) (*x509.Certificate, certs.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	entry := certs.NewCertificate(leaf)
+	entry.ID = id
+	return leaf, entry
+}
+
+func TestNew(t *testing.T) {
+	prober, err := New(config.ProbeSettings{Targets: []config.ProbeTarget{{Address: "a.example.com"}}}, staticLister{})
+	require.NoError(t, err)
+	assert.Nil(t, prober)
+	assert.Nil(t, prober.Results())
+	assert.Zero(t, prober.Interval())
+
+	prober, err = New(config.ProbeSettings{Enabled: true, Interval: "1h", Targets: []config.ProbeTarget{
+		{Address: "a.example.com"},
+		{Address: "10.0.0.1:8443", ServerName: "b.example.com"},
+	}}, staticLister{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, prober.Interval())
+	assert.Equal(t, []Target{
+		{Address: "a.example.com:443", ServerName: "a.example.com", Source: SourceConfig},
+		{Address: "10.0.0.1:8443", ServerName: "b.example.com", Source: SourceConfig},
+	}, prober.targets)
+
+	invalid := []config.ProbeSettings{
+		{Enabled: true},
+		{Enabled: true, SANPattern: "("},
+		{Enabled: true, SANPattern: "x", Interval: "soon"},
+		{Enabled: true, SANPattern: "x", Timeout: "-1s"},
+		{Enabled: true, SANPattern: "x", SANPort: 70000},
+		{Enabled: true, Targets: []config.ProbeTarget{{Address: ":443"}}},
+		{Enabled: true, Targets: []config.ProbeTarget{{Address: "a.example.com:http"}}},
+	}
+	for _, settings := range invalid {
+		_, err := New(settings, staticLister{})
+		assert.Error(t, err, "%+v", settings)
+	}
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	current, currentEntry := issue(t, "v1|pki:01", "ok.example.com", 1, now.AddDate(0, -1, 0), now.AddDate(0, 2, 0))
+	old, oldEntry := issue(t, "v1|pki:02", "api.example.com", 2, now.AddDate(0, -3, 0), now.AddDate(0, 0, 10))
+	_, renewedEntry := issue(t, "v1|pki:03", "api.example.com", 3, now.AddDate(0, 0, -1), now.AddDate(0, 3, 0))
+	revoked, revokedEntry := issue(t, "v1|pki:04", "revoked.example.com", 4, now.AddDate(0, -1, 0), now.AddDate(0, 2, 0))
+	revokedEntry.Revoked = true
+	expired, _ := issue(t, "v1|pki:05", "expired.example.com", 5, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1))
+	stranger, _ := issue(t, "", "ok.example.com", 6, now.AddDate(0, -1, 0), now.AddDate(0, 1, 0))
+	inventory := []certs.Certificate{currentEntry, oldEntry, renewedEntry, revokedEntry}
+
+	prober, err := New(config.ProbeSettings{
+		Enabled:    true,
+		SANPattern: `\.example\.com$`,
+		Targets: []config.ProbeTarget{
+			{Address: "stranger.internal:443", ServerName: "ok.example.com"},
+			{Address: "expired.example.com"},
+			{Address: "down.example.com"},
+		},
+	}, staticLister{certificates: inventory})
+	require.NoError(t, err)
+	prober.now = func() time.Time { return now }
+	served := map[string]*x509.Certificate{
+		"ok.example.com:443":      current,
+		"api.example.com:443":     old,
+		"revoked.example.com:443": revoked,
+		"expired.example.com:443": expired,
+		"stranger.internal:443":   stranger,
+		"down.example.com:443":    nil,
+	}
+	prober.dial = func(_ context.Context, address, _ string) ([]*x509.Certificate, error) {
+		if leaf := served[address]; leaf != nil {
+			return []*x509.Certificate{leaf}, nil
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	prober.Run(context.Background())
+
+	type outcome struct{ status, certificateID, currentID string }
+	got := make(map[string]outcome)
+	for _, result := range prober.Results() {
+		got[result.Address] = outcome{result.Status, result.CertificateID, result.CurrentID}
+	}
+	assert.Equal(t, map[string]outcome{
+		// The revoked certificate's SAN is not probed automatically, and the
+		// renewed certificate shares api.example.com with the old one.
+		"ok.example.com:443":      {StatusOK, "v1|pki:01", ""},
+		"api.example.com:443":     {StatusOutdated, "v1|pki:02", "v1|pki:03"},
+		"expired.example.com:443": {StatusExpired, "", ""},
+		"stranger.internal:443":   {StatusUnknown, "", "v1|pki:01"},
+		"down.example.com:443":    {StatusError, "", ""},
+	}, got)
+	assert.Equal(t, now, prober.LastRun())
+
+	prober.targets = []Target{{Address: "revoked.example.com:443", ServerName: "revoked.example.com", Source: SourceConfig}}
+	prober.sanPattern = nil
+	prober.Run(context.Background())
+	results := prober.Results()
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusRevoked, results[0].Status)
+	require.Len(t, results[0].Chain, 1)
+	assert.Equal(t, "04", results[0].Chain[0].SerialNumber)
+
+	prober.certs = staticLister{err: errors.New("vault down")}
+	prober.Run(context.Background())
+	assert.Equal(t, results, prober.Results(), "a failed listing keeps the previous results")
+}
+
+func TestRun_FailedVault(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	first, firstEntry := issue(t, "v1|pki:01", "one.example.com", 1, now.AddDate(0, -1, 0), now.AddDate(0, 2, 0))
+	second, secondEntry := issue(t, "v2|pki:02", "two.example.com", 2, now.AddDate(0, -1, 0), now.AddDate(0, 2, 0))
+	lister := &envelopeLister{
+		certificates: []certs.Certificate{firstEntry},
+		vaultErrors:  []vault.VaultError{{VaultID: "v2", Message: "vault down"}},
+	}
+	prober, err := New(config.ProbeSettings{
+		Enabled: true,
+		Targets: []config.ProbeTarget{{Address: "one.example.com"}, {Address: "two.example.com"}},
+	}, lister)
+	require.NoError(t, err)
+	prober.now = func() time.Time { return now }
+	served := map[string]*x509.Certificate{"one.example.com:443": first, "two.example.com:443": second}
+	prober.dial = func(_ context.Context, address, _ string) ([]*x509.Certificate, error) {
+		return []*x509.Certificate{served[address]}, nil
+	}
+	statuses := func() map[string]string {
+		got := make(map[string]string)
+		for _, result := range prober.Results() {
+			got[result.Address] = result.Status
+		}
+		return got
+	}
+
+	prober.Run(context.Background())
+	assert.Empty(t, prober.Results(), "a failed vault before a first listing keeps the previous results")
+	assert.True(t, prober.LastRun().IsZero())
+
+	lister.certificates, lister.vaultErrors = []certs.Certificate{firstEntry, secondEntry}, nil
+	prober.Run(context.Background())
+	assert.Equal(t, map[string]string{"one.example.com:443": StatusOK, "two.example.com:443": StatusOK}, statuses())
+
+	lister.certificates = []certs.Certificate{firstEntry}
+	lister.vaultErrors = []vault.VaultError{{VaultID: "v2", Message: "vault down"}}
+	prober.Run(context.Background())
+	assert.Equal(t, map[string]string{"one.example.com:443": StatusOK, "two.example.com:443": StatusOK}, statuses(),
+		"the failed vault keeps its certificates, so its endpoint does not turn unknown")
+
+	lister.vaultErrors = nil
+	prober.Run(context.Background())
+	assert.Equal(t, StatusUnknown, statuses()["two.example.com:443"], "a vault that lists again is taken at its word")
+}
+
+func TestDialTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	chain, err := dialTLS(context.Background(), server.Listener.Addr().String(), "example.com", time.Second)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, chain)
+	assert.Equal(t, server.Certificate().Raw, chain[0].Raw)
+}
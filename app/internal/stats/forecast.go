@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"vcv/internal/certs"
+)
+
+// Forecast periods: the length of each window ahead.
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// ForecastWindow is one period ahead; End is exclusive.
+type ForecastWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ForecastGroup counts, for one vault, mount and issuer, the certificates
+// expiring in each window. Counts is aligned with Forecast.Windows.
+type ForecastGroup struct {
+	Vault  string `json:"vault"`
+	Mount  string `json:"mount"`
+	Issuer string `json:"issuer"`
+	Counts []int  `json:"counts"`
+	Total  int    `json:"total"`
+}
+
+// Forecast counts upcoming expirations per window. Revoked and already
+// expired certificates are left out, as are the superseded ones, which
+// count toward Superseded instead when they would have expired inside the
+// windows.
+type Forecast struct {
+	Period     string           `json:"period"`
+	Windows    []ForecastWindow `json:"windows"`
+	Totals     []int            `json:"totals"`
+	Total      int              `json:"total"`
+	Superseded int              `json:"superseded"`
+	Groups     []ForecastGroup  `json:"groups"`
+}
+
+// ForecastWindows returns count consecutive windows of period starting at
+// now. Months are calendar months from now's day of month.
+func ForecastWindows(period string, count int, now time.Time) ([]ForecastWindow, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("forecast: window count must be positive")
+	}
+	windows := make([]ForecastWindow, 0, count)
+	for index := range count {
+		switch period {
+		case PeriodWeek:
+			windows = append(windows, ForecastWindow{Start: now.AddDate(0, 0, 7*index), End: now.AddDate(0, 0, 7*(index+1))})
+		case PeriodMonth:
+			windows = append(windows, ForecastWindow{Start: now.AddDate(0, index, 0), End: now.AddDate(0, index+1, 0)})
+		default:
+			return nil, fmt.Errorf("forecast: unknown period %q", period)
+		}
+	}
+	return windows, nil
+}
+
+// ComputeForecast counts the certificates expiring in count windows of
+// period from now. superseded holds the IDs certs.Superseded reports for the
+// inventory certificates were selected from: a renewal outside the selection
+// still supersedes. Groups are sorted by vault, mount and issuer and only
+// include those with an expiration inside the windows.
+func ComputeForecast(certificates []certs.Certificate, superseded map[string]bool, period string, count int, now time.Time) (Forecast, error) {
+	windows, err := ForecastWindows(period, count, now)
+	if err != nil {
+		return Forecast{}, err
+	}
+	result := Forecast{Period: period, Windows: windows, Totals: make([]int, count), Groups: []ForecastGroup{}}
+	groups := make(map[string]*ForecastGroup)
+	for _, certificate := range certificates {
+		if certificate.Revoked {
+			continue
+		}
+		index := sort.Search(len(windows), func(index int) bool {
+			return certificate.ExpiresAt.Before(windows[index].End)
+		})
+		if index == len(windows) || certificate.ExpiresAt.Before(windows[0].Start) {
+			continue
+		}
+		if superseded[certificate.ID] {
+			result.Superseded++
+			continue
+		}
+		vaultID, mount := certs.VaultAndMount(certificate.ID)
+		issuer := IssuerLabel(certificate)
+		key := MountKey(vaultID, mount) + "|" + issuer
+		group, ok := groups[key]
+		if !ok {
+			group = &ForecastGroup{Vault: vaultID, Mount: mount, Issuer: issuer, Counts: make([]int, count)}
+			groups[key] = group
+		}
+		group.Counts[index]++
+		group.Total++
+		result.Totals[index]++
+		result.Total++
+	}
+	for _, group := range groups {
+		result.Groups = append(result.Groups, *group)
+	}
+	sort.Slice(result.Groups, func(left, right int) bool {
+		a, b := result.Groups[left], result.Groups[right]
+		if a.Vault != b.Vault {
+			return a.Vault < b.Vault
+		}
+		if a.Mount != b.Mount {
+			return a.Mount < b.Mount
+		}
+		return a.Issuer < b.Issuer
+	})
+	return result, nil
+}
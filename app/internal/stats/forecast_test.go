@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+)
+
+func TestComputeForecast(t *testing.T) {
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	certificates := []certs.Certificate{
+		{ID: "v1|pki:1", ExpiresAt: now.Add(2 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki:2", ExpiresAt: now.Add(7 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki:3", ExpiresAt: now.Add(8 * day)},
+		{ID: "v2|pki:4", ExpiresAt: now.Add(20 * day), IssuerCN: "Other CA"},
+		{ID: "v2|pki:5", ExpiresAt: now.Add(10 * day), Revoked: true},
+		{ID: "v2|pki:6", ExpiresAt: now.Add(-day)},
+		{ID: "v2|pki:7", ExpiresAt: now.Add(50 * day)},
+		{ID: "pki:8"},
+		// 9 was renewed by 10 and drops out of the counts.
+		{ID: "v1|pki:9", CommonName: "app", CreatedAt: now.AddDate(-1, 0, 0), ExpiresAt: now.Add(3 * day), IssuerCN: "Root CA"},
+		{ID: "v1|pki:10", CommonName: "app", CreatedAt: now.Add(-day), ExpiresAt: now.AddDate(1, 0, 0), IssuerCN: "Root CA"},
+	}
+
+	weekly, err := ComputeForecast(certificates, certs.Superseded(certificates), PeriodWeek, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, PeriodWeek, weekly.Period)
+	require.Len(t, weekly.Windows, 3)
+	assert.Equal(t, ForecastWindow{Start: now.Add(7 * day), End: now.Add(14 * day)}, weekly.Windows[1])
+	assert.Equal(t, []int{1, 2, 1}, weekly.Totals)
+	assert.Equal(t, 4, weekly.Total)
+	assert.Equal(t, 1, weekly.Superseded)
+	assert.Equal(t, []ForecastGroup{
+		{Vault: "v1", Mount: "pki", Issuer: "Root CA", Counts: []int{1, 1, 0}, Total: 2},
+		{Vault: "v1", Mount: "pki", Issuer: "unknown", Counts: []int{0, 1, 0}, Total: 1},
+		{Vault: "v2", Mount: "pki", Issuer: "Other CA", Counts: []int{0, 0, 1}, Total: 1},
+	}, weekly.Groups)
+
+	monthly, err := ComputeForecast(certificates, certs.Superseded(certificates), PeriodMonth, 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), monthly.Windows[0].End, "months follow AddDate normalization")
+	assert.Equal(t, []int{4, 1}, monthly.Totals)
+
+	// The renewal is left out of the selection but still supersedes 9.
+	selected, err := ComputeForecast(certificates[:9], certs.Superseded(certificates), PeriodWeek, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, 4, selected.Total)
+	assert.Equal(t, 1, selected.Superseded)
+
+	empty, err := ComputeForecast(nil, nil, PeriodWeek, 1, now)
+	require.NoError(t, err)
+	assert.NotNil(t, empty.Groups)
+
+	_, err = ComputeForecast(certificates, certs.Superseded(certificates), "year", 1, now)
+	assert.Error(t, err)
+	_, err = ComputeForecast(certificates, certs.Superseded(certificates), PeriodWeek, 0, now)
+	assert.Error(t, err)
+}
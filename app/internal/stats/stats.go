@@ -0,0 +1,200 @@
+// Package stats aggregates the certificate inventory in one pass: counts by
+// status, vault, mount, issuer, key type and expiry bucket. The /api/stats
+// endpoint, the webhook notifier and the Prometheus collector all read the
+// same Stats, so the dashboard, alerts and metrics cannot disagree.
+// ComputeForecast likewise serves /api/forecast and its gauge.
+package stats
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"vcv/internal/certs"
+)
+
+// Expiry buckets, by whole days remaining rounded up. Revoked certificates
+// are only counted as revoked; undated ones count as expired, like their
+// status.
+const (
+	Bucket0To7d   = "0-7d"
+	Bucket7To30d  = "7-30d"
+	Bucket30To90d = "30-90d"
+	Bucket90dPlus = "90d+"
+	BucketExpired = "expired"
+	BucketRevoked = "revoked"
+)
+
+// Buckets lists the expiry buckets from soonest to latest.
+var Buckets = []string{Bucket0To7d, Bucket7To30d, Bucket30To90d, Bucket90dPlus, BucketExpired, BucketRevoked}
+
+// Statuses lists the statuses returned by certs.ExpiryThresholds.Status.
+var Statuses = []string{certs.StatusValid, certs.StatusWarning, certs.StatusCritical, certs.StatusExpired, certs.StatusRevoked}
+
+// Expiring counts certificates inside the warning and critical windows of
+// their mount, as alerts see them: a certificate inside the critical window
+// is normally inside the warning window too and counts toward both.
+type Expiring struct {
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+}
+
+// Counts summarizes a set of certificates. Every certificate is counted
+// under exactly one status and one bucket, so each map sums to Total.
+type Counts struct {
+	Total    int            `json:"total"`
+	Statuses map[string]int `json:"statuses"`
+	Expiring Expiring       `json:"expiring"`
+	Buckets  map[string]int `json:"buckets"`
+	// Issuers is keyed by issuer common name, "unknown" when missing.
+	Issuers map[string]int `json:"issuers"`
+	// KeyTypes is keyed by "ALGORITHM-SIZE", e.g. "RSA-2048", the form the
+	// key_algorithm filter of /api/certs accepts.
+	KeyTypes map[string]int `json:"keyTypes"`
+}
+
+// Stats holds the counts of the whole set and of each vault and mount.
+// Mounts is keyed by MountKey.
+type Stats struct {
+	Counts
+	Vaults map[string]Counts `json:"vaults"`
+	Mounts map[string]Counts `json:"mounts"`
+}
+
+// Compute aggregates certificates under thresholds at now. nil thresholds
+// leave every dated, unrevoked certificate valid.
+func Compute(certificates []certs.Certificate, thresholds *certs.ExpiryThresholds, now time.Time) Stats {
+	result := Stats{
+		Counts: newCounts(),
+		Vaults: make(map[string]Counts),
+		Mounts: make(map[string]Counts),
+	}
+	for _, certificate := range certificates {
+		vaultID, mount := certs.VaultAndMount(certificate.ID)
+		warning, critical := thresholds.WindowFor(vaultID, mount).Evaluate(certificate, now)
+		entry := entry{
+			status:   thresholds.Status(certificate, now),
+			bucket:   ExpiryBucket(certificate, now),
+			issuer:   IssuerLabel(certificate),
+			keyType:  KeyTypeLabel(certificate),
+			warning:  warning,
+			critical: critical,
+		}
+		result.add(entry)
+		vaultCounts, ok := result.Vaults[vaultID]
+		if !ok {
+			vaultCounts = newCounts()
+		}
+		vaultCounts.add(entry)
+		result.Vaults[vaultID] = vaultCounts
+		key := MountKey(vaultID, mount)
+		mountCounts, ok := result.Mounts[key]
+		if !ok {
+			mountCounts = newCounts()
+		}
+		mountCounts.add(entry)
+		result.Mounts[key] = mountCounts
+	}
+	return result
+}
+
+// entry is what Compute derives from one certificate.
+type entry struct {
+	status, bucket, issuer, keyType string
+	warning, critical               bool
+}
+
+func newCounts() Counts {
+	counts := Counts{
+		Statuses: make(map[string]int, len(Statuses)),
+		Buckets:  make(map[string]int, len(Buckets)),
+		Issuers:  make(map[string]int),
+		KeyTypes: make(map[string]int),
+	}
+	for _, status := range Statuses {
+		counts.Statuses[status] = 0
+	}
+	for _, bucket := range Buckets {
+		counts.Buckets[bucket] = 0
+	}
+	return counts
+}
+
+func (counts *Counts) add(entry entry) {
+	counts.Total++
+	counts.Statuses[entry.status]++
+	counts.Buckets[entry.bucket]++
+	counts.Issuers[entry.issuer]++
+	counts.KeyTypes[entry.keyType]++
+	if entry.warning {
+		counts.Expiring.Warning++
+	}
+	if entry.critical {
+		counts.Expiring.Critical++
+	}
+}
+
+// ExpiryBucket returns the expiry bucket of the certificate at now.
+func ExpiryBucket(certificate certs.Certificate, now time.Time) string {
+	if certificate.Revoked {
+		return BucketRevoked
+	}
+	if certificate.ExpiresAt.IsZero() || certificate.ExpiresAt.Before(now) {
+		return BucketExpired
+	}
+	days := int(math.Ceil(certificate.ExpiresAt.Sub(now).Hours() / 24))
+	switch {
+	case days <= 7:
+		return Bucket0To7d
+	case days <= 30:
+		return Bucket7To30d
+	case days <= 90:
+		return Bucket30To90d
+	default:
+		return Bucket90dPlus
+	}
+}
+
+// IssuerLabel returns the issuer common name, or "unknown".
+func IssuerLabel(certificate certs.Certificate) string {
+	if issuer := strings.TrimSpace(certificate.IssuerCN); issuer != "" {
+		return issuer
+	}
+	return "unknown"
+}
+
+// KeyTypeLabel returns "ALGORITHM-SIZE", with "unknown" for a missing
+// algorithm and "0" for a missing size.
+func KeyTypeLabel(certificate certs.Certificate) string {
+	algorithm := strings.TrimSpace(certificate.KeyAlgorithm)
+	if algorithm == "" {
+		algorithm = "unknown"
+	}
+	return algorithm + "-" + certs.KeySizeLabel(certificate.KeySize)
+}
+
+// SplitKeyType splits a KeyTypeLabel into algorithm and size.
+func SplitKeyType(label string) (algorithm, size string) {
+	index := strings.LastIndex(label, "-")
+	if index < 0 {
+		return label, "0"
+	}
+	return label[:index], label[index+1:]
+}
+
+// MountKey identifies a mount the way the mounts filter of /api/certs does:
+// "vault_id|mount", or the bare mount for certificates without a vault.
+func MountKey(vaultID, mount string) string {
+	if vaultID == "" {
+		return mount
+	}
+	return vaultID + "|" + mount
+}
+
+// SplitMountKey reverses MountKey.
+func SplitMountKey(key string) (vaultID, mount string) {
+	if vaultID, mount, found := strings.Cut(key, "|"); found {
+		return vaultID, mount
+	}
+	return "", key
+}
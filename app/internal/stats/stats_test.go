@@ -0,0 +1,225 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{Critical: 7, Warning: 30}, nil)
+	require.NoError(t, err)
+	certificates := []certs.Certificate{
+		{ID: "v1|pki:1", ExpiresAt: now.Add(3 * day), IssuerCN: "Root CA", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "v1|pki:2", ExpiresAt: now.Add(20 * day), IssuerCN: "Root CA", KeyAlgorithm: "RSA", KeySize: 2048},
+		{ID: "v1|pki:3", ExpiresAt: now.Add(3 * day), Acknowledgement: &certs.Acknowledgement{ID: "ack-1"}},
+		{ID: "v1|pki_int:4", ExpiresAt: now.Add(60 * day), KeyAlgorithm: "ECDSA", KeySize: 256},
+		{ID: "v2|pki:5", ExpiresAt: now.Add(365 * day), Revoked: true},
+		{ID: "v2|pki:6", ExpiresAt: now.Add(-day)},
+		{ID: "pki:7"},
+	}
+
+	result := Compute(certificates, thresholds, now)
+
+	assert.Equal(t, 7, result.Total)
+	assert.Equal(t, map[string]int{"valid": 2, "warning": 1, "critical": 1, "expired": 2, "revoked": 1}, result.Statuses)
+	assert.Equal(t, Expiring{Warning: 2, Critical: 1}, result.Expiring)
+	assert.Equal(t, map[string]int{"0-7d": 2, "7-30d": 1, "30-90d": 1, "90d+": 0, "expired": 2, "revoked": 1}, result.Buckets)
+	assert.Equal(t, map[string]int{"Root CA": 2, "unknown": 5}, result.Issuers)
+	assert.Equal(t, map[string]int{"RSA-2048": 2, "ECDSA-256": 1, "unknown-0": 4}, result.KeyTypes)
+
+	require.Contains(t, result.Vaults, "v1")
+	assert.Equal(t, 4, result.Vaults["v1"].Total)
+	assert.Equal(t, 2, result.Vaults["v2"].Total)
+	assert.Equal(t, 1, result.Vaults[""].Total)
+
+	assert.ElementsMatch(t, []string{"v1|pki", "v1|pki_int", "v2|pki", "pki"}, keys(result.Mounts))
+	assert.Equal(t, Expiring{Warning: 2, Critical: 1}, result.Mounts["v1|pki"].Expiring)
+	assert.Equal(t, 1, result.Mounts["v1|pki_int"].Buckets[Bucket30To90d])
+	assert.Equal(t, 1, result.Mounts["pki"].Statuses[certs.StatusExpired])
+}
+
+func TestCompute_MountThresholds(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	thresholds, err := certs.NewExpiryThresholds(config.ExpirationThresholds{
+		Critical: 7, Warning: 30,
+		Mounts: map[string]config.ExpirationThresholds{"pki_mesh": {CriticalDuration: "6h", WarningDuration: "1d"}},
+	}, nil)
+	require.NoError(t, err)
+	certificates := []certs.Certificate{
+		{ID: "v1|pki:1", ExpiresAt: now.Add(20 * time.Hour)},
+		{ID: "v1|pki_mesh:2", ExpiresAt: now.Add(20 * time.Hour)},
+	}
+
+	result := Compute(certificates, thresholds, now)
+
+	assert.Equal(t, certs.StatusCritical, statusOf(result.Mounts["v1|pki"]))
+	assert.Equal(t, certs.StatusWarning, statusOf(result.Mounts["v1|pki_mesh"]))
+	assert.Equal(t, Expiring{Warning: 2, Critical: 1}, result.Expiring)
+}
+
+func TestCompute_Empty(t *testing.T) {
+	result := Compute(nil, nil, time.Now())
+	assert.Zero(t, result.Total)
+	assert.Len(t, result.Statuses, len(Statuses))
+	assert.Len(t, result.Buckets, len(Buckets))
+	assert.Empty(t, result.Vaults)
+	assert.Empty(t, result.Mounts)
+}
+
+func TestCompute_Expiring(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	defaults := mustThresholds(t, config.ExpirationThresholds{Warning: 30, Critical: 7})
+	tests := []struct {
+		name         string
+		certificates []certs.Certificate
+		thresholds   *certs.ExpiryThresholds
+		want         Expiring
+	}{
+		{
+			name: "critical counts toward warning too",
+			certificates: []certs.Certificate{
+				{ID: "critical", ExpiresAt: now.Add(3 * day)},
+				{ID: "warning", ExpiresAt: now.Add(20 * day)},
+				{ID: "far-future", ExpiresAt: now.Add(365 * day)},
+				{ID: "expired", ExpiresAt: now.Add(-day)},
+				{ID: "revoked-but-soon", ExpiresAt: now.Add(day), Revoked: true},
+				{ID: "no-expiry"},
+			},
+			thresholds: defaults,
+			want:       Expiring{Warning: 2, Critical: 1},
+		},
+		{
+			name: "skips acknowledged",
+			certificates: []certs.Certificate{
+				{ID: "soon", ExpiresAt: now.Add(day)},
+				{ID: "decommissioned", ExpiresAt: now.Add(day), Acknowledgement: &certs.Acknowledgement{ID: "ack-1"}},
+			},
+			thresholds: defaults,
+			want:       Expiring{Warning: 1, Critical: 1},
+		},
+		{
+			name:         "disabled thresholds",
+			certificates: []certs.Certificate{{ID: "soon", ExpiresAt: now.Add(day)}},
+			thresholds:   mustThresholds(t, config.ExpirationThresholds{}),
+		},
+		{
+			name:         "nil thresholds",
+			certificates: []certs.Certificate{{ID: "soon", ExpiresAt: now.Add(day)}},
+		},
+		{
+			name:       "empty",
+			thresholds: defaults,
+		},
+		{
+			name:         "partial day over the threshold is not critical",
+			certificates: []certs.Certificate{{ID: "seven-days-and-a-bit", ExpiresAt: now.Add(7*day + time.Minute)}},
+			thresholds:   mustThresholds(t, config.ExpirationThresholds{Critical: 7}),
+		},
+		{
+			name: "durations override days",
+			certificates: []certs.Certificate{
+				{ID: "pki:a", ExpiresAt: now.Add(4 * time.Hour)},
+				{ID: "pki:b", ExpiresAt: now.Add(20 * time.Hour)},
+				{ID: "pki:c", ExpiresAt: now.Add(48 * time.Hour)},
+			},
+			thresholds: mustThresholds(t, config.ExpirationThresholds{
+				Critical: 7, Warning: 30, CriticalDuration: "6h", WarningDuration: "1d",
+			}),
+			want: Expiring{Warning: 2, Critical: 1},
+		},
+		{
+			name: "percent of lifetime",
+			certificates: []certs.Certificate{
+				// 72h certificate with 6h left: 8.3% of its lifetime remains.
+				{ID: "pki_mesh:a", CreatedAt: now.Add(-66 * time.Hour), ExpiresAt: now.Add(6 * time.Hour)},
+				// 72h certificate with 36h left: 50% remains.
+				{ID: "pki_mesh:b", CreatedAt: now.Add(-36 * time.Hour), ExpiresAt: now.Add(36 * time.Hour)},
+				// No issue date: percentages cannot apply.
+				{ID: "pki_mesh:c", ExpiresAt: now.Add(time.Hour)},
+			},
+			thresholds: mustThresholds(t, config.ExpirationThresholds{CriticalPercent: 10, WarningPercent: 50}),
+			want:       Expiring{Warning: 2, Critical: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Compute(tt.certificates, tt.thresholds, now).Expiring)
+		})
+	}
+}
+
+func TestExpiryBucket_Boundaries(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	cases := []struct {
+		expiresAt time.Time
+		want      string
+	}{
+		{now, Bucket0To7d},
+		{now.Add(7 * day), Bucket0To7d},
+		{now.Add(7*day + time.Minute), Bucket7To30d},
+		{now.Add(30 * day), Bucket7To30d},
+		{now.Add(90 * day), Bucket30To90d},
+		{now.Add(91 * day), Bucket90dPlus},
+		{now.Add(-time.Second), BucketExpired},
+		{time.Time{}, BucketExpired},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, ExpiryBucket(certs.Certificate{ExpiresAt: tc.expiresAt}, now), tc.expiresAt)
+	}
+	assert.Equal(t, BucketRevoked, ExpiryBucket(certs.Certificate{ExpiresAt: now.Add(-day), Revoked: true}, now))
+}
+
+func TestLabels(t *testing.T) {
+	assert.Equal(t, "Internal Intermediate CA", IssuerLabel(certs.Certificate{CommonName: "app.example.com", IssuerCN: "Internal Intermediate CA"}))
+	assert.Equal(t, "unknown", IssuerLabel(certs.Certificate{CommonName: "app.example.com"}))
+
+	assert.Equal(t, "RSA-1024", KeyTypeLabel(certs.Certificate{KeyAlgorithm: "RSA", KeySize: 1024}))
+	assert.Equal(t, "unknown-0", KeyTypeLabel(certs.Certificate{}))
+	algorithm, size := SplitKeyType("RSA-1024")
+	assert.Equal(t, "RSA", algorithm)
+	assert.Equal(t, "1024", size)
+
+	assert.Equal(t, "v1|pki", MountKey("v1", "pki"))
+	assert.Equal(t, "pki", MountKey("", "pki"))
+	vaultID, mount := SplitMountKey("v1|pki")
+	assert.Equal(t, "v1", vaultID)
+	assert.Equal(t, "pki", mount)
+	vaultID, mount = SplitMountKey("pki")
+	assert.Empty(t, vaultID)
+	assert.Equal(t, "pki", mount)
+}
+
+func keys(m map[string]Counts) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
+
+// statusOf returns the only status counted in a single-certificate group.
+func statusOf(counts Counts) string {
+	for status, count := range counts.Statuses {
+		if count > 0 {
+			return status
+		}
+	}
+	return ""
+}
+
+func mustThresholds(t *testing.T, thresholds config.ExpirationThresholds) *certs.ExpiryThresholds {
+	t.Helper()
+	compiled, err := certs.NewExpiryThresholds(thresholds, nil)
+	require.NoError(t, err)
+	return compiled
+}
@@ -18,6 +18,13 @@ type Client interface {
 	Shutdown()
 }
 
+// SyncReporter reports when the certificate listing was last read from
+// Vault. A cached listing keeps the time of the read that filled the cache;
+// the zero time means no listing has succeeded yet.
+type SyncReporter interface {
+	LastSync() time.Time
+}
+
 type CacheSizer interface {
 	CacheSize() int
 }
@@ -45,3 +52,30 @@ type VaultError struct {
 type CertificatesEnvelopeLister interface {
 	ListCertificatesEnvelope(ctx context.Context) ([]certs.Certificate, []VaultError)
 }
+
+// InventoryLister lists like CertificatesEnvelopeLister but before mount
+// policies hide or roll up certificates, so a certificate missing from the
+// listing is gone from Vault. Used by the event watcher.
+type InventoryLister interface {
+	ListInventory(ctx context.Context) ([]certs.Certificate, []VaultError)
+}
+
+// CAChainGetter returns the PEM certificates of a mount's issuing chain,
+// issuing CA first. Used for full-chain and PKCS#7 downloads.
+type CAChainGetter interface {
+	GetCAChain(ctx context.Context, mount string) ([]string, error)
+}
+
+// IssuersGetter returns the PEM certificate of every issuer of a mount, not
+// only its default one, so certificates signed by a rotated or secondary
+// issuer are still attributed to the mount.
+type IssuersGetter interface {
+	GetIssuers(ctx context.Context, mount string) ([]string, error)
+}
+
+// MountLister returns the configured PKI mounts of the enabled vaults, keyed
+// "vault|mount" (or the bare mount for single-vault clients) as
+// GetIntermediateCA expects.
+type MountLister interface {
+	Mounts() []string
+}
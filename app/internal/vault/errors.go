@@ -0,0 +1,66 @@
+package vault
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// Causes wrapped by client errors so callers can tell a bad request from a
+// missing certificate or an unreachable Vault without parsing messages.
+var (
+	ErrInvalidCertificateID = errors.New("invalid certificate id")
+	ErrCertificateNotFound  = errors.New("certificate not found")
+	ErrMountNotConfigured   = errors.New("mount not configured")
+	ErrUnknownVault         = errors.New("unknown vault")
+	ErrVaultUnavailable     = errors.New("vault unavailable")
+	ErrVaultResponse        = errors.New("unexpected vault response")
+)
+
+// Error records which vault of a multi-vault client produced Err.
+type Error struct {
+	VaultID string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.VaultID + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// VaultIDOf returns the vault recorded on err, or "" when none is.
+func VaultIDOf(err error) string {
+	var vaultErr *Error
+	if errors.As(err, &vaultErr) {
+		return vaultErr.VaultID
+	}
+	return ""
+}
+
+func withVaultID(vaultID string, err error) error {
+	if err == nil || VaultIDOf(err) != "" {
+		return err
+	}
+	return &Error{VaultID: vaultID, Err: err}
+}
+
+// readCause classifies a failed Vault read: Vault answered with an error
+// status, or could not be reached (network failure, sealed, standby).
+func readCause(err error) error {
+	var responseErr *api.ResponseError
+	if !errors.As(err, &responseErr) {
+		return ErrVaultUnavailable
+	}
+	switch responseErr.StatusCode {
+	case http.StatusNotFound:
+		return ErrCertificateNotFound
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return ErrVaultUnavailable
+	default:
+		return ErrVaultResponse
+	}
+}
@@ -0,0 +1,90 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"vcv/internal/certs"
+	"vcv/internal/config"
+)
+
+func TestRealClient_ErrorCauses(t *testing.T) {
+	certificatePEM := newVaultTestCertificatePEM(t)
+	server := newVaultTestServer(vaultTestServerState{certificatePEM: certificatePEM})
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+
+	if _, err := client.GetCertificateDetails(context.Background(), "pki:zz"); !errors.Is(err, ErrCertificateNotFound) {
+		t.Fatalf("expected ErrCertificateNotFound, got %v", err)
+	}
+	if _, err := client.GetCertificateDetails(context.Background(), "other:aa"); !errors.Is(err, ErrMountNotConfigured) {
+		t.Fatalf("expected ErrMountNotConfigured, got %v", err)
+	}
+	if _, err := client.GetCertificateDetails(context.Background(), "pki:"); !errors.Is(err, ErrInvalidCertificateID) {
+		t.Fatalf("expected ErrInvalidCertificateID, got %v", err)
+	}
+	if _, err := client.GetIntermediateCA(context.Background(), "other"); !errors.Is(err, ErrMountNotConfigured) {
+		t.Fatalf("expected ErrMountNotConfigured, got %v", err)
+	}
+}
+
+func TestRealClient_ReadErrorCauses(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{status: http.StatusInternalServerError, want: ErrVaultResponse},
+		{status: http.StatusForbidden, want: ErrVaultResponse},
+		{status: http.StatusServiceUnavailable, want: ErrVaultUnavailable},
+		{status: http.StatusTooManyRequests, want: ErrVaultUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"errors":["failure"]}`))
+			}))
+			defer server.Close()
+			client := newRealClientForTest(t, server.URL, []string{"pki"})
+			client.client.SetMaxRetries(0)
+			if _, err := client.GetCertificateDetails(context.Background(), "pki:aa"); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	client.client.SetMaxRetries(0)
+	if _, err := client.GetCertificateDetails(context.Background(), "pki:aa"); !errors.Is(err, ErrVaultUnavailable) {
+		t.Fatalf("expected ErrVaultUnavailable for an unreachable vault, got %v", err)
+	}
+}
+
+func TestMultiClient_ErrorsCarryVaultID(t *testing.T) {
+	instances := []config.VaultInstance{{ID: "v1"}, {ID: "v2"}}
+	c1 := &MockClient{}
+	c1.On("GetCertificateDetails", mock.Anything, "pki:aa").Return(certs.DetailedCertificate{}, fmt.Errorf("read: %w", ErrVaultUnavailable))
+	m := NewMultiClient(instances, map[string]Client{"v1": c1}, nil)
+
+	_, err := m.GetCertificateDetails(context.Background(), "v1|pki:aa")
+	if !errors.Is(err, ErrVaultUnavailable) || VaultIDOf(err) != "v1" {
+		t.Fatalf("expected ErrVaultUnavailable from v1, got %v (vault %q)", err, VaultIDOf(err))
+	}
+	_, err = m.GetCertificateDetails(context.Background(), "v2|pki:aa")
+	if !errors.Is(err, ErrUnknownVault) || VaultIDOf(err) != "v2" {
+		t.Fatalf("expected ErrUnknownVault from v2, got %v", err)
+	}
+	_, err = m.GetCertificatePEM(context.Background(), "|pki:aa")
+	if !errors.Is(err, ErrInvalidCertificateID) || VaultIDOf(err) != "" {
+		t.Fatalf("expected ErrInvalidCertificateID, got %v", err)
+	}
+}
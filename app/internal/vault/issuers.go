@@ -0,0 +1,75 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+
+	"vcv/internal/certs"
+	"vcv/internal/logger"
+)
+
+// MountIssuers returns every issuer of every configured mount of the enabled
+// vaults, keyed as MountLister reports them. Clients that do not list their
+// mounts have none; clients that are not IssuersGetters give the issuing CA
+// alone. Issuers that cannot be read or parsed are logged and skipped; the
+// error of the first mount that failed is returned only when no mount could
+// be read at all.
+func MountIssuers(ctx context.Context, client Client) (map[string][]*x509.Certificate, error) {
+	issuers := make(map[string][]*x509.Certificate)
+	lister, ok := client.(MountLister)
+	if !ok {
+		return issuers, nil
+	}
+	var firstErr error
+	for _, key := range lister.Mounts() {
+		issuerPEMs, err := mountIssuerPEMs(ctx, client, key)
+		if err != nil {
+			logger.Get().Warn().Err(err).Str("mount", key).Msg("skipping mount issuers")
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, issuerPEM := range issuerPEMs {
+			ders, err := certs.DecodePEMCertificates(issuerPEM)
+			if err != nil {
+				logger.Get().Warn().Err(err).Str("mount", key).Msg("skipping unreadable mount issuer")
+				continue
+			}
+			if issuer, parseErr := x509.ParseCertificate(ders[0]); parseErr == nil {
+				issuers[key] = append(issuers[key], issuer)
+			}
+		}
+	}
+	if len(issuers) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return issuers, nil
+}
+
+func mountIssuerPEMs(ctx context.Context, client Client, mount string) ([]string, error) {
+	if getter, ok := client.(IssuersGetter); ok {
+		return getter.GetIssuers(ctx, mount)
+	}
+	ca, err := client.GetIntermediateCA(ctx, mount)
+	if err != nil {
+		return nil, err
+	}
+	return []string{ca.PEM}, nil
+}
+
+// IssuedBy returns the keys of the mounts one of whose issuers signed
+// certificate, unsorted.
+func IssuedBy(certificate *x509.Certificate, issuers map[string][]*x509.Certificate) []string {
+	var keys []string
+	for key, mountIssuers := range issuers {
+		for _, issuer := range mountIssuers {
+			if bytes.Equal(certificate.RawIssuer, issuer.RawSubject) && certificate.CheckSignatureFrom(issuer) == nil {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,127 @@
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"vcv/internal/certs"
+)
+
+func newIssuerPEM(t *testing.T, name string) string {
+	t.Helper()
+	issuerPEM, _ := newIssuerWithLeaf(t, name)
+	return issuerPEM
+}
+
+// newIssuerWithLeaf returns a CA certificate in PEM and a leaf it signed.
+func newIssuerWithLeaf(t *testing.T, name string) (string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, ca, &leafKey.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(leafDER)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), leaf
+}
+
+// fakeIssuersClient lists mounts with several issuers each.
+type fakeIssuersClient struct {
+	fakeMountsClient
+	issuers map[string][]string
+}
+
+func (c *fakeIssuersClient) GetIssuers(_ context.Context, mount string) ([]string, error) {
+	if issuers, ok := c.issuers[mount]; ok {
+		return issuers, nil
+	}
+	return nil, ErrMountNotConfigured
+}
+
+func TestMountIssuers(t *testing.T) {
+	client := &fakeMountsClient{mounts: []string{"pki", "empty", "down"}}
+	client.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{PEM: newIssuerPEM(t, "Issuing CA")}, nil)
+	client.On("GetIntermediateCA", mock.Anything, "empty").Return(certs.DetailedCertificate{PEM: "not a pem"}, nil)
+	client.On("GetIntermediateCA", mock.Anything, "down").Return(certs.DetailedCertificate{}, ErrVaultUnavailable)
+
+	issuers, err := MountIssuers(context.Background(), client)
+
+	require.NoError(t, err, "one readable mount is enough")
+	require.Len(t, issuers, 1)
+	require.Len(t, issuers["pki"], 1)
+	assert.Equal(t, "Issuing CA", issuers["pki"][0].Subject.CommonName)
+	client.AssertExpectations(t)
+}
+
+func TestMountIssuers_NoMountReadable(t *testing.T) {
+	client := &fakeMountsClient{mounts: []string{"pki"}}
+	client.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{}, ErrVaultUnavailable)
+
+	_, err := MountIssuers(context.Background(), client)
+
+	assert.ErrorIs(t, err, ErrVaultUnavailable)
+}
+
+func TestMountIssuers_WithoutMountLister(t *testing.T) {
+	client := &MockClient{}
+
+	issuers, err := MountIssuers(context.Background(), client)
+
+	require.NoError(t, err)
+	assert.Empty(t, issuers)
+	client.AssertNotCalled(t, "GetIntermediateCA", mock.Anything, mock.Anything)
+}
+
+func TestMountIssuers_AllIssuersOfAMount(t *testing.T) {
+	defaultPEM := newIssuerPEM(t, "Issuing CA 2026")
+	rotatedPEM, rotatedLeaf := newIssuerWithLeaf(t, "Issuing CA 2025")
+	_, strangerLeaf := newIssuerWithLeaf(t, "Issuing CA 2025")
+	client := &fakeIssuersClient{
+		fakeMountsClient: fakeMountsClient{mounts: []string{"v1|pki", "v1|other"}},
+		issuers: map[string][]string{
+			"v1|pki":   {defaultPEM, rotatedPEM},
+			"v1|other": {newIssuerPEM(t, "Other CA")},
+		},
+	}
+
+	issuers, err := MountIssuers(context.Background(), client)
+
+	require.NoError(t, err)
+	assert.Len(t, issuers["v1|pki"], 2)
+	assert.Len(t, issuers["v1|other"], 1)
+	assert.Equal(t, []string{"v1|pki"}, IssuedBy(rotatedLeaf, issuers), "a leaf of a non-default issuer belongs to its mount")
+	assert.Empty(t, IssuedBy(strangerLeaf, issuers), "the same name with another key is not an issuer")
+	client.AssertNotCalled(t, "GetIntermediateCA", mock.Anything, mock.Anything)
+}
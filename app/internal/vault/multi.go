@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"vcv/internal/ack"
 	"vcv/internal/certs"
 	"vcv/internal/config"
 	"vcv/internal/logger"
@@ -17,12 +18,43 @@ type multiClient struct {
 	orderedVaultIDs []string
 	clientsByVault  map[string]Client
 	registry        *Registry
+	classifier      *certs.Classifier
+	policies        *certs.MountPolicies
+	acks            *ack.Store
+}
+
+// MultiClientOption customizes a Client built by NewMultiClient.
+type MultiClientOption func(*multiClient)
+
+// WithClassifier applies classification rules to every certificate the
+// multi-vault client returns, so handlers, metrics and the notifier all see
+// the same CertType.
+func WithClassifier(classifier *certs.Classifier) MultiClientOption {
+	return func(c *multiClient) {
+		c.classifier = classifier
+	}
+}
+
+// WithMountPolicies applies per-mount retention and rollup policies to every
+// listing, before the certificates reach handlers, metrics or the notifier.
+func WithMountPolicies(policies *certs.MountPolicies) MultiClientOption {
+	return func(c *multiClient) {
+		c.policies = policies
+	}
+}
+
+// WithAcknowledgements flags certificates covered by an active
+// acknowledgement so expiry counts, metrics and the notifier skip them.
+func WithAcknowledgements(store *ack.Store) MultiClientOption {
+	return func(c *multiClient) {
+		c.acks = store
+	}
 }
 
 // NewMultiClient creates a Client that fans out to multiple vault instances.
 // If a non-nil Registry is provided, only vaults currently enabled in the
 // registry will be queried; otherwise all vaults are used.
-func NewMultiClient(vaultInstances []config.VaultInstance, clientsByVault map[string]Client, registry *Registry) Client {
+func NewMultiClient(vaultInstances []config.VaultInstance, clientsByVault map[string]Client, registry *Registry, opts ...MultiClientOption) Client {
 	ordered := make([]string, 0, len(vaultInstances))
 	seen := make(map[string]struct{}, len(vaultInstances))
 	for _, instance := range vaultInstances {
@@ -48,7 +80,11 @@ func NewMultiClient(vaultInstances []config.VaultInstance, clientsByVault map[st
 		}
 		sort.Strings(ordered)
 	}
-	return &multiClient{orderedVaultIDs: ordered, clientsByVault: clientsByVault, registry: registry}
+	client := &multiClient{orderedVaultIDs: ordered, clientsByVault: clientsByVault, registry: registry}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
 }
 
 // activeVaultIDs returns the subset of orderedVaultIDs that are currently
@@ -115,13 +151,15 @@ func (c *multiClient) GetCertificateDetails(ctx context.Context, serialNumber st
 	}
 	client := c.clientsByVault[vaultID]
 	if client == nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("missing vault client for %s", vaultID)
+		return certs.DetailedCertificate{}, &Error{VaultID: vaultID, Err: ErrUnknownVault}
 	}
 	details, err := client.GetCertificateDetails(ctx, mountSerial)
 	if err != nil {
-		return certs.DetailedCertificate{}, err
+		return certs.DetailedCertificate{}, withVaultID(vaultID, err)
 	}
 	details.ID = fmt.Sprintf("%s|%s", vaultID, mountSerial)
+	details.CertType, details.CertLabel = c.classifier.Classify(details.Certificate)
+	details.Certificate = c.acks.Acknowledge(details.Certificate)
 	return details, nil
 }
 
@@ -132,9 +170,13 @@ func (c *multiClient) GetCertificatePEM(ctx context.Context, serialNumber string
 	}
 	client := c.clientsByVault[vaultID]
 	if client == nil {
-		return certs.PEMResponse{}, fmt.Errorf("missing vault client for %s", vaultID)
+		return certs.PEMResponse{}, &Error{VaultID: vaultID, Err: ErrUnknownVault}
 	}
-	return client.GetCertificatePEM(ctx, mountSerial)
+	pemResponse, err := client.GetCertificatePEM(ctx, mountSerial)
+	if err != nil {
+		return certs.PEMResponse{}, withVaultID(vaultID, err)
+	}
+	return pemResponse, nil
 }
 
 func (c *multiClient) GetIntermediateCA(ctx context.Context, mount string) (certs.DetailedCertificate, error) {
@@ -144,16 +186,78 @@ func (c *multiClient) GetIntermediateCA(ctx context.Context, mount string) (cert
 	}
 	client := c.clientsByVault[vaultID]
 	if client == nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("missing vault client for %s", vaultID)
+		return certs.DetailedCertificate{}, &Error{VaultID: vaultID, Err: ErrUnknownVault}
 	}
 	details, err := client.GetIntermediateCA(ctx, pureMount)
 	if err != nil {
-		return certs.DetailedCertificate{}, err
+		return certs.DetailedCertificate{}, withVaultID(vaultID, err)
 	}
 	details.ID = fmt.Sprintf("%s|%s", vaultID, details.ID)
+	details.CertType, details.CertLabel = c.classifier.Classify(details.Certificate)
+	details.Certificate = c.acks.Acknowledge(details.Certificate)
 	return details, nil
 }
 
+// GetCAChain resolves a composite "vault|mount" key. Clients without chain
+// support fall back to the issuing CA alone.
+func (c *multiClient) GetCAChain(ctx context.Context, mount string) ([]string, error) {
+	vaultID, pureMount, err := parseCompositeCAID(c.orderedVaultIDs, mount)
+	if err != nil {
+		return nil, err
+	}
+	client := c.clientsByVault[vaultID]
+	if client == nil {
+		return nil, &Error{VaultID: vaultID, Err: ErrUnknownVault}
+	}
+	if getter, ok := client.(CAChainGetter); ok {
+		chain, chainErr := getter.GetCAChain(ctx, pureMount)
+		return chain, withVaultID(vaultID, chainErr)
+	}
+	details, err := client.GetIntermediateCA(ctx, pureMount)
+	if err != nil {
+		return nil, withVaultID(vaultID, err)
+	}
+	return []string{details.PEM}, nil
+}
+
+// GetIssuers resolves a composite "vault|mount" key. Clients without issuer
+// listing fall back to the issuing CA alone.
+func (c *multiClient) GetIssuers(ctx context.Context, mount string) ([]string, error) {
+	vaultID, pureMount, err := parseCompositeCAID(c.orderedVaultIDs, mount)
+	if err != nil {
+		return nil, err
+	}
+	client := c.clientsByVault[vaultID]
+	if client == nil {
+		return nil, &Error{VaultID: vaultID, Err: ErrUnknownVault}
+	}
+	if getter, ok := client.(IssuersGetter); ok {
+		issuers, issuersErr := getter.GetIssuers(ctx, pureMount)
+		return issuers, withVaultID(vaultID, issuersErr)
+	}
+	details, err := client.GetIntermediateCA(ctx, pureMount)
+	if err != nil {
+		return nil, withVaultID(vaultID, err)
+	}
+	return []string{details.PEM}, nil
+}
+
+// Mounts returns the mounts of the enabled vaults as "vault|mount" keys, in
+// configuration order.
+func (c *multiClient) Mounts() []string {
+	var mounts []string
+	for _, vaultID := range c.activeVaultIDs() {
+		lister, ok := c.clientsByVault[vaultID].(MountLister)
+		if !ok {
+			continue
+		}
+		for _, mount := range lister.Mounts() {
+			mounts = append(mounts, vaultID+"|"+mount)
+		}
+	}
+	return mounts
+}
+
 func (c *multiClient) InvalidateCache() {
 	unique := make(map[Client]struct{})
 	for _, client := range c.clientsByVault {
@@ -263,13 +367,26 @@ func (c *multiClient) ListCertificates(ctx context.Context) ([]certs.Certificate
 		}
 		return left.ID < right.ID
 	})
-	return all, nil
+	return c.present(all), nil
 }
 
 // ListCertificatesEnvelope mirrors ListCertificates but returns the full set
 // of per-vault errors alongside successful certificates. Used by the HTTP
 // handler to expose partial-success state to the frontend.
 func (c *multiClient) ListCertificatesEnvelope(ctx context.Context) ([]certs.Certificate, []VaultError) {
+	all, errs := c.listEnvelope(ctx)
+	return c.present(all), errs
+}
+
+// ListInventory is ListCertificatesEnvelope without the mount policies:
+// certificates are classified and acknowledgements flagged, but none is
+// hidden or rolled up.
+func (c *multiClient) ListInventory(ctx context.Context) ([]certs.Certificate, []VaultError) {
+	all, errs := c.listEnvelope(ctx)
+	return c.acks.Apply(c.classifier.Apply(all)), errs
+}
+
+func (c *multiClient) listEnvelope(ctx context.Context) ([]certs.Certificate, []VaultError) {
 	active := c.activeVaultIDs()
 	if len(active) == 0 {
 		return []certs.Certificate{}, []VaultError{}
@@ -353,11 +470,33 @@ func (c *multiClient) ListCertificatesByVault(ctx context.Context) []ListCertifi
 			value.ID = fmt.Sprintf("%s|%s", vaultID, certificate.ID)
 			prefixed = append(prefixed, value)
 		}
-		results = append(results, ListCertificatesByVaultResult{VaultID: vaultID, Certificates: prefixed, Duration: duration, ListError: nil})
+		results = append(results, ListCertificatesByVaultResult{VaultID: vaultID, Certificates: c.present(prefixed), Duration: duration, ListError: nil})
 	}
 	return results
 }
 
+// present classifies listed certificates, applies mount policies and flags
+// acknowledged ones.
+func (c *multiClient) present(certificates []certs.Certificate) []certs.Certificate {
+	return c.acks.Apply(c.policies.Apply(c.classifier.Apply(certificates), time.Now()))
+}
+
+// LastSync returns the most recent sync of the active vaults, so a change in
+// any of them moves the combined inventory forward.
+func (c *multiClient) LastSync() time.Time {
+	var latest time.Time
+	for _, vaultID := range c.activeVaultIDs() {
+		reporter, ok := c.clientsByVault[vaultID].(SyncReporter)
+		if !ok {
+			continue
+		}
+		if synced := reporter.LastSync(); synced.After(latest) {
+			latest = synced
+		}
+	}
+	return latest
+}
+
 func (c *multiClient) CacheSize() int {
 	unique := make(map[Client]struct{})
 	for _, client := range c.clientsByVault {
@@ -394,16 +533,16 @@ func parseCompositeCertificateID(orderedVaultIDs []string, value string) (string
 		vaultID := strings.TrimSpace(parts[0])
 		mountSerial := strings.TrimSpace(parts[1])
 		if vaultID == "" || mountSerial == "" {
-			return "", "", fmt.Errorf("invalid certificate id")
+			return "", "", ErrInvalidCertificateID
 		}
 		return vaultID, mountSerial, nil
 	}
 	if len(orderedVaultIDs) == 0 {
-		return "", "", fmt.Errorf("invalid certificate id")
+		return "", "", ErrInvalidCertificateID
 	}
 	mountSerial := strings.TrimSpace(value)
 	if mountSerial == "" {
-		return "", "", fmt.Errorf("invalid certificate id")
+		return "", "", ErrInvalidCertificateID
 	}
 	return orderedVaultIDs[0], mountSerial, nil
 }
@@ -414,16 +553,16 @@ func parseCompositeCAID(orderedVaultIDs []string, value string) (string, string,
 		vaultID := strings.TrimSpace(parts[0])
 		pureMount := strings.TrimSpace(parts[1])
 		if vaultID == "" || pureMount == "" {
-			return "", "", fmt.Errorf("invalid ca mount")
+			return "", "", fmt.Errorf("%w: invalid ca mount", ErrInvalidCertificateID)
 		}
 		return vaultID, pureMount, nil
 	}
 	if len(orderedVaultIDs) == 0 {
-		return "", "", fmt.Errorf("no vaults configured")
+		return "", "", ErrVaultNotConfigured
 	}
 	mount := strings.TrimSpace(value)
 	if mount == "" {
-		return "", "", fmt.Errorf("mount cannot be empty")
+		return "", "", fmt.Errorf("%w: mount cannot be empty", ErrInvalidCertificateID)
 	}
 	return orderedVaultIDs[0], mount, nil
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"vcv/internal/ack"
 	"vcv/internal/certs"
 	"vcv/internal/config"
 	"vcv/internal/logger"
@@ -25,6 +27,24 @@ func (c *fakeSizerClient) CacheSize() int {
 	return c.cacheSize
 }
 
+type fakeMountsClient struct {
+	MockClient
+	mounts []string
+}
+
+func (c *fakeMountsClient) Mounts() []string {
+	return c.mounts
+}
+
+type fakeSyncClient struct {
+	MockClient
+	lastSync time.Time
+}
+
+func (c *fakeSyncClient) LastSync() time.Time {
+	return c.lastSync
+}
+
 func TestDisabledClient(t *testing.T) {
 	client := &disabledClient{}
 	err := client.CheckConnection(context.Background())
@@ -120,6 +140,48 @@ func TestMultiClient_GetIntermediateCA_MissingClient(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMultiClient_GetCAChain_FallsBackToIssuingCA(t *testing.T) {
+	instances := []config.VaultInstance{{ID: "v1"}}
+	c1 := &MockClient{}
+	c1.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{PEM: "ca-pem"}, nil)
+	m := NewMultiClient(instances, map[string]Client{"v1": c1}, nil)
+	getter, ok := m.(CAChainGetter)
+	assert.True(t, ok)
+	chain, err := getter.GetCAChain(context.Background(), "v1|pki")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ca-pem"}, chain)
+	c1.AssertExpectations(t)
+}
+
+func TestMultiClient_GetIssuers_FallsBackToIssuingCA(t *testing.T) {
+	instances := []config.VaultInstance{{ID: "v1"}}
+	c1 := &MockClient{}
+	c1.On("GetIntermediateCA", mock.Anything, "pki").Return(certs.DetailedCertificate{PEM: "ca-pem"}, nil)
+	m := NewMultiClient(instances, map[string]Client{"v1": c1}, nil)
+	getter, ok := m.(IssuersGetter)
+	assert.True(t, ok)
+	issuers, err := getter.GetIssuers(context.Background(), "v1|pki")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ca-pem"}, issuers)
+	c1.AssertExpectations(t)
+}
+
+func TestMultiClient_Mounts(t *testing.T) {
+	instances := []config.VaultInstance{
+		{ID: "v1", Enabled: boolPtr(true)},
+		{ID: "v2", Enabled: boolPtr(false)},
+		{ID: "v3", Enabled: boolPtr(true)},
+	}
+	m := NewMultiClient(instances, map[string]Client{
+		"v1": &fakeMountsClient{mounts: []string{"pki", "pki_int"}},
+		"v2": &fakeMountsClient{mounts: []string{"pki"}},
+		"v3": &MockClient{},
+	}, NewRegistry(instances))
+	lister, ok := m.(MountLister)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"v1|pki", "v1|pki_int"}, lister.Mounts(), "disabled vaults and clients without mounts are left out")
+}
+
 func TestMultiClient_CheckConnection(t *testing.T) {
 	t.Run("not configured", func(t *testing.T) {
 		m := NewMultiClient([]config.VaultInstance{}, map[string]Client{}, nil)
@@ -233,6 +295,22 @@ func TestMultiClient_CacheSize_AggregatesUnique(t *testing.T) {
 	assert.Equal(t, 5, size)
 }
 
+func TestMultiClient_LastSync_LatestActiveVault(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	instances := []config.VaultInstance{{ID: "v1"}, {ID: "v2"}, {ID: "v3"}}
+	clients := map[string]Client{
+		"v1": &fakeSyncClient{lastSync: older},
+		"v2": &fakeSyncClient{lastSync: newer},
+		"v3": &MockClient{},
+	}
+	m := NewMultiClient(instances, clients, nil)
+	assert.Equal(t, newer, m.(SyncReporter).LastSync())
+
+	empty := NewMultiClient(nil, nil, nil)
+	assert.True(t, empty.(SyncReporter).LastSync().IsZero())
+}
+
 func TestMultiClient_InvalidateCache_And_Shutdown_Unique(t *testing.T) {
 	instances := []config.VaultInstance{{ID: "v1"}, {ID: "v2"}}
 	c1 := &MockClient{}
@@ -609,3 +687,94 @@ func TestMultiClient_ListCertificatesEnvelope(t *testing.T) {
 		assert.Equal(t, "v1", resultErrs[0].VaultID)
 	})
 }
+
+func TestMultiClient_WithClassifier(t *testing.T) {
+	classifier, err := certs.NewClassifier([]config.ClassificationRule{{Type: "ca", IsCA: boolPtr(true)}})
+	assert.NoError(t, err)
+	client := new(MockClient)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "pki:1", CommonName: "root", CertType: "unknown", IsCA: true},
+		{ID: "pki:2", CommonName: "leaf", CertType: "machine"},
+	}, nil)
+	client.On("GetCertificateDetails", mock.Anything, "pki:1").Return(certs.DetailedCertificate{Certificate: certs.Certificate{ID: "pki:1", CertType: "unknown", IsCA: true}}, nil)
+	multi := NewMultiClient([]config.VaultInstance{{ID: "v1"}}, map[string]Client{"v1": client}, nil, WithClassifier(classifier))
+
+	listed, err := multi.ListCertificates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "machine", listed[0].CertType)
+	assert.Equal(t, "ca", listed[1].CertType)
+
+	enveloped, _ := multi.(CertificatesEnvelopeLister).ListCertificatesEnvelope(context.Background())
+	assert.Equal(t, "ca", enveloped[1].CertType)
+
+	byVault := multi.(CertificatesByVaultLister).ListCertificatesByVault(context.Background())
+	assert.Equal(t, "ca", byVault[0].Certificates[0].CertType)
+
+	details, err := multi.GetCertificateDetails(context.Background(), "v1|pki:1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ca", details.CertType)
+}
+
+func TestMultiClient_WithMountPolicies(t *testing.T) {
+	policies, err := certs.NewMountPolicies(map[string]config.MountPolicy{"pki_mesh": {HideExpiredAfterDays: 1, RollupLifetime: "24h"}})
+	assert.NoError(t, err)
+	now := time.Now()
+	client := new(MockClient)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "pki_mesh:1", CommonName: "svc", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(10 * time.Hour)},
+		{ID: "pki_mesh:2", CommonName: "svc", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(11 * time.Hour)},
+		{ID: "pki_mesh:3", CommonName: "svc", CreatedAt: now.Add(-72 * time.Hour), ExpiresAt: now.Add(-48 * time.Hour)},
+		{ID: "pki:4", CommonName: "web", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(10 * time.Hour)},
+	}, nil)
+	multi := NewMultiClient([]config.VaultInstance{{ID: "v1"}}, map[string]Client{"v1": client}, nil, WithMountPolicies(policies))
+
+	listed, err := multi.ListCertificates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, listed, 2)
+	assert.Equal(t, "v1|pki_mesh:2", listed[0].ID)
+	assert.Equal(t, 2, listed[0].RollupCount)
+	assert.Equal(t, "v1|pki:4", listed[1].ID)
+
+	enveloped, _ := multi.(CertificatesEnvelopeLister).ListCertificatesEnvelope(context.Background())
+	assert.Len(t, enveloped, 2)
+
+	byVault := multi.(CertificatesByVaultLister).ListCertificatesByVault(context.Background())
+	assert.Len(t, byVault[0].Certificates, 2)
+
+	inventory, errs := multi.(InventoryLister).ListInventory(context.Background())
+	assert.Empty(t, errs)
+	assert.Len(t, inventory, 4, "the inventory is not hidden or rolled up")
+	for _, certificate := range inventory {
+		assert.Zero(t, certificate.RollupCount)
+	}
+}
+
+func TestMultiClient_WithAcknowledgements(t *testing.T) {
+	store, err := ack.NewStore(filepath.Join(t.TempDir(), ack.FileName))
+	assert.NoError(t, err)
+	_, err = store.Add(ack.Entry{Pattern: "*.old.example.com", Reason: "decommissioned"})
+	assert.NoError(t, err)
+	client := new(MockClient)
+	client.On("ListCertificates", mock.Anything).Return([]certs.Certificate{
+		{ID: "pki:1", CommonName: "web.old.example.com"},
+		{ID: "pki:2", CommonName: "web.example.com"},
+	}, nil)
+	client.On("GetCertificateDetails", mock.Anything, "pki:1").Return(certs.DetailedCertificate{Certificate: certs.Certificate{ID: "pki:1", CommonName: "web.old.example.com"}}, nil)
+	multi := NewMultiClient([]config.VaultInstance{{ID: "v1"}}, map[string]Client{"v1": client}, nil, WithAcknowledgements(store))
+
+	listed, err := multi.ListCertificates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, listed, 2)
+	byID := make(map[string]certs.Certificate, len(listed))
+	for _, certificate := range listed {
+		byID[certificate.ID] = certificate
+	}
+	if assert.NotNil(t, byID["v1|pki:1"].Acknowledgement) {
+		assert.Equal(t, "decommissioned", byID["v1|pki:1"].Acknowledgement.Reason)
+	}
+	assert.Nil(t, byID["v1|pki:2"].Acknowledgement)
+
+	details, err := multi.GetCertificateDetails(context.Background(), "v1|pki:1")
+	assert.NoError(t, err)
+	assert.NotNil(t, details.Acknowledgement)
+}
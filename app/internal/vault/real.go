@@ -7,11 +7,13 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"slices"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"vcv/internal/cache"
@@ -30,6 +32,8 @@ type realClient struct {
 	addr     string
 	cache    *cache.Cache
 	stopChan chan struct{}
+	// lastSync is the UnixNano time of the last listing read from Vault.
+	lastSync atomic.Int64
 }
 
 func decodeBase64String(value string) ([]byte, error) {
@@ -254,6 +258,7 @@ func (c *realClient) ListCertificates(ctx context.Context) ([]certs.Certificate,
 
 	// Cache the result
 	c.cache.Set(cacheVersion+":certificates", allCertificates)
+	c.lastSync.Store(time.Now().UnixNano())
 
 	logger.Get().Debug().
 		Str("vault_addr", c.addr).
@@ -264,6 +269,15 @@ func (c *realClient) ListCertificates(ctx context.Context) ([]certs.Certificate,
 	return allCertificates, nil
 }
 
+// LastSync returns when ListCertificates last read from Vault.
+func (c *realClient) LastSync() time.Time {
+	nanos := c.lastSync.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
 func (c *realClient) listCertificatesFromMount(ctx context.Context, mount string) ([]certs.Certificate, map[string]bool, error) {
 	listPath := fmt.Sprintf("%s/certs", mount)
 	secret, err := c.client.Logical().ListWithContext(ctx, listPath)
@@ -332,50 +346,71 @@ func (c *realClient) fetchRevokedSerialsFromMount(ctx context.Context, mount str
 
 func (c *realClient) readCertificateFromMount(ctx context.Context, mount, serial string) (certs.Certificate, error) {
 	if serial == "" {
-		return certs.Certificate{}, fmt.Errorf("serial number cannot be empty")
+		return certs.Certificate{}, fmt.Errorf("%w: serial number cannot be empty", ErrInvalidCertificateID)
 	}
 
 	path := fmt.Sprintf("%s/cert/%s", mount, serial)
 	secret, err := c.client.Logical().ReadWithContext(ctx, path)
 	if err != nil {
-		return certs.Certificate{}, fmt.Errorf("failed to read certificate %s from mount %s: %w", serial, mount, err)
+		return certs.Certificate{}, fmt.Errorf("failed to read certificate %s from mount %s: %w: %w", serial, mount, readCause(err), err)
 	}
 	if secret == nil || secret.Data == nil {
-		return certs.Certificate{}, fmt.Errorf("certificate %s not found in mount %s", serial, mount)
+		return certs.Certificate{}, fmt.Errorf("%w: %s in mount %s", ErrCertificateNotFound, serial, mount)
 	}
 
 	certificatePEM, ok := secret.Data["certificate"].(string)
 	if !ok || certificatePEM == "" {
-		return certs.Certificate{}, fmt.Errorf("certificate field missing for %s in mount %s", serial, mount)
+		return certs.Certificate{}, fmt.Errorf("%w: certificate field missing for %s in mount %s", ErrVaultResponse, serial, mount)
 	}
 
 	block, _ := pem.Decode([]byte(certificatePEM))
 	if block == nil {
-		return certs.Certificate{}, fmt.Errorf("failed to decode PEM for certificate %s in mount %s", serial, mount)
+		return certs.Certificate{}, fmt.Errorf("%w: failed to decode PEM for certificate %s in mount %s", ErrVaultResponse, serial, mount)
 	}
 
 	x509Certificate, parseError := x509.ParseCertificate(block.Bytes)
 	if parseError != nil {
-		return certs.Certificate{}, fmt.Errorf("failed to parse certificate %s in mount %s: %w", serial, mount, parseError)
+		return certs.Certificate{}, fmt.Errorf("%w: failed to parse certificate %s in mount %s: %w", ErrVaultResponse, serial, mount, parseError)
 	}
 
-	subjectAlternativeNames := buildSANs(x509Certificate)
-
-	algo, keySize := certs.KeyAlgoAndSize(x509Certificate)
+	certificate := certs.NewCertificate(x509Certificate)
 	// Prefix ID with mount to avoid collisions across mounts
-	return certs.Certificate{
-		ID:           fmt.Sprintf("%s:%s", mount, serial),
-		SerialNumber: serial,
-		CommonName:   x509Certificate.Subject.CommonName,
-		Sans:         subjectAlternativeNames,
-		CertType:     certs.InferCertType(x509Certificate),
-		CreatedAt:    x509Certificate.NotBefore.UTC(),
-		ExpiresAt:    x509Certificate.NotAfter.UTC(),
-		Revoked:      false,
-		IssuerCN:     x509Certificate.Issuer.CommonName,
-		KeyAlgorithm: algo,
-		KeySize:      keySize,
-	}, nil
+	certificate.ID = fmt.Sprintf("%s:%s", mount, serial)
+	certificate.SerialNumber = serial
+	certificate.RevokedAt = revocationTime(secret.Data)
+	return certificate, nil
+}
+
+// revocationTime reads the revocation time of a pki/cert/<serial> response.
+// Vault reports revocation_time as Unix seconds, 0 when not revoked, and
+// newer versions add revocation_time_rfc3339.
+func revocationTime(data map[string]any) *time.Time {
+	if value, ok := data["revocation_time_rfc3339"].(string); ok && value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			revokedAt := parsed.UTC()
+			return &revokedAt
+		}
+	}
+	var seconds int64
+	switch value := data["revocation_time"].(type) {
+	case json.Number:
+		parsed, err := value.Int64()
+		if err != nil {
+			return nil
+		}
+		seconds = parsed
+	case float64:
+		seconds = int64(value)
+	case int64:
+		seconds = value
+	case int:
+		seconds = int64(value)
+	}
+	if seconds <= 0 {
+		return nil
+	}
+	revokedAt := time.Unix(seconds, 0).UTC()
+	return &revokedAt
 }
 
 func (c *realClient) GetCertificateDetails(ctx context.Context, serialNumber string) (certs.DetailedCertificate, error) {
@@ -385,7 +420,7 @@ func (c *realClient) GetCertificateDetails(ctx context.Context, serialNumber str
 		return certs.DetailedCertificate{}, err
 	}
 	if serial == "" {
-		return certs.DetailedCertificate{}, fmt.Errorf("serial number cannot be empty")
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: serial number cannot be empty", ErrInvalidCertificateID)
 	}
 
 	logger.Get().Debug().
@@ -415,48 +450,25 @@ func (c *realClient) GetCertificateDetails(ctx context.Context, serialNumber str
 			Str("serial", serial).
 			Err(err).
 			Msg("failed to read certificate from vault")
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to read certificate %s from mount %s: %w", serial, mount, err)
+		return certs.DetailedCertificate{}, fmt.Errorf("failed to read certificate %s from mount %s: %w: %w", serial, mount, readCause(err), err)
 	}
 	if secret == nil || secret.Data == nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("certificate %s not found in mount %s", serial, mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: %s in mount %s", ErrCertificateNotFound, serial, mount)
 	}
 
 	certificatePEM, ok := secret.Data["certificate"].(string)
 	if !ok || certificatePEM == "" {
-		return certs.DetailedCertificate{}, fmt.Errorf("certificate field missing for %s in mount %s", serial, mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: certificate field missing for %s in mount %s", ErrVaultResponse, serial, mount)
 	}
 
 	block, _ := pem.Decode([]byte(certificatePEM))
 	if block == nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to decode PEM for certificate %s in mount %s", serial, mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: failed to decode PEM for certificate %s in mount %s", ErrVaultResponse, serial, mount)
 	}
 
 	x509Certificate, parseError := x509.ParseCertificate(block.Bytes)
 	if parseError != nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to parse certificate %s in mount %s: %w", serial, mount, parseError)
-	}
-
-	// Calculate fingerprints
-	sha1Fingerprint := sha1.Sum(x509Certificate.Raw)
-	sha256Fingerprint := sha256.Sum256(x509Certificate.Raw)
-
-	subjectAlternativeNames := buildSANs(x509Certificate)
-
-	// Extract key usage
-	var usage []string
-	if len(x509Certificate.ExtKeyUsage) > 0 {
-		for _, extUsage := range x509Certificate.ExtKeyUsage {
-			switch extUsage {
-			case x509.ExtKeyUsageServerAuth:
-				usage = append(usage, "Server Auth")
-			case x509.ExtKeyUsageClientAuth:
-				usage = append(usage, "Client Auth")
-			case x509.ExtKeyUsageCodeSigning:
-				usage = append(usage, "Code Signing")
-			case x509.ExtKeyUsageEmailProtection:
-				usage = append(usage, "Email Protection")
-			}
-		}
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: failed to parse certificate %s in mount %s: %w", ErrVaultResponse, serial, mount, parseError)
 	}
 
 	// Get revoked status
@@ -465,30 +477,11 @@ func (c *realClient) GetCertificateDetails(ctx context.Context, serialNumber str
 		return certs.DetailedCertificate{}, err
 	}
 
-	algo, keySize := certs.KeyAlgoAndSize(x509Certificate)
-	details := certs.DetailedCertificate{
-		Certificate: certs.Certificate{
-			ID:           serialNumber, // Keep the prefixed ID
-			SerialNumber: serial,       // Store only the serial part
-			CommonName:   x509Certificate.Subject.CommonName,
-			Sans:         subjectAlternativeNames,
-			CertType:     certs.InferCertType(x509Certificate),
-			CreatedAt:    x509Certificate.NotBefore.UTC(),
-			ExpiresAt:    x509Certificate.NotAfter.UTC(),
-			Revoked:      revokedSet[serial],
-			IssuerCN:     x509Certificate.Issuer.CommonName,
-			KeyAlgorithm: algo,
-			KeySize:      keySize,
-		},
-		Issuer:            x509Certificate.Issuer.String(),
-		Subject:           x509Certificate.Subject.String(),
-		KeyAlgorithm:      algo,
-		KeySize:           keySize,
-		FingerprintSHA1:   hex.EncodeToString(sha1Fingerprint[:]),
-		FingerprintSHA256: hex.EncodeToString(sha256Fingerprint[:]),
-		Usage:             usage,
-		PEM:               certificatePEM,
-	}
+	details := certs.NewDetailedCertificate(x509Certificate, certificatePEM)
+	details.ID = serialNumber     // Keep the prefixed ID
+	details.SerialNumber = serial // Store only the serial part
+	details.Revoked = revokedSet[serial]
+	details.RevokedAt = revocationTime(secret.Data)
 
 	// Cache the full detailed certificate
 	c.cache.Set(cacheKey, details)
@@ -515,12 +508,12 @@ func (c *realClient) parseMountAndSerial(serialNumber string) (string, string, e
 		if slices.Contains(c.mounts, mount) {
 			return mount, serial, nil
 		}
-		return "", "", fmt.Errorf("mount %s is not configured", mount)
+		return "", "", fmt.Errorf("%w: %s", ErrMountNotConfigured, mount)
 	}
 
 	// Legacy behavior: if no prefix, use the first configured mount
 	if len(c.mounts) == 0 {
-		return "", "", fmt.Errorf("no mounts configured")
+		return "", "", fmt.Errorf("%w: no mounts configured", ErrMountNotConfigured)
 	}
 	return c.mounts[0], serialNumber, nil
 }
@@ -554,10 +547,10 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 		Msg("getting intermediate CA certificate")
 
 	if mount == "" {
-		return certs.DetailedCertificate{}, fmt.Errorf("mount cannot be empty")
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: mount cannot be empty", ErrInvalidCertificateID)
 	}
 	if !slices.Contains(c.mounts, mount) {
-		return certs.DetailedCertificate{}, fmt.Errorf("mount %s is not configured", mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: %s", ErrMountNotConfigured, mount)
 	}
 
 	// Try cache first
@@ -583,7 +576,7 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 			Str("path", path).
 			Err(err).
 			Msg("failed to read CA certificate from vault")
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to read CA for mount %s: %w", mount, err)
+		return certs.DetailedCertificate{}, fmt.Errorf("failed to read CA for mount %s: %w: %w", mount, readCause(err), err)
 	}
 	if secret == nil || secret.Data == nil {
 		logger.Get().Warn().
@@ -591,7 +584,7 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 			Str("mount", mount).
 			Str("path", path).
 			Msg("CA endpoint returned nil data")
-		return certs.DetailedCertificate{}, fmt.Errorf("CA not found in mount %s", mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: CA in mount %s", ErrCertificateNotFound, mount)
 	}
 
 	caPEM, _ := secret.Data["certificate"].(string)
@@ -602,17 +595,17 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 			Str("path", path).
 			Interface("data_keys", getMapKeys(secret.Data)).
 			Msg("certificate field missing in CA response")
-		return certs.DetailedCertificate{}, fmt.Errorf("certificate field missing in CA response (keys: %v)", getMapKeys(secret.Data))
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: certificate field missing in CA response (keys: %v)", ErrVaultResponse, getMapKeys(secret.Data))
 	}
 
 	block, _ := pem.Decode([]byte(caPEM))
 	if block == nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to decode PEM for CA in mount %s", mount)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: failed to decode PEM for CA in mount %s", ErrVaultResponse, mount)
 	}
 
 	x509Certificate, parseError := x509.ParseCertificate(block.Bytes)
 	if parseError != nil {
-		return certs.DetailedCertificate{}, fmt.Errorf("failed to parse CA in mount %s: %w", mount, parseError)
+		return certs.DetailedCertificate{}, fmt.Errorf("%w: failed to parse CA in mount %s: %w", ErrVaultResponse, mount, parseError)
 	}
 
 	caType := "intermediate"
@@ -627,17 +620,20 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 	algo, keySize := certs.KeyAlgoAndSize(x509Certificate)
 	details := certs.DetailedCertificate{
 		Certificate: certs.Certificate{
-			ID:           fmt.Sprintf("%s:ca", mount),
-			SerialNumber: x509Certificate.SerialNumber.String(),
-			CommonName:   x509Certificate.Subject.CommonName,
-			Sans:         append([]string(nil), x509Certificate.DNSNames...),
-			CertType:     certs.InferCertType(x509Certificate),
-			CreatedAt:    x509Certificate.NotBefore.UTC(),
-			ExpiresAt:    x509Certificate.NotAfter.UTC(),
-			Revoked:      false,
-			IssuerCN:     x509Certificate.Issuer.CommonName,
-			KeyAlgorithm: algo,
-			KeySize:      keySize,
+			ID:                   fmt.Sprintf("%s:ca", mount),
+			SerialNumber:         x509Certificate.SerialNumber.String(),
+			CommonName:           x509Certificate.Subject.CommonName,
+			Sans:                 append([]string(nil), x509Certificate.DNSNames...),
+			CertType:             certs.InferCertType(x509Certificate),
+			CreatedAt:            x509Certificate.NotBefore.UTC(),
+			ExpiresAt:            x509Certificate.NotAfter.UTC(),
+			Revoked:              false,
+			IssuerCN:             x509Certificate.Issuer.CommonName,
+			KeyAlgorithm:         algo,
+			KeySize:              keySize,
+			PublicKeyFingerprint: certs.PublicKeyFingerprint(x509Certificate),
+			ExtKeyUsages:         certs.ExtKeyUsageOIDs(x509Certificate),
+			IsCA:                 x509Certificate.IsCA,
 		},
 		Issuer:            x509Certificate.Issuer.String(),
 		Subject:           x509Certificate.Subject.String(),
@@ -661,6 +657,113 @@ func (c *realClient) GetIntermediateCA(ctx context.Context, mount string) (certs
 	return details, nil
 }
 
+// GetCAChain reads <mount>/cert/ca_chain, which lists the issuing CA and its
+// parents. Mounts whose chain is empty fall back to the issuing CA.
+func (c *realClient) GetCAChain(ctx context.Context, mount string) ([]string, error) {
+	if mount == "" {
+		return nil, fmt.Errorf("%w: mount cannot be empty", ErrInvalidCertificateID)
+	}
+	if !slices.Contains(c.mounts, mount) {
+		return nil, fmt.Errorf("%w: %s", ErrMountNotConfigured, mount)
+	}
+	cacheKey := fmt.Sprintf("%s:ca_chain_%s", cacheVersion, mount)
+	if cached, found := c.cache.Get(cacheKey); found {
+		if chain, ok := cached.([]string); ok {
+			return chain, nil
+		}
+	}
+
+	path := fmt.Sprintf("%s/cert/ca_chain", mount)
+	secret, err := c.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA chain for mount %s: %w: %w", mount, readCause(err), err)
+	}
+	var chainPEM string
+	if secret != nil && secret.Data != nil {
+		if entries, ok := secret.Data["ca_chain"].([]any); ok {
+			for _, entry := range entries {
+				if value, isString := entry.(string); isString {
+					chainPEM += strings.TrimSpace(value) + "\n"
+				}
+			}
+		}
+		if chainPEM == "" {
+			chainPEM, _ = secret.Data["certificate"].(string)
+		}
+	}
+	ders, decodeErr := certs.DecodePEMCertificates(chainPEM)
+	if decodeErr != nil {
+		details, caErr := c.GetIntermediateCA(ctx, mount)
+		if caErr != nil {
+			return nil, caErr
+		}
+		return []string{details.PEM}, nil
+	}
+	chain := make([]string, len(ders))
+	for i, der := range ders {
+		chain[i] = certs.EncodePEMCertificates([][]byte{der})
+	}
+	c.cache.Set(cacheKey, chain)
+	return chain, nil
+}
+
+// GetIssuers reads every issuer listed at <mount>/issuers. Mounts from
+// before multiple issuers list none and fall back to the issuing CA.
+func (c *realClient) GetIssuers(ctx context.Context, mount string) ([]string, error) {
+	if mount == "" {
+		return nil, fmt.Errorf("%w: mount cannot be empty", ErrInvalidCertificateID)
+	}
+	if !slices.Contains(c.mounts, mount) {
+		return nil, fmt.Errorf("%w: %s", ErrMountNotConfigured, mount)
+	}
+	cacheKey := fmt.Sprintf("%s:issuers_%s", cacheVersion, mount)
+	if cached, found := c.cache.Get(cacheKey); found {
+		if issuers, ok := cached.([]string); ok {
+			return issuers, nil
+		}
+	}
+
+	secret, err := c.client.Logical().ListWithContext(ctx, fmt.Sprintf("%s/issuers", mount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issuers for mount %s: %w: %w", mount, readCause(err), err)
+	}
+	var issuers []string
+	if secret != nil && secret.Data != nil {
+		keys, _ := secret.Data["keys"].([]any)
+		for _, key := range keys {
+			ref, ok := key.(string)
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("%s/issuer/%s/json", mount, ref)
+			issuer, readErr := c.client.Logical().ReadWithContext(ctx, path)
+			if readErr != nil {
+				return nil, fmt.Errorf("failed to read issuer %s of mount %s: %w: %w", ref, mount, readCause(readErr), readErr)
+			}
+			if issuer == nil || issuer.Data == nil {
+				continue
+			}
+			if issuerPEM, _ := issuer.Data["certificate"].(string); issuerPEM != "" {
+				issuers = append(issuers, issuerPEM)
+			}
+		}
+	}
+	if len(issuers) == 0 {
+		details, caErr := c.GetIntermediateCA(ctx, mount)
+		if caErr != nil {
+			return nil, caErr
+		}
+		issuers = []string{details.PEM}
+	}
+	c.cache.Set(cacheKey, issuers)
+	return issuers, nil
+}
+
+// Mounts returns the configured PKI mounts.
+func (c *realClient) Mounts() []string {
+	return append([]string(nil), c.mounts...)
+}
+
 func (c *realClient) InvalidateCache() {
 	logger.Get().Debug().
 		Str("vault_addr", c.addr).
@@ -685,14 +788,3 @@ func getMapKeys(m map[string]any) []string {
 	}
 	return keys
 }
-
-// buildSANs collects the subject alternative names (DNS, IP, email) from a certificate.
-func buildSANs(cert *x509.Certificate) []string {
-	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses)+len(cert.EmailAddresses))
-	sans = append(sans, cert.DNSNames...)
-	for _, address := range cert.IPAddresses {
-		sans = append(sans, address.String())
-	}
-	sans = append(sans, cert.EmailAddresses...)
-	return sans
-}
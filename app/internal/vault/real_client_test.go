@@ -86,9 +86,14 @@ func newVaultTestServer(state vaultTestServerState) *httptest.Server {
 			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"keys": []string{"bb"}}})
 			return
 		}
-		if r.Method == http.MethodGet && (r.URL.Path == "/v1/pki/cert/aa" || r.URL.Path == "/v1/pki/cert/bb" || r.URL.Path == "/v1/pki/cert/ca") {
+		if r.Method == http.MethodGet && r.URL.Path == "/v1/pki/cert/bb" {
 			w.WriteHeader(http.StatusOK)
-			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"certificate": state.certificatePEM}})
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"certificate": state.certificatePEM, "revocation_time": 1700000000}})
+			return
+		}
+		if r.Method == http.MethodGet && (r.URL.Path == "/v1/pki/cert/aa" || r.URL.Path == "/v1/pki/cert/ca") {
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"certificate": state.certificatePEM, "revocation_time": 0}})
 			return
 		}
 		w.WriteHeader(http.StatusNotFound)
@@ -345,6 +350,18 @@ func TestRealClient_ListCertificates_And_Details(t *testing.T) {
 	if certificates[0].CertType != "machine" {
 		t.Fatalf("expected machine certificate type, got %q", certificates[0].CertType)
 	}
+	for _, certificate := range certificates {
+		switch certificate.SerialNumber {
+		case "aa":
+			if certificate.Revoked || certificate.RevokedAt != nil {
+				t.Fatalf("expected aa not to be revoked, got %v at %v", certificate.Revoked, certificate.RevokedAt)
+			}
+		case "bb":
+			if !certificate.Revoked || certificate.RevokedAt == nil || !certificate.RevokedAt.Equal(time.Unix(1700000000, 0)) {
+				t.Fatalf("expected bb revoked at 1700000000, got %v at %v", certificate.Revoked, certificate.RevokedAt)
+			}
+		}
+	}
 	details, err := client.GetCertificateDetails(ctx, "pki:aa")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -450,6 +467,106 @@ func TestRealClient_GetIntermediateCA(t *testing.T) {
 	}
 }
 
+func TestRealClient_GetCAChain(t *testing.T) {
+	issuingPEM := newVaultTestCertificatePEM(t)
+	rootPEM := newVaultTestCertificatePEM(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/v1/pki/cert/ca_chain" {
+			requests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"ca_chain": []string{issuingPEM, rootPEM}}})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	chain, err := client.GetCAChain(context.Background(), "pki")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(chain) != 2 || chain[0] != issuingPEM || chain[1] != rootPEM {
+		t.Fatalf("unexpected chain: %v", chain)
+	}
+	if _, err := client.GetCAChain(context.Background(), "pki"); err != nil {
+		t.Fatalf("expected cached chain, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected 1 vault request, got %d", got)
+	}
+	if _, err := client.GetCAChain(context.Background(), "other"); err == nil {
+		t.Fatalf("expected error for unconfigured mount")
+	}
+}
+
+func TestRealClient_GetCAChain_EmptyFallsBackToIssuingCA(t *testing.T) {
+	certificatePEM := newVaultTestCertificatePEM(t)
+	server := newVaultTestServer(vaultTestServerState{certificatePEM: certificatePEM})
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	chain, err := client.GetCAChain(context.Background(), "pki")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(chain) != 1 || chain[0] != certificatePEM {
+		t.Fatalf("expected issuing CA fallback, got %v", chain)
+	}
+}
+
+func TestRealClient_GetIssuers(t *testing.T) {
+	defaultPEM := newVaultTestCertificatePEM(t)
+	rotatedPEM := newVaultTestCertificatePEM(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case (r.Method == "LIST" || r.URL.Query().Get("list") == "true") && r.URL.Path == "/v1/pki/issuers":
+			requests.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"keys": []string{"a1", "b2"}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/pki/issuer/a1/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"certificate": defaultPEM}})
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/pki/issuer/b2/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"certificate": rotatedPEM}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	issuers, err := client.GetIssuers(context.Background(), "pki")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(issuers) != 2 || issuers[0] != defaultPEM || issuers[1] != rotatedPEM {
+		t.Fatalf("unexpected issuers: %v", issuers)
+	}
+	if _, err := client.GetIssuers(context.Background(), "pki"); err != nil {
+		t.Fatalf("expected cached issuers, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected 1 vault request, got %d", got)
+	}
+	if _, err := client.GetIssuers(context.Background(), "other"); err == nil {
+		t.Fatalf("expected error for unconfigured mount")
+	}
+}
+
+func TestRealClient_GetIssuers_NoIssuersFallsBackToIssuingCA(t *testing.T) {
+	certificatePEM := newVaultTestCertificatePEM(t)
+	server := newVaultTestServer(vaultTestServerState{certificatePEM: certificatePEM})
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	issuers, err := client.GetIssuers(context.Background(), "pki")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(issuers) != 1 || issuers[0] != certificatePEM {
+		t.Fatalf("expected issuing CA fallback, got %v", issuers)
+	}
+}
+
 func TestRealClient_GetIntermediateCA_UnconfiguredMount(t *testing.T) {
 	certificatePEM := newVaultTestCertificatePEM(t)
 	server := newVaultTestServer(vaultTestServerState{certificatePEM: certificatePEM})
@@ -612,6 +729,37 @@ func TestRealClient_CacheSize(t *testing.T) {
 	}
 }
 
+func TestRealClient_LastSync(t *testing.T) {
+	certificatePEM := newVaultTestCertificatePEM(t)
+	server := newVaultTestServer(vaultTestServerState{certificatePEM: certificatePEM})
+	defer server.Close()
+	client := newRealClientForTest(t, server.URL, []string{"pki"})
+	if !client.LastSync().IsZero() {
+		t.Fatalf("expected zero last sync before listing")
+	}
+	before := time.Now()
+	if _, err := client.ListCertificates(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	synced := client.LastSync()
+	if synced.Before(before) {
+		t.Fatalf("expected last sync after %v, got %v", before, synced)
+	}
+	if _, err := client.ListCertificates(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !client.LastSync().Equal(synced) {
+		t.Fatalf("expected a cache hit to keep last sync %v, got %v", synced, client.LastSync())
+	}
+	client.InvalidateCache()
+	if _, err := client.ListCertificates(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !client.LastSync().After(synced) {
+		t.Fatalf("expected a fresh listing to move last sync past %v", synced)
+	}
+}
+
 // TestRealClient_Logging tests that logging works correctly for vault operations
 func TestRealClient_Logging(t *testing.T) {
 	// Setup logger to capture output
@@ -771,3 +919,33 @@ func TestRealClient_LoggingErrors(t *testing.T) {
 		t.Errorf("Expected mount listing error log, got: %s", output)
 	}
 }
+
+func TestRevocationTime(t *testing.T) {
+	want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	tests := []struct {
+		name string
+		data map[string]any
+		want *time.Time
+	}{
+		{name: "missing", data: map[string]any{}},
+		{name: "not revoked", data: map[string]any{"revocation_time": json.Number("0")}},
+		{name: "json number", data: map[string]any{"revocation_time": json.Number("1700000000")}, want: &want},
+		{name: "float", data: map[string]any{"revocation_time": float64(1700000000)}, want: &want},
+		{name: "rfc3339 preferred", data: map[string]any{"revocation_time": json.Number("1"), "revocation_time_rfc3339": "2023-11-14T22:13:20Z"}, want: &want},
+		{name: "invalid rfc3339 falls back", data: map[string]any{"revocation_time": json.Number("1700000000"), "revocation_time_rfc3339": "soon"}, want: &want},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := revocationTime(tt.data)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("expected nil, got %v", got)
+				}
+				return
+			}
+			if got == nil || !got.Equal(*tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,17 +1,26 @@
 // @vitest-environment jsdom
 import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
 import { render, screen, fireEvent } from '@testing-library/svelte'
-import type { Certificate, CertificatesEnvelope, I18nResponse, PublicConfigResponse, StatusResponse } from '$lib/types'
-
-const { listCertificates, config, status, i18n } = vi.hoisted(() => ({
+import type {
+  Certificate,
+  CertificateQuery,
+  CertificatesEnvelope,
+  I18nResponse,
+  PublicConfigResponse,
+  StatsEnvelope,
+  StatusResponse,
+} from '$lib/types'
+
+const { listCertificates, stats, config, status, i18n } = vi.hoisted(() => ({
   listCertificates: vi.fn(),
+  stats: vi.fn(),
   config: vi.fn(),
   status: vi.fn(),
   i18n: vi.fn(),
 }))
 
 vi.mock('$lib/api', () => ({
-  api: { listCertificates, config, status, i18n },
+  api: { listCertificates, stats, config, status, i18n },
   ApiError: class ApiError extends Error {},
 }))
 
@@ -31,8 +40,23 @@ function cert(overrides: Partial<Certificate> = {}): Certificate {
   }
 }
 
+/** Answers like GET /api/certs: `q` matches the common name, `total` counts matches before paging. */
 function mockOk(certificates: Certificate[]): void {
-  listCertificates.mockResolvedValue({ certificates, errors: [] } satisfies CertificatesEnvelope)
+  listCertificates.mockImplementation(async (_mounts?: string[], query: CertificateQuery = {}) => {
+    const matches = certificates.filter((c) => !query.q || c.commonName.includes(query.q))
+    return { certificates: matches, errors: [], total: matches.length } satisfies CertificatesEnvelope
+  })
+  stats.mockResolvedValue({
+    total: certificates.length,
+    statuses: { valid: certificates.length, warning: 0, critical: 0, expired: 0, revoked: 0 },
+    expiring: { warning: 0, critical: 0 },
+    buckets: {},
+    issuers: {},
+    keyTypes: {},
+    vaults: {},
+    mounts: {},
+    errors: [],
+  } satisfies StatsEnvelope)
   config.mockResolvedValue({
     expirationThresholds: { critical: 7, warning: 30 },
   } satisfies PublicConfigResponse)
@@ -46,6 +70,7 @@ function mockOk(certificates: Certificate[]): void {
 
 beforeEach(() => {
   listCertificates.mockReset()
+  stats.mockReset()
   config.mockReset()
   status.mockReset()
   i18n.mockReset()
@@ -65,6 +90,8 @@ describe('App', () => {
     expect(await screen.findAllByText('web.example.com')).toHaveLength(2)
     expect(screen.getAllByText('api.example.com')).toHaveLength(2)
     expect(listCertificates).toHaveBeenCalledTimes(1)
+    expect(listCertificates).toHaveBeenCalledWith(undefined, { sort: 'expiresAt', order: 'asc', page: 1, pageSize: 25 })
+    expect(stats).toHaveBeenCalledTimes(1)
   })
 
   it('debounces search and updates the result count only after the timer fires', async () => {
@@ -83,6 +110,8 @@ describe('App', () => {
     await vi.advanceTimersByTimeAsync(200)
 
     expect(screen.getByText('1 certificates')).toBeInTheDocument()
+    expect(listCertificates).toHaveBeenCalledTimes(2)
+    expect(listCertificates).toHaveBeenLastCalledWith(undefined, expect.objectContaining({ q: 'web', page: 1 }))
   })
 
   it('restores filter/sort/page state from the URL on mount', async () => {
@@ -94,6 +123,14 @@ describe('App', () => {
 
     const searchInput = screen.getByLabelText('Search certificates') as HTMLInputElement
     expect(searchInput.value).toBe('web')
+    expect(listCertificates).toHaveBeenCalledWith(undefined, {
+      q: 'web',
+      status: ['critical'],
+      sort: 'commonName',
+      order: 'desc',
+      page: 1,
+      pageSize: 25,
+    })
   })
 
   it('writes filter state back to the URL after the debounce, once hydrated', async () => {
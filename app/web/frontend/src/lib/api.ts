@@ -3,13 +3,25 @@ import type {
   AdminSessionResponse,
   AdminSettingsResponse,
   AdminVaultAddedResponse,
+  CertificateComparison,
+  CertificateQuery,
   CertificatesEnvelope,
   DetailedCertificate,
+  EventHistoryQuery,
+  EventHistoryResponse,
+  ForecastEnvelope,
+  ForecastQuery,
+  HistoryQuery,
+  HistoryResponse,
+  InspectResponse,
   I18nResponse,
   PemResponse,
+  ProbesResponse,
   PublicConfigResponse,
   SettingsFile,
+  StatsEnvelope,
   StatusResponse,
+  UnmanagedResponse,
   VersionInfo,
 } from './types'
 
@@ -26,8 +38,10 @@ export class ApiError extends Error {
 async function parseErrorMessage(response: Response, path: string, method: string): Promise<string> {
   let message = `${method} ${path} failed: ${response.status}`
   try {
-    const body = (await response.json()) as { error?: string }
-    if (body?.error) message = body.error
+    // Public /api/v1 routes answer { code, message }; admin routes { error }.
+    const body = (await response.json()) as { error?: string; message?: string }
+    if (body?.message) message = body.message
+    else if (body?.error) message = body.error
   } catch {
     // non-JSON body
   }
@@ -68,32 +82,112 @@ async function requestVoid(path: string, init?: RequestInit): Promise<void> {
   }
 }
 
+function certificateParams(mounts: string[] | undefined, query: CertificateQuery): URLSearchParams {
+  const params = new URLSearchParams()
+  if (mounts !== undefined) params.set('mounts', mounts.join(','))
+  if (query.q) params.set('q', query.q)
+  if (query.query) params.set('query', query.query)
+  if (query.status?.length) params.set('status', query.status.join(','))
+  if (query.expiringWithin) params.set('expiring_within', query.expiringWithin)
+  if (query.issuer) params.set('issuer', query.issuer)
+  if (query.keyAlgorithm?.length) params.set('key_algorithm', query.keyAlgorithm.join(','))
+  if (query.certType?.length) params.set('cert_type', query.certType.join(','))
+  if (query.sort) params.set('sort', query.sort)
+  if (query.order) params.set('order', query.order)
+  if (query.pageSize) params.set('page_size', String(query.pageSize))
+  if (query.page) params.set('page', String(query.page))
+  return params
+}
+
+function eventHistoryParams(mounts: string[] | undefined, query: EventHistoryQuery): URLSearchParams {
+  const params = new URLSearchParams()
+  if (mounts !== undefined) params.set('mounts', mounts.join(','))
+  if (query.type?.length) params.set('type', query.type.join(','))
+  if (query.certificateId) params.set('certificate_id', query.certificateId)
+  if (query.from) params.set('from', query.from)
+  if (query.to) params.set('to', query.to)
+  if (query.order) params.set('order', query.order)
+  if (query.pageSize) params.set('page_size', String(query.pageSize))
+  if (query.page) params.set('page', String(query.page))
+  return params
+}
+
 export const api = {
-  listCertificates(mounts?: string[]): Promise<CertificatesEnvelope> {
-    const qs = mounts === undefined ? '' : `?mounts=${encodeURIComponent(mounts.join(','))}`
-    return request<CertificatesEnvelope>(`/api/certs${qs}`)
+  listCertificates(mounts?: string[], query: CertificateQuery = {}): Promise<CertificatesEnvelope> {
+    const qs = certificateParams(mounts, query).toString()
+    return request<CertificatesEnvelope>(`/api/v1/certs${qs ? `?${qs}` : ''}`)
+  },
+  /** Aggregated counts of what listCertificates returns for the same filters; paging is ignored. */
+  stats(mounts?: string[], query: CertificateQuery = {}): Promise<StatsEnvelope> {
+    const qs = certificateParams(mounts, query).toString()
+    return request<StatsEnvelope>(`/api/v1/stats${qs ? `?${qs}` : ''}`)
+  },
+  /** Upcoming expirations per week or month of what listCertificates returns for the same filters. */
+  getForecast(mounts?: string[], query: CertificateQuery & ForecastQuery = {}): Promise<ForecastEnvelope> {
+    const params = certificateParams(mounts, query)
+    if (query.weeks) params.set('weeks', String(query.weeks))
+    if (query.months) params.set('months', String(query.months))
+    const qs = params.toString()
+    return request<ForecastEnvelope>(`/api/v1/forecast${qs ? `?${qs}` : ''}`)
+  },
+  /** A page of the event journal, newest first by default; `enabled` is false when the journal is off. */
+  getEventHistory(mounts?: string[], query: EventHistoryQuery = {}): Promise<EventHistoryResponse> {
+    const qs = eventHistoryParams(mounts, query).toString()
+    return request<EventHistoryResponse>(`/api/v1/events/history${qs ? `?${qs}` : ''}`)
+  },
+  /** Recorded counts over time for the selected mounts; `enabled` is false when history is off. */
+  getHistory(mounts?: string[], query: HistoryQuery = {}): Promise<HistoryResponse> {
+    const params = new URLSearchParams()
+    if (mounts !== undefined) params.set('mounts', mounts.join(','))
+    if (query.from) params.set('from', query.from)
+    if (query.to) params.set('to', query.to)
+    if (query.resolution) params.set('resolution', query.resolution)
+    const qs = params.toString()
+    return request<HistoryResponse>(`/api/v1/history${qs ? `?${qs}` : ''}`)
   },
   getCertificateDetails(id: string): Promise<DetailedCertificate> {
-    return request<DetailedCertificate>(`/api/certs/${encodeURIComponent(id)}/details`)
+    return request<DetailedCertificate>(`/api/v1/certs/${encodeURIComponent(id)}/details`)
+  },
+  /** Field-by-field diff of certificate `b` against `a`; the IDs may be in different vaults. */
+  compareCertificates(a: string, b: string): Promise<CertificateComparison> {
+    const qs = new URLSearchParams({ a, b }).toString()
+    return request<CertificateComparison>(`/api/v1/certs/compare?${qs}`)
+  },
+  /** Parses and lints pasted PEM; with `verify`, lists the mounts whose CA issued each certificate. Nothing is stored. */
+  inspect(pemText: string, verify = false): Promise<InspectResponse> {
+    const qs = verify ? '?verify=true' : ''
+    return request<InspectResponse>(`/api/v1/inspect${qs}`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/x-pem-file' },
+      body: pemText,
+    })
+  },
+  /** Latest live TLS probe results; `enabled` is false when probing is off. */
+  getProbes(): Promise<ProbesResponse> {
+    return request<ProbesResponse>('/api/v1/probes')
+  },
+  /** Endpoints of the last discovery scan whose certificate vcv does not manage. */
+  getUnmanagedCertificates(): Promise<UnmanagedResponse> {
+    return request<UnmanagedResponse>('/api/v1/findings/unmanaged')
   },
   getCertificatePem(id: string): Promise<PemResponse> {
-    return request<PemResponse>(`/api/certs/${encodeURIComponent(id)}/pem`)
+    return request<PemResponse>(`/api/v1/certs/${encodeURIComponent(id)}/pem`)
   },
   status(): Promise<StatusResponse> {
-    return request<StatusResponse>('/api/status')
+    return request<StatusResponse>('/api/v1/status')
   },
   config(): Promise<PublicConfigResponse> {
-    return request<PublicConfigResponse>('/api/config')
+    return request<PublicConfigResponse>('/api/v1/config')
   },
   version(): Promise<VersionInfo> {
-    return request<VersionInfo>('/api/version')
+    return request<VersionInfo>('/api/v1/version')
   },
   i18n(lang?: string): Promise<I18nResponse> {
     const qs = lang ? `?lang=${encodeURIComponent(lang)}` : ''
-    return request<I18nResponse>(`/api/i18n${qs}`)
+    return request<I18nResponse>(`/api/v1/i18n${qs}`)
   },
   getCertificateCA(id: string): Promise<DetailedCertificate> {
-    return request<DetailedCertificate>(`/api/certs/${encodeURIComponent(id)}/ca`)
+    return request<DetailedCertificate>(`/api/v1/certs/${encodeURIComponent(id)}/ca`)
   },
   adminSession(): Promise<AdminSessionResponse> {
     return request<AdminSessionResponse>('/api/admin/session')
@@ -0,0 +1,102 @@
+// @vitest-environment jsdom
+import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { render, screen, fireEvent, waitFor } from '@testing-library/svelte'
+import type { Certificate, CertificateComparison } from '$lib/types'
+
+const { listCertificates, compareCertificates } = vi.hoisted(() => ({
+  listCertificates: vi.fn(),
+  compareCertificates: vi.fn(),
+}))
+
+vi.mock('$lib/api', () => ({
+  api: { listCertificates, compareCertificates },
+  ApiError: class ApiError extends Error {},
+}))
+
+vi.mock('$lib/stores/i18n.svelte', () => ({
+  getI18n: () => ({ t: (_key: string, fallback?: string) => fallback ?? _key, lang: 'en' }),
+}))
+
+import CertCompare from '$lib/components/CertCompare.svelte'
+
+function cert(overrides: Partial<Certificate> = {}): Certificate {
+  return {
+    id: 'vault1|pki:bb',
+    serialNumber: 'bb',
+    commonName: 'web.example.com',
+    sans: ['web.example.com'],
+    certType: 'machine',
+    createdAt: '2026-01-01T00:00:00Z',
+    expiresAt: '2027-01-01T00:00:00Z',
+    revoked: false,
+    ...overrides,
+  }
+}
+
+const current = cert()
+const previous = cert({ id: 'vault1|pki:aa', serialNumber: 'aa', createdAt: '2025-01-01T00:00:00Z', expiresAt: '2026-01-15T00:00:00Z' })
+
+function same(value: string) {
+  return { a: value, b: value, changed: false }
+}
+
+const comparison: CertificateComparison = {
+  a: previous,
+  b: current,
+  identical: false,
+  sameKey: true,
+  subject: same('CN=web.example.com'),
+  issuer: same('CN=Example CA'),
+  keyAlgorithm: same('RSA'),
+  keySize: same('2048'),
+  signatureAlgorithm: same('SHA256-RSA'),
+  sans: { added: ['api.example.com'], removed: [], unchanged: ['web.example.com'], changed: true },
+  keyUsages: { added: [], removed: [], unchanged: ['Digital Signature'], changed: false },
+  extKeyUsages: { added: [], removed: [], unchanged: ['Server Authentication'], changed: false },
+  validity: {
+    notBefore: { a: '2025-01-01T00:00:00Z', b: '2026-01-01T00:00:00Z', changed: true },
+    notAfter: { a: '2026-01-15T00:00:00Z', b: '2027-01-01T00:00:00Z', changed: true },
+    lifetimeDays: { a: '379', b: '365', changed: true },
+  },
+  extensions: { added: [], removed: [], unchanged: [], changed: false },
+  changed: ['sans', 'validity'],
+}
+
+beforeEach(() => {
+  listCertificates.mockReset()
+  compareCertificates.mockReset()
+})
+
+describe('CertCompare', () => {
+  it('lists certificates with the same common name and renders the diff older to newer', async () => {
+    listCertificates.mockResolvedValue({
+      certificates: [current, previous, cert({ id: 'vault1|pki:cc', commonName: 'api.web.example.com' })],
+      errors: [],
+    })
+    compareCertificates.mockResolvedValue(comparison)
+    render(CertCompare, { props: { cert: current, onBack: vi.fn() } })
+
+    await waitFor(() =>
+      expect(listCertificates).toHaveBeenCalledWith(undefined, expect.objectContaining({ q: 'web.example.com' })),
+    )
+    const candidates = await screen.findAllByRole('button', { pressed: false })
+    expect(candidates).toHaveLength(1)
+    await fireEvent.click(screen.getByText('aa'))
+
+    expect(compareCertificates).toHaveBeenCalledWith(previous.id, current.id)
+    expect(await screen.findByText('+ api.example.com')).toBeInTheDocument()
+    expect(screen.getByText('Same key pair')).toBeInTheDocument()
+    expect(screen.getByText('379')).toBeInTheDocument()
+    expect(screen.getByText('365')).toBeInTheDocument()
+    // Unchanged sets stay out of the diff.
+    expect(screen.queryByText('Key usage')).toBeNull()
+  })
+
+  it('says so when no other certificate shares the common name', async () => {
+    listCertificates.mockResolvedValue({ certificates: [current], errors: [] })
+    render(CertCompare, { props: { cert: current, onBack: vi.fn() } })
+
+    expect(await screen.findByText('No other certificate with this common name')).toBeInTheDocument()
+    expect(compareCertificates).not.toHaveBeenCalled()
+  })
+})
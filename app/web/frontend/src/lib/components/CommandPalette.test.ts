@@ -1,8 +1,14 @@
 // @vitest-environment jsdom
-import { describe, it, expect, vi } from 'vitest'
-import { render, screen, fireEvent } from '@testing-library/svelte'
+import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { render, screen, fireEvent, waitFor } from '@testing-library/svelte'
 import type { Certificate } from '$lib/types'
 
+const { listCertificates } = vi.hoisted(() => ({ listCertificates: vi.fn() }))
+
+vi.mock('$lib/api', () => ({
+  api: { listCertificates },
+}))
+
 vi.mock('$lib/stores/i18n.svelte', () => ({
   getI18n: () => ({ t: (_key: string, fallback?: string) => fallback ?? _key }),
   LANGUAGES: [{ code: 'en', label: 'English' }],
@@ -24,11 +30,10 @@ function cert(overrides: Partial<Certificate> = {}): Certificate {
   }
 }
 
-function baseProps(certs: Certificate[]) {
+function baseProps() {
   return {
     open: true,
     onOpenChange: vi.fn(),
-    certs,
     theme: 'light' as const,
     onSelectCert: vi.fn(),
     onToggleStatus: vi.fn(),
@@ -37,32 +42,54 @@ function baseProps(certs: Certificate[]) {
   }
 }
 
+beforeEach(() => {
+  listCertificates.mockReset()
+})
+
 describe('CommandPalette', () => {
-  it('matches a certificate by common name, serial, or SAN substring', async () => {
-    const certs = [
-      cert({ id: 'a', commonName: 'web.example.com', serialNumber: 'aa:bb', sans: [] }),
-      cert({ id: 'b', commonName: 'other.internal', serialNumber: 'cc:dd', sans: ['san-only.example.com'] }),
-    ]
-    render(CommandPalette, { props: baseProps(certs) })
+  it('searches the whole inventory on the server and lists the matches', async () => {
+    listCertificates.mockResolvedValue({
+      certificates: [cert({ id: 'b', commonName: 'other.internal', sans: ['san-only.example.com'] })],
+      errors: [],
+    })
+    render(CommandPalette, { props: baseProps() })
 
     const input = screen.getByPlaceholderText('Search certificates or commands…')
     await fireEvent.input(input, { target: { value: 'san-only' } })
 
-    expect(screen.getByText('other.internal')).toBeInTheDocument()
-    expect(screen.queryByText('web.example.com')).not.toBeInTheDocument()
+    expect(await screen.findByText('other.internal')).toBeInTheDocument()
+    expect(listCertificates).toHaveBeenCalledTimes(1)
+    expect(listCertificates).toHaveBeenCalledWith(undefined, { q: 'san-only', pageSize: 25 })
   })
 
-  it('re-derives matches when the certificate list changes without a stale haystack', async () => {
-    const initial = [cert({ id: 'a', commonName: 'first.example.com' })]
-    const { rerender } = render(CommandPalette, { props: baseProps(initial) })
+  it('ignores a slower response for an earlier query', async () => {
+    let resolveFirst: (value: { certificates: Certificate[]; errors: [] }) => void = () => {}
+    listCertificates
+      .mockReturnValueOnce(
+        new Promise((resolve) => {
+          resolveFirst = resolve
+        }),
+      )
+      .mockResolvedValueOnce({ certificates: [cert({ id: 'b', commonName: 'second.example.com' })], errors: [] })
+    render(CommandPalette, { props: baseProps() })
 
     const input = screen.getByPlaceholderText('Search certificates or commands…')
+    await fireEvent.input(input, { target: { value: 'first' } })
+    await waitFor(() => expect(listCertificates).toHaveBeenCalledTimes(1))
     await fireEvent.input(input, { target: { value: 'second' } })
-    expect(screen.queryByText('second.example.com')).not.toBeInTheDocument()
+    expect(await screen.findByText('second.example.com')).toBeInTheDocument()
+
+    resolveFirst({ certificates: [cert({ id: 'a', commonName: 'first.example.com' })], errors: [] })
+    await new Promise((r) => setTimeout(r, 20))
+    expect(screen.queryByText('first.example.com')).not.toBeInTheDocument()
+  })
 
-    const updated = [cert({ id: 'b', commonName: 'second.example.com' })]
-    await rerender({ ...baseProps(updated) })
+  it('does not query the server for an empty search', async () => {
+    render(CommandPalette, { props: baseProps() })
 
-    expect(screen.getByText('second.example.com')).toBeInTheDocument()
+    const input = screen.getByPlaceholderText('Search certificates or commands…')
+    await fireEvent.input(input, { target: { value: '   ' } })
+    await new Promise((r) => setTimeout(r, 200))
+    expect(listCertificates).not.toHaveBeenCalled()
   })
 })
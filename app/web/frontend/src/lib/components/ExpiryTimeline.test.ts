@@ -1,7 +1,6 @@
 // @vitest-environment jsdom
 import { describe, it, expect, vi } from 'vitest'
 import { render, screen } from '@testing-library/svelte'
-import type { Certificate } from '$lib/types'
 
 vi.mock('$lib/stores/i18n.svelte', () => ({
   getI18n: () => ({
@@ -15,25 +14,11 @@ vi.mock('$lib/stores/i18n.svelte', () => ({
 
 import ExpiryTimeline from '$lib/components/ExpiryTimeline.svelte'
 
-const thresholds = { critical: 7, warning: 30 }
-
-function cert(overrides: Partial<Certificate> = {}): Certificate {
-  return {
-    id: 'v|m:1',
-    serialNumber: 'aa:bb',
-    commonName: 'web.example.com',
-    sans: [],
-    certType: 'machine',
-    createdAt: '2024-01-01T00:00:00Z',
-    expiresAt: new Date(Date.now() + 3.5 * 86_400_000).toISOString(),
-    revoked: false,
-    ...overrides,
-  }
-}
+const empty = { '0-7d': 0, '7-30d': 0, '30-90d': 0, '90d+': 0, expired: 0, revoked: 0 }
 
 describe('ExpiryTimeline', () => {
-  it('renders bucket counts and threshold-aware labels', () => {
-    render(ExpiryTimeline, { props: { certs: [cert()], thresholds } })
+  it('renders the stats buckets with day-range labels', () => {
+    render(ExpiryTimeline, { props: { buckets: { ...empty, '0-7d': 1, '90d+': 4 } } })
 
     const region = screen.getByRole('region', { name: 'Upcoming expirations' })
     expect(region).toBeInTheDocument()
@@ -41,14 +26,15 @@ describe('ExpiryTimeline', () => {
     expect(region.textContent).toContain('8–30 days')
     expect(region.textContent).toContain('31–90 days')
     expect(region.textContent).toContain('> 90 days')
-    // One cert expiring in ~3 days shows up in the critical bucket.
     const criticalBucket = region.querySelector('.vcv-expiry-bucket-critical .vcv-expiry-bucket-count')
     expect(criticalBucket?.textContent).toBe('1')
+    const laterBucket = region.querySelector('.vcv-expiry-bucket-muted .vcv-expiry-bucket-count')
+    expect(laterBucket?.textContent).toBe('4')
   })
 
   it('renders nothing when no certificate expires in the future', () => {
     const { container } = render(ExpiryTimeline, {
-      props: { certs: [cert({ revoked: true })], thresholds },
+      props: { buckets: { ...empty, expired: 2, revoked: 1 } },
     })
 
     expect(container.querySelector('.vcv-expiry-timeline')).toBeNull()
@@ -1,18 +1,25 @@
 import { api, ApiError } from '$lib/api'
 import type { I18nStore } from '$lib/stores/i18n.svelte'
-import type { Certificate, VaultListError } from '$lib/types'
+import type { Certificate, CertificateQuery, CertStatus, VaultListError } from '$lib/types'
 
 export interface CertsStore {
+  /** The requested page of matching certificates. */
   readonly certificates: Certificate[]
+  /** Matches before paging. */
+  readonly total: number
+  /** Matches per status, ignoring the status filter. */
+  readonly statusCounts: Partial<Record<CertStatus, number>>
   readonly vaultErrors: VaultListError[]
   readonly loading: boolean
   readonly error: string | null
   readonly lastFetched: Date | null
-  refresh(mounts?: string[]): Promise<void>
+  refresh(mounts?: string[], query?: CertificateQuery): Promise<void>
 }
 
 export function createCertsStore(i18n: I18nStore): CertsStore {
   let certificates = $state<Certificate[]>([])
+  let total = $state(0)
+  let statusCounts = $state<Partial<Record<CertStatus, number>>>({})
   let vaultErrors = $state<VaultListError[]>([])
   let loading = $state(false)
   let error = $state<string | null>(null)
@@ -20,14 +27,16 @@ export function createCertsStore(i18n: I18nStore): CertsStore {
   /** Ignores out-of-order list responses when refreshes overlap. */
   let refreshGen = 0
 
-  async function refresh(mounts?: string[]): Promise<void> {
+  async function refresh(mounts?: string[], query: CertificateQuery = {}): Promise<void> {
     const gen = ++refreshGen
     loading = true
     error = null
     try {
-      const envelope = await api.listCertificates(mounts)
+      const envelope = await api.listCertificates(mounts, query)
       if (gen !== refreshGen) return
       certificates = envelope.certificates ?? []
+      total = envelope.total ?? certificates.length
+      statusCounts = envelope.statusCounts ?? {}
       vaultErrors = envelope.errors ?? []
       lastFetched = new Date()
     } catch (err: unknown) {
@@ -37,6 +46,8 @@ export function createCertsStore(i18n: I18nStore): CertsStore {
           ? err.message
           : i18n.t('loadNetworkError', 'Network error loading certificates. Please try again.')
       certificates = []
+      total = 0
+      statusCounts = {}
       vaultErrors = []
     } finally {
       // Only the latest in-flight request may clear loading.
@@ -48,6 +59,12 @@ export function createCertsStore(i18n: I18nStore): CertsStore {
     get certificates() {
       return certificates
     },
+    get total() {
+      return total
+    },
+    get statusCounts() {
+      return statusCounts
+    },
     get vaultErrors() {
       return vaultErrors
     },
@@ -73,6 +73,29 @@ describe('createCertsStore', () => {
     expect(store.loading).toBe(false)
   })
 
+  it('passes the mounts and query through and keeps the envelope totals', async () => {
+    listCertificates.mockResolvedValueOnce({
+      certificates: [sampleCert('a')],
+      errors: [],
+      total: 42,
+      statusCounts: { valid: 40, expired: 2 },
+      page: 2,
+      pageSize: 1,
+    })
+    const store = createCertsStore(i18n)
+    await store.refresh(['v1|pki'], { q: 'web', sort: 'expiresAt', order: 'asc', page: 2, pageSize: 1 })
+    expect(listCertificates).toHaveBeenCalledWith(['v1|pki'], {
+      q: 'web',
+      sort: 'expiresAt',
+      order: 'asc',
+      page: 2,
+      pageSize: 1,
+    })
+    expect(store.certificates.map((c) => c.id)).toEqual(['a'])
+    expect(store.total).toBe(42)
+    expect(store.statusCounts).toEqual({ valid: 40, expired: 2 })
+  })
+
   it('ignores a stale slower response after a newer refresh wins', async () => {
     const first = deferred<CertificatesEnvelope>()
     const second = deferred<CertificatesEnvelope>()
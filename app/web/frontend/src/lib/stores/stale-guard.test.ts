@@ -1,7 +1,7 @@
 import { describe, it, expect, vi, beforeEach, type Mock } from 'vitest'
-import type { I18nResponse, PublicConfigResponse, StatusResponse } from '$lib/types'
+import type { I18nResponse, PublicConfigResponse, StatsEnvelope, StatusResponse } from '$lib/types'
 
-const { configFn, statusFn, i18nFn, ApiError } = vi.hoisted(() => {
+const { configFn, statusFn, statsFn, i18nFn, ApiError } = vi.hoisted(() => {
   class ApiError extends Error {
     status: number
     constructor(status: number, message: string) {
@@ -13,18 +13,20 @@ const { configFn, statusFn, i18nFn, ApiError } = vi.hoisted(() => {
   return {
     configFn: vi.fn(),
     statusFn: vi.fn(),
+    statsFn: vi.fn(),
     i18nFn: vi.fn(),
     ApiError,
   }
 })
 
 vi.mock('$lib/api', () => ({
-  api: { config: configFn, status: statusFn, i18n: i18nFn },
+  api: { config: configFn, status: statusFn, stats: statsFn, i18n: i18nFn },
   ApiError,
 }))
 
 import { createConfigStore } from '$lib/stores/config.svelte'
 import { createStatusStore } from '$lib/stores/status.svelte'
+import { createStatsStore } from '$lib/stores/stats.svelte'
 import { createI18nStore } from '$lib/stores/i18n.svelte'
 
 interface AsyncStore {
@@ -126,6 +128,7 @@ function staleGuardSuite<S extends AsyncStore, R>(opts: {
 beforeEach(() => {
   configFn.mockReset()
   statusFn.mockReset()
+  statsFn.mockReset()
   i18nFn.mockReset()
 })
 
@@ -155,6 +158,33 @@ staleGuardSuite<ReturnType<typeof createStatusStore>, StatusResponse>({
   newerValue: 'newer',
 })
 
+function statsEnvelope(total: number): StatsEnvelope {
+  return {
+    total,
+    statuses: { valid: total, warning: 0, critical: 0, expired: 0, revoked: 0 },
+    expiring: { warning: 0, critical: 0 },
+    buckets: {},
+    issuers: {},
+    keyTypes: {},
+    vaults: {},
+    mounts: {},
+    errors: [],
+  }
+}
+
+staleGuardSuite<ReturnType<typeof createStatsStore>, StatsEnvelope>({
+  label: 'createStatsStore',
+  mock: statsFn,
+  setup: () => {
+    const store = createStatsStore()
+    return { store, fetch: () => store.refresh() }
+  },
+  newer: statsEnvelope(2),
+  stale: statsEnvelope(9),
+  read: (store) => store.stats?.total,
+  newerValue: 2,
+})
+
 staleGuardSuite<ReturnType<typeof createI18nStore>, I18nResponse>({
   label: 'createI18nStore',
   mock: i18nFn,
@@ -0,0 +1,48 @@
+import { api, ApiError } from '$lib/api'
+import type { StatsEnvelope } from '$lib/types'
+
+export interface StatsStore {
+  /** Server-side counts of the whole inventory, from GET /api/v1/stats. */
+  readonly stats: StatsEnvelope | null
+  readonly loading: boolean
+  readonly error: string | null
+  refresh(mounts?: string[]): Promise<void>
+}
+
+export function createStatsStore(): StatsStore {
+  let stats = $state<StatsEnvelope | null>(null)
+  let loading = $state(false)
+  let error = $state<string | null>(null)
+  /** Ignores out-of-order stats responses when refreshes overlap. */
+  let refreshGen = 0
+
+  async function refresh(mounts?: string[]): Promise<void> {
+    const gen = ++refreshGen
+    loading = true
+    error = null
+    try {
+      const next = await api.stats(mounts)
+      if (gen !== refreshGen) return
+      stats = next
+    } catch (err: unknown) {
+      if (gen !== refreshGen) return
+      // Keep the last counts: the certificate list reports the failure.
+      error = err instanceof ApiError ? err.message : 'Failed to fetch stats'
+    } finally {
+      if (gen === refreshGen) loading = false
+    }
+  }
+
+  return {
+    get stats() {
+      return stats
+    },
+    get loading() {
+      return loading
+    },
+    get error() {
+      return error
+    },
+    refresh,
+  }
+}
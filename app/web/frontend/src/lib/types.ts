@@ -6,9 +6,26 @@ export interface Certificate {
   commonName: string
   sans: string[]
   certType: string
+  certLabel?: string
+  extKeyUsages?: string[]
+  isCA?: boolean
   createdAt: string
   expiresAt: string
   revoked: boolean
+  /** Revocation time reported by Vault, when revoked. */
+  revokedAt?: string
+  publicKeyFingerprint?: string
+  sharedKeyWith?: string[]
+  /** Size of the short-lived group this row stands for (mount rollup policy). */
+  rollupCount?: number
+  /** Set when an active acknowledgement excludes the certificate from alerts. */
+  acknowledgement?: CertificateAcknowledgement
+}
+
+export interface CertificateAcknowledgement {
+  id: string
+  reason?: string
+  expiresAt?: string
 }
 
 export interface DetailedCertificate extends Certificate {
@@ -31,6 +48,258 @@ export interface VaultListError {
 export interface CertificatesEnvelope {
   certificates: Certificate[]
   errors: VaultListError[]
+  /** Matches before paging. */
+  total?: number
+  /** Matches per status, ignoring the status filter. */
+  statusCounts?: Partial<Record<CertStatus, number>>
+  page?: number
+  pageSize?: number
+}
+
+/** Counts of one group of certificates, from GET /api/stats. */
+export interface CertificateCounts {
+  total: number
+  /** Every certificate counts under exactly one status. */
+  statuses: Record<CertStatus, number>
+  /** Inside the warning/critical window of their mount; critical ones usually count toward both. */
+  expiring: { warning: number; critical: number }
+  /** Keyed by `0-7d`, `7-30d`, `30-90d`, `90d+`, `expired`, `revoked`. */
+  buckets: Record<string, number>
+  issuers: Record<string, number>
+  /** Keyed by `ALGORITHM-SIZE`, e.g. `RSA-2048`. */
+  keyTypes: Record<string, number>
+}
+
+export interface StatsEnvelope extends CertificateCounts {
+  vaults: Record<string, CertificateCounts>
+  /** Keyed by `vault|mount`, or the bare mount for certificates without a vault. */
+  mounts: Record<string, CertificateCounts>
+  errors: VaultListError[]
+}
+
+export type ForecastPeriod = 'week' | 'month'
+
+/** Window count for GET /api/forecast: `weeks` (1-104) or `months` (1-24), not both. */
+export interface ForecastQuery {
+  weeks?: number
+  months?: number
+}
+
+/** Expirations of one vault, mount and issuer; `counts` is aligned with `windows`. */
+export interface ForecastGroup {
+  vault: string
+  mount: string
+  issuer: string
+  counts: number[]
+  total: number
+}
+
+export interface ForecastEnvelope {
+  period: ForecastPeriod
+  /** Consecutive windows starting now; `end` is exclusive. */
+  windows: { start: string; end: string }[]
+  totals: number[]
+  total: number
+  /** Certificates left out because a later one with the same common name renewed them. */
+  superseded: number
+  groups: ForecastGroup[]
+  errors: VaultListError[]
+}
+
+export type InventoryEventType =
+  | 'certificate_added'
+  | 'certificate_revoked'
+  | 'certificate_expired'
+  | 'certificate_warning'
+  | 'certificate_critical'
+  | 'certificate_tidied'
+  | 'certificate_removed'
+  | 'vault_connected'
+  | 'vault_disconnected'
+
+/** One inventory change, from GET /api/events and /api/events/history. */
+export interface InventoryEvent {
+  id: number
+  type: InventoryEventType
+  time: string
+  vaultId?: string
+  mount?: string
+  certificateId?: string
+  commonName?: string
+  status?: CertStatus
+  expiresAt?: string
+}
+
+/** Filters and paging of GET /api/events/history; dates are RFC 3339 times or `YYYY-MM-DD`. */
+export interface EventHistoryQuery {
+  type?: InventoryEventType[]
+  certificateId?: string
+  from?: string
+  to?: string
+  order?: 'asc' | 'desc'
+  page?: number
+  pageSize?: number
+}
+
+export interface EventHistoryResponse {
+  enabled: boolean
+  events: InventoryEvent[]
+  /** Every matching event, not just this page. */
+  total: number
+  page: number
+  pageSize: number
+}
+
+export type HistoryResolution = 'hour' | 'day'
+
+/** Range of GET /api/history; dates are RFC 3339 times or `YYYY-MM-DD`. */
+export interface HistoryQuery {
+  from?: string
+  to?: string
+  resolution?: HistoryResolution
+}
+
+/** Counts of the selected mounts at one recorded snapshot. */
+export interface HistoryPoint {
+  at: string
+  total: number
+  statuses: Record<CertStatus, number>
+  expiring: { warning: number; critical: number }
+  /** Selected vaults that could not be listed; they add nothing to the counts. */
+  failedVaults?: string[]
+}
+
+export interface HistoryResponse {
+  enabled: boolean
+  resolution: HistoryResolution
+  from: string
+  to: string
+  points: HistoryPoint[]
+}
+
+/** A single-valued field of both certificates, from GET /api/certs/compare. */
+export interface ValueChange {
+  a: string
+  b: string
+  changed: boolean
+}
+
+/** A multi-valued field of both certificates; `added` means present in `b` only. */
+export interface SetChange {
+  added: string[]
+  removed: string[]
+  /** Extensions only: present in both with a different value or criticality. */
+  modified?: string[]
+  unchanged: string[]
+  changed: boolean
+}
+
+export interface CertificateComparison {
+  a: Certificate
+  b: Certificate
+  identical: boolean
+  sameKey: boolean
+  subject: ValueChange
+  issuer: ValueChange
+  keyAlgorithm: ValueChange
+  keySize: ValueChange
+  signatureAlgorithm: ValueChange
+  sans: SetChange
+  keyUsages: SetChange
+  extKeyUsages: SetChange
+  validity: { notBefore: ValueChange; notAfter: ValueChange; lifetimeDays: ValueChange }
+  extensions: SetChange
+  /** Names of the fields above that differ. */
+  changed: string[]
+}
+
+export interface LintFinding {
+  rule: string
+  severity: 'error' | 'warning' | 'info'
+  message: string
+}
+
+export interface CertificateRequestDetails {
+  subject: string
+  commonName: string
+  sans: string[]
+  keyAlgorithm: string
+  keySize: number
+  signatureAlgorithm: string
+  publicKeyFingerprint: string
+  pem: string
+}
+
+export interface InspectResponse {
+  certificates: Array<DetailedCertificate & { findings: LintFinding[]; issuedBy: string[] }>
+  requests: Array<CertificateRequestDetails & { findings: LintFinding[] }>
+  /** True when `issuedBy` was filled, i.e. the request used `verify=true`. */
+  verified: boolean
+}
+
+export type ProbeStatus = 'ok' | 'outdated' | 'unknown' | 'revoked' | 'expired' | 'error'
+
+export interface PresentedCertificate {
+  subject: string
+  issuer: string
+  sans: string[]
+  serialNumber: string
+  fingerprintSHA256: string
+  notBefore: string
+  notAfter: string
+}
+
+export interface ProbeResult {
+  address: string
+  serverName: string
+  source: 'config' | 'san'
+  status: ProbeStatus
+  error?: string
+  checkedAt: string
+  chain: PresentedCertificate[]
+  /** Inventory certificate the endpoint serves. */
+  certificateId?: string
+  /** Newer inventory certificate that should be deployed instead. */
+  currentId?: string
+}
+
+export interface ProbesResponse {
+  enabled: boolean
+  lastRun?: string
+  results: ProbeResult[]
+}
+
+export interface DiscoveredEndpoint {
+  address: string
+  checkedAt: string
+  certificate: PresentedCertificate
+  managed: boolean
+  certificateId?: string
+  issuedBy?: string
+}
+
+export interface UnmanagedResponse {
+  enabled: boolean
+  lastRun?: string
+  scannedEndpoints: number
+  tlsEndpoints: number
+  unmanaged: DiscoveredEndpoint[]
+}
+
+/** Server-side filter, sort and paging parameters of GET /api/certs. */
+export interface CertificateQuery {
+  q?: string
+  /** Certificate query language, e.g. `san:*.prod.example.com AND expires<20d`. */
+  query?: string
+  status?: CertStatus[]
+  expiringWithin?: string
+  issuer?: string
+  keyAlgorithm?: string[]
+  certType?: string[]
+  sort?: 'commonName' | 'expiresAt' | 'createdAt' | 'serialNumber' | 'vault' | 'pki' | 'issuer'
+  order?: 'asc' | 'desc'
+  page?: number
+  pageSize?: number
 }
 
 export interface PemResponse {
@@ -63,6 +332,14 @@ export type CertStatus = 'valid' | 'expired' | 'revoked' | 'critical' | 'warning
 export interface ExpirationThresholds {
   critical: number
   warning: number
+  /** Exact windows from GET /api/config; take precedence over whole days. */
+  criticalSeconds?: number
+  warningSeconds?: number
+  /** Remaining share of total lifetime (0-100) that also triggers the level. */
+  criticalPercent?: number
+  warningPercent?: number
+  /** Overrides keyed by bare mount name or "vault_id|mount". */
+  mounts?: Record<string, ExpirationThresholds>
 }
 
 export interface VaultInstance {
@@ -79,10 +356,22 @@ export interface VaultInstance {
   tls_ca_path?: string
   tls_server_name?: string
   enabled?: boolean | null
+  expiration_thresholds?: ExpirationThresholdSettings | null
+}
+
+/** settings.json shape of certificates.expiration_thresholds (and per-vault overrides). */
+export interface ExpirationThresholdSettings {
+  critical: number
+  warning: number
+  critical_duration?: string
+  warning_duration?: string
+  critical_percent?: number
+  warning_percent?: number
+  mounts?: Record<string, ExpirationThresholdSettings>
 }
 
 export interface CertificateSettings {
-  expiration_thresholds: ExpirationThresholds
+  expiration_thresholds: ExpirationThresholdSettings
 }
 
 export interface MetricsSettings {
@@ -98,6 +387,8 @@ export interface CORSSettings {
 
 export interface NotificationSettings {
   webhook_url?: string
+  /** Certificate query restricting which certificates count toward notifications. */
+  query?: string
 }
 
 export interface AppSettings {
@@ -154,4 +445,5 @@ export interface PublicConfigResponse {
   metrics?: { per_certificate?: boolean; enhanced_metrics?: boolean }
   pkiMounts?: string[]
   vaults?: { id: string; displayName: string; pkiMounts: string[] }[]
+  certTypes?: { type: string; label?: string }[]
 }
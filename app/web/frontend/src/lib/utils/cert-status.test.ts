@@ -31,6 +31,20 @@ describe('daysUntilExpiry', () => {
 })
 
 describe('certStatus', () => {
+  it('applies exact windows, lifetime percentages and mount overrides', () => {
+    const thresholds = {
+      critical: 7,
+      warning: 30,
+      criticalSeconds: 7 * 86400,
+      warningSeconds: 30 * 86400,
+      mounts: { 'v|m': { critical: 1, warning: 1, criticalSeconds: 6 * 3600, warningSeconds: 0, warningPercent: 50 } },
+    }
+    const shortLived = { ...cert('2026-06-17T12:00:00Z'), createdAt: '2026-06-14T12:00:00Z' }
+    expect(certStatus(shortLived, thresholds, NOW)).toBe('warning') // 12h left of 72h
+    expect(certStatus({ ...shortLived, expiresAt: '2026-06-17T04:00:00Z' }, thresholds, NOW)).toBe('critical')
+    expect(certStatus({ ...shortLived, id: 'v|other:1' }, thresholds, NOW)).toBe('critical') // global 7 days
+  })
+
   it('classifies by threshold with default 7/30', () => {
     expect(certStatus(cert('2026-09-01T00:00:00Z'), undefined, NOW)).toBe('valid') // >30d
     expect(certStatus(cert('2026-07-01T00:00:00Z'), undefined, NOW)).toBe('warning') // 14d
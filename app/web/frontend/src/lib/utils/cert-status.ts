@@ -21,11 +21,41 @@ export function certStatus(
   // Unknown expiry is treated as expired for safety (NaN never compares as < or <=).
   if (!Number.isFinite(days)) return 'expired'
   if (days < 0) return 'expired'
-  if (days <= thresholds.critical) return 'critical'
-  if (days <= thresholds.warning) return 'warning'
+  const window = thresholdsForCert(cert, thresholds)
+  const exact =
+    window.criticalSeconds != null ||
+    window.warningSeconds != null ||
+    window.criticalPercent != null ||
+    window.warningPercent != null
+  if (!exact) {
+    if (days <= window.critical) return 'critical'
+    if (days <= window.warning) return 'warning'
+    return 'valid'
+  }
+  const remainingMs = new Date(cert.expiresAt).getTime() - now.getTime()
+  const lifetimeMs = new Date(cert.expiresAt).getTime() - new Date(cert.createdAt).getTime()
+  const criticalSeconds = window.criticalSeconds ?? window.critical * 86400
+  const warningSeconds = window.warningSeconds ?? window.warning * 86400
+  if (withinWindow(remainingMs, lifetimeMs, criticalSeconds, window.criticalPercent)) return 'critical'
+  if (withinWindow(remainingMs, lifetimeMs, warningSeconds, window.warningPercent)) return 'warning'
   return 'valid'
 }
 
+/** Mirrors certs.ExpiryThresholds.WindowFor: "vault|mount", then mount, then global. */
+export function thresholdsForCert(cert: Certificate, thresholds: ExpirationThresholds): ExpirationThresholds {
+  const mounts = thresholds.mounts
+  if (!mounts) return thresholds
+  const { vault, mount } = parseCertID(cert.id)
+  if (vault && mounts[`${vault}|${mount}`]) return mounts[`${vault}|${mount}`]
+  return mounts[mount] ?? thresholds
+}
+
+function withinWindow(remainingMs: number, lifetimeMs: number, seconds: number, percent?: number): boolean {
+  if (seconds > 0 && remainingMs <= seconds * 1000) return true
+  if (percent == null || percent <= 0 || !Number.isFinite(lifetimeMs) || lifetimeMs <= 0) return false
+  return remainingMs * 100 <= percent * lifetimeMs
+}
+
 export function statusBadgeClass(status: CertStatus): string {
   switch (status) {
     case 'valid':
@@ -40,6 +40,26 @@ describe('thresholdsFromConfig', () => {
     })
   })
 
+  it('keeps exact windows, percentages and mount overrides', () => {
+    expect(
+      thresholdsFromConfig({
+        critical: 0,
+        warning: 2,
+        criticalSeconds: 0,
+        warningSeconds: 129600,
+        criticalPercent: 10,
+        mounts: { pki_mesh: { critical: 1, warning: 1, criticalSeconds: 21600, warningSeconds: 86400 } },
+      }),
+    ).toEqual({
+      critical: 0,
+      warning: 2,
+      criticalSeconds: 0,
+      warningSeconds: 129600,
+      criticalPercent: 10,
+      mounts: { pki_mesh: { critical: 1, warning: 1, criticalSeconds: 21600, warningSeconds: 86400 } },
+    })
+  })
+
   it('uses an explicit fallback when provided', () => {
     const custom = { critical: 1, warning: 2 }
     expect(thresholdsFromConfig(undefined, custom)).toEqual(custom)
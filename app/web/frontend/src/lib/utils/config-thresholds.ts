@@ -6,7 +6,7 @@ import { DEFAULT_THRESHOLDS } from '$lib/utils/cert-status'
  * Invalid or missing values fall back to DEFAULT_THRESHOLDS.
  */
 export function thresholdsFromConfig(
-  raw: { critical?: number; warning?: number } | undefined,
+  raw: Partial<ExpirationThresholds> | undefined,
   fallback: ExpirationThresholds = DEFAULT_THRESHOLDS,
 ): ExpirationThresholds {
   if (
@@ -15,10 +15,29 @@ export function thresholdsFromConfig(
     typeof raw.warning === 'number' &&
     Number.isFinite(raw.critical) &&
     Number.isFinite(raw.warning) &&
-    raw.critical > 0 &&
-    raw.warning > 0
+    (raw.critical > 0 || positive(raw.criticalSeconds) || positive(raw.criticalPercent)) &&
+    (raw.warning > 0 || positive(raw.warningSeconds) || positive(raw.warningPercent))
   ) {
-    return { critical: raw.critical, warning: raw.warning }
+    return withWindows(raw as ExpirationThresholds)
   }
   return fallback
 }
+
+function positive(value: number | undefined): boolean {
+  return typeof value === 'number' && Number.isFinite(value) && value > 0
+}
+
+/** Copies the exact windows only when the server sent them. */
+function withWindows(raw: ExpirationThresholds): ExpirationThresholds {
+  const out: ExpirationThresholds = { critical: Math.max(raw.critical, 0), warning: Math.max(raw.warning, 0) }
+  if (typeof raw.criticalSeconds === 'number' || typeof raw.warningSeconds === 'number') {
+    out.criticalSeconds = positive(raw.criticalSeconds) ? raw.criticalSeconds : 0
+    out.warningSeconds = positive(raw.warningSeconds) ? raw.warningSeconds : 0
+  }
+  if (positive(raw.criticalPercent)) out.criticalPercent = raw.criticalPercent
+  if (positive(raw.warningPercent)) out.warningPercent = raw.warningPercent
+  if (raw.mounts) {
+    out.mounts = Object.fromEntries(Object.entries(raw.mounts).map(([key, value]) => [key, withWindows(value)]))
+  }
+  return out
+}